@@ -22,6 +22,7 @@ var (
 			cli.NewListCommand(actionListCmd, actionListRun, nil),
 			cli.NewGetCommand(actionShowCmd, actionShowRun, nil),
 			cli.NewCommand(actionDeleteCmd, actionDeleteRun, nil),
+			cli.NewListCommand(actionImpactCmd, actionImpactRun, nil),
 			cli.NewCommand(actionDocCmd, actionDocRun, nil),
 		})
 )
@@ -67,6 +68,22 @@ func actionDeleteRun(v cli.Values) error {
 	return client.ActionDelete(v["action-name"])
 }
 
+var actionImpactCmd = cli.Command{
+	Name:  "impact",
+	Short: "List the jobs, pipelines, applications and workflow nodes depending on a CDS action",
+	Args: []cli.Arg{
+		{Name: "action-name"},
+	},
+}
+
+func actionImpactRun(v cli.Values) (cli.ListResult, error) {
+	impacts, err := client.ActionImpact(v["action-name"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(impacts), nil
+}
+
 var actionDocCmd = cli.Command{
 	Name:  "doc",
 	Short: "Generate Action Documentation: cdsctl action doc <path-to-hclFile>",
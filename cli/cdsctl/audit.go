@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	auditCmd = cli.Command{
+		Name:  "audit",
+		Short: "Browse CDS audit log",
+	}
+
+	audit = cli.NewCommand(auditCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(auditListCmd, auditListRun, nil),
+			cli.NewListCommand(auditShowCmd, auditShowRun, nil),
+		})
+)
+
+var auditListCmd = cli.Command{
+	Name:  "list",
+	Short: "List audit entries, most recent first",
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Filter on a project key, mandatory if you are not CDS administrator",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "user",
+			Usage: "Filter on the author of the changes",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "entity-type",
+			Usage: "Filter on an entity type: workflow, pipeline, permission, key, variable, worker_model, token",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "entity",
+			Usage: "Filter on an entity name, ie: pipeline/build",
+			Kind:  reflect.String,
+		},
+		{
+			Name:    "since",
+			Usage:   "Only entries after this date (RFC3339) or duration, ie: 24h",
+			Kind:    reflect.String,
+			IsValid: isAuditTime,
+		},
+		{
+			Name:    "until",
+			Usage:   "Only entries before this date (RFC3339) or duration, ie: 1h",
+			Kind:    reflect.String,
+			IsValid: isAuditTime,
+		},
+		{
+			Name:    "limit",
+			Usage:   "Max number of entries",
+			Default: "100",
+			Kind:    reflect.String,
+			IsValid: isPositiveInt,
+		},
+	},
+}
+
+func isAuditTime(s string) bool {
+	_, err := parseAuditTime(s)
+	return err == nil
+}
+
+// parseAuditTime accepts either a RFC3339 date or a duration counted back from now
+func parseAuditTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func auditListRun(v cli.Values) (cli.ListResult, error) {
+	f := sdk.AuditFilter{
+		ProjectKey: v["project"],
+		Username:   v["user"],
+		EntityType: v["entity-type"],
+		EntityName: v["entity"],
+	}
+	var err error
+	if f.Since, err = parseAuditTime(v["since"]); err != nil {
+		return nil, err
+	}
+	if f.Until, err = parseAuditTime(v["until"]); err != nil {
+		return nil, err
+	}
+	f.Limit, _ = strconv.Atoi(v["limit"])
+
+	audits, err := client.AuditList(f)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(audits), nil
+}
+
+var auditShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show the changes recorded by an audit entry",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func auditShowRun(v cli.Values) (cli.ListResult, error) {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit id %s", v["id"])
+	}
+	a, err := client.AuditGet(id)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(a.Diff), nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+func init() {
+	cli.ArgCompletions["project-key"] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+		projs, err := c.ProjectList()
+		res := make([]string, len(projs))
+		for i := range projs {
+			res[i] = projs[i].Key
+		}
+		return res, err
+	})
+	for _, name := range []string{"application-name", "app-name"} {
+		cli.ArgCompletions[name] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+			apps, err := c.ApplicationList(v["project-key"])
+			res := make([]string, len(apps))
+			for i := range apps {
+				res[i] = apps[i].Name
+			}
+			return res, err
+		})
+	}
+	for _, name := range []string{"environment-name", "env-name"} {
+		cli.ArgCompletions[name] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+			envs, err := c.EnvironmentList(v["project-key"])
+			res := make([]string, len(envs))
+			for i := range envs {
+				res[i] = envs[i].Name
+			}
+			return res, err
+		})
+	}
+	cli.ArgCompletions["pipeline-name"] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+		pips, err := c.PipelineList(v["project-key"])
+		res := make([]string, len(pips))
+		for i := range pips {
+			res[i] = pips[i].Name
+		}
+		return res, err
+	})
+	for _, name := range []string{"workflow-name", "workflow"} {
+		cli.ArgCompletions[name] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+			ws, err := c.WorkflowList(v["project-key"])
+			res := make([]string, len(ws))
+			for i := range ws {
+				res[i] = ws[i].Name
+			}
+			return res, err
+		})
+	}
+	cli.ArgCompletions["action-name"] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+		actions, err := c.ActionList()
+		res := make([]string, len(actions))
+		for i := range actions {
+			res[i] = actions[i].Name
+		}
+		return res, err
+	})
+	cli.ArgCompletions["groupname"] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+		groups, err := c.GroupList()
+		res := make([]string, len(groups))
+		for i := range groups {
+			res[i] = groups[i].Name
+		}
+		return res, err
+	})
+	cli.ArgCompletions["username"] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+		users, err := c.UserList()
+		res := make([]string, len(users))
+		for i := range users {
+			res[i] = users[i].Username
+		}
+		return res, err
+	})
+	cli.ArgCompletions["model"] = completeNames(func(c cdsclient.Interface, v cli.Values) ([]string, error) {
+		models, err := c.WorkerModels()
+		res := make([]string, len(models))
+		for i := range models {
+			res[i] = models[i].Name
+		}
+		return res, err
+	})
+	cli.ArgCompletions["context-name"] = completeContexts
+}
+
+// completionClient returns the client used to complete the arguments, the completion commands
+// don't load the configuration before they run but the flags of the completed command line are set
+func completionClient() (cdsclient.Interface, error) {
+	if client != nil {
+		return client, nil
+	}
+	c, err := loadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	client, err = loadClient(c)
+	return client, err
+}
+
+// completeNames returns the function completing an argument with the names returned by a loader
+// querying the API
+func completeNames(load func(c cdsclient.Interface, v cli.Values) ([]string, error)) cli.CompletionFunc {
+	return func(v cli.Values) ([]string, error) {
+		c, err := completionClient()
+		if err != nil {
+			return nil, err
+		}
+		return load(c, v)
+	}
+}
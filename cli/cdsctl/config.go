@@ -1,14 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/BurntSushi/toml"
+	ntoml "github.com/naoina/toml"
 
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/keychain"
@@ -18,7 +21,18 @@ type config struct {
 	Host                  string
 	user                  string
 	token                 string
-	InsecureSkipVerifyTLS bool
+	InsecureSkipVerifyTLS bool `toml:"insecure_skip_verify_tls"`
+	// Context is the name of the current context, it's used instead of Host when it's set
+	Context  string                   `toml:"context,omitempty"`
+	Contexts map[string]contextConfig `toml:"contexts,omitempty"`
+}
+
+// contextConfig is a CDS instance with the user logged on it, the token of the user is stored in
+// the keychain
+type contextConfig struct {
+	Host                  string `toml:"host"`
+	User                  string `toml:"user"`
+	InsecureSkipVerifyTLS bool   `toml:"insecure_skip_verify_tls"`
 }
 
 func loadConfig(configFile string) (*cdsclient.Config, error) {
@@ -36,44 +50,43 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 		}
 	}
 
-	dir, err := os.Getwd()
-	if err != nil {
-		return nil, err
-	}
-
-	u, err := user.Current()
-	if err != nil {
-		return nil, err
-	}
-
-	var configFiles []string
-	if configFile != "" {
-		configFiles = []string{configFile}
-	} else {
-		configFiles = []string{
-			path.Join(dir, ".cdsrc"),
-			path.Join(u.HomeDir, ".cdsrc"),
+	// The context given by the flag is used instead of the environment variables
+	if c.Host == "" || contextName != "" {
+		file, err := configFilePath(configFile, false)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	var i int
-	for c.Host == "" && i < len(configFiles) {
-		if _, err := os.Stat(configFiles[i]); err == nil {
-			b, err := ioutil.ReadFile(configFiles[i])
+		if file != "" {
+			fc, err := readConfig(file)
 			if err != nil {
 				if verbose {
-					fmt.Printf("Unable to read %s \n", configFiles[i])
+					fmt.Printf("Unable to read %s \n", file)
 				}
 				return nil, err
 			}
-			if _, err := toml.Decode(string(b), c); err != nil {
-				return nil, err
+			name := contextName
+			if name == "" {
+				name = fc.Context
+			}
+			if name != "" {
+				ctx, has := fc.Contexts[name]
+				if !has {
+					return nil, fmt.Errorf("unknown context %s", name)
+				}
+				c.Host = ctx.Host
+				c.user = ctx.User
+				c.InsecureSkipVerifyTLS = ctx.InsecureSkipVerifyTLS
+				currentContext = name
+			} else {
+				c.Host = fc.Host
+				c.InsecureSkipVerifyTLS = fc.InsecureSkipVerifyTLS
 			}
 			if verbose {
-				fmt.Println("Configuration loaded from", configFiles[i])
+				fmt.Println("Configuration loaded from", file)
 			}
+		} else if contextName != "" {
+			return nil, fmt.Errorf("unknown context %s", contextName)
 		}
-		i++
 	}
 
 	if c.Host == "" {
@@ -91,7 +104,11 @@ func loadConfig(configFile string) (*cdsclient.Config, error) {
 }
 
 func loadClient(c *cdsclient.Config) (cdsclient.Interface, error) {
-	user, secret, err := keychain.GetSecret(c.Host)
+	url := c.Host
+	if currentContext != "" {
+		url = contextSecretURL(currentContext, c.Host)
+	}
+	user, secret, err := keychain.GetSecret(url)
 	if err != nil {
 		return nil, err
 	}
@@ -99,3 +116,68 @@ func loadClient(c *cdsclient.Config) (cdsclient.Interface, error) {
 	c.Token = secret
 	return cdsclient.New(*c), nil
 }
+
+// configFilePath returns the configuration file given by the flag, or the first existing file
+// between ./.cdsrc and ~/.cdsrc. If none exists it returns ~/.cdsrc when the file is needed to
+// write the configuration, and an empty path otherwise
+func configFilePath(configFile string, write bool) (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	configFiles := []string{
+		path.Join(dir, ".cdsrc"),
+		path.Join(u.HomeDir, ".cdsrc"),
+	}
+	for _, f := range configFiles {
+		if _, err := os.Stat(f); err == nil {
+			return f, nil
+		}
+	}
+	if write {
+		return configFiles[1], nil
+	}
+	return "", nil
+}
+
+// readConfig reads a configuration file, it returns an empty configuration if the file doesn't
+// exist
+func readConfig(file string) (*config, error) {
+	c := &config{}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.Decode(string(b), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func writeConfig(file string, c *config) error {
+	var buf = new(bytes.Buffer)
+	e := ntoml.NewEncoder(buf)
+	if err := e.Encode(c); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), os.FileMode(0644))
+}
+
+// contextSecretURL returns the URL of the token of a context in the keychain, each context has its
+// own token even if several contexts use the same CDS instance
+func contextSecretURL(name, host string) string {
+	return strings.TrimSuffix(host, "/") + "/cdsctl/contexts/" + name
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk/keychain"
+)
+
+var (
+	contextCmd = cli.Command{
+		Name:  "context",
+		Short: "Manage the CDS instances used by cdsctl",
+		Long: `A context is a CDS instance with the user logged on it, its token is stored in the keychain. The current context
+is used by all the commands, unless another one is given with --context.`,
+	}
+
+	ctx = cli.NewCommand(contextCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(contextListCmd, contextListRun, nil),
+			cli.NewCommand(contextUseCmd, contextUseRun, nil),
+			cli.NewCommand(contextAddCmd, contextAddRun, nil),
+			cli.NewCommand(contextRemoveCmd, contextRemoveRun, nil),
+		})
+)
+
+// contextItem is a context displayed by context list
+type contextItem struct {
+	Name    string `cli:"name,key"`
+	Host    string `cli:"host"`
+	User    string `cli:"user"`
+	Current bool   `cli:"current"`
+}
+
+var contextListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the contexts",
+}
+
+func contextListRun(v cli.Values) (cli.ListResult, error) {
+	file, err := configFilePath(configFile, false)
+	if err != nil || file == "" {
+		return nil, err
+	}
+	c, err := readConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []contextItem{}
+	for name, ctx := range c.Contexts {
+		items = append(items, contextItem{Name: name, Host: ctx.Host, User: ctx.User, Current: name == c.Context})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return cli.AsListResult(items), nil
+}
+
+var contextUseCmd = cli.Command{
+	Name:  "use",
+	Short: "Set the current context",
+	Args: []cli.Arg{
+		{Name: "context-name"},
+	},
+}
+
+func contextUseRun(v cli.Values) error {
+	file, err := configFilePath(configFile, true)
+	if err != nil {
+		return err
+	}
+	c, err := readConfig(file)
+	if err != nil {
+		return err
+	}
+	if _, has := c.Contexts[v["context-name"]]; !has {
+		return fmt.Errorf("unknown context %s", v["context-name"])
+	}
+	c.Context = v["context-name"]
+	if err := writeConfig(file, c); err != nil {
+		return err
+	}
+	fmt.Printf("Switched to context %s\n", c.Context)
+	return nil
+}
+
+var contextAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Login to CDS and save the login as a context",
+	Long:  "Login to CDS and save the login as a context, the context is updated if it already exists.",
+	Args: []cli.Arg{
+		{Name: "context-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:      "host",
+			ShortHand: "H",
+			Usage:     "CDS API Url",
+			IsValid: func(s string) bool {
+				match, _ := regexp.MatchString(`http[s]?:\/\/(.*)`, s)
+				return match
+			},
+			Kind: reflect.String,
+		}, {
+			Name:      "username",
+			ShortHand: "u",
+			Usage:     "CDS Username",
+			Kind:      reflect.String,
+		}, {
+			Name:      "password",
+			ShortHand: "p",
+			Usage:     "CDS Password",
+			Kind:      reflect.String,
+		},
+	},
+}
+
+func contextAddRun(v cli.Values) error {
+	contextName = v["context-name"]
+	return loginRun(v)
+}
+
+var contextRemoveCmd = cli.Command{
+	Name:  "remove",
+	Short: "Remove a context and its token",
+	Args: []cli.Arg{
+		{Name: "context-name"},
+	},
+}
+
+func contextRemoveRun(v cli.Values) error {
+	file, err := configFilePath(configFile, true)
+	if err != nil {
+		return err
+	}
+	c, err := readConfig(file)
+	if err != nil {
+		return err
+	}
+	name := v["context-name"]
+	ctx, has := c.Contexts[name]
+	if !has {
+		return fmt.Errorf("unknown context %s", name)
+	}
+
+	delete(c.Contexts, name)
+	if c.Context == name {
+		c.Context = ""
+	}
+	if err := writeConfig(file, c); err != nil {
+		return err
+	}
+	return keychain.DeleteSecret(contextSecretURL(name, ctx.Host))
+}
+
+// saveContext adds or updates a context in the configuration file, and stores its token in the
+// keychain
+func saveContext(name, url, username, token string) error {
+	file, err := configFilePath(configFile, true)
+	if err != nil {
+		return err
+	}
+	c, err := readConfig(file)
+	if err != nil {
+		return err
+	}
+	if c.Contexts == nil {
+		c.Contexts = map[string]contextConfig{}
+	}
+	c.Contexts[name] = contextConfig{Host: url, User: username, InsecureSkipVerifyTLS: insecureSkipVerifyTLS}
+	// Without a previous login, the first context is the current one
+	if c.Context == "" && c.Host == "" {
+		c.Context = name
+	}
+	if err := writeConfig(file, c); err != nil {
+		return err
+	}
+
+	if err := keychain.StoreSecret(contextSecretURL(name, url), username, token); err != nil {
+		return err
+	}
+	fmt.Printf("Context %s saved in %s\n", name, file)
+	return nil
+}
+
+func completeContexts(v cli.Values) ([]string, error) {
+	items, err := contextListRun(v)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, len(items))
+	for i := range items {
+		res[i] = items[i].(contextItem).Name
+	}
+	return res, nil
+}
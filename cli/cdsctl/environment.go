@@ -18,6 +18,7 @@ var (
 	environment = cli.NewCommand(environmentCmd, nil,
 		[]*cobra.Command{
 			cli.NewListCommand(environmentListCmd, environmentListRun, nil),
+			cli.NewListCommand(environmentImpactCmd, environmentImpactRun, nil),
 			environmentKey,
 		})
 )
@@ -37,3 +38,20 @@ func environmentListRun(v cli.Values) (cli.ListResult, error) {
 	}
 	return cli.AsListResult(apps), nil
 }
+
+var environmentImpactCmd = cli.Command{
+	Name:  "impact",
+	Short: "List the applications and workflow nodes depending on a CDS environment",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "env-name"},
+	},
+}
+
+func environmentImpactRun(v cli.Values) (cli.ListResult, error) {
+	impacts, err := client.EnvironmentImpact(v["project-key"], v["env-name"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(impacts), nil
+}
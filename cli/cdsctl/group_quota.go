@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	groupQuotaCmd = cli.Command{
+		Name:  "quota",
+		Short: "Manage CDS group quota",
+	}
+
+	groupQuota = cli.NewCommand(groupQuotaCmd, nil,
+		[]*cobra.Command{
+			cli.NewGetCommand(groupQuotaShowCmd, groupQuotaShowRun, nil),
+			cli.NewCommand(groupQuotaSetCmd, groupQuotaSetRun, nil),
+		})
+)
+
+var groupQuotaShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show the quota of a group and its usage",
+	Args: []cli.Arg{
+		{Name: "groupname"},
+	},
+}
+
+func groupQuotaShowRun(v cli.Values) (interface{}, error) {
+	usage, err := client.GroupQuota(v["groupname"])
+	if err != nil {
+		return nil, err
+	}
+	return *usage, nil
+}
+
+var groupQuotaSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Set the quota of a group, 0 means no limit",
+	Args: []cli.Arg{
+		{Name: "groupname"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "max-workers",
+			Usage:   "Max number of concurrent workers",
+			Default: "0",
+			Kind:    reflect.String,
+			IsValid: isPositiveInt,
+		},
+		{
+			Name:    "cpu-minutes-per-day",
+			Usage:   "Max number of CPU minutes per day",
+			Default: "0",
+			Kind:    reflect.String,
+			IsValid: isPositiveInt,
+		},
+		{
+			Name:  "models",
+			Usage: "Max number of concurrent workers by model, ie: model1=2,model2=5",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+func isPositiveInt(s string) bool {
+	i, err := strconv.ParseInt(s, 10, 64)
+	return err == nil && i >= 0
+}
+
+func groupQuotaSetRun(v cli.Values) error {
+	q := sdk.GroupQuota{}
+	q.MaxWorkers, _ = strconv.ParseInt(v["max-workers"], 10, 64)
+	q.CPUMinutesPerDay, _ = strconv.ParseInt(v["cpu-minutes-per-day"], 10, 64)
+
+	if v["models"] != "" {
+		for _, m := range strings.Split(v["models"], ",") {
+			t := strings.SplitN(m, "=", 2)
+			if len(t) != 2 {
+				return fmt.Errorf("Invalid model quota %s", m)
+			}
+			max, err := strconv.ParseInt(t[1], 10, 64)
+			if err != nil || max < 0 {
+				return fmt.Errorf("Invalid model quota %s", m)
+			}
+			q.Models = append(q.Models, sdk.GroupModelQuota{Model: strings.TrimSpace(t[0]), MaxWorkers: max})
+		}
+	}
+
+	return client.GroupQuotaUpdate(v["groupname"], &q)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var (
+	hatcheryCmd = cli.Command{
+		Name:  "hatchery",
+		Short: "Manage CDS hatchery",
+	}
+
+	hatchery = cli.NewCommand(hatcheryCmd, nil,
+		[]*cobra.Command{
+			hatcheryMaintenance,
+		})
+)
+
+var (
+	hatcheryMaintenanceCmd = cli.Command{
+		Name:  "maintenance",
+		Short: "Manage the maintenance mode of a CDS hatchery",
+		Long: `In maintenance, the hatchery stops spawning workers and its workers finish their current job then exit.
+The jobs booked by the hatchery are spawned by other hatcheries.`,
+	}
+
+	hatcheryMaintenance = cli.NewCommand(hatcheryMaintenanceCmd, nil,
+		[]*cobra.Command{
+			cli.NewCommand(hatcheryMaintenanceEnableCmd, hatcheryMaintenanceEnableRun, nil),
+			cli.NewCommand(hatcheryMaintenanceDisableCmd, hatcheryMaintenanceDisableRun, nil),
+		})
+)
+
+var hatcheryMaintenanceEnableCmd = cli.Command{
+	Name:  "enable",
+	Short: "Set a CDS hatchery in maintenance",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func hatcheryMaintenanceEnableRun(v cli.Values) error {
+	return client.HatcheryMaintenance(v["name"], true)
+}
+
+var hatcheryMaintenanceDisableCmd = cli.Command{
+	Name:  "disable",
+	Short: "End the maintenance of a CDS hatchery",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func hatcheryMaintenanceDisableRun(v cli.Values) error {
+	return client.HatcheryMaintenance(v["name"], false)
+}
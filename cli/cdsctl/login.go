@@ -1,9 +1,7 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"os/user"
 	"path"
@@ -12,7 +10,6 @@ import (
 	"runtime"
 
 	"github.com/howeyc/gopass"
-	"github.com/naoina/toml"
 
 	"github.com/ovh/cds/cli"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -118,6 +115,10 @@ func doLogin(url, username, password string, env bool) error {
 		return nil
 	}
 
+	if contextName != "" {
+		return saveContext(contextName, url, username, token)
+	}
+
 	if configFile == "" {
 		u, err := user.Current()
 		if err != nil {
@@ -134,16 +135,15 @@ func doLogin(url, username, password string, env bool) error {
 		}
 	}
 
-	tomlConf := config{
-		Host: url,
-		InsecureSkipVerifyTLS: insecureSkipVerifyTLS,
-	}
-	var buf = new(bytes.Buffer)
-	e := toml.NewEncoder(buf)
-	if err := e.Encode(tomlConf); err != nil {
+	// The contexts are kept, but the login is used instead of the current context
+	tomlConf, err := readConfig(configFile)
+	if err != nil {
 		return err
 	}
-	if err := ioutil.WriteFile(configFile, buf.Bytes(), os.FileMode(0644)); err != nil {
+	tomlConf.Host = url
+	tomlConf.InsecureSkipVerifyTLS = insecureSkipVerifyTLS
+	tomlConf.Context = ""
+	if err := writeConfig(configFile, tomlConf); err != nil {
 		return err
 	}
 
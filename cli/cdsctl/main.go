@@ -11,6 +11,8 @@ import (
 
 var (
 	configFile            string
+	contextName           string
+	currentContext        string
 	cfg                   *cdsclient.Config
 	verbose               bool
 	noWarnings            bool
@@ -24,19 +26,24 @@ func main() {
 	health := cli.NewGetCommand(healthCmd, healthRun, nil, cli.CommandWithoutExtraFlags)
 	version := cli.NewCommand(versionCmd, versionRun, nil, cli.CommandWithoutExtraFlags)
 	monitoring := cli.NewGetCommand(monitoringCmd, monitoringRun, nil, cli.CommandWithoutExtraFlags)
+	search := cli.NewListCommand(searchCmd, searchRun, nil)
 
 	root := cli.NewCommand(mainCmd, mainRun,
 		[]*cobra.Command{
 			action,
+			audit,
+			ctx,
 			login,
 			signup,
 			application,
 			environment,
 			pipeline,
 			group,
+			hatchery,
 			project,
 			worker,
 			workflow,
+			search,
 			usr,
 			monitoring,
 			health,
@@ -44,13 +51,18 @@ func main() {
 		},
 	)
 
+	completion, complete := cli.NewCompletionCommands(root)
+	shell := cli.NewShellCommand(root)
+	root.AddCommand(completion, complete, shell)
+
 	root.PersistentFlags().StringVarP(&configFile, "file", "f", "", "set configuration file")
+	root.PersistentFlags().StringVarP(&contextName, "context", "c", "", "use a context instead of the current one")
 	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	root.PersistentFlags().BoolVarP(&noWarnings, "no-warnings", "w", false, "do not display warnings")
 	root.PersistentFlags().BoolVarP(&insecureSkipVerifyTLS, "insecure", "k", false, `(SSL) This option explicitly allows curl to perform "insecure" SSL connections and transfers.`)
 	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
-		//Do not load config nor display warnings on login
-		if cmd == login || (cmd.Run == nil && cmd.RunE == nil) {
+		//Do not load config nor display warnings on login, the contexts, the completion and the interactive mode
+		if cmd == login || cmd == completion || cmd == complete || cmd == shell || cmd.Parent() == ctx || (cmd.Run == nil && cmd.RunE == nil) {
 			return
 		}
 
@@ -19,6 +19,9 @@ var (
 		[]*cobra.Command{
 			cli.NewListCommand(projectListCmd, projectListRun, nil),
 			cli.NewGetCommand(projectShowCmd, projectShowRun, nil),
+			cli.NewCommand(projectExportCmd, projectExportRun, nil),
+			cli.NewCommand(projectImportCmd, projectImportRun, nil),
+			cli.NewCommand(projectEncryptCmd, projectEncryptRun, nil),
 			projectKey,
 		})
 )
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var projectExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a whole CDS project in a directory",
+	Long: `Export the variables, permissions, applications, pipelines, environments and workflows of a project
+in a directory, with a file per entity. The secrets are encrypted with a key derived from the secret key of the CDS
+instance and from the key of the project: the directory can only be imported in a project with the same key, on an
+instance sharing the same secret key.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "format",
+			Usage: "yml or json",
+			IsValid: func(s string) bool {
+				if s != "json" && s != "yml" {
+					return false
+				}
+				return true
+			},
+			Kind:    reflect.String,
+			Default: "yml",
+		},
+	},
+}
+
+func projectExportRun(v cli.Values) error {
+	format, err := exportentities.GetFormat(v["format"])
+	if err != nil {
+		return err
+	}
+	tree, err := client.ProjectExport(v["project-key"])
+	if err != nil {
+		return err
+	}
+	return exportentities.WriteTree(v["path"], tree, format)
+}
+
+var projectImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a whole CDS project from a directory",
+	Long: `Import a directory exported by "cdsctl project export" in a project. The entities of the directory
+are added or updated, nothing is deleted from the project. Importing the same directory twice doesn't change anything.
+
+Use --dry-run to show the planned changes without applying them.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "dry-run",
+			Usage: "Show the changes without applying them",
+			IsValid: func(s string) bool {
+				if s != "true" && s != "false" {
+					return false
+				}
+				return true
+			},
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+	},
+}
+
+func projectImportRun(v cli.Values) error {
+	tree, err := exportentities.ReadTree(v["path"])
+	if err != nil {
+		return err
+	}
+	changes, err := client.ProjectImport(v["project-key"], tree, v.GetBool("dry-run"))
+	if err != nil {
+		return err
+	}
+
+	var n int
+	for _, c := range changes {
+		if c.Action == sdk.ImportUnchanged {
+			continue
+		}
+		n++
+		fmt.Printf("%s %s %s\n", c.Action, c.EntityType, c.EntityName)
+		for _, d := range c.Diff {
+			fmt.Printf("  %s: %q -> %q\n", d.Key, d.Before, d.After)
+		}
+	}
+	if n == 0 {
+		fmt.Println("Nothing to import")
+	}
+	return nil
+}
+
+var projectEncryptCmd = cli.Command{
+	Name:  "encrypt",
+	Short: "Encrypt a secret with the key of a project",
+	Long: `Encrypt a secret with the key of a project, the value is read on the standard input if it's not given.
+The encrypted value can be written in the exported files of the project instead of the secret and stored safely in a
+repository, it's decrypted by CDS when the files are imported in the project. Only the values of the password and key
+variables and parameters can be encrypted.
+
+The value is encrypted with a key derived from the secret key of the CDS instance and from the key of the project: it
+can only be decrypted in a project with the same key, on an instance sharing the same secret key.`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "secret-value"},
+	},
+}
+
+func projectEncryptRun(v cli.Values) error {
+	value := v["secret-value"]
+	if value == "" {
+		btes, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		value = strings.TrimSuffix(string(btes), "\n")
+	}
+
+	variable := &sdk.Variable{Type: sdk.SecretVariable, Value: value}
+	if err := client.ProjectVariableEncrypt(v["project-key"], variable); err != nil {
+		return err
+	}
+	fmt.Println(variable.Value)
+	return nil
+}
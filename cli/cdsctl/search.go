@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var searchCmd = cli.Command{
+	Name:  "search",
+	Short: "Search across the projects you can read",
+	Long: `Search the names of projects, applications, pipelines, environments, workflows and variables,
+the actions used and the requirements of the jobs, the repositories and the content of the scripts.
+
+Example:
+
+	cdsctl search docker --type requirement,script
+`,
+	Args: []cli.Arg{
+		{Name: "query"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Search only in this project",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "type",
+			Usage: "Comma separated types of result: " + strings.Join(sdk.SearchTypes, ", "),
+			Kind:  reflect.String,
+		},
+		{
+			Name:    "limit",
+			Usage:   "Max number of results by type",
+			Default: "50",
+			Kind:    reflect.String,
+			IsValid: isPositiveInt,
+		},
+	},
+}
+
+func searchRun(v cli.Values) (cli.ListResult, error) {
+	f := sdk.SearchFilter{
+		Query:      v["query"],
+		ProjectKey: v["project"],
+	}
+	if v["type"] != "" {
+		f.Types = strings.Split(v["type"], ",")
+	}
+	f.Limit, _ = strconv.Atoi(v["limit"])
+
+	results, err := client.Search(f)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(results), nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
@@ -16,6 +18,7 @@ var (
 		[]*cobra.Command{
 			cli.NewListCommand(workerListCmd, workerListRun, nil),
 			workerModel,
+			workerMaintenance,
 		})
 )
 
@@ -31,3 +34,54 @@ func workerListRun(v cli.Values) (cli.ListResult, error) {
 	}
 	return cli.AsListResult(workers), nil
 }
+
+var (
+	workerMaintenanceCmd = cli.Command{
+		Name:  "maintenance",
+		Short: "Manage the maintenance mode of a CDS worker",
+		Long:  "In maintenance, the worker finishes its current job then exits instead of taking a new one",
+	}
+
+	workerMaintenance = cli.NewCommand(workerMaintenanceCmd, nil,
+		[]*cobra.Command{
+			cli.NewCommand(workerMaintenanceEnableCmd, workerMaintenanceEnableRun, nil),
+			cli.NewCommand(workerMaintenanceDisableCmd, workerMaintenanceDisableRun, nil),
+		})
+)
+
+var workerMaintenanceEnableCmd = cli.Command{
+	Name:  "enable",
+	Short: "Drain a CDS worker",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func workerMaintenanceEnableRun(v cli.Values) error {
+	return workerSetMaintenance(v["name"], true)
+}
+
+var workerMaintenanceDisableCmd = cli.Command{
+	Name:  "disable",
+	Short: "Stop draining a CDS worker",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func workerMaintenanceDisableRun(v cli.Values) error {
+	return workerSetMaintenance(v["name"], false)
+}
+
+func workerSetMaintenance(name string, maintenance bool) error {
+	workers, err := client.WorkerList()
+	if err != nil {
+		return err
+	}
+	for _, w := range workers {
+		if w.Name == name {
+			return client.WorkerMaintenance(w.ID, maintenance)
+		}
+	}
+	return fmt.Errorf("worker %s not found", name)
+}
@@ -15,6 +15,8 @@ var (
 	workerModel = cli.NewCommand(workerModelCmd, nil,
 		[]*cobra.Command{
 			cli.NewListCommand(workerModelListCmd, workerModelListRun, nil),
+			cli.NewListCommand(workerModelImpactCmd, workerModelImpactRun, nil),
+			workerModelVersion,
 		})
 )
 
@@ -30,3 +32,23 @@ func workerModelListRun(v cli.Values) (cli.ListResult, error) {
 	}
 	return cli.AsListResult(workerModels), nil
 }
+
+var workerModelImpactCmd = cli.Command{
+	Name:  "impact",
+	Short: "List the jobs, pipelines, applications and workflow nodes depending on a CDS worker model",
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func workerModelImpactRun(v cli.Values) (cli.ListResult, error) {
+	m, err := workerModelByName(v["name"])
+	if err != nil {
+		return nil, err
+	}
+	impacts, err := client.WorkerModelImpact(m.ID)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(impacts), nil
+}
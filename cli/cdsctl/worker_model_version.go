@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	workerModelVersionCmd = cli.Command{
+		Name:  "version",
+		Short: "Manage the versions of a Worker Model",
+	}
+
+	workerModelVersion = cli.NewCommand(workerModelVersionCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(workerModelVersionListCmd, workerModelVersionListRun, nil),
+			cli.NewGetCommand(workerModelVersionAddCmd, workerModelVersionAddRun, nil),
+			cli.NewCommand(workerModelVersionRolloutCmd, workerModelVersionRolloutRun, nil),
+			cli.NewCommand(workerModelVersionPromoteCmd, workerModelVersionPromoteRun, nil),
+		})
+)
+
+func workerModelByName(name string) (*sdk.Model, error) {
+	models, err := client.WorkerModels()
+	if err != nil {
+		return nil, err
+	}
+	for i := range models {
+		if models[i].Name == name {
+			return &models[i], nil
+		}
+	}
+	return nil, fmt.Errorf("worker model %s not found", name)
+}
+
+var workerModelVersionListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the versions of a worker model, the last version first",
+	Args: []cli.Arg{
+		{Name: "model"},
+	},
+}
+
+func workerModelVersionListRun(v cli.Values) (cli.ListResult, error) {
+	m, err := workerModelByName(v["model"])
+	if err != nil {
+		return nil, err
+	}
+	versions, err := client.WorkerModelVersions(m.ID)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(versions), nil
+}
+
+var workerModelVersionAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a version to a docker worker model, the version is checked by the hatcheries before it can be rolled out and promoted",
+	Args: []cli.Arg{
+		{Name: "model"},
+		{Name: "image"},
+	},
+}
+
+func workerModelVersionAddRun(v cli.Values) (interface{}, error) {
+	m, err := workerModelByName(v["model"])
+	if err != nil {
+		return nil, err
+	}
+	version, err := client.WorkerModelVersionAdd(m.ID, &sdk.ModelVersion{Image: v["image"]})
+	if err != nil {
+		return nil, err
+	}
+	return *version, nil
+}
+
+var workerModelVersionRolloutCmd = cli.Command{
+	Name:  "rollout",
+	Short: "Use a checked version for a percent of the spawns of a worker model",
+	Args: []cli.Arg{
+		{Name: "model"},
+		{Name: "version", IsValid: isPositiveInt},
+		{Name: "percent", IsValid: isPositiveInt},
+	},
+}
+
+func workerModelVersionRolloutRun(v cli.Values) error {
+	m, err := workerModelByName(v["model"])
+	if err != nil {
+		return err
+	}
+	version, _ := strconv.ParseInt(v["version"], 10, 64)
+	rollout, _ := strconv.ParseInt(v["percent"], 10, 64)
+	return client.WorkerModelVersionRollout(m.ID, version, rollout)
+}
+
+var workerModelVersionPromoteCmd = cli.Command{
+	Name:  "promote",
+	Short: "Make a version the current version of a worker model, promoting a previous version rolls the model back",
+	Args: []cli.Arg{
+		{Name: "model"},
+		{Name: "version", IsValid: isPositiveInt},
+	},
+}
+
+func workerModelVersionPromoteRun(v cli.Values) error {
+	m, err := workerModelByName(v["model"])
+	if err != nil {
+		return err
+	}
+	version, _ := strconv.ParseInt(v["version"], 10, 64)
+	return client.WorkerModelVersionPromote(m.ID, version)
+}
@@ -16,7 +16,10 @@ var (
 		[]*cobra.Command{
 			cli.NewListCommand(workflowListCmd, workflowListRun, nil),
 			cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil),
+			cli.NewCommand(workflowRunCmd, workflowRunRun, nil),
+			cli.NewCommand(workflowStatusCmd, workflowStatusRun, nil),
 			workflowArtifact,
+			workflowTests,
 		})
 )
 
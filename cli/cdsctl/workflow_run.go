@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+// workflowRunWaitDelay is the delay between two checks of the status of a run while waiting for its end
+var workflowRunWaitDelay = 5 * time.Second
+
+var workflowRunWaitFlag = cli.Flag{
+	Name:    "wait",
+	Usage:   "Wait the end of the run, exit with an error if the run failed",
+	Default: "false",
+	Kind:    reflect.Bool,
+}
+
+var workflowRunCmd = cli.Command{
+	Name:  "run",
+	Short: "Run a CDS workflow",
+	Long: `Run a CDS workflow from its root, or run again a node of a previous run:
+
+	cdsctl workflow run MYPROJECT my-workflow --data '{"git.branch":"master"}' --parameter version=1.0
+	cdsctl workflow run MYPROJECT my-workflow --node-name deploy --run-number 12 --wait`,
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "data",
+			Usage: "Payload of the run in JSON, default: the default payload of the node",
+			Kind:  reflect.String,
+			IsValid: func(s string) bool {
+				return s == "" || json.Valid([]byte(s))
+			},
+		},
+		{
+			Name:  "parameter",
+			Usage: "Pipeline parameters of the node, ie: name1=value1,name2=value2. The other parameters keep their default value",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "node-name",
+			Usage: "Run again this node of a previous run",
+			Kind:  reflect.String,
+		},
+		{
+			Name:  "run-number",
+			Usage: "Number of the run to run the node again, default: the last run",
+			Kind:  reflect.String,
+			IsValid: func(s string) bool {
+				return s == "" || isPositiveInt(s)
+			},
+		},
+		workflowRunWaitFlag,
+	},
+}
+
+func workflowRunRun(v cli.Values) error {
+	key, name := v["project-key"], v["workflow-name"]
+
+	var node *sdk.WorkflowNode
+	var number int64
+	if v["node-name"] != "" {
+		run, err := workflowRunGet(key, name, v["run-number"])
+		if err != nil {
+			return err
+		}
+		node = run.Workflow.GetNodeByName(v["node-name"])
+		if node == nil {
+			return fmt.Errorf("node %s not found in run #%d", v["node-name"], run.Number)
+		}
+		number = run.Number
+	} else {
+		w, err := client.WorkflowGet(key, name)
+		if err != nil {
+			return err
+		}
+		node = w.Root
+	}
+
+	manual := sdk.WorkflowNodeRunManual{}
+	if v["data"] != "" {
+		if err := json.Unmarshal([]byte(v["data"]), &manual.Payload); err != nil {
+			return fmt.Errorf("invalid data: %v", err)
+		}
+	}
+	if v["parameter"] != "" {
+		params, err := workflowRunParameters(node, v["parameter"])
+		if err != nil {
+			return err
+		}
+		manual.PipelineParameters = params
+	}
+
+	var fromNodeID int64
+	if number != 0 {
+		fromNodeID = node.ID
+	}
+	run, err := client.WorkflowRunFromManual(key, name, manual, number, fromNodeID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Workflow %s #%d has been started\n", name, run.Number)
+
+	if !v.GetBool("wait") {
+		return nil
+	}
+	return workflowRunWait(key, name, run.Number)
+}
+
+// workflowRunParameters returns the default pipeline parameters of the node overridden by the given parameters
+func workflowRunParameters(node *sdk.WorkflowNode, s string) ([]sdk.Parameter, error) {
+	var params []sdk.Parameter
+	if node.Context != nil {
+		params = append(params, node.Context.DefaultPipelineParameters...)
+	}
+	for _, p := range strings.Split(s, ",") {
+		t := strings.SplitN(p, "=", 2)
+		if len(t) != 2 {
+			return nil, fmt.Errorf("invalid parameter %s, it should be name=value", p)
+		}
+		sdk.AddParameter(&params, t[0], sdk.StringParameter, t[1])
+	}
+	return params, nil
+}
+
+var workflowStatusCmd = cli.Command{
+	Name:  "status",
+	Short: "Show the status of the nodes of a CDS workflow run",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "run-number", IsValid: isPositiveInt},
+	},
+	Flags: []cli.Flag{
+		workflowRunWaitFlag,
+	},
+}
+
+func workflowStatusRun(v cli.Values) error {
+	key, name := v["project-key"], v["workflow-name"]
+	run, err := workflowRunGet(key, name, v["run-number"])
+	if err != nil {
+		return err
+	}
+
+	if v.GetBool("wait") {
+		return workflowRunWait(key, name, run.Number)
+	}
+	printWorkflowRun(run)
+	return nil
+}
+
+// workflowRunGet returns the run given its number, the last run if number is empty
+func workflowRunGet(key, name, number string) (*sdk.WorkflowRun, error) {
+	if number == "" {
+		return client.WorkflowRunLatest(key, name)
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("run-number have to be an integer")
+	}
+	return client.WorkflowRun(key, name, n)
+}
+
+// workflowRunWait waits the end of a run and prints its nodes, it returns an error if the run failed
+func workflowRunWait(key, name string, number int64) error {
+	for {
+		run, err := client.WorkflowRun(key, name, number)
+		if err != nil {
+			return err
+		}
+		switch run.Status() {
+		case sdk.StatusWaiting.String(), sdk.StatusBuilding.String():
+			time.Sleep(workflowRunWaitDelay)
+			continue
+		case sdk.StatusSuccess.String():
+			printWorkflowRun(run)
+			return nil
+		}
+		printWorkflowRun(run)
+		return fmt.Errorf("workflow %s #%d failed", name, number)
+	}
+}
+
+func printWorkflowRun(run *sdk.WorkflowRun) {
+	fmt.Printf("Workflow %s #%d: %s\n", run.Workflow.Name, run.Number, run.Status())
+	if run.Workflow.Root == nil {
+		return
+	}
+	printWorkflowNodeRun(run, run.Workflow.Root, "", "")
+	for _, j := range run.Workflow.Joins {
+		var sources []string
+		for _, id := range j.SourceNodeIDs {
+			if n := run.Workflow.GetNode(id); n != nil {
+				sources = append(sources, n.Name)
+			}
+		}
+		fmt.Printf("join %s\n", strings.Join(sources, ", "))
+		for i := range j.Triggers {
+			last := i == len(j.Triggers)-1
+			printWorkflowNodeRun(run, &j.Triggers[i].WorkflowDestNode, treePrefix(last), treeIndent(last))
+		}
+	}
+}
+
+func printWorkflowNodeRun(run *sdk.WorkflowRun, n *sdk.WorkflowNode, prefix, indent string) {
+	status := "Not run"
+	if nr := run.LastNodeRun(n.ID); nr != nil {
+		status = fmt.Sprintf("#%d.%d %s", nr.Number, nr.SubNumber, nr.Status)
+	}
+	fmt.Printf("%s%s [%s]\n", prefix, n.Name, status)
+	for i := range n.Triggers {
+		last := i == len(n.Triggers)-1
+		printWorkflowNodeRun(run, &n.Triggers[i].WorkflowDestNode, indent+treePrefix(last), indent+treeIndent(last))
+	}
+}
+
+func treePrefix(last bool) string {
+	if last {
+		return "└── "
+	}
+	return "├── "
+}
+
+func treeIndent(last bool) string {
+	if last {
+		return "    "
+	}
+	return "│   "
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var (
+	workflowTestsCmd = cli.Command{
+		Name:  "tests",
+		Short: "Show tests trends of a Workflow",
+	}
+
+	workflowTests = cli.NewCommand(workflowTestsCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(workflowTestsListCmd, workflowTestsListRun, nil),
+			cli.NewListCommand(workflowTestsHistoryCmd, workflowTestsHistoryRun, nil),
+		})
+)
+
+var workflowTestsRunsFlag = cli.Flag{
+	Name:    "runs",
+	Usage:   "Number of workflow runs to analyze",
+	Default: "50",
+	Kind:    reflect.String,
+	IsValid: func(s string) bool {
+		i, err := strconv.Atoi(s)
+		return err == nil && i > 0
+	},
+}
+
+var workflowTestsListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the failure rate, average duration and flakiness of the tests of a Workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+	},
+	Flags: []cli.Flag{
+		workflowTestsRunsFlag,
+		{
+			Name:    "flaky",
+			Usage:   "List only the flaky tests, which both succeeded and failed on a same commit",
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+	},
+}
+
+func workflowTestsListRun(v cli.Values) (cli.ListResult, error) {
+	runs, err := strconv.Atoi(v["runs"])
+	if err != nil {
+		return nil, fmt.Errorf("runs flag have to be an integer")
+	}
+	stats, err := client.WorkflowTests(v["project-key"], v["workflow"], runs, v.GetBool("flaky"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(stats), nil
+}
+
+var workflowTestsHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "List the results of a test case on the last runs of a Workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+		{Name: "suite"},
+		{Name: "case"},
+	},
+	Flags: []cli.Flag{
+		workflowTestsRunsFlag,
+	},
+}
+
+func workflowTestsHistoryRun(v cli.Values) (cli.ListResult, error) {
+	runs, err := strconv.Atoi(v["runs"])
+	if err != nil {
+		return nil, fmt.Errorf("runs flag have to be an integer")
+	}
+	stats, err := client.WorkflowTestHistory(v["project-key"], v["workflow"], runs, v["suite"], v["case"])
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(stats.History), nil
+}
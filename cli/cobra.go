@@ -89,6 +89,7 @@ func newCommand(c Command, run interface{}, subCommands []*cobra.Command, mods .
 
 	definedArgs := append(c.Args, c.OptionalArgs...)
 	sort.Sort(orderArgs(definedArgs...))
+	commandArgs[cmd] = argsDefinition{args: definedArgs, mandatory: len(c.Args)}
 
 	cmd.Short = c.Short
 	cmd.Long = c.Long
@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// CompletionFunc returns the values which can complete an argument, the values of the previous
+// arguments of the command are given by name
+type CompletionFunc func(Values) ([]string, error)
+
+// ArgCompletions are the functions completing the arguments of the commands, by name of argument
+var ArgCompletions = map[string]CompletionFunc{}
+
+// argsDefinition are the arguments of a command in the order of the command line, the mandatory
+// arguments first
+type argsDefinition struct {
+	args      []Arg
+	mandatory int
+}
+
+// commandArgs are the arguments of the cobra commands built by newCommand
+var commandArgs = map[*cobra.Command]argsDefinition{}
+
+// completeCmdName is the hidden command called by the completion scripts
+const completeCmdName = "__complete"
+
+// Complete returns the completions of the last word of a command line, the words are given
+// without the name of the root command
+func Complete(root *cobra.Command, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	cmd, args, expectValue := findCommand(root, words[:len(words)-1])
+
+	// The current word is the value of a flag
+	if expectValue {
+		return nil
+	}
+
+	res := []string{}
+	if strings.HasPrefix(cur, "-") {
+		for _, f := range commandFlags(cmd) {
+			res = append(res, "--"+f.Name)
+		}
+		return filterPrefix(res, cur)
+	}
+
+	if len(args) == 0 && cmd.HasAvailableSubCommands() {
+		for _, c := range cmd.Commands() {
+			if c.IsAvailableCommand() {
+				res = append(res, c.Name())
+			}
+		}
+		sort.Strings(res)
+		return filterPrefix(res, cur)
+	}
+
+	defined := commandArgs[cmd].args
+	if len(defined) == 0 && len(args) == 0 {
+		return filterPrefix(cmd.ValidArgs, cur)
+	}
+	if len(args) >= len(defined) {
+		return nil
+	}
+	f, has := ArgCompletions[defined[len(args)].Name]
+	if !has {
+		return nil
+	}
+	vals := Values{}
+	for i := range args {
+		vals[defined[i].Name] = args[i]
+	}
+	setFlags(cmd, words[:len(words)-1])
+	res, err := f(vals)
+	if err != nil {
+		return nil
+	}
+	return filterPrefix(res, cur)
+}
+
+// findCommand returns the command called by a command line, its arguments, and whether the command
+// line ends with a flag waiting for a value
+func findCommand(root *cobra.Command, words []string) (*cobra.Command, []string, bool) {
+	cmd := root
+	args := []string{}
+	var expectValue bool
+	for _, w := range words {
+		if expectValue {
+			expectValue = false
+			continue
+		}
+		if strings.HasPrefix(w, "-") {
+			if strings.Contains(w, "=") {
+				continue
+			}
+			name := strings.TrimLeft(w, "-")
+			for _, f := range commandFlags(cmd) {
+				if (f.Name == name || f.Shorthand == name) && f.Value.Type() != "bool" {
+					expectValue = true
+				}
+			}
+			continue
+		}
+		if len(args) == 0 {
+			if sub := findSubCommand(cmd, w); sub != nil {
+				cmd = sub
+				continue
+			}
+		}
+		args = append(args, w)
+	}
+	return cmd, args, expectValue
+}
+
+// setFlags sets the values of the flags of a command line, the flags are not parsed by the hidden
+// command but the completion functions need them, e.g. the configuration file to query the API
+func setFlags(cmd *cobra.Command, words []string) {
+	flags := commandFlags(cmd)
+	for i := 0; i < len(words); i++ {
+		if !strings.HasPrefix(words[i], "-") {
+			continue
+		}
+		name := strings.TrimLeft(words[i], "-")
+		var value string
+		hasValue := false
+		if j := strings.Index(name, "="); j >= 0 {
+			name, value, hasValue = name[:j], name[j+1:], true
+		}
+		for _, f := range flags {
+			if f.Name != name && f.Shorthand != name {
+				continue
+			}
+			if !hasValue && f.Value.Type() != "bool" && i+1 < len(words) {
+				i++
+				value, hasValue = words[i], true
+			}
+			if !hasValue {
+				value = "true"
+			}
+			f.Value.Set(value)
+		}
+	}
+}
+
+func findSubCommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// commandFlags returns the flags of a command and the persistent flags of its parents
+func commandFlags(cmd *cobra.Command) []*pflag.Flag {
+	res := []*pflag.Flag{}
+	seen := map[string]bool{}
+	add := func(f *pflag.Flag) {
+		if !seen[f.Name] && !f.Hidden {
+			seen[f.Name] = true
+			res = append(res, f)
+		}
+	}
+	cmd.Flags().VisitAll(add)
+	cmd.PersistentFlags().VisitAll(add)
+	for p := cmd.Parent(); p != nil; p = p.Parent() {
+		p.PersistentFlags().VisitAll(add)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	res := []string{}
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+const bashCompletion = `# bash completion for {{name}}
+_{{name}}() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=( $(compgen -W "$({{name}} {{complete}} "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null)" -- "$cur") )
+}
+complete -o default -F _{{name}} {{name}}
+`
+
+const zshCompletion = `#compdef {{name}}
+_{{name}}() {
+    local -a completions
+    completions=("${(@f)$({{name}} {{complete}} "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    compadd -- ${completions[@]}
+}
+compdef _{{name}} {{name}}
+`
+
+const fishCompletion = `# fish completion for {{name}}
+function __{{name}}_complete
+    set -l args (commandline -opc)
+    set -e args[1]
+    {{name}} {{complete}} $args (commandline -ct) 2>/dev/null
+end
+complete -c {{name}} -f -a '(__{{name}}_complete)'
+`
+
+// CompletionScript writes the completion script of a root command for a shell: bash, zsh or fish
+func CompletionScript(w io.Writer, root *cobra.Command, shell string) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return fmt.Errorf("unsupported shell %s", shell)
+	}
+	script = strings.Replace(script, "{{name}}", root.Name(), -1)
+	script = strings.Replace(script, "{{complete}}", completeCmdName, -1)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// NewCompletionCommands returns the command printing the completion scripts of a root command, and
+// the hidden command called by the scripts to complete a command line
+func NewCompletionCommands(root *cobra.Command) (*cobra.Command, *cobra.Command) {
+	completion := &cobra.Command{
+		Use:   "completion SHELL",
+		Short: "Print the completion script of bash, zsh or fish",
+		Long: fmt.Sprintf(`Print the completion script of bash, zsh or fish. The names of the projects, applications, pipelines,
+environments and workflows are completed by querying the API.
+
+	# bash
+	source <(%[1]s completion bash)
+	# zsh
+	source <(%[1]s completion zsh)
+	# fish
+	%[1]s completion fish | source`, root.Name()),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				ExitOnError(ErrWrongUsage, cmd.Help)
+			}
+			ExitOnError(CompletionScript(os.Stdout, root, args[0]))
+		},
+	}
+
+	complete := &cobra.Command{
+		Use:    completeCmdName,
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, s := range Complete(root, args) {
+				fmt.Println(s)
+			}
+		},
+	}
+	// The flags of the command line to complete are arguments of the hidden command
+	complete.DisableFlagParsing = true
+	return completion, complete
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplete(t *testing.T) {
+	run := func(Values) error { return nil }
+	show := NewCommand(Command{
+		Name:  "show",
+		Args:  []Arg{{Name: "project-key"}, {Name: "workflow-name"}},
+		Flags: []Flag{{Name: "format", Kind: reflect.String}, {Name: "verbose", Kind: reflect.Bool}},
+	}, run, nil)
+	workflow := NewCommand(Command{Name: "workflow", Aliases: []string{"w"}}, nil, []*cobra.Command{show})
+	root := NewCommand(Command{Name: "cdsctl"}, nil, []*cobra.Command{workflow, NewCommand(Command{Name: "version"}, run, nil)})
+	file := root.PersistentFlags().StringP("file", "f", "", "")
+
+	ArgCompletions["project-key"] = func(Values) ([]string, error) { return []string{"FOO", "BAR"}, nil }
+	ArgCompletions["workflow-name"] = func(v Values) ([]string, error) {
+		return []string{v["project-key"] + "-build", v["project-key"] + "-deploy"}, nil
+	}
+	defer delete(ArgCompletions, "project-key")
+	defer delete(ArgCompletions, "workflow-name")
+
+	assert.Equal(t, []string{"version", "workflow"}, Complete(root, nil))
+	assert.Equal(t, []string{"workflow"}, Complete(root, []string{"w"}))
+	assert.Equal(t, []string{"show"}, Complete(root, []string{"w", ""}))
+	assert.Equal(t, []string{"FOO", "BAR"}, Complete(root, []string{"workflow", "show", ""}))
+	assert.Equal(t, []string{"FOO-build", "FOO-deploy"}, Complete(root, []string{"workflow", "show", "FOO", ""}))
+	assert.Equal(t, []string{"FOO-deploy"}, Complete(root, []string{"workflow", "show", "--verbose", "FOO", "FOO-d"}))
+	assert.Equal(t, []string{"FOO", "BAR"}, Complete(root, []string{"-f", "cdsrc", "workflow", "show", "--format", "json", ""}))
+	assert.Equal(t, "cdsrc", *file)
+	assert.Equal(t, []string{"FOO", "BAR"}, Complete(root, []string{"workflow", "show", "--file=.cdsrc", ""}))
+	assert.Equal(t, ".cdsrc", *file)
+	assert.Empty(t, Complete(root, []string{"workflow", "show", "--format", ""}))
+	assert.Empty(t, Complete(root, []string{"workflow", "show", "FOO", "FOO-build", ""}))
+	assert.Equal(t, []string{"--file", "--format"}, Complete(root, []string{"workflow", "show", "--f"}))
+}
+
+func TestCompletionScript(t *testing.T) {
+	root := NewCommand(Command{Name: "cdsctl"}, nil, nil)
+	for _, s := range []string{"bash", "zsh", "fish"} {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, CompletionScript(buf, root, s))
+		assert.Contains(t, buf.String(), "cdsctl __complete")
+	}
+	assert.Error(t, CompletionScript(new(bytes.Buffer), root, "powershell"))
+}
+
+func TestSplitLine(t *testing.T) {
+	words, err := splitLine(`workflow  run KEY "my workflow" --data '{"a": "b"}' ""`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"workflow", "run", "KEY", "my workflow", "--data", `{"a": "b"}`, ""}, words)
+
+	_, err = splitLine(`workflow run "KEY`)
+	assert.Error(t, err)
+}
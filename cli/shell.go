@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	ui "github.com/gizak/termui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// NewShellCommand returns the command running the interactive mode of a root command: a prompt
+// running the command lines with the binary itself. The missing subcommands and arguments are
+// selected in a list when they can be completed, or asked on the standard input
+func NewShellCommand(root *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Run the interactive mode",
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitOnError(runShell(root))
+		},
+	}
+}
+
+func runShell(root *cobra.Command) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Interactive mode of %s, type help to list the commands and exit to quit\n", root.Name())
+	for {
+		fmt.Printf("%s> ", root.Name())
+		words, err := splitLine(ReadLine())
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if words[0] == "exit" || words[0] == "quit" {
+			return nil
+		}
+
+		words, ok, err := askMissing(root, words)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		c := exec.Command(exe, append(globalFlags(root), words...)...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			// The commands print their own errors
+			if _, ok := err.(*exec.ExitError); !ok {
+				return err
+			}
+		}
+	}
+}
+
+// globalFlags returns the persistent flags of the root command set on the command line of the
+// interactive mode, so that they're given to the commands it runs
+func globalFlags(root *cobra.Command) []string {
+	res := []string{}
+	root.PersistentFlags().Visit(func(f *pflag.Flag) {
+		res = append(res, "--"+f.Name+"="+f.Value.String())
+	})
+	return res
+}
+
+// askMissing completes a command line with the subcommand and the mandatory arguments it lacks. It
+// returns false if the user cancels a selection
+func askMissing(root *cobra.Command, words []string) ([]string, bool, error) {
+	for _, w := range words {
+		if w == "--help" || w == "-h" {
+			return words, true, nil
+		}
+	}
+
+	for {
+		cmd, args, expectValue := findCommand(root, words)
+		if expectValue {
+			return words, true, nil
+		}
+		completions := Complete(root, append(append([]string{}, words...), ""))
+
+		var name string
+		switch {
+		case len(args) == 0 && !cmd.Runnable() && cmd.HasAvailableSubCommands():
+			name = cmd.CommandPath()
+		case len(args) < commandArgs[cmd].mandatory:
+			name = strings.ToUpper(commandArgs[cmd].args[len(args)].Name)
+			if len(completions) == 0 {
+				fmt.Printf("%s: ", name)
+				words = append(words, ReadLine())
+				continue
+			}
+		default:
+			return words, true, nil
+		}
+
+		v, ok, err := SelectItem(name, completions)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		words = append(words, v)
+	}
+}
+
+// SelectItem displays items in a scrollable list and returns the one selected with <enter>. It
+// returns false if the selection is cancelled with q or <escape>
+func SelectItem(title string, items []string) (string, bool, error) {
+	if len(items) == 0 {
+		return "", false, nil
+	}
+	if err := ui.Init(); err != nil {
+		return "", false, err
+	}
+	defer ui.Close()
+	ui.DefaultEvtStream.ResetHandlers()
+
+	l := NewScrollableList()
+	l.BorderLabel = " " + title + " (enter to select, q to cancel) "
+	l.Items = items
+	l.ItemFgColor = ui.ColorWhite
+	l.ItemBgColor = ui.ColorBlack
+	l.Width = ui.TermWidth()
+	l.Height = min(len(items)+2, ui.TermHeight())
+
+	var selected bool
+	ui.Handle("/sys/kbd/<up>", func(ui.Event) { l.CursorUp() })
+	ui.Handle("/sys/kbd/<down>", func(ui.Event) { l.CursorDown() })
+	ui.Handle("/sys/kbd/<previous>", func(ui.Event) { l.PageUp() })
+	ui.Handle("/sys/kbd/<next>", func(ui.Event) { l.PageDown() })
+	ui.Handle("/sys/kbd/<enter>", func(ui.Event) {
+		selected = true
+		ui.StopLoop()
+	})
+	for _, k := range []string{"q", "<escape>", "C-c"} {
+		ui.Handle("/sys/kbd/"+k, func(ui.Event) { ui.StopLoop() })
+	}
+
+	l.render()
+	ui.Loop()
+	if !selected {
+		return "", false, nil
+	}
+	return items[l.Cursor], true, nil
+}
+
+// splitLine splits a command line in words, the words can be quoted with simple or double quotes
+func splitLine(line string) ([]string, error) {
+	words := []string{}
+	var word []rune
+	var quote rune
+	var inWord bool
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word = append(word, r)
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, string(word))
+				word = nil
+				inWord = false
+			}
+		default:
+			word = append(word, r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %c", quote)
+	}
+	if inWord {
+		words = append(words, string(word))
+	}
+	return words, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/template"
+)
+
+type TemplateBuildTestDeployWorkflow struct {
+	template.Common
+}
+
+func (t *TemplateBuildTestDeployWorkflow) Name() string {
+	return "cds-template-build-test-deploy-workflow"
+}
+
+func (t *TemplateBuildTestDeployWorkflow) Description() string {
+	return `
+This template creates a workflow:
+
+- an application, named as the workflow, with the repository variable
+- a build pipeline: git clone and make build, artifact upload
+- a test pipeline: git clone and make test, junit report
+- a deploy pipeline: artifact download and an empty deploy script
+- a Production environment
+
+The workflow runs build, then test, then deploy on Production.
+`
+}
+
+func (t *TemplateBuildTestDeployWorkflow) Identifier() string {
+	return "github.com/ovh/cds/contrib/templates/cds-template-build-test-deploy-workflow/TemplateBuildTestDeployWorkflow"
+}
+
+func (t *TemplateBuildTestDeployWorkflow) Author() string {
+	return "CDS Team <cds@ovh.net>"
+}
+
+func (t *TemplateBuildTestDeployWorkflow) Type() string {
+	return "WORKFLOW"
+}
+
+func (t *TemplateBuildTestDeployWorkflow) Parameters() []sdk.TemplateParam {
+	return []sdk.TemplateParam{
+		{
+			Name:        "repo",
+			Type:        sdk.RepositoryVariable,
+			Value:       "",
+			Description: "Your source code repository",
+		},
+	}
+}
+
+func (t *TemplateBuildTestDeployWorkflow) ActionsNeeded() []string {
+	return []string{
+		sdk.GitCloneAction,
+	}
+}
+
+// Apply is not supported: this template only generates workflows
+func (t *TemplateBuildTestDeployWorkflow) Apply(opts template.IApplyOptions) (sdk.Application, error) {
+	return sdk.Application{}, sdk.ErrNotImplemented
+}
+
+func (t *TemplateBuildTestDeployWorkflow) ApplyWorkflow(opts template.IApplyOptions) (sdk.TemplateWorkflow, error) {
+	name := opts.WorkflowName()
+	makeRequirement := []sdk.Requirement{
+		{
+			Name:  "make",
+			Type:  sdk.BinaryRequirement,
+			Value: "make",
+		},
+	}
+
+	build := sdk.Pipeline{
+		Name: name + "-build",
+		Type: sdk.BuildPipeline,
+		Stages: []sdk.Stage{
+			{
+				Name:       "Build",
+				BuildOrder: 0,
+				Enabled:    true,
+				Jobs: []sdk.Job{
+					{
+						Enabled: true,
+						Action: sdk.Action{
+							Name: "Compile",
+							Actions: []sdk.Action{
+								sdk.Action{
+									Name: sdk.GitCloneAction,
+								},
+								sdk.NewActionScript("make build", makeRequirement),
+								sdk.NewActionArtifactUpload("{{.cds.app.name}}", "{{.cds.version}}"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	test := sdk.Pipeline{
+		Name: name + "-test",
+		Type: sdk.TestingPipeline,
+		Stages: []sdk.Stage{
+			{
+				Name:       "Test",
+				BuildOrder: 0,
+				Enabled:    true,
+				Jobs: []sdk.Job{
+					{
+						Enabled: true,
+						Action: sdk.Action{
+							Name: "Unit tests",
+							Actions: []sdk.Action{
+								sdk.Action{
+									Name: sdk.GitCloneAction,
+								},
+								sdk.NewActionScript("make test", makeRequirement),
+								sdk.NewActionJUnit("*.xml"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	deploy := sdk.Pipeline{
+		Name: name + "-deploy",
+		Type: sdk.DeploymentPipeline,
+		Stages: []sdk.Stage{
+			{
+				Name:       "Deploy",
+				BuildOrder: 0,
+				Enabled:    true,
+				Jobs: []sdk.Job{
+					{
+						Enabled: true,
+						Action: sdk.Action{
+							Name: "Deploy",
+							Actions: []sdk.Action{
+								sdk.NewActionArtifactDownload(".", "{{.cds.version}}"),
+								sdk.NewActionScript(`#!/bin/bash
+
+set -xe
+
+echo "TODO: deploy {{.cds.app.name}} on {{.cds.environment}}"`, nil),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	app := &sdk.Application{Name: name}
+	prod := &sdk.Environment{Name: "Production"}
+
+	return sdk.TemplateWorkflow{
+		Pipelines: []sdk.Pipeline{build, test, deploy},
+		Applications: []sdk.Application{
+			{
+				Name: name,
+				Variable: []sdk.Variable{
+					{Name: "repo", Value: opts.Parameters().Get("repo"), Type: sdk.StringVariable},
+				},
+			},
+		},
+		Environments: []sdk.Environment{*prod},
+		Workflow: sdk.Workflow{
+			Name: name,
+			Root: &sdk.WorkflowNode{
+				Pipeline: sdk.Pipeline{Name: build.Name},
+				Context:  &sdk.WorkflowNodeContext{Application: app},
+				Triggers: []sdk.WorkflowNodeTrigger{
+					{
+						WorkflowDestNode: sdk.WorkflowNode{
+							Pipeline: sdk.Pipeline{Name: test.Name},
+							Context:  &sdk.WorkflowNodeContext{Application: app},
+							Triggers: []sdk.WorkflowNodeTrigger{
+								{
+									WorkflowDestNode: sdk.WorkflowNode{
+										Pipeline: sdk.Pipeline{Name: deploy.Name},
+										Context: &sdk.WorkflowNodeContext{
+											Application: app,
+											Environment: prod,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func main() {
+	template.Main(&TemplateBuildTestDeployWorkflow{})
+}
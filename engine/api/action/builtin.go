@@ -182,6 +182,119 @@ Tag the current branch and push it.`
 		return err
 	}
 
+	// ----------------------------------- Sign -----------------------
+	sign := sdk.NewAction(sdk.SignAction)
+	sign.Type = sdk.BuiltinAction
+	sign.Description = `CDS Builtin Action.
+Sign files with a PGP key and upload the detached signatures as artifacts.`
+
+	sign.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: "Path of files to sign, example: ./dist/*.tar.gz",
+		Type:        sdk.StringParameter,
+	})
+	sign.Parameter(sdk.Parameter{
+		Name:        "signKey",
+		Value:       "",
+		Description: "Set the PGP key used to sign the files",
+		Type:        sdk.KeyParameter,
+	})
+	sign.Parameter(sdk.Parameter{
+		Name:        "tag",
+		Description: "Signatures will be uploaded with a tag, generally {{.cds.version}}",
+		Value:       "{{.cds.version}}",
+		Type:        sdk.StringParameter,
+	})
+	if err := checkBuiltinAction(db, sign); err != nil {
+		return err
+	}
+
+	// ----------------------------------- HTTP Request -----------------------
+	httpRequest := sdk.NewAction(sdk.HTTPRequestAction)
+	httpRequest.Type = sdk.BuiltinAction
+	httpRequest.Description = `CDS Builtin Action.
+Send an HTTP request, retry it until the expected status is returned and extract values of the JSON response into build variables.`
+
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "method",
+		Description: "HTTP method: GET, POST, PUT, PATCH, DELETE...",
+		Value:       "GET",
+		Type:        sdk.StringParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "url",
+		Description: "URL of the request",
+		Type:        sdk.StringParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "headers",
+		Description: "Headers of the request, one per line, example: Content-Type: application/json",
+		Type:        sdk.TextParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "body",
+		Description: "Body of the request",
+		Type:        sdk.TextParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "basicAuthUser",
+		Description: "User for basic authentication",
+		Type:        sdk.StringParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "basicAuthPassword",
+		Description: "Password for basic authentication, use a secret variable, example: {{.cds.app.password}}",
+		Type:        sdk.StringParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "bearerToken",
+		Description: "Token sent in the Authorization header, use a secret variable, example: {{.cds.proj.token}}",
+		Type:        sdk.StringParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "insecureSkipVerify",
+		Description: "Skip the verification of the server certificate",
+		Value:       "false",
+		Type:        sdk.BooleanParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "caCertificate",
+		Description: "PEM encoded CA certificate used to verify the server certificate",
+		Type:        sdk.TextParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "timeout",
+		Description: "Timeout of each attempt, in seconds",
+		Value:       "30",
+		Type:        sdk.NumberParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "retry",
+		Description: "Number of retries on error or unexpected status",
+		Value:       "0",
+		Type:        sdk.NumberParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "retryDelay",
+		Description: "Delay before the first retry, in seconds. The delay is doubled on each retry",
+		Value:       "1",
+		Type:        sdk.NumberParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "expectedStatus",
+		Description: "Expected status codes and ranges, example: 200,201,300-399",
+		Value:       "200-299",
+		Type:        sdk.StringParameter,
+	})
+	httpRequest.Parameter(sdk.Parameter{
+		Name:        "extract",
+		Description: "Values of the JSON response to set as build variables, one per line, example: deployID=data.deployments[0].id",
+		Type:        sdk.TextParameter,
+	})
+	if err := checkBuiltinAction(db, httpRequest); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -303,6 +416,10 @@ func createBuiltinArtifactDownloadAction(db *gorp.DbMap) error {
 		Name:        "application",
 		Description: "Application from where artifacts will be downloaded, generally {{.cds.application}}",
 		Type:        sdk.StringParameter})
+	dl.Parameter(sdk.Parameter{
+		Name:        "verifyKey",
+		Description: "If set, check the detached signatures of downloaded artifacts with this PGP key",
+		Type:        sdk.KeyParameter})
 	dl.Parameter(sdk.Parameter{
 		Name:        "enabled",
 		Type:        sdk.BooleanParameter,
@@ -12,6 +12,8 @@ import (
 
 	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/plugin"
 )
@@ -20,13 +22,16 @@ import (
 func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 	//FIXME: run this in a jail with apparmor
 	log.Debug("actionplugin.Get> Getting info from '%s' (%s)", name, path)
-	client := plugin.NewClient(context.Background(), name, path, "ID", "http://127.0.0.1:8081", true)
+	p, err := grpcplugin.Start(context.Background(), path)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
+	}
 	defer func() {
-		log.Debug("actionplugin.Get> kill rpc-server")
-		client.Kill()
+		log.Debug("actionplugin.Get> kill plugin")
+		p.Kill()
 	}()
-	log.Debug("actionplugin.Get> Client '%s'", name)
-	_plugin, err := client.Instance()
+	log.Debug("actionplugin.Get> Plugin '%s' speaks %s version %d", name, p.Protocol, p.Version)
+	manifest, err := actionplugin.Manifest(context.Background(), p, name)
 	if err != nil {
 		return nil, nil, sdk.WrapError(err, "actionplugin.Get> ")
 	}
@@ -51,16 +56,16 @@ func Get(name, path string) (*sdk.ActionPlugin, *plugin.Parameters, error) {
 
 	ap := sdk.ActionPlugin{
 		Filename:    name,
-		Name:        _plugin.Name(),
-		Author:      _plugin.Author(),
-		Description: _plugin.Description(),
+		Name:        manifest.Name,
+		Author:      manifest.Author,
+		Description: manifest.Description,
 		Path:        path,
 		Size:        stat.Size(),
 		Perm:        uint32(stat.Mode().Perm()),
 		MD5sum:      md5sumStr,
 	}
 
-	params := _plugin.Parameters()
+	params := manifest.PluginParameters()
 
 	return &ap, &params, nil
 }
@@ -25,6 +25,7 @@ func (api *API) InitRouter() {
 	r.Handle("/action/import", r.POST(api.importActionHandler, NeedAdmin(true)))
 	r.Handle("/action/requirement", r.GET(api.getActionsRequirements, Auth(false)))
 	r.Handle("/action/{permActionName}", r.GET(api.getActionHandler), r.POST(api.addActionHandler), r.PUT(api.updateActionHandler), r.DELETE(api.deleteActionHandler))
+	r.Handle("/action/{permActionName}/impact", r.GET(api.getActionImpactHandler))
 	r.Handle("/action/{actionName}/using", r.GET(api.getPipelinesUsingActionHandler, NeedAdmin(true)))
 	r.Handle("/action/{actionID}/audit", r.GET(api.getActionAuditHandler, NeedAdmin(true)))
 
@@ -32,6 +33,13 @@ func (api *API) InitRouter() {
 	r.Handle("/admin/warning", r.DELETE(api.adminTruncateWarningsHandler, NeedAdmin(true)))
 	r.Handle("/admin/maintenance", r.POST(api.postAdminMaintenanceHandler, NeedAdmin(true)), r.GET(api.getAdminMaintenanceHandler, NeedAdmin(true)), r.DELETE(api.deleteAdminMaintenanceHandler, NeedAdmin(true)))
 
+	// Audit
+	r.Handle("/audit", r.GET(api.getAuditsHandler))
+	r.Handle("/audit/{id}", r.GET(api.getAuditHandler))
+
+	// Search
+	r.Handle("/search", r.GET(api.getSearchHandler))
+
 	// Action plugin
 	r.Handle("/plugin", r.POST(api.addPluginHandler, NeedAdmin(true)), r.PUT(api.updatePluginHandler, NeedAdmin(true)))
 	r.Handle("/plugin/{name}", r.DELETE(api.deletePluginHandler, NeedAdmin(true)))
@@ -62,10 +70,16 @@ func (api *API) InitRouter() {
 	r.Handle("/group/{permGroupName}/user/{user}", r.DELETE(api.removeUserFromGroupHandler))
 	r.Handle("/group/{permGroupName}/user/{user}/admin", r.POST(api.setUserGroupAdminHandler), r.DELETE(api.removeUserGroupAdminHandler))
 	r.Handle("/group/{permGroupName}/token/{expiration}", r.POST(api.generateTokenHandler))
+	r.Handle("/group/{permGroupName}/quota", r.GET(api.getGroupQuotaHandler), r.PUT(api.putGroupQuotaHandler, NeedAdmin(true)), r.DELETE(api.deleteGroupQuotaHandler, NeedAdmin(true)))
+	r.Handle("/group/{permGroupName}/variableset", r.GET(api.getVariableSetsInGroupHandler), r.POST(api.postVariableSetInGroupHandler))
+	r.Handle("/group/{permGroupName}/variableset/{name}", r.GET(api.getVariableSetInGroupHandler), r.PUT(api.putVariableSetInGroupHandler), r.DELETE(api.deleteVariableSetInGroupHandler))
+	r.Handle("/group/{permGroupName}/variableset/{name}/audit", r.GET(api.getVariableSetAuditInGroupHandler))
+	r.Handle("/group/{permGroupName}/variableset/{name}/variable/{varName}", r.POST(api.postVariableInVariableSetHandler), r.PUT(api.putVariableInVariableSetHandler), r.DELETE(api.deleteVariableInVariableSetHandler))
 
 	// Hatchery
 	r.Handle("/hatchery", r.POST(api.registerHatcheryHandler, Auth(false)))
 	r.Handle("/hatchery/{id}", r.PUT(api.refreshHatcheryHandler))
+	r.Handle("/hatchery/{name}/maintenance", r.POST(api.postHatcheryMaintenanceHandler, NeedAdmin(true)), r.DELETE(api.deleteHatcheryMaintenanceHandler, NeedAdmin(true)))
 
 	// Hooks
 	r.Handle("/hook", r.POST(api.receiveHookHandler, Auth(false) /* Public handler called by third parties */))
@@ -90,8 +104,14 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/variable/audit", r.GET(api.getVariablesAuditInProjectnHandler))
 	r.Handle("/project/{key}/variable/audit/{auditID}", r.PUT(api.restoreProjectVariableAuditHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/variable/{name}", r.GET(api.getVariableInProjectHandler, DEPRECATED), r.POST(api.addVariableInProjectHandler), r.PUT(api.updateVariableInProjectHandler), r.DELETE(api.deleteVariableFromProjectHandler))
+	r.Handle("/project/{permProjectKey}/variable/{name}/impact", r.GET(api.getVariableImpactInProjectHandler))
 	r.Handle("/project/{permProjectKey}/variable/{name}/audit", r.GET(api.getVariableAuditInProjectHandler))
+	r.Handle("/project/{permProjectKey}/variableset", r.GET(api.getVariableSetsInProjectHandler))
+	r.Handle("/project/{permProjectKey}/variableset/{name}", r.POST(api.postVariableSetInProjectHandler), r.DELETE(api.deleteVariableSetInProjectHandler))
 	r.Handle("/project/{permProjectKey}/applications", r.GET(api.getApplicationsHandler), r.POST(api.addApplicationHandler))
+	r.Handle("/project/{permProjectKey}/encrypt", r.POST(api.postEncryptVariableInProjectHandler))
+	r.Handle("/project/{permProjectKey}/export", r.GET(api.getProjectExportHandler))
+	r.Handle("/project/{permProjectKey}/import", r.POST(api.postProjectImportHandler))
 	r.Handle("/project/{permProjectKey}/notifications", r.GET(api.getProjectNotificationsHandler))
 	r.Handle("/project/{permProjectKey}/keys", r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler))
 	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler))
@@ -120,7 +140,10 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/application/{permApplicationName}/variable/audit", r.GET(api.getVariablesAuditInApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/variable/audit/{auditID}", r.PUT(api.restoreAuditHandler, DEPRECATED))
 	r.Handle("/project/{key}/application/{permApplicationName}/variable/{name}", r.GET(api.getVariableInApplicationHandler), r.POST(api.addVariableInApplicationHandler), r.PUT(api.updateVariableInApplicationHandler), r.DELETE(api.deleteVariableFromApplicationHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/variable/{name}/impact", r.GET(api.getVariableImpactInApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/variable/{name}/audit", r.GET(api.getVariableAuditInApplicationHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/variableset", r.GET(api.getVariableSetsInApplicationHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/variableset/{name}", r.POST(api.postVariableSetInApplicationHandler), r.DELETE(api.deleteVariableSetInApplicationHandler))
 
 	// Pipeline
 	r.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/history", r.GET(api.getPipelineHistoryHandler))
@@ -169,6 +192,8 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}", r.GET(api.getWorkflowNodeRunJobStepHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/artifacts", r.GET(api.getWorkflowNodeRunArtifactsHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/artifact/{artifactId}", r.GET(api.getDownloadArtifactHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests", r.GET(api.getWorkflowTestsHandler))
+	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/tests/history", r.GET(api.getWorkflowTestHistoryHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/node/{nodeID}/triggers/condition", r.GET(api.getWorkflowTriggerConditionHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/join/{joinID}/triggers/condition", r.GET(api.getWorkflowTriggerJoinConditionHandler))
 	r.Handle("/project/{permProjectKey}/workflows/{workflowName}/runs/{number}/nodes/{nodeRunID}/release", r.POST(api.releaseApplicationWorkflowHandler))
@@ -190,6 +215,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/environment/import", r.POST(api.importNewEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/import/{permEnvironmentName}", r.POST(api.importIntoEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}", r.GET(api.getEnvironmentHandler), r.PUT(api.updateEnvironmentHandler), r.DELETE(api.deleteEnvironmentHandler))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/impact", r.GET(api.getEnvironmentImpactHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys", r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/keys/{name}", r.DELETE(api.deleteKeyInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/clone/{cloneName}", r.POST(api.cloneEnvironmentHandler))
@@ -200,6 +226,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/group/{group}", r.PUT(api.updateGroupRoleOnEnvironmentHandler), r.DELETE(api.deleteGroupFromEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable", r.GET(api.getVariablesInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}", r.GET(api.getVariableInEnvironmentHandler), r.POST(api.addVariableInEnvironmentHandler), r.PUT(api.updateVariableInEnvironmentHandler), r.DELETE(api.deleteVariableFromEnvironmentHandler))
+	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}/impact", r.GET(api.getVariableImpactInEnvironmentHandler))
 	r.Handle("/project/{key}/environment/{permEnvironmentName}/variable/{name}/audit", r.GET(api.getVariableAuditInEnvironmentHandler))
 
 	// Artifacts
@@ -277,6 +304,8 @@ func (api *API) InitRouter() {
 	r.Handle("/template/deploy", r.GET(api.getDeployTemplatesHandler, Auth(false)))
 	r.Handle("/template/{id}", r.PUT(api.updateTemplateHandler, NeedAdmin(true)), r.DELETE(api.deleteTemplateHandler, NeedAdmin(true)))
 	r.Handle("/project/{permProjectKey}/template", r.POST(api.applyTemplateHandler))
+	r.Handle("/project/{permProjectKey}/template/workflow", r.POST(api.applyWorkflowTemplateHandler))
+	r.Handle("/project/{permProjectKey}/template/workflow/preview", r.POST(api.previewWorkflowTemplateHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/template", r.POST(api.applyTemplateOnApplicationHandler))
 
 	// UI
@@ -299,6 +328,7 @@ func (api *API) InitRouter() {
 	r.Handle("/worker/waiting", r.POST(api.workerWaitingHandler))
 	r.Handle("/worker/unregister", r.POST(api.unregisterWorkerHandler))
 	r.Handle("/worker/{id}/disable", r.POST(api.disableWorkerHandler))
+	r.Handle("/worker/{id}/maintenance", r.POST(api.postWorkerMaintenanceHandler, NeedAdmin(true)), r.DELETE(api.deleteWorkerMaintenanceHandler, NeedAdmin(true)))
 
 	// Worker models
 	r.Handle("/worker/model", r.POST(api.addWorkerModelHandler), r.GET(api.getWorkerModelsHandler))
@@ -307,6 +337,10 @@ func (api *API) InitRouter() {
 	r.Handle("/worker/model/type", r.GET(api.getWorkerModelTypesHandler))
 	r.Handle("/worker/model/communication", r.GET(api.getWorkerModelCommunicationsHandler))
 	r.Handle("/worker/model/{permModelID}", r.PUT(api.updateWorkerModelHandler), r.DELETE(api.deleteWorkerModelHandler))
+	r.Handle("/worker/model/{permModelID}/impact", r.GET(api.getWorkerModelImpactHandler))
+	r.Handle("/worker/model/{permModelID}/version", r.GET(api.getWorkerModelVersionsHandler), r.POST(api.postWorkerModelVersionHandler))
+	r.Handle("/worker/model/{permModelID}/version/{version}/promote", r.POST(api.postPromoteWorkerModelVersionHandler))
+	r.Handle("/worker/model/{permModelID}/version/{version}/rollout", r.PUT(api.putRolloutWorkerModelVersionHandler))
 	r.Handle("/worker/model/capability/type", r.GET(api.getWorkerModelCapaTypesHandler))
 
 	// Workflows
@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
@@ -501,7 +502,8 @@ func (api *API) cloneApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
-		if err := cloneApplication(tx, api.Cache, proj, &newApp, appToClone, getUser(ctx)); err != nil {
+		evts, err := cloneApplication(tx, api.Cache, proj, &newApp, appToClone, getUser(ctx))
+		if err != nil {
 			log.Warning("cloneApplicationHandler> Cannot insert new application %s: %s\n", newApp.Name, err)
 			return err
 		}
@@ -515,19 +517,20 @@ func (api *API) cloneApplicationHandler() Handler {
 			log.Warning("cloneApplicationHandler> Cannot commit transaction : %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		return WriteJSON(w, r, newApp, http.StatusOK)
 	}
 }
 
 // cloneApplication Clone an application with all her dependencies: pipelines, permissions, triggers
-func cloneApplication(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, newApp *sdk.Application, appToClone *sdk.Application, u *sdk.User) error {
+func cloneApplication(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, newApp *sdk.Application, appToClone *sdk.Application, u *sdk.User) ([]sdk.EventAudit, error) {
 	newApp.Pipelines = appToClone.Pipelines
 	newApp.ApplicationGroups = appToClone.ApplicationGroups
 
 	// Create Application
 	if err := application.Insert(db, store, proj, newApp, u); err != nil {
-		return err
+		return nil, err
 	}
 
 	var variablesToDelete []string
@@ -547,34 +550,39 @@ func cloneApplication(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 	}
 
 	// Insert variable
+	evts := []sdk.EventAudit{}
 	for _, v := range newApp.Variable {
 		var errVar error
 		// If variable is a key variable, generate a new one for this application
 		if v.Type == sdk.KeyVariable {
-			errVar = application.AddKeyPairToApplication(db, store, newApp, v.Name, u)
+			var evtsKey []sdk.EventAudit
+			evtsKey, errVar = application.AddKeyPairToApplication(db, store, newApp, v.Name, u)
+			evts = append(evts, evtsKey...)
 		} else {
-			errVar = application.InsertVariable(db, store, newApp, v, u)
+			var evt sdk.EventAudit
+			evt, errVar = application.InsertVariable(db, store, newApp, v, u)
+			evts = append(evts, evt)
 		}
 		if errVar != nil {
-			return errVar
+			return nil, errVar
 		}
 	}
 
 	// Attach pipeline + Set pipeline parameters
 	for _, appPip := range newApp.Pipelines {
 		if _, err := application.AttachPipeline(db, newApp.ID, appPip.Pipeline.ID); err != nil {
-			return err
+			return nil, err
 		}
 
 		if err := application.UpdatePipelineApplication(db, store, newApp, appPip.Pipeline.ID, appPip.Parameters, u); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	// Load trigger to clone
 	triggers, err := trigger.LoadTriggerByApp(db, appToClone.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Clone trigger
@@ -585,7 +593,7 @@ func cloneApplication(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 		}
 		t.SrcApplication = *newApp
 		if err := trigger.InsertTrigger(db, &t); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -596,21 +604,21 @@ func cloneApplication(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 		appPip.Triggers, errTrig = trigger.LoadTriggersByAppAndPipeline(db, newApp.ID, appPip.Pipeline.ID)
 		if errTrig != nil {
 			log.Warning("cloneApplication> Cannot load triggers: %s\n", errTrig)
-			return errTrig
+			return nil, errTrig
 		}
 	}
 
 	// Insert Permission
 	if err := application.AddGroup(db, store, proj, newApp, u, newApp.ApplicationGroups...); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := sanity.CheckApplication(db, proj, newApp); err != nil {
 		log.Warning("cloneApplication> Cannot check application sanity: %s\n", err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	return evts, nil
 }
 
 func (api *API) updateApplicationHandler() Handler {
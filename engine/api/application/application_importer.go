@@ -14,11 +14,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-//Import is able to create a new application and all its components
-func Import(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.Application, repomanager *sdk.RepositoriesManager, u *sdk.User, msgChan chan<- sdk.Message) error {
+//Import is able to create a new application and all its components, it returns the events of the audit to publish once committed
+func Import(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.Application, repomanager *sdk.RepositoriesManager, u *sdk.User, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
 	//Save application in database
 	if err := Insert(db, store, proj, app, u); err != nil {
-		return sdk.WrapError(err, "application.Import")
+		return nil, sdk.WrapError(err, "application.Import")
 	}
 
 	if msgChan != nil {
@@ -33,24 +33,27 @@ func Import(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.
 		app.ApplicationGroups = proj.ProjectGroups
 	}
 
-	if err := importVariables(db, store, proj, app, u, msgChan); err != nil {
-		return err
+	evts, err := importVariables(db, store, proj, app, u, msgChan)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := ImportPipelines(db, store, proj, app, u, msgChan); err != nil {
-		return err
+	evtsPipelines, err := ImportPipelines(db, store, proj, app, u, msgChan)
+	if err != nil {
+		return nil, err
 	}
+	evts = append(evts, evtsPipelines...)
 
 	//Insert group permission on application
 	for i := range app.ApplicationGroups {
 		//Load the group by name
 		g, err := group.LoadGroup(db, app.ApplicationGroups[i].Group.Name)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Debug("application.Import> Insert group %d in application", g.ID)
 		if err := AddGroup(db, store, proj, app, u, app.ApplicationGroups[i]); err != nil {
-			return err
+			return nil, err
 		}
 		if msgChan != nil {
 			msgChan <- sdk.NewMessage(sdk.MsgAppGroupSetPermission, g.Name, app.Name)
@@ -61,35 +64,40 @@ func Import(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.
 	app.RepositoriesManager = repomanager
 	if app.RepositoriesManager != nil && app.RepositoryFullname != "" && len(app.Pipelines) > 0 {
 		if err := repositoriesmanager.InsertForApplication(db, app, proj.Key); err != nil {
-			return err
+			return nil, err
 		}
 		//Manage hook
 		if _, err := hook.CreateHook(db, store, proj.Key, repomanager, app.RepositoryFullname, app, &app.Pipelines[0].Pipeline); err != nil {
-			return err
+			return nil, err
 		}
 		if msgChan != nil {
 			msgChan <- sdk.NewMessage(sdk.MsgHookCreated, app.RepositoryFullname, app.Pipelines[0].Pipeline.Name)
 		}
 	}
 
-	return nil
+	return evts, nil
 }
 
 //importVariables is able to create variable on an existing application
-func importVariables(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.Application, u *sdk.User, msgChan chan<- sdk.Message) error {
+func importVariables(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.Application, u *sdk.User, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+	evts := []sdk.EventAudit{}
 	for _, newVar := range app.Variable {
 		var errCreate error
 		switch newVar.Type {
 		case sdk.KeyVariable:
-			errCreate = AddKeyPairToApplication(db, store, app, newVar.Name, u)
+			var evtsKey []sdk.EventAudit
+			evtsKey, errCreate = AddKeyPairToApplication(db, store, app, newVar.Name, u)
+			evts = append(evts, evtsKey...)
 			break
 		default:
-			errCreate = InsertVariable(db, store, app, newVar, u)
+			var evt sdk.EventAudit
+			evt, errCreate = InsertVariable(db, store, app, newVar, u)
+			evts = append(evts, evt)
 			break
 		}
 		if errCreate != nil {
 			log.Warning("importVariables> Cannot add variable %s in application %s:  %s\n", newVar.Name, app.Name, errCreate)
-			return errCreate
+			return nil, errCreate
 		}
 	}
 
@@ -97,30 +105,31 @@ func importVariables(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 		msgChan <- sdk.NewMessage(sdk.MsgAppVariablesCreated, app.Name)
 	}
 
-	return nil
+	return evts, nil
 }
 
-//ImportPipelines is able to create pipelines on an existing application
-func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.Application, u *sdk.User, msgChan chan<- sdk.Message) error {
+//ImportPipelines is able to create pipelines on an existing application, it returns the events of the audit to publish once committed
+func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, app *sdk.Application, u *sdk.User, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+	evts := []sdk.EventAudit{}
 	//Import pipelines
 	for i := range app.Pipelines {
 		//Import pipeline
 		log.Debug("application.Import> Import pipeline %s", app.Pipelines[i].Pipeline.Name)
 		if err := pipeline.Import(db, proj, &app.Pipelines[i].Pipeline, msgChan, u); err != nil {
-			return err
+			return nil, err
 		}
 
 		//Check if application is attached to the pipeline
 		attached, err := IsAttached(db, proj.ID, app.ID, app.Pipelines[i].Pipeline.Name)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		//Attach pipeline
 		if !attached {
 			log.Debug("application.Import> Attach pipeline %s", app.Pipelines[i].Pipeline.Name)
 			if _, err := AttachPipeline(db, app.ID, app.Pipelines[i].Pipeline.ID); err != nil {
-				return err
+				return nil, err
 			}
 			if msgChan != nil {
 				msgChan <- sdk.NewMessage(sdk.MsgPipelineAttached, app.Pipelines[i].Pipeline.Name, app.Name)
@@ -144,7 +153,7 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 				log.Debug("Load t.SrcApplication.Name:%s", t.SrcApplication.Name)
 				srcApp, err := LoadByName(db, store, proj.Key, t.SrcApplication.Name, u, LoadOptions.Default)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				t.SrcApplication = *srcApp
 			}
@@ -158,7 +167,7 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 				log.Debug("ImportPipelines> Load t.SrcPipeline.Name:%s", t.SrcApplication.Name)
 				srcPipeline, err := pipeline.LoadPipeline(db, proj.Key, t.SrcPipeline.Name, false)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				t.SrcPipeline = *srcPipeline
 			}
@@ -169,7 +178,7 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 			} else {
 				dest, err := LoadByName(db, store, proj.Key, t.DestApplication.Name, u, LoadOptions.Default)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				t.DestApplication = *dest
 			}
@@ -180,7 +189,7 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 			} else {
 				destPipeline, err := pipeline.LoadPipeline(db, proj.Key, t.DestPipeline.Name, false)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				t.DestPipeline = *destPipeline
 			}
@@ -189,23 +198,27 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 			if t.SrcEnvironment.Name == "" {
 				t.SrcEnvironment = sdk.DefaultEnv
 			} else {
-				if err := environment.Import(db, proj, &t.SrcEnvironment, msgChan, u); err != nil {
-					return sdk.WrapError(err, "ImportPipelines> Cannot import environment %s", t.SrcEnvironment.Name)
+				evtsEnv, err := environment.Import(db, proj, &t.SrcEnvironment, msgChan, u)
+				if err != nil {
+					return nil, sdk.WrapError(err, "ImportPipelines> Cannot import environment %s", t.SrcEnvironment.Name)
 				}
+				evts = append(evts, evtsEnv...)
 			}
 
 			//Load or import destination environment
 			if t.DestEnvironment.Name == "" {
 				t.DestEnvironment = sdk.DefaultEnv
 			} else {
-				if err := environment.Import(db, proj, &t.DestEnvironment, msgChan, u); err != nil {
-					return sdk.WrapError(err, "ImportPipelines> Cannot import environment %s", t.DestEnvironment.Name)
+				evtsEnv, err := environment.Import(db, proj, &t.DestEnvironment, msgChan, u)
+				if err != nil {
+					return nil, sdk.WrapError(err, "ImportPipelines> Cannot import environment %s", t.DestEnvironment.Name)
 				}
+				evts = append(evts, evtsEnv...)
 			}
 
 			//Check if environment and pipeline type are compatible
 			if t.DestEnvironment.ID == sdk.DefaultEnv.ID && t.DestPipeline.Type == sdk.DeploymentPipeline {
-				return sdk.ErrNoEnvironmentProvided
+				return nil, sdk.ErrNoEnvironmentProvided
 			}
 
 			log.Debug("application.Import> creating trigger SrcApp=%d SrpPip=%d SrcEnv=%d DestApp=%d DestPip=%d DestEnv=%d", t.SrcApplication.ID, t.SrcPipeline.ID, t.SrcEnvironment.ID, t.DestApplication.ID, t.DestPipeline.ID, t.DestEnvironment.ID)
@@ -213,12 +226,12 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 			//Check if trigger exists
 			exists, err := trigger.Exists(db, t.SrcApplication.ID, t.SrcPipeline.ID, t.SrcEnvironment.ID, t.DestApplication.ID, t.DestPipeline.ID, t.DestEnvironment.ID)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if !exists {
 				//Insert trigger
 				if err := trigger.InsertTrigger(db, t); err != nil {
-					return err
+					return nil, err
 				}
 				if msgChan != nil {
 					msgChan <- sdk.NewMessage(sdk.MsgPipelineTriggerCreated, t.SrcPipeline.Name, t.SrcApplication.Name, t.DestPipeline.Name, t.DestApplication.Name)
@@ -227,5 +240,5 @@ func ImportPipelines(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project,
 			}
 		}
 	}
-	return nil
+	return evts, nil
 }
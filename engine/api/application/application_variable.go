@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/secret"
@@ -242,25 +243,25 @@ func GetAllVariableByID(db gorp.SqlExecutor, applicationID int64, fargs ...FuncA
 	return variables, err
 }
 
-// InsertVariable Insert a new variable in the given application
-func InsertVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, variable sdk.Variable, u *sdk.User) error {
+// InsertVariable Insert a new variable in the given application, it returns the event of the audit to publish once committed
+func InsertVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, variable sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
-		return fmt.Errorf("You try to insert a placeholder for new variable %s", variable.Name)
+		return sdk.EventAudit{}, fmt.Errorf("You try to insert a placeholder for new variable %s", variable.Name)
 	}
 
 	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
 	if err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot encrypt secret")
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot encrypt secret")
 	}
 
 	query := `INSERT INTO application_variable(application_id, var_name, var_value, cipher_value, var_type)
 		  VALUES($1, $2, $3, $4, $5) RETURNING id`
 	if err := db.QueryRow(query, app.ID, variable.Name, clear, cipher, string(variable.Type)).Scan(&variable.ID); err != nil && strings.Contains(err.Error(), "application_variable_pkey") {
-		return sdk.ErrVariableExists
+		return sdk.EventAudit{}, sdk.ErrVariableExists
 	}
 	if err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot insert variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot insert variable %s", variable.Name)
 	}
 
 	ava := &sdk.ApplicationVariableAudit{
@@ -272,19 +273,27 @@ func InsertVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 		Versionned:    time.Now(),
 	}
 
+	evt, err := auditVariable(db, app, u, nil, &variable)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot audit variable %s", variable.Name)
+	}
+
 	if err := inserAudit(db, ava); err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot insert audit for variable %d", variable.ID)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot insert audit for variable %d", variable.ID)
 	}
 
-	return UpdateLastModified(db, store, app, u)
+	if err := UpdateLastModified(db, store, app, u); err != nil {
+		return sdk.EventAudit{}, err
+	}
+	return evt, nil
 }
 
-// UpdateVariable Update a variable in the given application
-func UpdateVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, variable *sdk.Variable, u *sdk.User) error {
+// UpdateVariable Update a variable in the given application, it returns the event of the audit to publish once committed
+func UpdateVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	varValue := variable.Value
 	variableBefore, err := LoadVariableByID(db, app.ID, variable.ID, WithClearPassword())
 	if err != nil {
-		return sdk.WrapError(err, "UpdateVariable> cannot load variable %d", variable.ID)
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> cannot load variable %d", variable.ID)
 	}
 
 	if sdk.NeedPlaceholder(variable.Type) && variable.Value == sdk.PasswordPlaceholder {
@@ -292,20 +301,20 @@ func UpdateVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 	}
 	clear, cipher, err := secret.EncryptS(variable.Type, varValue)
 	if err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot encrypt secret %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot encrypt secret %s", variable.Name)
 	}
 
 	query := `UPDATE application_variable SET var_name= $1, var_value=$2, cipher_value=$3 WHERE id = $4`
 	result, err := db.Exec(query, variable.Name, clear, cipher, variable.ID)
 	if err != nil {
-		return sdk.WrapError(err, "Cannot update variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "Cannot update variable %s", variable.Name)
 	}
 	rowAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return sdk.EventAudit{}, err
 	}
 	if rowAffected == 0 {
-		return ErrNoVariable
+		return sdk.EventAudit{}, ErrNoVariable
 	}
 
 	ava := &sdk.ApplicationVariableAudit{
@@ -318,29 +327,37 @@ func UpdateVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 		Versionned:     time.Now(),
 	}
 
+	evt, err := auditVariable(db, app, u, variableBefore, variable)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot audit variable %s", variable.Name)
+	}
+
 	if err := inserAudit(db, ava); err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot insert audit for variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot insert audit for variable %s", variable.Name)
 	}
 
 	// Update application
-	return UpdateLastModified(db, store, app, u)
+	if err := UpdateLastModified(db, store, app, u); err != nil {
+		return sdk.EventAudit{}, err
+	}
+	return evt, nil
 }
 
-// DeleteVariable Delete a variable from the given pipeline
-func DeleteVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, variable *sdk.Variable, u *sdk.User) error {
+// DeleteVariable Delete a variable from the given pipeline, it returns the event of the audit to publish once committed
+func DeleteVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	query := `DELETE FROM application_variable
 		  WHERE application_variable.application_id = $1 AND application_variable.var_name = $2`
 	result, err := db.Exec(query, app.ID, variable.Name)
 	if err != nil {
-		return sdk.WrapError(err, "DeleteVariable> Cannot delete variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot delete variable %s", variable.Name)
 	}
 
 	rowAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return sdk.EventAudit{}, err
 	}
 	if rowAffected == 0 {
-		return ErrNoVariable
+		return sdk.EventAudit{}, ErrNoVariable
 	}
 
 	ava := &sdk.ApplicationVariableAudit{
@@ -352,11 +369,19 @@ func DeleteVariable(db gorp.SqlExecutor, store cache.Store, app *sdk.Application
 		Versionned:     time.Now(),
 	}
 
+	evt, err := auditVariable(db, app, u, variable, nil)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot audit variable %s", variable.Name)
+	}
+
 	if err := inserAudit(db, ava); err != nil {
-		return sdk.WrapError(err, "DeleteVariable> Cannot insert audit for variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot insert audit for variable %s", variable.Name)
 	}
 
-	return UpdateLastModified(db, store, app, u)
+	if err := UpdateLastModified(db, store, app, u); err != nil {
+		return sdk.EventAudit{}, err
+	}
+	return evt, nil
 }
 
 // DeleteAllVariable Delete all variables from the given pipeline
@@ -371,10 +396,10 @@ func DeleteAllVariable(db gorp.SqlExecutor, applicationID int64) error {
 }
 
 // AddKeyPairToApplication generate a ssh key pair and add them as application variables
-func AddKeyPairToApplication(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, keyname string, u *sdk.User) error {
+func AddKeyPairToApplication(db gorp.SqlExecutor, store cache.Store, app *sdk.Application, keyname string, u *sdk.User) ([]sdk.EventAudit, error) {
 	pub, priv, errGenerate := keys.Generatekeypair(keyname)
 	if errGenerate != nil {
-		return sdk.WrapError(errGenerate, "AddKeyPairToApplication> Cannot generate key")
+		return nil, sdk.WrapError(errGenerate, "AddKeyPairToApplication> Cannot generate key")
 	}
 
 	v := sdk.Variable{
@@ -383,8 +408,9 @@ func AddKeyPairToApplication(db gorp.SqlExecutor, store cache.Store, app *sdk.Ap
 		Value: priv,
 	}
 
-	if err := InsertVariable(db, store, app, v, u); err != nil {
-		return err
+	evtPriv, err := InsertVariable(db, store, app, v, u)
+	if err != nil {
+		return nil, err
 	}
 
 	p := sdk.Variable{
@@ -393,7 +419,11 @@ func AddKeyPairToApplication(db gorp.SqlExecutor, store cache.Store, app *sdk.Ap
 		Value: pub,
 	}
 
-	return InsertVariable(db, store, app, p, u)
+	evtPub, err := InsertVariable(db, store, app, p, u)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.EventAudit{evtPriv, evtPub}, nil
 }
 
 // insertAudit  insert an application variable audit
@@ -430,3 +460,13 @@ func LoadVariableAudits(db gorp.SqlExecutor, appID, varID int64) ([]sdk.Applicat
 	}
 	return avas, nil
 }
+
+// auditVariable records the change of a variable in the audit log
+func auditVariable(db gorp.SqlExecutor, app *sdk.Application, u *sdk.User, before, after *sdk.Variable) (sdk.EventAudit, error) {
+	name := after
+	if name == nil {
+		name = before
+	}
+	e := audit.Entity{ProjectKey: app.ProjectKey, Type: sdk.AuditEntityVariable, Name: "application/" + app.Name + "/" + name.Name}
+	return audit.Add(db, u, e, before, after)
+}
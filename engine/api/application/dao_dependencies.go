@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/trigger"
+	"github.com/ovh/cds/engine/api/variableset"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -34,6 +35,12 @@ var (
 			return sdk.WrapError(err, "application.loadVariables> Unable to load variables for application %d", app.ID)
 		}
 		app.Variable = variables
+
+		sets, err := variableset.LoadAllByApplication(db, app.ID)
+		if err != nil {
+			return sdk.WrapError(err, "application.loadVariables> Unable to load variable sets for application %d", app.ID)
+		}
+		app.VariableSets = sets
 		return nil
 	}
 
@@ -43,6 +50,12 @@ var (
 			return sdk.WrapError(err, "application.loadVariablesWithClearPassword> Unable to load variables for application %d", app.ID)
 		}
 		app.Variable = variables
+
+		sets, err := variableset.LoadAllByApplication(db, app.ID, variableset.WithClearPassword())
+		if err != nil {
+			return sdk.WrapError(err, "application.loadVariablesWithClearPassword> Unable to load variable sets for application %d", app.ID)
+		}
+		app.VariableSets = sets
 		return nil
 	}
 
@@ -52,6 +65,12 @@ var (
 			return sdk.WrapError(err, "application.loadVariablesWithEncryptPassword> Unable to load variables for application %d", app.ID)
 		}
 		app.Variable = variables
+
+		sets, err := variableset.LoadAllByApplication(db, app.ID, variableset.WithEncryptPassword())
+		if err != nil {
+			return sdk.WrapError(err, "application.loadVariablesWithEncryptedPassword> Unable to load variable sets for application %d", app.ID)
+		}
+		app.VariableSets = sets
 		return nil
 	}
 
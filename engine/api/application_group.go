@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
@@ -53,10 +54,20 @@ func (api *API) updateGroupRoleOnApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		role, err := group.LoadRoleInApplication(tx, app.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot load role of group %s in application %s", groupName, appName)
+		}
+
 		if err := group.UpdateGroupRoleInApplication(tx, key, appName, groupName, groupApplication.Permission); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot update permission for group %s in application %s", groupName, appName)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "application/"+appName, groupName, role, groupApplication.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot audit permission")
+		}
+
 		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot update last modified date")
 		}
@@ -64,6 +75,7 @@ func (api *API) updateGroupRoleOnApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		if err := application.LoadGroupByApplication(api.mustDB(), app); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnApplicationHandler: Cannot load application groups")
@@ -117,6 +129,11 @@ func (api *API) updateGroupsInApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		if err := application.LoadGroupByApplication(tx, app); err != nil {
+			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot load groups of application %s", appName)
+		}
+		before := app.ApplicationGroups
+
 		if err := group.DeleteAllGroupFromApplication(tx, app.ID); err != nil {
 			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot delete groups from application %s", appName)
 		}
@@ -125,6 +142,11 @@ func (api *API) updateGroupsInApplicationHandler() Handler {
 			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot add groups in application %s", app.Name)
 		}
 
+		evtsPerm, err := api.auditPermissions(ctx, tx, key, "application/"+appName, before, groupsPermission)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot audit permissions")
+		}
+
 		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot update last modified date")
 		}
@@ -136,6 +158,7 @@ func (api *API) updateGroupsInApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupsInApplicationHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		return WriteJSON(w, r, app, http.StatusOK)
 	}
@@ -174,10 +197,25 @@ func (api *API) addGroupInApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		role, err := group.LoadRoleInApplication(tx, app.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot load role of group %s in application %s", g.Name, app.Name)
+		}
+
 		if err := application.AddGroup(tx, api.Cache, proj, app, getUser(ctx), groupPermission); err != nil {
 			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot add group %s in application %s", g.Name, app.Name)
 		}
 
+		newRole, err := group.LoadRoleInApplication(tx, app.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot load role of group %s in application %s", g.Name, app.Name)
+		}
+
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "application/"+appName, g.Name, role, newRole)
+		if err != nil {
+			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot audit permission")
+		}
+
 		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot update application last modified date")
 		}
@@ -185,6 +223,7 @@ func (api *API) addGroupInApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		if err := application.LoadGroupByApplication(api.mustDB(), app); err != nil {
 			return sdk.WrapError(err, "addGroupInApplicationHandler> Cannot load application groups")
@@ -213,10 +252,25 @@ func (api *API) deleteGroupFromApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		g, err := group.LoadGroup(tx, groupName)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot load group %s", groupName)
+		}
+
+		role, err := group.LoadRoleInApplication(tx, app.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot load role of group %s in application %s", groupName, appName)
+		}
+
 		if err := group.DeleteGroupFromApplication(tx, key, appName, groupName); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot delete group %s from pipeline %s", groupName, appName)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "application/"+appName, groupName, role, 0)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot audit permission")
+		}
+
 		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot update application last modified date")
 		}
@@ -224,6 +278,7 @@ func (api *API) deleteGroupFromApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		if err := application.LoadGroupByApplication(api.mustDB(), app); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromApplicationHandler: Cannot load application groups")
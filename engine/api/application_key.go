@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -51,11 +52,17 @@ func (api *API) deleteKeyInApplicationHandler() Handler {
 			return sdk.WrapError(errT, "v> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		evts := []sdk.EventAudit{}
 		for _, k := range app.Keys {
 			if k.Name == keyName {
 				if err := application.DeleteApplicationKey(tx, app.ID, keyName); err != nil {
 					return sdk.WrapError(err, "deleteKeyInApplicationHandler> Cannot delete key %s", k.Name)
 				}
+				evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityKey, Name: "application/" + appName + "/" + k.Name}, k, nil)
+				if err != nil {
+					return sdk.WrapError(err, "deleteKeyInApplicationHandler> Cannot audit key %s", k.Name)
+				}
+				evts = append(evts, evt)
 				if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 					return sdk.WrapError(err, "deleteKeyInApplicationHandler> Cannot update application last modified date")
 				}
@@ -65,6 +72,7 @@ func (api *API) deleteKeyInApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteKeyInApplicationHandler> Cannot commit transaction")
 		}
+		audit.Publish(evts...)
 
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -123,6 +131,11 @@ func (api *API) addKeyInApplicationHandler() Handler {
 			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot insert application key")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityKey, Name: "application/" + appName + "/" + newKey.Name}, nil, newKey)
+		if err != nil {
+			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot audit key %s", newKey.Name)
+		}
+
 		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot update project last modified date")
 		}
@@ -130,6 +143,7 @@ func (api *API) addKeyInApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addKeyInApplicationHandler> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		return WriteJSON(w, r, newKey, http.StatusOK)
 	}
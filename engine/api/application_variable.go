@@ -8,6 +8,8 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/impact"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
 	"github.com/ovh/cds/engine/api/secret"
@@ -76,6 +78,8 @@ func (api *API) restoreAuditHandler() Handler {
 			return sdk.ErrUnknownError
 		}
 
+		evts := []sdk.EventAudit{}
+
 		for _, v := range variables {
 			if sdk.NeedPlaceholder(v.Type) {
 				value, err := secret.Decrypt([]byte(v.Value))
@@ -85,17 +89,19 @@ func (api *API) restoreAuditHandler() Handler {
 				}
 				v.Value = string(value)
 			}
-			err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx))
+			evt, err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx))
 			if err != nil {
 				log.Warning("restoreAuditHandler: Cannot insert variable %s for application %s:  %s\n", v.Name, appName, err)
 				return err
 			}
+			evts = append(evts, evt)
 		}
 
 		if err := tx.Commit(); err != nil {
 			log.Warning("restoreAuditHandler: Cannot commit transaction:  %s\n", err)
 			return sdk.ErrUnknownError
 		}
+		audit.Publish(evts...)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
@@ -193,6 +199,14 @@ func (api *API) deleteVariableFromApplicationHandler() Handler {
 			return sdk.WrapError(err, "deleteVariableInApplicationHandler: Cannot load application: %s", appName)
 		}
 
+		impacts, errImpact := impact.Variable(api.mustDB(), api.Cache, getUser(ctx), key, "cds.app."+varName)
+		if errImpact != nil {
+			return sdk.WrapError(errImpact, "deleteVariableFromApplicationHandler> Cannot load impact of variable %s", varName)
+		}
+		if err := checkImpacts(r, impacts); err != nil {
+			return sdk.WrapError(err, "deleteVariableFromApplicationHandler> Variable %s is used by %d entities", varName, len(impacts))
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return sdk.WrapError(err, "deleteVariableFromApplicationHandler: Cannot start transaction")
@@ -205,7 +219,8 @@ func (api *API) deleteVariableFromApplicationHandler() Handler {
 			return sdk.WrapError(errV, "deleteVariableFromApplicationHandler> Cannot load variable %s", varName)
 		}
 
-		if err := application.DeleteVariable(tx, api.Cache, app, varToDelete, getUser(ctx)); err != nil {
+		evt, err := application.DeleteVariable(tx, api.Cache, app, varToDelete, getUser(ctx))
+		if err != nil {
 			log.Warning("deleteVariableFromApplicationHandler: Cannot delete %s: %s\n", varName, err)
 			return sdk.WrapError(err, "deleteVariableFromApplicationHandler: Cannot delete %s", varName)
 		}
@@ -213,6 +228,7 @@ func (api *API) deleteVariableFromApplicationHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteVariableFromApplicationHandler: Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		go func() {
 			if err := sanity.CheckApplication(api.mustDB(), p, app); err != nil {
@@ -260,6 +276,8 @@ func (api *API) updateVariablesInApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		// Preload values, if one password variable has a password placeholder, we can't just insert
 		// the placeholder !
 		preload, err := application.GetAllVariable(tx, key, appName, application.WithClearPassword())
@@ -285,34 +303,42 @@ func (api *API) updateVariablesInApplicationHandler() Handler {
 					}
 				}
 
-				if err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx)); err != nil {
+				evt, err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx))
+				if err != nil {
 					log.Warning("updateVariablesInApplicationHandler: Cannot insert variable %s for application %s:  %s\n", v.Name, appName, err)
 					return err
 				}
+				evts = append(evts, evt)
 				break
 			case sdk.KeyVariable:
 				if v.Value == "" {
-					if err := application.AddKeyPairToApplication(tx, api.Cache, app, v.Name, getUser(ctx)); err != nil {
+					evtsKey, err := application.AddKeyPairToApplication(tx, api.Cache, app, v.Name, getUser(ctx))
+					if err != nil {
 						log.Warning("updateVariablesInApplicationHandler> cannot generate keypair: %s\n", err)
 						return err
 					}
+					evts = append(evts, evtsKey...)
 				} else if v.Value == sdk.PasswordPlaceholder {
 					for _, p := range preload {
 						if p.ID == v.ID {
 							v.Value = p.Value
 						}
 					}
-					if err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx)); err != nil {
+					evt, err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx))
+					if err != nil {
 						log.Warning("updateVariablesInApplication: Cannot insert variable %s in project %s: %s\n", v.Name, p.Key, err)
 						return err
 					}
+					evts = append(evts, evt)
 				}
 				break
 			default:
-				if err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx)); err != nil {
+				evt, err := application.InsertVariable(tx, api.Cache, app, v, getUser(ctx))
+				if err != nil {
 					log.Warning("updateVariablesInApplicationHandler: Cannot insert variable %s for application %s:  %s\n", v.Name, appName, err)
 					return err
 				}
+				evts = append(evts, evt)
 			}
 		}
 
@@ -320,6 +346,7 @@ func (api *API) updateVariablesInApplicationHandler() Handler {
 			log.Warning("updateVariablesInApplicationHandler: Cannot commit transaction:  %s\n", err)
 			return sdk.ErrUnknownError
 		}
+		audit.Publish(evts...)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
@@ -366,13 +393,15 @@ func (api *API) updateVariableInApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
-		if err := application.UpdateVariable(tx, api.Cache, app, &newVar, getUser(ctx)); err != nil {
+		evt, err := application.UpdateVariable(tx, api.Cache, app, &newVar, getUser(ctx))
+		if err != nil {
 			return sdk.WrapError(err, "updateVariableInApplicationHandler: Cannot update variable %s for application %s", varName, appName)
 		}
 
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateVariableInApplicationHandler: Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		app.Variable, err = application.GetAllVariableByID(api.mustDB(), app.ID)
 		if err != nil {
@@ -427,12 +456,15 @@ func (api *API) addVariableInApplicationHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
 		switch newVar.Type {
 		case sdk.KeyVariable:
-			err = application.AddKeyPairToApplication(tx, api.Cache, app, newVar.Name, getUser(ctx))
+			evts, err = application.AddKeyPairToApplication(tx, api.Cache, app, newVar.Name, getUser(ctx))
 			break
 		default:
-			err = application.InsertVariable(tx, api.Cache, app, newVar, getUser(ctx))
+			var evt sdk.EventAudit
+			evt, err = application.InsertVariable(tx, api.Cache, app, newVar, getUser(ctx))
+			evts = append(evts, evt)
 			break
 		}
 		if err != nil {
@@ -444,6 +476,7 @@ func (api *API) addVariableInApplicationHandler() Handler {
 			log.Warning("addVariableInApplicationHandler: Cannot commit transaction:  %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		app.Variable, err = application.GetAllVariableByID(api.mustDB(), app.ID)
 		if err != nil {
@@ -0,0 +1,305 @@
+package audit
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsamin/go-dump"
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+)
+
+// DefaultLimit is the max number of entries returned by LoadAll when the filter has no limit
+const DefaultLimit = 100
+
+// Entity describes an audited entity, its name is a path for the entities which belong to another one
+type Entity struct {
+	ProjectKey string
+	Type       string
+	Name       string
+}
+
+// Add records a change on an entity in the audit log and returns the event of the entry, to publish once the
+// transaction is committed. A nil before means a creation, a nil after means a deletion. Secret values are masked
+// before being recorded
+func Add(db gorp.SqlExecutor, u *sdk.User, e Entity, before, after interface{}) (sdk.EventAudit, error) {
+	a := sdk.Audit{
+		Created:    time.Now(),
+		ProjectKey: e.ProjectKey,
+		EntityType: e.Type,
+		EntityName: e.Name,
+	}
+	if u != nil {
+		a.Username = u.Username
+	}
+
+	var err error
+	if a.Before, err = mask(before); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to read %s %s", e.Type, e.Name)
+	}
+	if a.After, err = mask(after); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to read %s %s", e.Type, e.Name)
+	}
+
+	switch {
+	case a.Before == nil:
+		a.EventType = sdk.AuditAdd
+	case a.After == nil:
+		a.EventType = sdk.AuditDelete
+	default:
+		a.EventType = sdk.AuditUpdate
+	}
+	if a.Diff, err = Diff(a.Before, a.After); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to compute diff of %s %s", e.Type, e.Name)
+	}
+
+	beforeJSON, err := nullJSON(a.Before)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to marshal %s %s", e.Type, e.Name)
+	}
+	afterJSON, err := nullJSON(a.After)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to marshal %s %s", e.Type, e.Name)
+	}
+	diffJSON, err := nullJSON(a.Diff)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to marshal diff of %s %s", e.Type, e.Name)
+	}
+
+	query := `INSERT INTO audit (created, username, project_key, entity_type, entity_name, event_type, before, after, diff)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
+	if err := db.QueryRow(query, a.Created, a.Username, a.ProjectKey, a.EntityType, a.EntityName, a.EventType,
+		beforeJSON, afterJSON, diffJSON).Scan(&a.ID); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "audit.Add> Unable to insert audit of %s %s", e.Type, e.Name)
+	}
+
+	return sdk.EventAudit{
+		ID:         a.ID,
+		Username:   a.Username,
+		ProjectKey: a.ProjectKey,
+		EntityType: a.EntityType,
+		EntityName: a.EntityName,
+		EventType:  a.EventType,
+		Diff:       a.Diff,
+	}, nil
+}
+
+// Publish exports the events of entries of the audit log to the event broker
+func Publish(events ...sdk.EventAudit) {
+	for _, e := range events {
+		event.Publish(e)
+	}
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// childrenPattern returns the LIKE pattern matching the names of the entities which belong to an entity
+func childrenPattern(name string) string {
+	return likeEscaper.Replace(strings.TrimSuffix(name, "/")) + "/%"
+}
+
+const selectQuery = `SELECT id, created, username, project_key, entity_type, entity_name, event_type, before, after, diff FROM audit`
+
+// LoadAll loads the entries of the audit log matching the filter, the most recent first. The before and
+// after values are not loaded
+func LoadAll(db gorp.SqlExecutor, f sdk.AuditFilter) ([]sdk.Audit, error) {
+	var clauses []string
+	var args []interface{}
+	where := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+
+	if f.ProjectKey != "" {
+		where("project_key = $%d", f.ProjectKey)
+	}
+	if f.Username != "" {
+		where("username = $%d", f.Username)
+	}
+	if f.EntityType != "" {
+		where("entity_type = $%d", f.EntityType)
+	}
+	if f.EntityName != "" {
+		args = append(args, f.EntityName, childrenPattern(f.EntityName))
+		clauses = append(clauses, fmt.Sprintf("(entity_name = $%d OR entity_name LIKE $%d)", len(args)-1, len(args)))
+	}
+	if !f.Since.IsZero() {
+		where("created >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		where("created <= $%d", f.Until)
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	query := `SELECT id, created, username, project_key, entity_type, entity_name, event_type, NULL, NULL, diff FROM audit`
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY created DESC, id DESC LIMIT %d", limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "audit.LoadAll> Unable to load audits")
+	}
+	defer rows.Close()
+
+	audits := []sdk.Audit{}
+	for rows.Next() {
+		a, err := scan(rows)
+		if err != nil {
+			return nil, sdk.WrapError(err, "audit.LoadAll> Unable to read audit")
+		}
+		audits = append(audits, *a)
+	}
+	return audits, rows.Err()
+}
+
+// Load loads an entry of the audit log with its before and after values
+func Load(db gorp.SqlExecutor, id int64) (*sdk.Audit, error) {
+	a, err := scan(db.QueryRow(selectQuery+` WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrAuditNotFound
+		}
+		return nil, sdk.WrapError(err, "audit.Load> Unable to load audit %d", id)
+	}
+	return a, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(s scanner) (*sdk.Audit, error) {
+	a := &sdk.Audit{}
+	var before, after, diff sql.NullString
+	if err := s.Scan(&a.ID, &a.Created, &a.Username, &a.ProjectKey, &a.EntityType, &a.EntityName, &a.EventType, &before, &after, &diff); err != nil {
+		return nil, err
+	}
+	if before.Valid {
+		if err := json.Unmarshal([]byte(before.String), &a.Before); err != nil {
+			return nil, err
+		}
+	}
+	if after.Valid {
+		if err := json.Unmarshal([]byte(after.String), &a.After); err != nil {
+			return nil, err
+		}
+	}
+	if diff.Valid {
+		if err := json.Unmarshal([]byte(diff.String), &a.Diff); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func nullJSON(i interface{}) (sql.NullString, error) {
+	if i == nil {
+		return sql.NullString{}, nil
+	}
+	btes, err := json.Marshal(i)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(btes), Valid: true}, nil
+}
+
+// mask returns the JSON representation of an entity where the values of the secret variables and
+// parameters and the private keys are replaced by placeholders
+func mask(i interface{}) (interface{}, error) {
+	btes, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the numbers as they are, ids would be displayed with an exponent otherwise
+	dec := json.NewDecoder(bytes.NewReader(btes))
+	dec.UseNumber()
+	var res interface{}
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	maskValue(res)
+	return res, nil
+}
+
+func maskValue(i interface{}) {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		if t, ok := v["type"].(string); ok && sdk.NeedPlaceholder(t) {
+			if _, has := v["value"]; has {
+				v["value"] = sdk.PasswordPlaceholder
+			}
+		}
+		if p, ok := v["private"].(string); ok && p != "" {
+			v["private"] = sdk.PasswordPlaceholder
+		}
+		for _, c := range v {
+			maskValue(c)
+		}
+	case []interface{}:
+		for _, c := range v {
+			maskValue(c)
+		}
+	}
+}
+
+// Diff returns the values which differ between the JSON representations of two entities, sorted by key
+func Diff(before, after interface{}) ([]sdk.AuditDiff, error) {
+	b, err := flatten(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := flatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []sdk.AuditDiff{}
+	for k, v := range b {
+		if v != a[k] {
+			diffs = append(diffs, sdk.AuditDiff{Key: k, Before: v, After: a[k]})
+		}
+	}
+	for k, v := range a {
+		if _, has := b[k]; !has && v != "" {
+			diffs = append(diffs, sdk.AuditDiff{Key: k, After: v})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs, nil
+}
+
+func flatten(i interface{}) (map[string]string, error) {
+	res := map[string]string{}
+	if i == nil {
+		return res, nil
+	}
+	m, err := dump.ToMap(i, dump.WithDefaultLowerCaseFormatter())
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range m {
+		// Skip the type and length informations added by dump
+		if strings.Contains(k, "__") {
+			continue
+		}
+		res[k] = v
+	}
+	return res, nil
+}
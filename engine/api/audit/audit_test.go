@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestMask(t *testing.T) {
+	v := []sdk.Variable{
+		{Name: "login", Type: sdk.StringVariable, Value: "foo"},
+		{Name: "password", Type: sdk.SecretVariable, Value: "bar"},
+	}
+	res, err := mask(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars, ok := res.([]interface{})
+	if !ok || len(vars) != 2 {
+		t.Fatalf("unexpected masked value %v", res)
+	}
+	assert.Equal(t, "foo", vars[0].(map[string]interface{})["value"])
+	assert.Equal(t, sdk.PasswordPlaceholder, vars[1].(map[string]interface{})["value"])
+
+	var p *sdk.Pipeline
+	res, err = mask(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, res)
+}
+
+func TestDiff(t *testing.T) {
+	before, err := mask(map[string]interface{}{"group": "dev", "permission": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := mask(map[string]interface{}{"group": "dev", "permission": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := Diff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []sdk.AuditDiff{{Key: "permission", Before: "4", After: "7"}}, diffs)
+
+	diffs, err = Diff(nil, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []sdk.AuditDiff{
+		{Key: "group", After: "dev"},
+		{Key: "permission", After: "7"},
+	}, diffs)
+
+	diffs, err = Diff(before, before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, diffs)
+}
+
+func TestChildrenPattern(t *testing.T) {
+	assert.Equal(t, "application/my-app/%", childrenPattern("application/my-app/"))
+	assert.Equal(t, `application/my\_app\%/%`, childrenPattern("application/my_app%"))
+	assert.Equal(t, `variableset/a\\b/%`, childrenPattern(`variableset/a\b`))
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getAuditsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f := sdk.AuditFilter{
+			ProjectKey: r.FormValue("project"),
+			Username:   r.FormValue("user"),
+			EntityType: r.FormValue("entity_type"),
+			EntityName: r.FormValue("entity"),
+		}
+
+		var err error
+		if s := r.FormValue("since"); s != "" {
+			if f.Since, err = time.Parse(time.RFC3339, s); err != nil {
+				return sdk.WrapError(sdk.ErrWrongRequest, "getAuditsHandler> Invalid since date %s", s)
+			}
+		}
+		if s := r.FormValue("until"); s != "" {
+			if f.Until, err = time.Parse(time.RFC3339, s); err != nil {
+				return sdk.WrapError(sdk.ErrWrongRequest, "getAuditsHandler> Invalid until date %s", s)
+			}
+		}
+		if s := r.FormValue("limit"); s != "" {
+			if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit < 0 {
+				return sdk.WrapError(sdk.ErrWrongRequest, "getAuditsHandler> Invalid limit %s", s)
+			}
+		}
+
+		// Only the administrators can browse the whole audit log, the others must choose a project
+		if !getUser(ctx).Admin {
+			if f.ProjectKey == "" {
+				return sdk.WrapError(sdk.ErrForbidden, "getAuditsHandler> Project is mandatory")
+			}
+			if permission.ProjectPermission(f.ProjectKey, getUser(ctx)) < permission.PermissionRead {
+				return sdk.WrapError(sdk.ErrForbidden, "getAuditsHandler> Not allowed to read audits of project %s", f.ProjectKey)
+			}
+		}
+
+		audits, err := audit.LoadAll(api.mustDB(), f)
+		if err != nil {
+			return sdk.WrapError(err, "getAuditsHandler> Cannot load audits")
+		}
+		return WriteJSON(w, r, audits, http.StatusOK)
+	}
+}
+
+func (api *API) getAuditHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		id, err := strconv.ParseInt(vars["id"], 10, 64)
+		if err != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getAuditHandler> Invalid id %s", vars["id"])
+		}
+
+		a, err := audit.Load(api.mustDB(), id)
+		if err != nil {
+			return sdk.WrapError(err, "getAuditHandler> Cannot load audit %d", id)
+		}
+
+		if !getUser(ctx).Admin {
+			if a.ProjectKey == "" || permission.ProjectPermission(a.ProjectKey, getUser(ctx)) < permission.PermissionRead {
+				return sdk.WrapError(sdk.ErrAuditNotFound, "getAuditHandler> Not allowed to read audit %d", id)
+			}
+		}
+		return WriteJSON(w, r, a, http.StatusOK)
+	}
+}
+
+// auditPermission records the change of the role of a group in the audit log, a role 0 means that
+// the group has no permission. It returns the events of the audit to publish once committed
+func (api *API) auditPermission(ctx context.Context, db gorp.SqlExecutor, key, name, groupName string, before, after int) ([]sdk.EventAudit, error) {
+	if before == after {
+		return nil, nil
+	}
+	role := func(r int) interface{} {
+		if r == 0 {
+			return nil
+		}
+		return map[string]interface{}{"group": groupName, "permission": r}
+	}
+	e := audit.Entity{ProjectKey: key, Type: sdk.AuditEntityPermission, Name: name + "/" + groupName}
+	evt, err := audit.Add(db, getUser(ctx), e, role(before), role(after))
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.EventAudit{evt}, nil
+}
+
+// auditPermissions records the changes between two lists of group permissions in the audit log
+func (api *API) auditPermissions(ctx context.Context, db gorp.SqlExecutor, key, name string, before, after []sdk.GroupPermission) ([]sdk.EventAudit, error) {
+	roles := map[string]int{}
+	for _, gp := range before {
+		roles[gp.Group.Name] = gp.Permission
+	}
+	evts := []sdk.EventAudit{}
+	for _, gp := range after {
+		evtsGroup, err := api.auditPermission(ctx, db, key, name, gp.Group.Name, roles[gp.Group.Name], gp.Permission)
+		if err != nil {
+			return nil, err
+		}
+		evts = append(evts, evtsGroup...)
+		delete(roles, gp.Group.Name)
+	}
+	for g, r := range roles {
+		evtsGroup, err := api.auditPermission(ctx, db, key, name, g, r, 0)
+		if err != nil {
+			return nil, err
+		}
+		evts = append(evts, evtsGroup...)
+	}
+	return evts, nil
+}
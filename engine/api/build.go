@@ -340,6 +340,9 @@ func (api *API) takePipelineBuildJobHandler() Handler {
 		if caller.Status != sdk.StatusChecking {
 			return sdk.WrapError(sdk.ErrWrongRequest, "takePipelineBuildJobHandler> worker %s is not available to for build (status = %s)", caller.ID, caller.Status)
 		}
+		if caller.Maintenance {
+			return sdk.WrapError(sdk.ErrWorkerMaintenance, "takePipelineBuildJobHandler> worker %s cannot take job %d", caller.Name, id)
+		}
 
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
@@ -404,6 +407,10 @@ func (api *API) bookPipelineBuildJobHandler() Handler {
 			return sdk.WrapError(errc, "bookPipelineBuildJobHandler> invalid id")
 		}
 
+		if getHatchery(ctx).Maintenance {
+			return sdk.WrapError(sdk.ErrHatcheryMaintenance, "bookPipelineBuildJobHandler> hatchery %s cannot book job %d", getHatchery(ctx).Name, id)
+		}
+
 		if _, err := pipeline.BookPipelineBuildJob(id, getHatchery(ctx)); err != nil {
 			return sdk.WrapError(err, "bookPipelineBuildJobHandler> job already booked")
 		}
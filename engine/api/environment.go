@@ -7,8 +7,10 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/impact"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
@@ -73,6 +75,8 @@ func (api *API) updateEnvironmentsHandler() Handler {
 			return err
 		}
 		defer tx.Rollback()
+
+		evts := []sdk.EventAudit{}
 		for i := range envs {
 			env := &envs[i]
 			env.ProjectID = proj.ID
@@ -163,43 +167,47 @@ func (api *API) updateEnvironmentsHandler() Handler {
 						log.Warning("updateEnvironmentsHandler> %s (%s)\n", errMsg, getUser(ctx).Username)
 						return sdk.NewError(sdk.ErrInvalidSecretValue, fmt.Errorf("%s", errMsg))
 					}
-					err = environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
+					evt, err := environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
 					if err != nil {
 						log.Warning("updateEnvironmentsHandler> Cannot insert variables on environments: %s\n", err)
 						return err
 					}
+					evts = append(evts, evt)
 
 					// put placeholder because env.Variable will be in the handler response
 					varEnv.Value = sdk.PasswordPlaceholder
 					break
 				case sdk.KeyVariable:
 					if varEnv.Value == "" {
-						err := environment.AddKeyPairToEnvironment(tx, env.ID, varEnv.Name, getUser(ctx))
+						evtsKey, err := environment.AddKeyPairToEnvironment(tx, env.ID, varEnv.Name, getUser(ctx))
 						if err != nil {
 							log.Warning("updateEnvironmentsHandler> cannot generate keypair: %s\n", err)
 							return err
 						}
+						evts = append(evts, evtsKey...)
 					} else if varEnv.Value == sdk.PasswordPlaceholder {
 						for _, p := range preload {
 							if p.ID == varEnv.ID {
 								varEnv.Value = p.Value
 							}
 						}
-						err = environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
+						evt, err := environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
 						if err != nil {
 							log.Warning("updateEnvironments: Cannot insert variable %s:  %s\n", varEnv.Name, err)
 							return err
 						}
+						evts = append(evts, evt)
 					}
 					// put placeholder because env.Variable will be in the handler response
 					varEnv.Value = sdk.PasswordPlaceholder
 					break
 				default:
-					err = environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
+					evt, err := environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
 					if err != nil {
 						log.Warning("updateEnvironmentsHandler> Cannot insert variables on environments: %s\n", err)
 						return err
 					}
+					evts = append(evts, evt)
 				}
 			}
 		}
@@ -214,6 +222,7 @@ func (api *API) updateEnvironmentsHandler() Handler {
 			log.Warning("updateEnvironmentsHandler> Cannot commit transaction: %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, proj); err != nil {
@@ -308,6 +317,14 @@ func (api *API) deleteEnvironmentHandler() Handler {
 			return errEnv
 		}
 
+		impacts, errImpact := impact.Environment(api.mustDB(), api.Cache, getUser(ctx), projectKey, env)
+		if errImpact != nil {
+			return sdk.WrapError(errImpact, "deleteEnvironmentHandler> Cannot load impact of environment %s", environmentName)
+		}
+		if err := checkImpacts(r, impacts); err != nil {
+			return sdk.WrapError(err, "deleteEnvironmentHandler> Environment %s is used by %d entities", environmentName, len(impacts))
+		}
+
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
 			log.Warning("deleteEnvironmentHandler> Cannot begin transaction: %s\n", errBegin)
@@ -373,6 +390,8 @@ func (api *API) updateEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		if err := environment.UpdateEnvironment(tx, env); err != nil {
 			log.Warning("updateEnvironmentHandler> Cannot update environment %s: %s\n", environmentName, err)
 			return err
@@ -407,11 +426,12 @@ func (api *API) updateEnvironmentHandler() Handler {
 						varEnv.Value = ""
 					}
 				}
-				err = environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
+				evt, err := environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
 				if err != nil {
 					log.Warning("updateEnvironmentHandler> Cannot insert variables on environments: %s\n", err)
 					return err
 				}
+				evts = append(evts, evt)
 			}
 		}
 
@@ -428,6 +448,7 @@ func (api *API) updateEnvironmentHandler() Handler {
 			log.Warning("updateEnvironmentHandler> Cannot commit transaction: %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		var errEnvs error
 		p.Environments, errEnvs = environment.LoadEnvironments(api.mustDB(), p.Key, true, getUser(ctx))
@@ -492,10 +513,13 @@ func (api *API) cloneEnvironmentHandler() Handler {
 		}
 
 		//Insert variables
+		evts := []sdk.EventAudit{}
 		for _, v := range envPost.Variable {
-			if environment.InsertVariable(tx, envPost.ID, &v, getUser(ctx)); err != nil {
+			evt, err := environment.InsertVariable(tx, envPost.ID, &v, getUser(ctx))
+			if err != nil {
 				return sdk.WrapError(err, "cloneEnvironmentHandler> Unable to insert variable: %s", err)
 			}
+			evts = append(evts, evt)
 		}
 
 		//Insert environment
@@ -513,6 +537,7 @@ func (api *API) cloneEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return err
 		}
+		audit.Publish(evts...)
 
 		//return the project with all environments
 		var errEnvs error
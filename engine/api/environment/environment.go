@@ -393,10 +393,10 @@ func LoadEnvironmentByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 }
 
 // AddKeyPairToEnvironment generate a ssh key pair and add them as env variables
-func AddKeyPairToEnvironment(db gorp.SqlExecutor, envID int64, keyname string, u *sdk.User) error {
+func AddKeyPairToEnvironment(db gorp.SqlExecutor, envID int64, keyname string, u *sdk.User) ([]sdk.EventAudit, error) {
 	pub, priv, errGenerate := keys.Generatekeypair(keyname)
 	if errGenerate != nil {
-		return errGenerate
+		return nil, errGenerate
 	}
 
 	v := &sdk.Variable{
@@ -405,8 +405,9 @@ func AddKeyPairToEnvironment(db gorp.SqlExecutor, envID int64, keyname string, u
 		Value: priv,
 	}
 
-	if err := InsertVariable(db, envID, v, u); err != nil {
-		return err
+	evtPriv, err := InsertVariable(db, envID, v, u)
+	if err != nil {
+		return nil, err
 	}
 
 	p := &sdk.Variable{
@@ -415,5 +416,9 @@ func AddKeyPairToEnvironment(db gorp.SqlExecutor, envID int64, keyname string, u
 		Value: pub,
 	}
 
-	return InsertVariable(db, envID, p, u)
+	evtPub, err := InsertVariable(db, envID, p, u)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.EventAudit{evtPriv, evtPub}, nil
 }
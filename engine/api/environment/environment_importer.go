@@ -8,11 +8,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-//Import import or reuser the provided environment
-func Import(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, msgChan chan<- sdk.Message, u *sdk.User) error {
+//Import import or reuser the provided environment, it returns the events of the audit to publish once committed
+func Import(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, msgChan chan<- sdk.Message, u *sdk.User) ([]sdk.EventAudit, error) {
 	exists, err := Exists(db, proj.Key, env.Name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	//If environment exists, reload it
@@ -24,11 +24,11 @@ func Import(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, msgCha
 		//Reload environment
 		e, err := LoadEnvironmentByName(db, proj.Key, env.Name)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		*env = *e
 
-		return nil
+		return nil, nil
 	}
 
 	//Else create it
@@ -36,7 +36,7 @@ func Import(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, msgCha
 	env.ProjectKey = proj.Key
 	if err := InsertEnvironment(db, env); err != nil {
 		log.Warning("environment.Exists> Unable to create env %s on project %s(%d) : %s", env.Name, env.ProjectKey, env.ProjectID, err)
-		return err
+		return nil, err
 	}
 
 	//If no GroupPermission provided, inherit from project
@@ -45,47 +45,56 @@ func Import(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, msgCha
 	}
 	if err := group.InsertGroupsInEnvironment(db, env.EnvironmentGroups, env.ID); err != nil {
 		log.Warning("environment.Import> unable to import groups in environment %s, %s", env.Name, err)
-		return err
+		return nil, err
 	}
 
 	//Insert all variables
+	evts := []sdk.EventAudit{}
 	for i := range env.Variable {
-		if err := InsertVariable(db, env.ID, &env.Variable[i], u); err != nil {
-			return err
+		evt, err := InsertVariable(db, env.ID, &env.Variable[i], u)
+		if err != nil {
+			return nil, err
 		}
+		evts = append(evts, evt)
 	}
 
 	if msgChan != nil {
 		msgChan <- sdk.NewMessage(sdk.MsgEnvironmentCreated, env.Name)
 	}
 
-	return nil
+	return evts, nil
 }
 
-//ImportInto import variables and groups on an existing environment
-func ImportInto(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, into *sdk.Environment, msgChan chan<- sdk.Message, u *sdk.User) error {
+//ImportInto import variables and groups on an existing environment, it returns the events of the audit to publish once committed
+func ImportInto(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, into *sdk.Environment, msgChan chan<- sdk.Message, u *sdk.User) ([]sdk.EventAudit, error) {
 
 	if len(into.EnvironmentGroups) == 0 {
 		if err := loadGroupByEnvironment(db, into); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	evts := []sdk.EventAudit{}
+
 	var updateVar = func(v *sdk.Variable) {
 		log.Debug("ImportInto> Updating var %s", v.Name)
-		if err := UpdateVariable(db, into.ID, v, u); err != nil {
+		evt, err := UpdateVariable(db, into.ID, v, u)
+		if err != nil {
 			msgChan <- sdk.NewMessage(sdk.MsgEnvironmentVariableCannotBeUpdated, v.Name, into.Name, err)
 			return
 		}
+		evts = append(evts, evt)
 		msgChan <- sdk.NewMessage(sdk.MsgEnvironmentVariableUpdated, v.Name, into.Name)
 	}
 
 	var insertVar = func(v *sdk.Variable) {
 		log.Debug("ImportInto> Creating var %s", v.Name)
-		if err := InsertVariable(db, into.ID, v, u); err != nil {
+		evt, err := InsertVariable(db, into.ID, v, u)
+		if err != nil {
 			msgChan <- sdk.NewMessage(sdk.MsgEnvironmentVariableCannotBeCreated, v.Name, into.Name, err)
 			return
 		}
+		evts = append(evts, evt)
 		msgChan <- sdk.NewMessage(sdk.MsgEnvironmentVariableCreated, v.Name, into.Name)
 	}
 
@@ -169,5 +178,5 @@ func ImportInto(db gorp.SqlExecutor, proj *sdk.Project, env *sdk.Environment, in
 
 	log.Debug("ImportInto> Done")
 
-	return nil
+	return evts, nil
 }
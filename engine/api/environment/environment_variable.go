@@ -9,6 +9,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/lib/pq"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 )
@@ -280,14 +281,14 @@ func GetAllVariableByID(db gorp.SqlExecutor, environmentID int64, args ...GetAll
 	return variables, err
 }
 
-// InsertVariable Insert a new variable in the given environment
-func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Variable, u *sdk.User) error {
+// InsertVariable Insert a new variable in the given environment, it returns the event of the audit to publish once committed
+func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	query := `INSERT INTO environment_variable(environment_id, name, value, cipher_value, type)
 		  VALUES($1, $2, $3, $4, $5) RETURNING id`
 
 	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
 	if err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot encrypt secret %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot encrypt secret %s", variable.Name)
 	}
 
 	err = db.QueryRow(query, environmentID, variable.Name, clear, cipher, string(variable.Type)).Scan(&variable.ID)
@@ -295,7 +296,7 @@ func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Vari
 		if errPG, ok := err.(*pq.Error); ok && errPG.Code == "23505" {
 			err = sdk.ErrVariableExists
 		}
-		return sdk.WrapError(err, "InsertVariable> Cannot insert variable %s in db", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot insert variable %s in db", variable.Name)
 	}
 
 	eva := &sdk.EnvironmentVariableAudit{
@@ -306,18 +307,22 @@ func InsertVariable(db gorp.SqlExecutor, environmentID int64, variable *sdk.Vari
 		VariableID:    variable.ID,
 		Versionned:    time.Now(),
 	}
+	evt, err := auditVariable(db, environmentID, u, nil, variable)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot audit variable %s", variable.Name)
+	}
 	if err := insertAudit(db, eva); err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot add audit")
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot add audit")
 	}
-	return nil
+	return evt, nil
 }
 
-// UpdateVariable Update a variable in the given environment
-func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u *sdk.User) error {
+// UpdateVariable Update a variable in the given environment, it returns the event of the audit to publish once committed
+func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	varValue := variable.Value
 	varBefore, errV := GetVariableByID(db, envID, variable.ID, WithClearPassword())
 	if errV != nil {
-		return sdk.WrapError(errV, "UpdateVariable> Cannot load variable %d", variable.ID)
+		return sdk.EventAudit{}, sdk.WrapError(errV, "UpdateVariable> Cannot load variable %d", variable.ID)
 	}
 
 	// If we are updating a batch of variables, some of them might be secrets, we don't want to crush the value
@@ -327,7 +332,7 @@ func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u
 
 	clear, cipher, err := secret.EncryptS(variable.Type, varValue)
 	if err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot encrypt secret")
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot encrypt secret")
 	}
 
 	query := `UPDATE environment_variable
@@ -338,14 +343,14 @@ func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u
 		if errPG, ok := err.(*pq.Error); ok && errPG.Code == "23505" {
 			err = sdk.ErrVariableExists
 		}
-		return sdk.WrapError(err, "Cannot update variable %s in db", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "Cannot update variable %s in db", variable.Name)
 	}
 	rowAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return sdk.EventAudit{}, err
 	}
 	if rowAffected == 0 {
-		return sdk.ErrNoVariable
+		return sdk.EventAudit{}, sdk.ErrNoVariable
 	}
 
 	eva := &sdk.EnvironmentVariableAudit{
@@ -357,23 +362,27 @@ func UpdateVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u
 		VariableID:     variable.ID,
 		Versionned:     time.Now(),
 	}
+	evt, err := auditVariable(db, envID, u, &varBefore, variable)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot audit variable %s", variable.Name)
+	}
 	if err := insertAudit(db, eva); err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot add audit")
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot add audit")
 	}
-	return nil
+	return evt, nil
 }
 
-// DeleteVariable Delete a variable from the given pipeline
-func DeleteVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u *sdk.User) error {
+// DeleteVariable Delete a variable from the given pipeline, it returns the event of the audit to publish once committed
+func DeleteVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	query := `DELETE FROM environment_variable
 	          WHERE environment_variable.environment_id = $1 AND environment_variable.name = $2`
 	result, err := db.Exec(query, envID, variable.Name)
 	rowAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return sdk.EventAudit{}, err
 	}
 	if rowAffected == 0 {
-		return sdk.ErrNoVariable
+		return sdk.EventAudit{}, sdk.ErrNoVariable
 	}
 
 	eva := &sdk.EnvironmentVariableAudit{
@@ -384,10 +393,14 @@ func DeleteVariable(db gorp.SqlExecutor, envID int64, variable *sdk.Variable, u
 		VariableID:     variable.ID,
 		Versionned:     time.Now(),
 	}
+	evt, err := auditVariable(db, envID, u, variable, nil)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot audit variable %s", variable.Name)
+	}
 	if err := insertAudit(db, eva); err != nil {
-		return sdk.WrapError(err, "DeleteVariable> Cannot add audit")
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot add audit")
 	}
-	return nil
+	return evt, nil
 }
 
 // DeleteAllVariable Delete all variables from the given pipeline
@@ -401,6 +414,26 @@ func DeleteAllVariable(db gorp.SqlExecutor, environmentID int64) error {
 	return nil
 }
 
+// auditVariable records the change of a variable in the audit log
+func auditVariable(db gorp.SqlExecutor, envID int64, u *sdk.User, before, after *sdk.Variable) (sdk.EventAudit, error) {
+	name := after
+	if name == nil {
+		name = before
+	}
+
+	var envName, projectKey string
+	query := `SELECT environment.name, project.projectkey
+	          FROM environment
+	          JOIN project ON project.id = environment.project_id
+	          WHERE environment.id = $1`
+	if err := db.QueryRow(query, envID).Scan(&envName, &projectKey); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "auditVariable> Cannot load environment %d", envID)
+	}
+
+	e := audit.Entity{ProjectKey: projectKey, Type: sdk.AuditEntityVariable, Name: "environment/" + envName + "/" + name.Name}
+	return audit.Add(db, u, e, before, after)
+}
+
 // insertAudit Insert an audit for an environment variable
 func insertAudit(db gorp.SqlExecutor, eva *sdk.EnvironmentVariableAudit) error {
 	dbEnvVarAudit := dbEnvironmentVariableAudit(*eva)
@@ -52,11 +52,13 @@ func TestImportInto_Variable(t *testing.T) {
 		Value: "value2",
 	}
 
-	test.NoError(t, environment.InsertVariable(db, env.ID, &v0, u))
-	test.NoError(t, environment.InsertVariable(db, env.ID, &v1, u))
-	test.NoError(t, environment.InsertVariable(db, env.ID, &v2, u))
+	_, err := environment.InsertVariable(db, env.ID, &v0, u)
+	test.NoError(t, err)
+	_, err = environment.InsertVariable(db, env.ID, &v1, u)
+	test.NoError(t, err)
+	_, err = environment.InsertVariable(db, env.ID, &v2, u)
+	test.NoError(t, err)
 
-	var err error
 	env.Variable, err = environment.GetAllVariableByID(db, env.ID)
 	test.NoError(t, err)
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
@@ -60,10 +61,20 @@ func (api *API) updateGroupRoleOnEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		role, errR := group.LoadRoleInEnvironment(tx, env.ID, g.ID)
+		if errR != nil {
+			return sdk.WrapError(errR, "updateGroupRoleOnEnvironmentHandler> Cannot load role of group %s in environment %s", groupName, envName)
+		}
+
 		if err := group.UpdateGroupRoleInEnvironment(tx, key, envName, groupName, groupEnvironment.Permission); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler: Cannot update permission for group %s in environment %s", groupName, envName)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "environment/"+envName, groupName, role, groupEnvironment.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler> Cannot audit permission")
+		}
+
 		if err := environment.UpdateLastModified(tx, api.Cache, getUser(ctx), env); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler: Cannot update environment last modified date")
 		}
@@ -75,6 +86,7 @@ func (api *API) updateGroupRoleOnEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnEnvironmentHandler> Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		envUpdated, errE := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
 		if errE != nil {
@@ -108,6 +120,8 @@ func (api *API) addGroupsInEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		for _, gp := range groupPermission {
 			g, errL := group.LoadGroup(tx, gp.Group.Name)
 			if errL != nil {
@@ -126,6 +140,12 @@ func (api *API) addGroupsInEnvironmentHandler() Handler {
 			if err := group.InsertGroupInEnvironment(tx, env.ID, g.ID, gp.Permission); err != nil {
 				return sdk.WrapError(err, "addGroupsInEnvironmentHandler: Cannot add group %s in environment %s", g.Name, env.Name)
 			}
+
+			evtsPerm, err := api.auditPermission(ctx, tx, key, "environment/"+envName, g.Name, 0, gp.Permission)
+			if err != nil {
+				return sdk.WrapError(err, "addGroupsInEnvironmentHandler> Cannot audit permission")
+			}
+			evts = append(evts, evtsPerm...)
 		}
 
 		// Update last modified on environment
@@ -149,6 +169,7 @@ func (api *API) addGroupsInEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addGroupsInEnvironmentHandler: Cannot commit transaction")
 		}
+		audit.Publish(evts...)
 
 		envUpdated, errL := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
 		if errL != nil {
@@ -199,6 +220,12 @@ func (api *API) addGroupInEnvironmentHandler() Handler {
 			return err
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, api.mustDB(), key, "environment/"+envName, g.Name, 0, groupPermission.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "addGroupInEnvironmentHandler> Cannot audit permission")
+		}
+		audit.Publish(evtsPerm...)
+
 		return nil
 	}
 }
@@ -227,10 +254,25 @@ func (api *API) deleteGroupFromEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		g, errG := group.LoadGroup(tx, groupName)
+		if errG != nil {
+			return sdk.WrapError(errG, "deleteGroupFromEnvironmentHandler: Cannot load group %s", groupName)
+		}
+
+		role, errR := group.LoadRoleInEnvironment(tx, env.ID, g.ID)
+		if errR != nil {
+			return sdk.WrapError(errR, "deleteGroupFromEnvironmentHandler: Cannot load role of group %s in environment %s", groupName, envName)
+		}
+
 		if err := group.DeleteGroupFromEnvironment(tx, proj.Key, envName, groupName); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromEnvironmentHandler: Cannot delete group %s from pipeline %s", groupName, envName)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "environment/"+envName, groupName, role, 0)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromEnvironmentHandler: Cannot audit permission")
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), proj); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromEnvironmentHandler: Cannot update project last modified date")
 		}
@@ -242,6 +284,7 @@ func (api *API) deleteGroupFromEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(errT, "deleteGroupFromEnvironmentHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		return nil
 	}
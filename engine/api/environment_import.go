@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/hcl"
 	"gopkg.in/yaml.v2"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/project"
@@ -60,6 +61,9 @@ func (api *API) importNewEnvironmentHandler() Handler {
 		}
 
 		env := payload.Environment()
+		if err := decryptVariables(key, env.Variable); err != nil {
+			return sdk.WrapError(err, "importNewEnvironmentHandler> Unable to decrypt secrets of environment %s", env.Name)
+		}
 		for i := range env.EnvironmentGroups {
 			eg := &env.EnvironmentGroups[i]
 			g, err := group.LoadGroup(api.mustDB(), eg.Group.Name)
@@ -93,7 +97,8 @@ func (api *API) importNewEnvironmentHandler() Handler {
 
 		defer tx.Rollback()
 
-		if err := environment.Import(api.mustDB(), proj, env, msgChan, getUser(ctx)); err != nil {
+		evts, err := environment.Import(api.mustDB(), proj, env, msgChan, getUser(ctx))
+		if err != nil {
 			log.Warning("importNewEnvironmentHandler> Error on import : %s", err)
 			return err
 		}
@@ -120,6 +125,7 @@ func (api *API) importNewEnvironmentHandler() Handler {
 			log.Warning("importNewEnvironmentHandler> Cannot commit transaction: %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		return WriteJSON(w, r, msgListString, http.StatusOK)
 	}
@@ -187,6 +193,9 @@ func (api *API) importIntoEnvironmentHandler() Handler {
 		}
 
 		newEnv := payload.Environment()
+		if err := decryptVariables(key, newEnv.Variable); err != nil {
+			return sdk.WrapError(err, "importIntoEnvironmentHandler> Unable to decrypt secrets of environment %s", newEnv.Name)
+		}
 
 		for i := range newEnv.EnvironmentGroups {
 			eg := &newEnv.EnvironmentGroups[i]
@@ -211,7 +220,8 @@ func (api *API) importIntoEnvironmentHandler() Handler {
 			}
 		}()
 
-		if err := environment.ImportInto(tx, proj, newEnv, env, msgChan, getUser(ctx)); err != nil {
+		evts, err := environment.ImportInto(tx, proj, newEnv, env, msgChan, getUser(ctx))
+		if err != nil {
 			log.Warning("importIntoEnvironmentHandler> Error on import : %s", err)
 			return err
 		}
@@ -237,6 +247,7 @@ func (api *API) importIntoEnvironmentHandler() Handler {
 			log.Warning("importIntoEnvironmentHandler> Cannot commit transaction: %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, proj); err != nil {
 			log.Warning("importIntoEnvironmentHandler> Cannot check warnings: %s\n", err)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/project"
@@ -54,11 +55,17 @@ func (api *API) deleteKeyInEnvironmentHandler() Handler {
 			return sdk.WrapError(errT, "v> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		evts := []sdk.EventAudit{}
 		for _, k := range env.Keys {
 			if k.Name == keyName {
 				if err := environment.DeleteEnvironmentKey(tx, env.ID, keyName); err != nil {
 					return sdk.WrapError(err, "deleteKeyInEnvironmentHandler> Cannot delete key %s", k.Name)
 				}
+				evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityKey, Name: "environment/" + envName + "/" + k.Name}, k, nil)
+				if err != nil {
+					return sdk.WrapError(err, "deleteKeyInEnvironmentHandler> Cannot audit key %s", k.Name)
+				}
+				evts = append(evts, evt)
 				if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 					return sdk.WrapError(err, "deleteKeyInEnvironmentHandler> Cannot update application last modified date")
 				}
@@ -68,6 +75,7 @@ func (api *API) deleteKeyInEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteKeyInEnvironmentHandler> Cannot commit transaction")
 		}
+		audit.Publish(evts...)
 
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -132,6 +140,11 @@ func (api *API) addKeyInEnvironmentHandler() Handler {
 			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot insert application key")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityKey, Name: "environment/" + envName + "/" + newKey.Name}, nil, newKey)
+		if err != nil {
+			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot audit key %s", newKey.Name)
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot update project last modified date")
 		}
@@ -139,6 +152,7 @@ func (api *API) addKeyInEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addKeyInEnvironmentHandler> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		return WriteJSON(w, r, newKey, http.StatusOK)
 	}
@@ -271,7 +271,8 @@ func Test_cloneEnvironmentHandler(t *testing.T) {
 		Type:  sdk.StringVariable,
 		Value: "val1",
 	}
-	test.NoError(t, environment.InsertVariable(api.mustDB(), env.ID, v, u))
+	_, err := environment.InsertVariable(api.mustDB(), env.ID, v, u)
+	test.NoError(t, err)
 
 	vars := map[string]string{
 		"key": proj.Key,
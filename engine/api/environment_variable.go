@@ -8,7 +8,9 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/impact"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
 	"github.com/ovh/cds/engine/api/secret"
@@ -71,6 +73,8 @@ func (api *API) restoreEnvironmentAuditHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		if err := environment.DeleteAllVariable(tx, env.ID); err != nil {
 			log.Warning("restoreEnvironmentAuditHandler> Cannot delete variables on environments for update: %s\n", err)
 			return err
@@ -86,10 +90,12 @@ func (api *API) restoreEnvironmentAuditHandler() Handler {
 				}
 				varEnv.Value = string(value)
 			}
-			if err := environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx)); err != nil {
+			evt, err := environment.InsertVariable(tx, env.ID, varEnv, getUser(ctx))
+			if err != nil {
 				log.Warning("restoreEnvironmentAuditHandler> Cannot insert variables on environments: %s\n", err)
 				return err
 			}
+			evts = append(evts, evt)
 		}
 
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
@@ -101,6 +107,7 @@ func (api *API) restoreEnvironmentAuditHandler() Handler {
 			log.Warning("restoreEnvironmentAuditHandler: Cannot commit transaction:  %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
 			log.Warning("restoreEnvironmentAuditHandler: Cannot check warnings: %s\n", err)
@@ -206,6 +213,14 @@ func (api *API) deleteVariableFromEnvironmentHandler() Handler {
 			return sdk.WrapError(errEnv, "deleteVariableFromEnvironmentHandler: Cannot load environment %s", envName)
 		}
 
+		impacts, errImpact := impact.Variable(api.mustDB(), api.Cache, getUser(ctx), key, "cds.env."+varName)
+		if errImpact != nil {
+			return sdk.WrapError(errImpact, "deleteVariableFromEnvironmentHandler> Cannot load impact of variable %s", varName)
+		}
+		if err := checkImpacts(r, impacts); err != nil {
+			return sdk.WrapError(err, "deleteVariableFromEnvironmentHandler> Variable %s is used by %d entities", varName, len(impacts))
+		}
+
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
 			return sdk.WrapError(errBegin, "deleteVariableFromEnvironmentHandler: Cannot start transaction")
@@ -218,7 +233,8 @@ func (api *API) deleteVariableFromEnvironmentHandler() Handler {
 			return sdk.WrapError(errV, "deleteVariableFromEnvironmentHandler> Cannot load variable %s", varName)
 		}
 
-		if err := environment.DeleteVariable(tx, env.ID, varToDelete, getUser(ctx)); err != nil {
+		evt, err := environment.DeleteVariable(tx, env.ID, varToDelete, getUser(ctx))
+		if err != nil {
 			return sdk.WrapError(err, "deleteVariableFromEnvironmentHandler: Cannot delete %s", varName)
 		}
 
@@ -233,6 +249,7 @@ func (api *API) deleteVariableFromEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteVariableFromEnvironmentHandler: Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		apps, errApps := application.LoadAll(api.mustDB(), api.Cache, p.Key, getUser(ctx), application.LoadOptions.WithVariables)
 		if errApps != nil {
@@ -282,7 +299,8 @@ func (api *API) updateVariableInEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
-		if err := environment.UpdateVariable(api.mustDB(), env.ID, &newVar, getUser(ctx)); err != nil {
+		evt, err := environment.UpdateVariable(api.mustDB(), env.ID, &newVar, getUser(ctx))
+		if err != nil {
 			return sdk.WrapError(err, "updateVariableInEnvironmentHandler: Cannot update variable %s for environment %s", varName, envName)
 		}
 
@@ -297,6 +315,7 @@ func (api *API) updateVariableInEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateVariableInEnvironmentHandler: Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
 			return sdk.WrapError(err, "updateVariableInEnvironmentHandler: Cannot check warnings")
@@ -354,12 +373,15 @@ func (api *API) addVariableInEnvironmentHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		var evts []sdk.EventAudit
 		var errInsert error
 		switch newVar.Type {
 		case sdk.KeyVariable:
-			errInsert = environment.AddKeyPairToEnvironment(tx, env.ID, newVar.Name, getUser(ctx))
+			evts, errInsert = environment.AddKeyPairToEnvironment(tx, env.ID, newVar.Name, getUser(ctx))
 		default:
-			errInsert = environment.InsertVariable(tx, env.ID, &newVar, getUser(ctx))
+			var evt sdk.EventAudit
+			evt, errInsert = environment.InsertVariable(tx, env.ID, &newVar, getUser(ctx))
+			evts = append(evts, evt)
 		}
 		if errInsert != nil {
 			return sdk.WrapError(errInsert, "addVariableInEnvironmentHandler: Cannot add variable %s in environment %s", varName, envName)
@@ -375,6 +397,7 @@ func (api *API) addVariableInEnvironmentHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addVariableInEnvironmentHandler: cannot commit tx")
 		}
+		audit.Publish(evts...)
 
 		if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
 			return sdk.WrapError(err, "addVariableInEnvironmentHandler: Cannot check warnings")
@@ -104,7 +104,7 @@ func TestUpdateVariableInEnvironmentHandler(t *testing.T) {
 		Value: "bar",
 		Type:  sdk.StringVariable,
 	}
-	if err := environment.InsertVariable(api.mustDB(), env.ID, &v, u); err != nil {
+	if _, err := environment.InsertVariable(api.mustDB(), env.ID, &v, u); err != nil {
 		t.Fail()
 		return
 	}
@@ -173,7 +173,7 @@ func TestDeleteVariableFromEnvironmentHandler(t *testing.T) {
 		Value: "bar",
 		Type:  sdk.StringVariable,
 	}
-	if err := environment.InsertVariable(api.mustDB(), env.ID, &v, u); err != nil {
+	if _, err := environment.InsertVariable(api.mustDB(), env.ID, &v, u); err != nil {
 		t.Fail()
 		return
 	}
@@ -235,7 +235,7 @@ func TestGetVariablesInEnvironmentHandler(t *testing.T) {
 		Value: "bar",
 		Type:  sdk.StringVariable,
 	}
-	if err := environment.InsertVariable(api.mustDB(), env.ID, &v, u); err != nil {
+	if _, err := environment.InsertVariable(api.mustDB(), env.ID, &v, u); err != nil {
 		t.Fail()
 		return
 	}
@@ -292,7 +292,7 @@ func Test_getVariableAuditInEnvironmentHandler(t *testing.T) {
 		Type:  "string",
 		Value: "bar",
 	}
-	if err := environment.InsertVariable(api.mustDB(), e.ID, &v, u); err != nil {
+	if _, err := environment.InsertVariable(api.mustDB(), e.ID, &v, u); err != nil {
 		t.Fatal(err)
 	}
 
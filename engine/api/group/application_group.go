@@ -79,3 +79,8 @@ func deleteGroupApplicationByGroup(db gorp.SqlExecutor, group *sdk.Group) error
 	_, err := db.Exec(query, group.ID)
 	return err
 }
+
+// LoadRoleInApplication returns the role of a group on a application, 0 if the group is not attached to the application
+func LoadRoleInApplication(db gorp.SqlExecutor, applicationID, groupID int64) (int, error) {
+	return loadRole(db, `SELECT role FROM application_group WHERE application_id = $1 AND group_id = $2`, applicationID, groupID)
+}
@@ -99,3 +99,8 @@ func deleteGroupEnvironmentByGroup(db gorp.SqlExecutor, group *sdk.Group) error
 	_, err := db.Exec(query, group.ID)
 	return err
 }
+
+// LoadRoleInEnvironment returns the role of a group on a environment, 0 if the group is not attached to the environment
+func LoadRoleInEnvironment(db gorp.SqlExecutor, environmentID, groupID int64) (int, error) {
+	return loadRole(db, `SELECT role FROM environment_group WHERE environment_id = $1 AND group_id = $2`, environmentID, groupID)
+}
@@ -384,3 +384,14 @@ func RemoveUserGroupAdmin(db gorp.SqlExecutor, groupID int64, userID int64) erro
 	}
 	return nil
 }
+
+func loadRole(db gorp.SqlExecutor, query string, id, groupID int64) (int, error) {
+	var role int
+	if err := db.QueryRow(query, id, groupID).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return role, nil
+}
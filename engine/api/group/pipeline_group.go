@@ -87,3 +87,8 @@ func deleteGroupPipelineByGroup(db gorp.SqlExecutor, group *sdk.Group) error {
 	_, err := db.Exec(query, group.ID)
 	return err
 }
+
+// LoadRoleInPipeline returns the role of a group on a pipeline, 0 if the group is not attached to the pipeline
+func LoadRoleInPipeline(db gorp.SqlExecutor, pipelineID, groupID int64) (int, error) {
+	return loadRole(db, `SELECT role FROM pipeline_group WHERE pipeline_id = $1 AND group_id = $2`, pipelineID, groupID)
+}
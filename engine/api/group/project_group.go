@@ -76,3 +76,8 @@ func CheckGroupInProject(db gorp.SqlExecutor, projectID, groupID int64) (bool, e
 	}
 	return false, nil
 }
+
+// LoadRoleInProject returns the role of a group on a project, 0 if the group is not attached to the project
+func LoadRoleInProject(db gorp.SqlExecutor, projectID, groupID int64) (int, error) {
+	return loadRole(db, `SELECT role FROM project_group WHERE project_id = $1 AND group_id = $2`, projectID, groupID)
+}
@@ -0,0 +1,66 @@
+package group
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadQuota loads the quota of a group, it returns nil if the group has no quota
+func LoadQuota(db gorp.SqlExecutor, groupID int64) (*sdk.GroupQuota, error) {
+	query := `SELECT group_id, max_workers, cpu_minutes_per_day, models FROM group_quota WHERE group_id = $1`
+	return loadQuota(db, query, groupID)
+}
+
+// LoadAndLockQuota loads the quota of a group and locks it until the end of the transaction, it returns
+// nil if the group has no quota
+func LoadAndLockQuota(db gorp.SqlExecutor, groupID int64) (*sdk.GroupQuota, error) {
+	query := `SELECT group_id, max_workers, cpu_minutes_per_day, models FROM group_quota WHERE group_id = $1 FOR UPDATE`
+	return loadQuota(db, query, groupID)
+}
+
+func loadQuota(db gorp.SqlExecutor, query string, groupID int64) (*sdk.GroupQuota, error) {
+	q := &sdk.GroupQuota{}
+	var models []byte
+	if err := db.QueryRow(query, groupID).Scan(&q.GroupID, &q.MaxWorkers, &q.CPUMinutesPerDay, &models); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadQuota> Unable to load quota of group %d", groupID)
+	}
+	if len(models) > 0 {
+		if err := json.Unmarshal(models, &q.Models); err != nil {
+			return nil, sdk.WrapError(err, "loadQuota> Unable to unmarshal models quotas of group %d", groupID)
+		}
+	}
+	return q, nil
+}
+
+// InsertOrUpdateQuota saves the quota of a group
+func InsertOrUpdateQuota(db gorp.SqlExecutor, q *sdk.GroupQuota) error {
+	models, err := json.Marshal(q.Models)
+	if err != nil {
+		return sdk.WrapError(err, "InsertOrUpdateQuota> Unable to marshal models quotas")
+	}
+
+	if err := DeleteQuota(db, q.GroupID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO group_quota (group_id, max_workers, cpu_minutes_per_day, models) VALUES ($1, $2, $3, $4)`
+	if _, err := db.Exec(query, q.GroupID, q.MaxWorkers, q.CPUMinutesPerDay, models); err != nil {
+		return sdk.WrapError(err, "InsertOrUpdateQuota> Unable to save quota of group %d", q.GroupID)
+	}
+	return nil
+}
+
+// DeleteQuota removes the quota of a group
+func DeleteQuota(db gorp.SqlExecutor, groupID int64) error {
+	if _, err := db.Exec(`DELETE FROM group_quota WHERE group_id = $1`, groupID); err != nil {
+		return sdk.WrapError(err, "DeleteQuota> Unable to delete quota of group %d", groupID)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getGroupQuotaHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permGroupName"]
+
+		g, errl := group.LoadGroup(api.mustDB(), name)
+		if errl != nil {
+			return sdk.WrapError(errl, "getGroupQuotaHandler> Cannot load group %s", name)
+		}
+
+		q, errq := group.LoadQuota(api.mustDB(), g.ID)
+		if errq != nil {
+			return sdk.WrapError(errq, "getGroupQuotaHandler> Cannot load quota of group %s", name)
+		}
+		if q == nil {
+			q = &sdk.GroupQuota{GroupID: g.ID}
+		}
+
+		usage, erru := workflow.GroupQuotaUsage(api.mustDB(), api.Cache, g, q)
+		if erru != nil {
+			return sdk.WrapError(erru, "getGroupQuotaHandler> Cannot compute usage of group %s", name)
+		}
+		return WriteJSON(w, r, usage, http.StatusOK)
+	}
+}
+
+func (api *API) putGroupQuotaHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permGroupName"]
+
+		var q sdk.GroupQuota
+		if err := UnmarshalBody(r, &q); err != nil {
+			return sdk.WrapError(err, "putGroupQuotaHandler> Cannot unmarshal quota")
+		}
+		if q.MaxWorkers < 0 || q.CPUMinutesPerDay < 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "putGroupQuotaHandler> Quotas cannot be negative")
+		}
+		for _, m := range q.Models {
+			if m.Model == "" || m.MaxWorkers < 0 {
+				return sdk.WrapError(sdk.ErrWrongRequest, "putGroupQuotaHandler> Invalid quota for model %s", m.Model)
+			}
+		}
+
+		g, errl := group.LoadGroup(api.mustDB(), name)
+		if errl != nil {
+			return sdk.WrapError(errl, "putGroupQuotaHandler> Cannot load group %s", name)
+		}
+		q.GroupID = g.ID
+
+		if err := group.InsertOrUpdateQuota(api.mustDB(), &q); err != nil {
+			return sdk.WrapError(err, "putGroupQuotaHandler> Cannot save quota of group %s", name)
+		}
+		return WriteJSON(w, r, q, http.StatusOK)
+	}
+}
+
+func (api *API) deleteGroupQuotaHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permGroupName"]
+
+		g, errl := group.LoadGroup(api.mustDB(), name)
+		if errl != nil {
+			return sdk.WrapError(errl, "deleteGroupQuotaHandler> Cannot load group %s", name)
+		}
+
+		if err := group.DeleteQuota(api.mustDB(), g.ID); err != nil {
+			return sdk.WrapError(err, "deleteGroupQuotaHandler> Cannot delete quota of group %s", name)
+		}
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/hatchery"
+	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -48,6 +50,71 @@ func (api *API) refreshHatcheryHandler() Handler {
 		if err := hatchery.RefreshHatchery(api.mustDB(), hatcheryID); err != nil {
 			return sdk.WrapError(err, "refreshHatcheryHandler> cannot refresh last beat of %s", hatcheryID)
 		}
+
+		// The hatchery gets its maintenance mode with its heartbeat
+		if h := getHatchery(ctx); h != nil {
+			return WriteJSON(w, r, h, http.StatusOK)
+		}
+		return nil
+	}
+}
+
+func (api *API) postHatcheryMaintenanceHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "postHatcheryMaintenanceHandler> unable to start transaction")
+		}
+		defer tx.Rollback()
+
+		ids, workerIDs, err := hatchery.SetMaintenance(tx, name, true)
+		if err != nil {
+			return sdk.WrapError(err, "postHatcheryMaintenanceHandler> cannot set hatchery %s in maintenance", name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postHatcheryMaintenanceHandler> unable to commit transaction")
+		}
+		api.resetWorkersCache(workerIDs)
+
+		// The jobs booked by the hatchery and not spawned yet are booked by other hatcheries
+		nPb, err := pipeline.UnbookPipelineBuildJobs(api.mustDB(), ids)
+		if err != nil {
+			return sdk.WrapError(err, "postHatcheryMaintenanceHandler> cannot release jobs booked by hatchery %s", name)
+		}
+		nWf, err := workflow.UnbookNodeJobRuns(api.mustDB(), api.Cache, ids)
+		if err != nil {
+			return sdk.WrapError(err, "postHatcheryMaintenanceHandler> cannot release jobs booked by hatchery %s", name)
+		}
+
+		log.Info("postHatcheryMaintenanceHandler> hatchery %s in maintenance, %d workers drained, %d jobs released", name, len(workerIDs), nPb+nWf)
+		return nil
+	}
+}
+
+func (api *API) deleteHatcheryMaintenanceHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := mux.Vars(r)["name"]
+
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "deleteHatcheryMaintenanceHandler> unable to start transaction")
+		}
+		defer tx.Rollback()
+
+		_, workerIDs, err := hatchery.SetMaintenance(tx, name, false)
+		if err != nil {
+			return sdk.WrapError(err, "deleteHatcheryMaintenanceHandler> cannot end maintenance of hatchery %s", name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "deleteHatcheryMaintenanceHandler> unable to commit transaction")
+		}
+		api.resetWorkersCache(workerIDs)
+
+		log.Info("deleteHatcheryMaintenanceHandler> end of maintenance of hatchery %s", name)
 		return nil
 	}
 }
@@ -29,8 +29,9 @@ func InsertHatchery(dbmap *gorp.DbMap, h *sdk.Hatchery) error {
 		return errg
 	}
 
-	query := `INSERT INTO hatchery (name, group_id, last_beat, uid) VALUES ($1, $2, NOW(), $3) RETURNING id`
-	if err := tx.QueryRow(query, h.Name, h.GroupID, h.UID).Scan(&h.ID); err != nil {
+	// a hatchery registered back keeps its maintenance mode
+	query := `INSERT INTO hatchery (name, group_id, last_beat, uid, maintenance) VALUES ($1, $2, NOW(), $3, $4) RETURNING id`
+	if err := tx.QueryRow(query, h.Name, h.GroupID, h.UID, h.Maintenance).Scan(&h.ID); err != nil {
 		return err
 	}
 
@@ -118,14 +119,14 @@ func LoadDeadHatcheries(db gorp.SqlExecutor, timeout float64) ([]sdk.Hatchery, e
 
 // LoadHatchery fetch hatchery info from database given UID
 func LoadHatchery(db gorp.SqlExecutor, uid string) (*sdk.Hatchery, error) {
-	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id
+	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id, maintenance
 							FROM hatchery
 							LEFT JOIN hatchery_model ON hatchery_model.hatchery_id = hatchery.id
 							WHERE uid = $1`
 
 	var h sdk.Hatchery
 	var wmID sql.NullInt64
-	err := db.QueryRow(query, uid).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID)
+	err := db.QueryRow(query, uid).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID, &h.Maintenance)
 	if err != nil {
 		return nil, err
 	}
@@ -139,14 +140,14 @@ func LoadHatchery(db gorp.SqlExecutor, uid string) (*sdk.Hatchery, error) {
 
 // LoadHatcheryByName fetch hatchery info from database given name
 func LoadHatcheryByName(db gorp.SqlExecutor, name string) (*sdk.Hatchery, error) {
-	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id
+	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id, maintenance
 			FROM hatchery
 			LEFT JOIN hatchery_model ON hatchery_model.hatchery_id = hatchery.id
 			WHERE hatchery.name = $1`
 
 	var h sdk.Hatchery
 	var wmID sql.NullInt64
-	err := db.QueryRow(query, name).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID)
+	err := db.QueryRow(query, name).Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID, &h.Maintenance)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrNoHatchery
@@ -165,7 +166,7 @@ func LoadHatcheryByName(db gorp.SqlExecutor, name string) (*sdk.Hatchery, error)
 func LoadHatcheries(db gorp.SqlExecutor) ([]sdk.Hatchery, error) {
 	var hatcheries []sdk.Hatchery
 
-	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id
+	query := `SELECT id, uid, name, last_beat, group_id, worker_model_id, maintenance
 							FROM hatchery
 							LEFT JOIN hatchery_model ON hatchery_model.hatchery_id = hatchery.id
 							LIMIT 10000`
@@ -178,7 +179,7 @@ func LoadHatcheries(db gorp.SqlExecutor) ([]sdk.Hatchery, error) {
 	var wmID sql.NullInt64
 	for rows.Next() {
 		var h sdk.Hatchery
-		err = rows.Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID)
+		err = rows.Scan(&h.ID, &h.UID, &h.Name, &h.LastBeat, &h.GroupID, &wmID, &h.Maintenance)
 		if err != nil {
 			return nil, err
 		}
@@ -211,6 +212,38 @@ func RefreshHatchery(db gorp.SqlExecutor, hatchID string) error {
 	return nil
 }
 
+// SetMaintenance enables or disables the maintenance mode of the hatcheries registered with the given name,
+// the workers of these hatcheries are set in the same mode. It returns the ids of the hatcheries and of their workers
+func SetMaintenance(db gorp.SqlExecutor, name string, maintenance bool) ([]int64, []string, error) {
+	rows, err := db.Query(`UPDATE hatchery SET maintenance = $1 WHERE name = $2 RETURNING id`, maintenance, name)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "SetMaintenance> Unable to update hatchery %s", name)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, nil, sdk.WrapError(err, "SetMaintenance> Unable to scan hatchery id")
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil, sdk.ErrNoHatchery
+	}
+
+	var workerIDs []string
+	for _, id := range ids {
+		wIDs, err := worker.SetMaintenanceByHatchery(db, id, maintenance)
+		if err != nil {
+			return nil, nil, err
+		}
+		workerIDs = append(workerIDs, wIDs...)
+	}
+	return ids, workerIDs, nil
+}
+
 func generateID() (string, error) {
 	size := 64
 	bs := make([]byte, size)
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/impact"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+)
+
+// readableImpacts removes the impacts on the projects the user cannot read, the actions and worker
+// models being shared between projects
+func readableImpacts(u *sdk.User, impacts []sdk.Impact) []sdk.Impact {
+	if u.Admin {
+		return impacts
+	}
+	res := []sdk.Impact{}
+	for _, i := range impacts {
+		if permission.ProjectPermission(i.ProjectKey, u) >= permission.PermissionRead {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// checkImpacts refuses a deletion when other entities depend on the deleted one, unless it is forced
+func checkImpacts(r *http.Request, impacts []sdk.Impact) error {
+	if len(impacts) > 0 && !FormBool(r, "force") {
+		return sdk.ErrEntityUsed
+	}
+	return nil
+}
+
+func (api *API) getActionImpactHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permActionName"]
+
+		a, err := action.LoadPublicAction(api.mustDB(), name)
+		if err != nil {
+			return sdk.WrapError(err, "getActionImpactHandler> Cannot load action %s", name)
+		}
+
+		impacts, err := impact.Action(api.mustDB(), api.Cache, getUser(ctx), a)
+		if err != nil {
+			return sdk.WrapError(err, "getActionImpactHandler> Cannot load impact of action %s", name)
+		}
+		return WriteJSON(w, r, readableImpacts(getUser(ctx), impacts), http.StatusOK)
+	}
+}
+
+func (api *API) getWorkerModelImpactHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permModelID")
+		if err != nil {
+			return sdk.WrapError(err, "getWorkerModelImpactHandler> Invalid permModelID")
+		}
+
+		m, err := worker.LoadWorkerModelByID(api.mustDB(), id)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkerModelImpactHandler> Cannot load worker model %d", id)
+		}
+
+		impacts, err := impact.Model(api.mustDB(), api.Cache, getUser(ctx), m)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkerModelImpactHandler> Cannot load impact of worker model %s", m.Name)
+		}
+		return WriteJSON(w, r, readableImpacts(getUser(ctx), impacts), http.StatusOK)
+	}
+}
+
+func (api *API) getEnvironmentImpactHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		envName := vars["permEnvironmentName"]
+
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
+		if err != nil {
+			return sdk.WrapError(err, "getEnvironmentImpactHandler> Cannot load environment %s", envName)
+		}
+
+		impacts, err := impact.Environment(api.mustDB(), api.Cache, getUser(ctx), key, env)
+		if err != nil {
+			return sdk.WrapError(err, "getEnvironmentImpactHandler> Cannot load impact of environment %s", envName)
+		}
+		return WriteJSON(w, r, impacts, http.StatusOK)
+	}
+}
+
+func (api *API) getVariableImpactInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		impacts, err := impact.Variable(api.mustDB(), api.Cache, getUser(ctx), key, "cds.proj."+vars["name"])
+		if err != nil {
+			return sdk.WrapError(err, "getVariableImpactInProjectHandler> Cannot load impact of variable %s", vars["name"])
+		}
+		return WriteJSON(w, r, impacts, http.StatusOK)
+	}
+}
+
+func (api *API) getVariableImpactInApplicationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+
+		impacts, err := impact.Variable(api.mustDB(), api.Cache, getUser(ctx), key, "cds.app."+vars["name"])
+		if err != nil {
+			return sdk.WrapError(err, "getVariableImpactInApplicationHandler> Cannot load impact of variable %s", vars["name"])
+		}
+		return WriteJSON(w, r, impacts, http.StatusOK)
+	}
+}
+
+func (api *API) getVariableImpactInEnvironmentHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+
+		impacts, err := impact.Variable(api.mustDB(), api.Cache, getUser(ctx), key, "cds.env."+vars["name"])
+		if err != nil {
+			return sdk.WrapError(err, "getVariableImpactInEnvironmentHandler> Cannot load impact of variable %s", vars["name"])
+		}
+		return WriteJSON(w, r, impacts, http.StatusOK)
+	}
+}
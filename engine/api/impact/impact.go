@@ -0,0 +1,288 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// jobs selects the jobs matching a condition, with their pipeline. The joined actions used as jobs
+// must be aliased job in the from clause
+const jobs = `SELECT DISTINCT project.projectkey, pipeline.id, pipeline.name, job.name FROM %s
+	JOIN pipeline_action ON pipeline_action.action_id = job.id
+	JOIN pipeline_stage ON pipeline_stage.id = pipeline_action.pipeline_stage_id
+	JOIN pipeline ON pipeline.id = pipeline_stage.pipeline_id
+	JOIN project ON project.id = pipeline.project_id
+	WHERE %s
+	ORDER BY 1, 3, 4`
+
+// analysis collects the entities depending on another one. The pipelines and environments found
+// are then followed to the applications and workflow nodes using them
+type analysis struct {
+	db           gorp.SqlExecutor
+	store        cache.Store
+	u            *sdk.User
+	impacts      []sdk.Impact
+	seen         map[string]bool
+	projects     map[string]bool
+	pipelines    map[int64]string
+	environments map[int64]string
+}
+
+func newAnalysis(db gorp.SqlExecutor, store cache.Store, u *sdk.User) *analysis {
+	return &analysis{
+		db:           db,
+		store:        store,
+		u:            u,
+		impacts:      []sdk.Impact{},
+		seen:         map[string]bool{},
+		projects:     map[string]bool{},
+		pipelines:    map[int64]string{},
+		environments: map[int64]string{},
+	}
+}
+
+func (a *analysis) add(t, key, name, reason string) {
+	k := t + "/" + key + "/" + name
+	if a.seen[k] {
+		return
+	}
+	a.seen[k] = true
+	a.impacts = append(a.impacts, sdk.Impact{Type: t, ProjectKey: key, Name: name, Reason: reason})
+}
+
+func (a *analysis) addPipeline(key string, id int64, name, reason string) {
+	a.projects[key] = true
+	a.pipelines[id] = name
+	a.add(sdk.ImpactTypePipeline, key, "pipeline/"+name, reason)
+}
+
+// jobs adds the jobs matching the condition and their pipelines
+func (a *analysis) jobs(from, where, reason string, args ...interface{}) error {
+	rows, err := a.db.Query(fmt.Sprintf(jobs, from, where), args...)
+	if err != nil {
+		return sdk.WrapError(err, "impact.jobs> Unable to load jobs")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, pip, job string
+		var pipID int64
+		if err := rows.Scan(&key, &pipID, &pip, &job); err != nil {
+			return sdk.WrapError(err, "impact.jobs> Unable to read job")
+		}
+		a.add(sdk.ImpactTypeJob, key, "pipeline/"+pip+"/"+job, reason)
+		a.addPipeline(key, pipID, pip, "job "+job+" "+reason)
+	}
+	return rows.Err()
+}
+
+// applications adds the applications matching the query, which selects their project key and name and the reason
+func (a *analysis) applications(query string, args ...interface{}) error {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return sdk.WrapError(err, "impact.applications> Unable to load applications")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, app, reason string
+		if err := rows.Scan(&key, &app, &reason); err != nil {
+			return sdk.WrapError(err, "impact.applications> Unable to read application")
+		}
+		a.add(sdk.ImpactTypeApplication, key, "application/"+app, reason)
+	}
+	return rows.Err()
+}
+
+// result follows the pipelines found to the applications attached to them, and the pipelines and
+// environments found to the workflow nodes using them
+func (a *analysis) result() ([]sdk.Impact, error) {
+	if len(a.pipelines) > 0 {
+		ids := make([]string, 0, len(a.pipelines))
+		for id := range a.pipelines {
+			ids = append(ids, fmt.Sprintf("%d", id))
+		}
+		query := `SELECT DISTINCT project.projectkey, application.name, 'runs pipeline ' || pipeline.name FROM application_pipeline
+			JOIN application ON application.id = application_pipeline.application_id
+			JOIN pipeline ON pipeline.id = application_pipeline.pipeline_id
+			JOIN project ON project.id = application.project_id
+			WHERE application_pipeline.pipeline_id = ANY(string_to_array($1, ',')::bigint[])
+			ORDER BY 1, 2, 3`
+		if err := a.applications(query, strings.Join(ids, ",")); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(a.projects))
+	for key := range a.projects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := a.workflowNodes(key); err != nil {
+			return nil, err
+		}
+	}
+	return a.impacts, nil
+}
+
+// workflowNodes adds the nodes of the workflows of a project running one of the pipelines or environments found
+func (a *analysis) workflowNodes(key string) error {
+	ws, err := workflow.LoadAll(a.db, key)
+	if err != nil && err != sdk.ErrWorkflowNotFound {
+		return sdk.WrapError(err, "impact.workflowNodes> Unable to load workflows of project %s", key)
+	}
+
+	for _, w := range ws {
+		wf, err := workflow.LoadByID(a.db, a.store, w.ID, a.u)
+		if err != nil {
+			return sdk.WrapError(err, "impact.workflowNodes> Unable to load workflow %s", w.Name)
+		}
+		if wf.Root == nil || !a.involved(wf) {
+			continue
+		}
+
+		for _, id := range append([]int64{wf.Root.ID}, wf.Nodes()...) {
+			n := wf.GetNode(id)
+			if n == nil {
+				continue
+			}
+			if pip, has := a.pipelines[n.PipelineID]; has {
+				a.add(sdk.ImpactTypeWorkflowNode, key, "workflow/"+wf.Name+"/"+n.Name, "runs pipeline "+pip)
+			}
+			if n.Context == nil {
+				continue
+			}
+			if env, has := a.environments[n.Context.EnvironmentID]; has {
+				a.add(sdk.ImpactTypeWorkflowNode, key, "workflow/"+wf.Name+"/"+n.Name, "runs on environment "+env)
+			}
+		}
+	}
+	return nil
+}
+
+func (a *analysis) involved(w *sdk.Workflow) bool {
+	for _, id := range w.InvolvedPipelines() {
+		if _, has := a.pipelines[id]; has {
+			return true
+		}
+	}
+	for _, id := range w.InvolvedEnvironments() {
+		if _, has := a.environments[id]; has {
+			return true
+		}
+	}
+	return false
+}
+
+// Action returns the jobs using an action as step, and the pipelines, applications and workflow nodes depending on them
+func Action(db gorp.SqlExecutor, store cache.Store, u *sdk.User, act *sdk.Action) ([]sdk.Impact, error) {
+	a := newAnalysis(db, store, u)
+	if err := a.jobs(`action_edge JOIN action job ON job.id = action_edge.parent_id`, `action_edge.child_id = $1`,
+		"uses action "+act.Name, act.ID); err != nil {
+		return nil, sdk.WrapError(err, "impact.Action> Unable to load jobs using action %s", act.Name)
+	}
+	return a.result()
+}
+
+// Model returns the jobs requiring a worker model, directly or through their steps, and the pipelines,
+// applications and workflow nodes depending on them
+func Model(db gorp.SqlExecutor, store cache.Store, u *sdk.User, m *sdk.Model) ([]sdk.Impact, error) {
+	a := newAnalysis(db, store, u)
+	where := `job.id IN (
+		SELECT action_requirement.action_id FROM action_requirement
+		WHERE action_requirement.type = $1 AND action_requirement.value = $2
+		UNION
+		SELECT action_edge.parent_id FROM action_edge
+		JOIN action_requirement ON action_requirement.action_id = action_edge.child_id
+		WHERE action_requirement.type = $1 AND action_requirement.value = $2
+	)`
+	if err := a.jobs(`action job`, where, "requires model "+m.Name, sdk.ModelRequirement, m.Name); err != nil {
+		return nil, sdk.WrapError(err, "impact.Model> Unable to load jobs requiring model %s", m.Name)
+	}
+	return a.result()
+}
+
+// Environment returns the applications scheduling or triggering pipelines on an environment and the workflow nodes running on it
+func Environment(db gorp.SqlExecutor, store cache.Store, u *sdk.User, key string, env *sdk.Environment) ([]sdk.Impact, error) {
+	a := newAnalysis(db, store, u)
+	a.projects[key] = true
+	a.environments[env.ID] = env.Name
+
+	query := `SELECT DISTINCT project.projectkey, application.name, 'schedules pipeline ' || pipeline.name FROM pipeline_scheduler
+		JOIN application ON application.id = pipeline_scheduler.application_id
+		JOIN pipeline ON pipeline.id = pipeline_scheduler.pipeline_id
+		JOIN project ON project.id = application.project_id
+		WHERE pipeline_scheduler.environment_id = $1
+		UNION
+		SELECT DISTINCT project.projectkey, application.name, 'triggers pipeline ' || pipeline.name FROM pipeline_trigger
+		JOIN application ON application.id = pipeline_trigger.src_application_id
+		JOIN pipeline ON pipeline.id = pipeline_trigger.dest_pipeline_id
+		JOIN project ON project.id = application.project_id
+		WHERE pipeline_trigger.src_environment_id = $1 OR pipeline_trigger.dest_environment_id = $1
+		ORDER BY 1, 2, 3`
+	if err := a.applications(query, env.ID); err != nil {
+		return nil, sdk.WrapError(err, "impact.Environment> Unable to load applications using environment %s", env.Name)
+	}
+	return a.result()
+}
+
+// Variable returns the jobs, pipelines and applications of a project referencing a variable, ie: cds.proj.foo
+// in the steps, requirements, pipeline parameters or application variables, and the entities depending on them
+func Variable(db gorp.SqlExecutor, store cache.Store, u *sdk.User, key, name string) ([]sdk.Impact, error) {
+	a := newAnalysis(db, store, u)
+	reason := "references " + name
+	// The variables are also given to the scripts as environment variables, ie: CDS_PROJ_FOO
+	envName := strings.ToUpper(strings.Replace(name, ".", "_", -1))
+
+	where := `project.projectkey = $1 AND job.id IN (
+		SELECT action_edge.parent_id FROM action_edge
+		JOIN action_edge_parameter ON action_edge_parameter.action_edge_id = action_edge.id
+		WHERE strpos(action_edge_parameter.value, $2) > 0 OR strpos(action_edge_parameter.value, $3) > 0
+		UNION
+		SELECT action_requirement.action_id FROM action_requirement
+		WHERE strpos(action_requirement.value, $2) > 0
+	)`
+	if err := a.jobs(`action job`, where, reason, key, name, envName); err != nil {
+		return nil, sdk.WrapError(err, "impact.Variable> Unable to load jobs referencing %s", name)
+	}
+
+	query := `SELECT DISTINCT pipeline.id, pipeline.name FROM pipeline_parameter
+		JOIN pipeline ON pipeline.id = pipeline_parameter.pipeline_id
+		JOIN project ON project.id = pipeline.project_id
+		WHERE project.projectkey = $1 AND strpos(pipeline_parameter.value, $2) > 0
+		ORDER BY 2`
+	rows, err := db.Query(query, key, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "impact.Variable> Unable to load pipelines referencing %s", name)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var pip string
+		if err := rows.Scan(&id, &pip); err != nil {
+			return nil, sdk.WrapError(err, "impact.Variable> Unable to read pipeline")
+		}
+		a.addPipeline(key, id, pip, "parameter "+reason)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, sdk.WrapError(err, "impact.Variable> Unable to read pipelines")
+	}
+
+	query = `SELECT DISTINCT project.projectkey, application.name, 'variable ' || application_variable.var_name || ' ' || $3 FROM application_variable
+		JOIN application ON application.id = application_variable.application_id
+		JOIN project ON project.id = application.project_id
+		WHERE project.projectkey = $1 AND strpos(application_variable.var_value, $2) > 0
+		ORDER BY 1, 2, 3`
+	if err := a.applications(query, key, name, reason); err != nil {
+		return nil, sdk.WrapError(err, "impact.Variable> Unable to load applications referencing %s", name)
+	}
+	return a.result()
+}
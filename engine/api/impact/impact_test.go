@@ -0,0 +1,71 @@
+package impact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestModel(t *testing.T) {
+	db, cache := test.SetupPG(t)
+
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	test.NoError(t, pipeline.InsertStage(db, s))
+
+	model := sdk.Model{Name: "model-" + key}
+	j := &sdk.Job{
+		Enabled: true,
+		Action: sdk.Action{
+			Name:    "job1",
+			Enabled: true,
+			Requirements: []sdk.Requirement{
+				{Name: model.Name, Type: sdk.ModelRequirement, Value: model.Name},
+			},
+			Actions: []sdk.Action{
+				sdk.NewScriptAction("echo lol"),
+			},
+		},
+	}
+	test.NoError(t, pipeline.InsertJob(db, j, s.ID, &pip))
+	s.Jobs = append(s.Jobs, *j)
+	pip.Stages = append(pip.Stages, *s)
+
+	w := sdk.Workflow{
+		Name:       "w1",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Name:     "node1",
+			Pipeline: pip,
+		},
+	}
+	test.NoError(t, workflow.Insert(db, cache, &w, proj, u))
+
+	impacts, err := Model(db, cache, u, &model)
+	test.NoError(t, err)
+	assert.Equal(t, []sdk.Impact{
+		{Type: sdk.ImpactTypeJob, ProjectKey: key, Name: "pipeline/pip1/job1", Reason: "requires model " + model.Name},
+		{Type: sdk.ImpactTypePipeline, ProjectKey: key, Name: "pipeline/pip1", Reason: "job job1 requires model " + model.Name},
+		{Type: sdk.ImpactTypeWorkflowNode, ProjectKey: key, Name: "workflow/w1/node1", Reason: "runs pipeline pip1"},
+	}, impacts)
+}
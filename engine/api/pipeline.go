@@ -12,7 +12,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/action"
 	"github.com/ovh/cds/engine/api/application"
-
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
@@ -523,6 +523,11 @@ func (api *API) updatePipelineHandler() Handler {
 			return sdk.WrapError(err, "updatePipelineHandler> cannot load pipeline %s", name)
 		}
 
+		before, err := pipeline.LoadPipeline(api.mustDB(), key, name, true)
+		if err != nil {
+			return sdk.WrapError(err, "updatePipelineHandler> cannot load pipeline %s", name)
+		}
+
 		pipelineDB.Name = p.Name
 		pipelineDB.Type = p.Type
 
@@ -540,6 +545,11 @@ func (api *API) updatePipelineHandler() Handler {
 			return sdk.WrapError(err, "updatePipelineHandler> Cannot update pipeline last modified date")
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, key, pipelineDB.Name, before)
+		if err != nil {
+			return sdk.WrapError(err, "updatePipelineHandler> Cannot audit pipeline %s", name)
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), proj); err != nil {
 			return sdk.WrapError(err, "updatePipelineHandler> cannot update project last modified date")
 		}
@@ -559,6 +569,7 @@ func (api *API) updatePipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updatePipelineHandler> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 		return WriteJSON(w, r, pipelineDB, http.StatusOK)
 	}
 }
@@ -640,6 +651,11 @@ func (api *API) addPipelineHandler() Handler {
 			return sdk.WrapError(err, "Cannot add groups on pipeline")
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, key, p.Name, nil)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot audit pipeline")
+		}
+
 		for _, app := range p.AttachedApplication {
 			if _, err := application.AttachPipeline(tx, app.ID, p.ID); err != nil {
 				return sdk.WrapError(err, "Cannot attach pipeline %d to %d", app.ID, p.ID)
@@ -656,6 +672,7 @@ func (api *API) addPipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		p.Permission = permission.PermissionReadWriteExecute
 
@@ -803,7 +820,7 @@ func (api *API) deletePipelineHandler() Handler {
 			return sdk.WrapError(errP, "deletePipeline> Cannot load project")
 		}
 
-		p, err := pipeline.LoadPipeline(api.mustDB(), proj.Key, pipelineName, false)
+		p, err := pipeline.LoadPipeline(api.mustDB(), proj.Key, pipelineName, true)
 		if err != nil {
 			return sdk.WrapError(err, "deletePipeline> Cannot load pipeline %s", pipelineName)
 		}
@@ -836,6 +853,11 @@ func (api *API) deletePipelineHandler() Handler {
 			return sdk.WrapError(err, "deletePipeline> Cannot delete pipeline %s", pipelineName)
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityPipeline, Name: pipelineName}, p, nil)
+		if err != nil {
+			return sdk.WrapError(err, "deletePipeline> Cannot audit pipeline %s", pipelineName)
+		}
+
 		if err := project.UpdateLastModified(api.mustDB(), api.Cache, getUser(ctx), proj); err != nil {
 			return sdk.WrapError(err, "deletePipeline> Cannot update project last modified date")
 		}
@@ -843,6 +865,7 @@ func (api *API) deletePipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deletePipeline> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 		return nil
 	}
 }
@@ -874,6 +897,11 @@ func (api *API) addJobToPipelineHandler() Handler {
 			return sdk.WrapError(errPip, "addJoinedActionToPipelineHandler> Cannot load pipeline %s for project %s", pipelineName, projectKey)
 		}
 
+		before, errB := pipeline.LoadPipeline(api.mustDB(), projectKey, pipelineName, true)
+		if errB != nil {
+			return sdk.WrapError(errB, "addJoinedActionToPipelineHandler> Cannot load pipeline %s for project %s", pipelineName, projectKey)
+		}
+
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
 			return errBegin
@@ -904,9 +932,15 @@ func (api *API) addJobToPipelineHandler() Handler {
 			return sdk.WrapError(err, "addActionToPipelineHandler> Cannot compute registration needs")
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, projectKey, pipelineName, before)
+		if err != nil {
+			return sdk.WrapError(err, "addActionToPipelineHandler> Cannot audit pipeline %s", pipelineName)
+		}
+
 		if err := tx.Commit(); err != nil {
 			return err
 		}
+		audit.Publish(evt)
 
 		return WriteJSON(w, r, job, http.StatusOK)
 	}
@@ -929,6 +963,11 @@ func (api *API) updateJoinedActionHandler() Handler {
 			return sdk.WrapError(sdk.ErrPipelineNotFound, "updateJoinedAction> Cannot load pipeline %s for project %s: %s", pipName, key, errlp)
 		}
 
+		before, errlp := pipeline.LoadPipeline(api.mustDB(), key, pipName, true)
+		if errlp != nil {
+			return sdk.WrapError(errlp, "updateJoinedAction> Cannot load pipeline %s for project %s", pipName, key)
+		}
+
 		actionID, errp := strconv.ParseInt(actionIDString, 10, 60)
 		if errp != nil {
 			return sdk.WrapError(sdk.ErrWrongRequest, "updateJoinedAction> Action ID %s must be an int", actionID)
@@ -983,9 +1022,15 @@ func (api *API) updateJoinedActionHandler() Handler {
 			return sdk.WrapError(err, "updateJoinedAction> Cannot compute registration needs")
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, key, pipName, before)
+		if err != nil {
+			return sdk.WrapError(err, "updateJoinedAction> Cannot audit pipeline %s", pipName)
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateJoinedAction> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		return WriteJSON(w, r, a, http.StatusOK)
 	}
@@ -1008,6 +1053,11 @@ func (api *API) deleteJoinedActionHandler() Handler {
 			return sdk.WrapError(sdk.ErrPipelineNotFound, "deleteJoinedAction> Cannot load pipeline %s for project %s, err:%s", pipName, projectKey, errload)
 		}
 
+		before, errload := pipeline.LoadPipeline(api.mustDB(), projectKey, pipName, true)
+		if errload != nil {
+			return sdk.WrapError(errload, "deleteJoinedAction> Cannot load pipeline %s for project %s", pipName, projectKey)
+		}
+
 		tx, errb := api.mustDB().Begin()
 		if errb != nil {
 			return sdk.WrapError(errb, "deleteJoinedAction> Cannot start transaction")
@@ -1028,9 +1078,15 @@ func (api *API) deleteJoinedActionHandler() Handler {
 			return sdk.WrapError(err, "deleteJoinedAction> cannot update pipeline last_modified date")
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, projectKey, pipName, before)
+		if err != nil {
+			return sdk.WrapError(err, "deleteJoinedAction> Cannot audit pipeline %s", pipName)
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteJoinedAction> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		return nil
 	}
@@ -1457,3 +1513,13 @@ func (api *API) getPipelineBuildCommitsHandler() Handler {
 		return WriteJSON(w, r, cm, http.StatusOK)
 	}
 }
+
+// auditPipeline records the pipeline with its stages and jobs as it is in the transaction in the audit log,
+// it returns the event of the audit to publish once committed
+func (api *API) auditPipeline(ctx context.Context, db gorp.SqlExecutor, key, name string, before *sdk.Pipeline) (sdk.EventAudit, error) {
+	after, err := pipeline.LoadPipeline(db, key, name, true)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "auditPipeline> Cannot load pipeline %s", name)
+	}
+	return audit.Add(db, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityPipeline, Name: name}, before, after)
+}
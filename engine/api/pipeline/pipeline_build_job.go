@@ -232,6 +232,32 @@ func BookPipelineBuildJob(pbJobID int64, hatchery *sdk.Hatchery) (*sdk.Hatchery,
 	return &h, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookPipelineBuildJob> job %d already booked by %s (%d)", pbJobID, h.Name, h.ID)
 }
 
+// UnbookPipelineBuildJobs releases the waiting jobs booked by the given hatcheries, so that other hatcheries
+// can book them. It returns the number of released jobs
+func UnbookPipelineBuildJobs(db gorp.SqlExecutor, hatcheryIDs []int64) (int, error) {
+	var ids []int64
+	if _, err := db.Select(&ids, `SELECT id FROM pipeline_build_job WHERE status = $1`, sdk.StatusWaiting.String()); err != nil {
+		return 0, sdk.WrapError(err, "UnbookPipelineBuildJobs> Unable to load waiting jobs")
+	}
+
+	var n int
+	for _, id := range ids {
+		k := keyBookJob(id)
+		h := sdk.Hatchery{}
+		if !Store.Get(k, &h) {
+			continue
+		}
+		for _, hID := range hatcheryIDs {
+			if h.ID == hID {
+				Store.Delete(k)
+				n++
+				break
+			}
+		}
+	}
+	return n, nil
+}
+
 // AddSpawnInfosPipelineBuildJob saves spawn info before starting worker
 func AddSpawnInfosPipelineBuildJob(db gorp.SqlExecutor, pbJobID int64, infos []sdk.SpawnInfo) (*sdk.PipelineBuildJob, error) {
 	pbJob, err := GetPipelineBuildJobForUpdate(db, pbJobID)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
@@ -68,10 +69,20 @@ func (api *API) updateGroupRoleOnPipelineHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		role, err := group.LoadRoleInPipeline(tx, p.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot load role of group %s in pipeline %s", g.Name, p.Name)
+		}
+
 		if err := group.UpdateGroupRoleInPipeline(tx, p.ID, g.ID, groupPipeline.Permission); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot add group %s in pipeline %s", g.Name, p.Name)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "pipeline/"+p.Name, g.Name, role, groupPipeline.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot audit permission")
+		}
+
 		if err := pipeline.UpdatePipelineLastModified(tx, proj, p, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot update pipeline last_modified date")
 		}
@@ -79,6 +90,7 @@ func (api *API) updateGroupRoleOnPipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot start transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		if err := pipeline.LoadGroupByPipeline(api.mustDB(), p); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleOnPipelineHandler: Cannot load groups for pipeline %s", p.Name)
@@ -130,6 +142,10 @@ func (api *API) updateGroupsOnPipelineHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		if err := pipeline.LoadGroupByPipeline(tx, p); err != nil {
+			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot load groups of pipeline %s", p.Name)
+		}
+
 		if err := group.DeleteAllGroupFromPipeline(tx, p.ID); err != nil {
 			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot delete groups from pipeline %s", p.Name)
 		}
@@ -145,6 +161,11 @@ func (api *API) updateGroupsOnPipelineHandler() Handler {
 			}
 		}
 
+		evtsPerm, err := api.auditPermissions(ctx, tx, key, "pipeline/"+p.Name, p.GroupPermission, groupsPermission)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot audit permissions")
+		}
+
 		if err := pipeline.UpdatePipelineLastModified(tx, proj, p, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot update pipeline last_modified date")
 		}
@@ -152,6 +173,7 @@ func (api *API) updateGroupsOnPipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupsOnPipelineHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		return nil
 	}
@@ -202,6 +224,11 @@ func (api *API) addGroupInPipelineHandler() Handler {
 			return sdk.WrapError(err, "addGroupInPipeline: Cannot add group %s in pipeline %s", g.Name, p.Name)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "pipeline/"+p.Name, g.Name, 0, groupPermission.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "addGroupInPipeline: Cannot audit permission")
+		}
+
 		if err := pipeline.UpdatePipelineLastModified(tx, proj, p, getUser(ctx)); err != nil {
 			return sdk.WrapError(err, "addGroupInPipeline: Cannot update pipeline last_modified date")
 		}
@@ -209,6 +236,7 @@ func (api *API) addGroupInPipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addGroupInPipeline: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		if err := pipeline.LoadGroupByPipeline(api.mustDB(), p); err != nil {
 			return sdk.WrapError(err, "addGroupInPipeline: Cannot load group")
@@ -240,10 +268,20 @@ func (api *API) deleteGroupFromPipelineHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		role, err := group.LoadRoleInPipeline(tx, p.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot load role of group %s in pipeline %s", g.Name, p.Name)
+		}
+
 		if err := group.DeleteGroupFromPipeline(tx, p.ID, g.ID); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot delete group %s from project %s", g.Name, p.Name)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "pipeline/"+p.Name, g.Name, role, 0)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot audit permission")
+		}
+
 		proj, errproj := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
 		if errproj != nil {
 			return sdk.WrapError(errproj, "deleteGroupFromPipelineHandler> unable to load project")
@@ -256,6 +294,7 @@ func (api *API) deleteGroupFromPipelineHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		if err := pipeline.LoadGroupByPipeline(api.mustDB(), p); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromPipelineHandler: Cannot load groups")
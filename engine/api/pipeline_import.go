@@ -72,6 +72,9 @@ func (api *API) importPipelineHandler() Handler {
 		if errP != nil {
 			return sdk.WrapError(errP, "importPipelineHandler> Unable to parse pipeline %s", payload.Name)
 		}
+		if err := decryptPipelineSecrets(key, pip); err != nil {
+			return sdk.WrapError(err, "importPipelineHandler> Unable to decrypt secrets of pipeline %s", payload.Name)
+		}
 
 		// Load group in permission
 		for i := range pip.GroupPermission {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
@@ -219,13 +220,18 @@ func (api *API) addProjectHandler() Handler {
 			}
 		}
 
+		evts := []sdk.EventAudit{}
 		for _, v := range p.Variable {
 			var errVar error
 			switch v.Type {
 			case sdk.KeyVariable:
-				errVar = project.AddKeyPair(tx, p, v.Name, getUser(ctx))
+				var evtsKey []sdk.EventAudit
+				evtsKey, errVar = project.AddKeyPair(tx, p, v.Name, getUser(ctx))
+				evts = append(evts, evtsKey...)
 			default:
-				errVar = project.InsertVariable(tx, p, &v, getUser(ctx))
+				var evt sdk.EventAudit
+				evt, errVar = project.InsertVariable(tx, p, &v, getUser(ctx))
+				evts = append(evts, evt)
 			}
 			if errVar != nil {
 				return sdk.WrapError(errVar, "addProject> Cannot add variable %s in project %s", v.Name, p.Name)
@@ -239,6 +245,7 @@ func (api *API) addProjectHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addProject> Cannot commit transaction")
 		}
+		audit.Publish(evts...)
 
 		return WriteJSON(w, r, p, http.StatusCreated)
 	}
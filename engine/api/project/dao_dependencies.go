@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/variableset"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 )
@@ -54,7 +55,15 @@ var (
 	}
 
 	loadVariables = func(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, u *sdk.User) error {
-		return loadAllVariables(db, store, proj)
+		if err := loadAllVariables(db, store, proj); err != nil {
+			return err
+		}
+		sets, err := variableset.LoadAllByProject(db, proj.ID)
+		if err != nil {
+			return sdk.WrapError(err, "project.loadVariables> Unable to load variable sets")
+		}
+		proj.VariableSets = sets
+		return nil
 	}
 
 	loadApplicationVariables = func(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, u *sdk.User) error {
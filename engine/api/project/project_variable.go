@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
@@ -229,18 +230,18 @@ func GetVariableInProject(db gorp.SqlExecutor, projectID int64, variableName str
 	return variable, errD
 }
 
-// InsertVariable Insert a new variable in the given project
-func InsertVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variable, u *sdk.User) error {
+// InsertVariable Insert a new variable in the given project, it returns the event of the audit to publish once committed
+func InsertVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	query := `INSERT INTO project_variable(project_id, var_name, var_value, cipher_value, var_type)
 		  VALUES($1, $2, $3, $4, $5) RETURNING id`
 
 	clear, cipher, err := secret.EncryptS(variable.Type, variable.Value)
 	if err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot encryp secret %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot encryp secret %s", variable.Name)
 	}
 
 	if err := db.QueryRow(query, proj.ID, variable.Name, clear, cipher, string(variable.Type)).Scan(&variable.ID); err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot insert variable %s in DB", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot insert variable %s in DB", variable.Name)
 	}
 
 	pva := &sdk.ProjectVariableAudit{
@@ -252,14 +253,19 @@ func InsertVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 		Versionned:    time.Now(),
 	}
 
+	evt, err := auditVariable(db, proj, u, nil, variable)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot audit variable %s", variable.Name)
+	}
+
 	if err := insertAudit(db, pva); err != nil {
-		return sdk.WrapError(err, "InsertVariable> Cannot insert audit for variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "InsertVariable> Cannot insert audit for variable %s", variable.Name)
 	}
-	return nil
+	return evt, nil
 }
 
-// UpdateVariable Update a variable in the given project
-func UpdateVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variable, u *sdk.User) error {
+// UpdateVariable Update a variable in the given project, it returns the event of the audit to publish once committed
+func UpdateVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	varValue := variable.Value
 	// Clear password for audit
 	previousVar, err := GetVariableByID(db, proj.ID, variable.ID, WithClearPassword())
@@ -271,14 +277,14 @@ func UpdateVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 
 	clear, cipher, err := secret.EncryptS(variable.Type, varValue)
 	if err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot encrypt secret %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot encrypt secret %s", variable.Name)
 	}
 
 	query := `UPDATE project_variable SET var_name=$1, var_value=$2, cipher_value=$3, var_type=$4
 		   WHERE id=$5`
 	_, err = db.Exec(query, variable.Name, clear, cipher, string(variable.Type), variable.ID)
 	if err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot update variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot update variable %s", variable.Name)
 	}
 
 	pva := &sdk.ProjectVariableAudit{
@@ -291,19 +297,24 @@ func UpdateVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 		Versionned:     time.Now(),
 	}
 
+	evt, err := auditVariable(db, proj, u, previousVar, variable)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot audit variable %s", variable.Name)
+	}
+
 	if err := insertAudit(db, pva); err != nil {
-		return sdk.WrapError(err, "UpdateVariable> Cannot insert audit for variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "UpdateVariable> Cannot insert audit for variable %s", variable.Name)
 	}
 
-	return nil
+	return evt, nil
 }
 
-// DeleteVariable Delete a variable from the given project
-func DeleteVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variable, u *sdk.User) error {
+// DeleteVariable Delete a variable from the given project, it returns the event of the audit to publish once committed
+func DeleteVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
 	query := `DELETE FROM project_variable WHERE project_id=$1 AND id=$2`
 	_, err := db.Exec(query, proj.ID, variable.ID)
 	if err != nil {
-		return sdk.WrapError(err, "DeleteVariable> Cannot delete variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot delete variable %s", variable.Name)
 	}
 
 	pva := &sdk.ProjectVariableAudit{
@@ -315,11 +326,16 @@ func DeleteVariable(db gorp.SqlExecutor, proj *sdk.Project, variable *sdk.Variab
 		Versionned:     time.Now(),
 	}
 
+	evt, err := auditVariable(db, proj, u, variable, nil)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot audit variable %s", variable.Name)
+	}
+
 	if err := insertAudit(db, pva); err != nil {
-		return sdk.WrapError(err, "DeleteVariable> Cannot insert audit for variable %s", variable.Name)
+		return sdk.EventAudit{}, sdk.WrapError(err, "DeleteVariable> Cannot insert audit for variable %s", variable.Name)
 	}
 
-	return err
+	return evt, nil
 }
 
 // DeleteAllVariable Delete all variables from the given project
@@ -334,10 +350,10 @@ func DeleteAllVariable(db gorp.SqlExecutor, projectID int64) error {
 }
 
 // AddKeyPair generate a ssh key pair and add them as project variables
-func AddKeyPair(db gorp.SqlExecutor, proj *sdk.Project, keyname string, u *sdk.User) error {
+func AddKeyPair(db gorp.SqlExecutor, proj *sdk.Project, keyname string, u *sdk.User) ([]sdk.EventAudit, error) {
 	pub, priv, errGenerate := keys.Generatekeypair(keyname)
 	if errGenerate != nil {
-		return errGenerate
+		return nil, errGenerate
 	}
 
 	v := &sdk.Variable{
@@ -346,8 +362,9 @@ func AddKeyPair(db gorp.SqlExecutor, proj *sdk.Project, keyname string, u *sdk.U
 		Value: priv,
 	}
 
-	if err := InsertVariable(db, proj, v, u); err != nil {
-		return err
+	evtPriv, err := InsertVariable(db, proj, v, u)
+	if err != nil {
+		return nil, err
 	}
 
 	p := &sdk.Variable{
@@ -356,7 +373,20 @@ func AddKeyPair(db gorp.SqlExecutor, proj *sdk.Project, keyname string, u *sdk.U
 		Value: pub,
 	}
 
-	return InsertVariable(db, proj, p, u)
+	evtPub, err := InsertVariable(db, proj, p, u)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.EventAudit{evtPriv, evtPub}, nil
+}
+
+// auditVariable records the change of a variable in the audit log
+func auditVariable(db gorp.SqlExecutor, proj *sdk.Project, u *sdk.User, before, after *sdk.Variable) (sdk.EventAudit, error) {
+	name := after
+	if name == nil {
+		name = before
+	}
+	return audit.Add(db, u, audit.Entity{ProjectKey: proj.Key, Type: sdk.AuditEntityVariable, Name: "project/" + name.Name}, before, after)
 }
 
 // insertAudit insert an audit on a project variable
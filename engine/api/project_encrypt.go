@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// postEncryptVariableInProjectHandler encrypts the value of a variable with the key of a project.
+// The returned value can be written in the exported entities of the project, it's decrypted when
+// they are imported
+func (api *API) postEncryptVariableInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		var v sdk.Variable
+		if err := UnmarshalBody(r, &v); err != nil {
+			return sdk.WrapError(err, "postEncryptVariableInProjectHandler> Cannot read body")
+		}
+
+		enc, err := secret.EncryptValue(key, v.Value)
+		if err != nil {
+			return sdk.WrapError(err, "postEncryptVariableInProjectHandler> Cannot encrypt value")
+		}
+		v.Value = exportentities.Secret(enc)
+		return WriteJSON(w, r, v, http.StatusOK)
+	}
+}
+
+// decryptSecret returns the clear value of a secret encrypted with the key of a project, or the
+// value itself if it's not encrypted. Only the values of the types hidden by a placeholder can be
+// encrypted, otherwise the secret would be served in clear once imported
+func decryptSecret(key, typ, value string) (string, error) {
+	enc, ok := exportentities.ParseSecret(value)
+	if !ok {
+		return value, nil
+	}
+	if !sdk.NeedPlaceholder(typ) {
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "decryptSecret> A value of type %s cannot be encrypted", typ)
+	}
+	return secret.DecryptValue(key, enc)
+}
+
+// decryptPipelineSecrets decrypts the parameters of a pipeline and the parameters of its steps
+func decryptPipelineSecrets(key string, pip *sdk.Pipeline) error {
+	if err := decryptParameters(key, pip.Parameter); err != nil {
+		return sdk.WrapError(err, "decryptPipelineSecrets> Cannot decrypt parameters of pipeline %s", pip.Name)
+	}
+
+	var decryptAction func(a *sdk.Action) error
+	decryptAction = func(a *sdk.Action) error {
+		if err := decryptParameters(key, a.Parameters); err != nil {
+			return sdk.WrapError(err, "decryptPipelineSecrets> Cannot decrypt parameters of %s", a.Name)
+		}
+		for i := range a.Actions {
+			if err := decryptAction(&a.Actions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := range pip.Stages {
+		for j := range pip.Stages[i].Jobs {
+			if err := decryptAction(&pip.Stages[i].Jobs[j].Action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func decryptParameters(key string, params []sdk.Parameter) error {
+	for i := range params {
+		v, err := decryptSecret(key, params[i].Type, params[i].Value)
+		if err != nil {
+			return sdk.WrapError(err, "decryptParameters> Cannot decrypt %s", params[i].Name)
+		}
+		params[i].Value = v
+	}
+	return nil
+}
+
+// decryptVariables decrypts the values of a list of variables
+func decryptVariables(key string, vars []sdk.Variable) error {
+	for i := range vars {
+		v, err := decryptSecret(key, vars[i].Type, vars[i].Value)
+		if err != nil {
+			return sdk.WrapError(err, "decryptVariables> Cannot decrypt %s", vars[i].Name)
+		}
+		vars[i].Value = v
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func Test_decryptSecret(t *testing.T) {
+	secret.Init("3dojuwevn94y7orh5e3t4ejtmbtstest")
+	enc, err := secret.EncryptValue("KEY", "my-secret")
+	assert.NoError(t, err)
+
+	clear, err := decryptSecret("KEY", sdk.SecretVariable, exportentities.Secret(enc))
+	assert.NoError(t, err)
+	assert.Equal(t, "my-secret", clear)
+
+	// The values which are not encrypted are kept whatever their type
+	clear, err = decryptSecret("KEY", sdk.StringVariable, "my-value")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-value", clear)
+
+	// A secret can't be decrypted in a value served in clear
+	_, err = decryptSecret("KEY", sdk.StringVariable, exportentities.Secret(enc))
+	assert.Equal(t, sdk.ErrWrongRequest, errors.Cause(err))
+
+	vars := []sdk.Variable{
+		{Name: "password", Type: sdk.SecretVariable, Value: exportentities.Secret(enc)},
+		{Name: "text", Type: sdk.TextVariable, Value: exportentities.Secret(enc)},
+	}
+	err = decryptVariables("KEY", vars)
+	assert.Equal(t, sdk.ErrWrongRequest, errors.Cause(err))
+
+	params := []sdk.Parameter{
+		{Name: "key", Type: sdk.KeyParameter, Value: exportentities.Secret(enc)},
+		{Name: "string", Type: sdk.StringParameter, Value: exportentities.Secret(enc)},
+	}
+	err = decryptParameters("KEY", params)
+	assert.Equal(t, sdk.ErrWrongRequest, errors.Cause(err))
+	assert.Equal(t, "my-secret", params[0].Value)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// loadProjectTree loads a project with its applications, pipelines, environments and workflows as
+// a project tree. The values of the secrets are in clear
+func (api *API) loadProjectTree(ctx context.Context, db gorp.SqlExecutor, key string) (*exportentities.ProjectTree, error) {
+	u := getUser(ctx)
+	proj, err := project.Load(db, api.Cache, key, u, project.LoadOptions.WithGroups)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadProjectTree> Cannot load project %s", key)
+	}
+	if proj.Variable, err = project.GetAllVariableInProject(db, proj.ID, project.WithClearPassword()); err != nil {
+		return nil, sdk.WrapError(err, "loadProjectTree> Cannot load variables of project %s", key)
+	}
+	tree := &exportentities.ProjectTree{Project: *exportentities.NewProject(proj)}
+
+	envs, err := environment.LoadEnvironments(db, key, true, u)
+	if err != nil && err != sdk.ErrNoEnvironment {
+		return nil, sdk.WrapError(err, "loadProjectTree> Cannot load environments of project %s", key)
+	}
+	for i := range envs {
+		if envs[i].Variable, err = environment.GetAllVariableByID(db, envs[i].ID, environment.WithClearPassword()); err != nil {
+			return nil, sdk.WrapError(err, "loadProjectTree> Cannot load variables of environment %s", envs[i].Name)
+		}
+		tree.Environments = append(tree.Environments, *exportentities.NewEnvironment(&envs[i]))
+	}
+
+	pips, err := pipeline.LoadPipelines(db, proj.ID, false, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadProjectTree> Cannot load pipelines of project %s", key)
+	}
+	for _, p := range pips {
+		pip, err := pipeline.LoadPipeline(db, key, p.Name, true)
+		if err != nil {
+			return nil, sdk.WrapError(err, "loadProjectTree> Cannot load pipeline %s", p.Name)
+		}
+		tree.Pipelines = append(tree.Pipelines, *exportentities.NewPipeline(pip))
+	}
+
+	apps, err := application.LoadAll(db, api.Cache, key, u, application.LoadOptions.WithVariablesWithClearPassword,
+		application.LoadOptions.WithPipelines, application.LoadOptions.WithGroups, application.LoadOptions.WithRepositoryManager)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadProjectTree> Cannot load applications of project %s", key)
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+	for i := range apps {
+		tree.Applications = append(tree.Applications, *exportentities.NewApplication(&apps[i]))
+	}
+
+	ws, err := workflow.LoadAll(db, key)
+	if err != nil && err != sdk.ErrWorkflowNotFound {
+		return nil, sdk.WrapError(err, "loadProjectTree> Cannot load workflows of project %s", key)
+	}
+	sort.Slice(ws, func(i, j int) bool { return ws[i].Name < ws[j].Name })
+	for _, w := range ws {
+		wf, err := workflow.LoadByID(db, api.Cache, w.ID, u)
+		if err != nil {
+			return nil, sdk.WrapError(err, "loadProjectTree> Cannot load workflow %s", w.Name)
+		}
+		ew, err := exportentities.NewWorkflow(wf)
+		if err != nil {
+			return nil, sdk.WrapError(err, "loadProjectTree> Cannot export workflow %s", w.Name)
+		}
+		tree.Workflows = append(tree.Workflows, *ew)
+	}
+	return tree, nil
+}
+
+// getProjectExportHandler exports a whole project as a project tree, the values of the secrets are
+// encrypted with the key of the project so they can only be imported in a project with the same key
+func (api *API) getProjectExportHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+
+		tree, err := api.loadProjectTree(ctx, api.mustDB(), key)
+		if err != nil {
+			return sdk.WrapError(err, "getProjectExportHandler> Cannot load project %s", key)
+		}
+
+		if err := tree.Values(func(name string, v exportentities.VariableValue) (exportentities.VariableValue, error) {
+			if !sdk.NeedPlaceholder(v.Type) {
+				return v, nil
+			}
+			enc, err := secret.EncryptValue(key, v.Value)
+			if err != nil {
+				return v, sdk.WrapError(err, "getProjectExportHandler> Cannot encrypt %s", name)
+			}
+			return exportentities.VariableValue{Type: v.Type, Value: exportentities.Secret(enc)}, nil
+		}); err != nil {
+			return err
+		}
+		return WriteJSON(w, r, tree, http.StatusOK)
+	}
+}
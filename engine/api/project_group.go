@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/permission"
@@ -38,10 +39,21 @@ func (api *API) deleteGroupFromProjectHandler() Handler {
 		}
 
 		defer tx.Rollback()
+
+		role, err := group.LoadRoleInProject(tx, p.ID, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot load role of group %s on project %s", g.Name, p.Name)
+		}
+
 		if err := group.DeleteGroupFromProject(api.mustDB(), p.ID, g.ID); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot delete group %s from project %s", g.Name, p.Name)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "project", g.Name, role, 0)
+		if err != nil {
+			return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot audit permission")
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot update last modified date")
 		}
@@ -49,6 +61,7 @@ func (api *API) deleteGroupFromProjectHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteGroupFromProjectHandler: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -106,10 +119,20 @@ func (api *API) updateGroupRoleOnProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		role, errr := group.LoadRoleInProject(tx, p.ID, g.ID)
+		if errr != nil {
+			return sdk.WrapError(errr, "updateGroupRoleHandler: Cannot load role of group %s on project %s", g.Name, p.Name)
+		}
+
 		if err := group.UpdateGroupRoleInProject(api.mustDB(), p.ID, g.ID, groupProject.Permission); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleHandler: Cannot add group %s in project %s", g.Name, p.Name)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "project", g.Name, role, groupProject.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupRoleHandler: Cannot audit permission")
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleHandler: Cannot update last modified date")
 		}
@@ -117,6 +140,7 @@ func (api *API) updateGroupRoleOnProjectHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupRoleHandler: Cannot start transaction: %s")
 		}
+		audit.Publish(evtsPerm...)
 		return WriteJSON(w, r, groupProject, http.StatusOK)
 	}
 }
@@ -159,6 +183,10 @@ func (api *API) updateGroupsInProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		if err := group.LoadGroupByProject(tx, p); err != nil {
+			return sdk.WrapError(err, "updateGroupsInProject: Cannot load groups of project %s", p.Name)
+		}
+
 		err = group.DeleteGroupProjectByProject(tx, p.ID)
 		if err != nil {
 			return sdk.WrapError(err, "updateGroupsInProject: Cannot delete groups from project %s", p.Name)
@@ -175,9 +203,15 @@ func (api *API) updateGroupsInProjectHandler() Handler {
 			}
 		}
 
+		evtsPerm, err := api.auditPermissions(ctx, tx, key, "project", p.ProjectGroups, groupProject)
+		if err != nil {
+			return sdk.WrapError(err, "updateGroupsInProject: Cannot audit permissions")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateGroupsInProject: Cannot commit transaction")
 		}
+		audit.Publish(evtsPerm...)
 		return nil
 	}
 }
@@ -217,10 +251,18 @@ func (api *API) addGroupInProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		if err := group.InsertGroupInProject(tx, p.ID, g.ID, groupProject.Permission); err != nil {
 			return sdk.WrapError(err, "AddGroupInProject: Cannot add group %s in project %s", g.Name, p.Name)
 		}
 
+		evtsPerm, err := api.auditPermission(ctx, tx, key, "project", g.Name, 0, groupProject.Permission)
+		if err != nil {
+			return sdk.WrapError(err, "AddGroupInProject: Cannot audit permission")
+		}
+		evts = append(evts, evtsPerm...)
+
 		// apply on application
 		applications, errla := application.LoadAll(tx, api.Cache, p.Key, getUser(ctx))
 		if errla != nil {
@@ -229,17 +271,22 @@ func (api *API) addGroupInProjectHandler() Handler {
 
 		for _, app := range applications {
 			if permission.AccessToApplication(app.ID, getUser(ctx), permission.PermissionReadWriteExecute) {
-				inApp, err := group.CheckGroupInApplication(tx, app.ID, g.ID)
+				role, err := group.LoadRoleInApplication(tx, app.ID, g.ID)
 				if err != nil {
 					return sdk.WrapError(err, "AddGroupInProject: Cannot check if group %s is already in the application %s", g.Name, app.Name)
 				}
-				if inApp {
+				if inApp := role != 0; inApp {
 					if err := group.UpdateGroupRoleInApplication(tx, p.Key, app.Name, g.Name, groupProject.Permission); err != nil {
 						return sdk.WrapError(err, "AddGroupInProject: Cannot update group %s on application %s", g.Name, app.Name)
 					}
 				} else if err := application.AddGroup(tx, api.Cache, p, &app, getUser(ctx), groupProject); err != nil {
 					return sdk.WrapError(err, "AddGroupInProject: Cannot insert group %s on application %s", g.Name, app.Name)
 				}
+				evtsPerm, err := api.auditPermission(ctx, tx, key, "application/"+app.Name, g.Name, role, groupProject.Permission)
+				if err != nil {
+					return sdk.WrapError(err, "AddGroupInProject: Cannot audit permission")
+				}
+				evts = append(evts, evtsPerm...)
 			}
 		}
 
@@ -251,17 +298,22 @@ func (api *API) addGroupInProjectHandler() Handler {
 
 		for _, pip := range pipelines {
 			if permission.AccessToPipeline(sdk.DefaultEnv.ID, pip.ID, getUser(ctx), permission.PermissionReadWriteExecute) {
-				inPip, err := group.CheckGroupInPipeline(tx, pip.ID, g.ID)
+				role, err := group.LoadRoleInPipeline(tx, pip.ID, g.ID)
 				if err != nil {
 					return sdk.WrapError(err, "AddGroupInProject: Cannot check if group %s is already in the pipeline %s", g.Name, pip.Name)
 				}
-				if inPip {
+				if inPip := role != 0; inPip {
 					if err := group.UpdateGroupRoleInPipeline(tx, pip.ID, g.ID, groupProject.Permission); err != nil {
 						return sdk.WrapError(err, "AddGroupInProject: Cannot update group %s on pipeline %s", g.Name, pip.Name)
 					}
 				} else if err := group.InsertGroupInPipeline(tx, pip.ID, g.ID, groupProject.Permission); err != nil {
 					return sdk.WrapError(err, "AddGroupInProject: Cannot insert group %s on pipeline %s", g.Name, pip.Name)
 				}
+				evtsPerm, err := api.auditPermission(ctx, tx, key, "pipeline/"+pip.Name, g.Name, role, groupProject.Permission)
+				if err != nil {
+					return sdk.WrapError(err, "AddGroupInProject: Cannot audit permission")
+				}
+				evts = append(evts, evtsPerm...)
 			}
 		}
 
@@ -273,17 +325,22 @@ func (api *API) addGroupInProjectHandler() Handler {
 
 		for _, env := range envs {
 			if permission.AccessToEnvironment(env.ID, getUser(ctx), permission.PermissionReadWriteExecute) {
-				inEnv, err := group.IsInEnvironment(tx, env.ID, g.ID)
+				role, err := group.LoadRoleInEnvironment(tx, env.ID, g.ID)
 				if err != nil {
 					return sdk.WrapError(err, "AddGroupInProject: Cannot check if group %s is already in the environment %s", g.Name, env.Name)
 				}
-				if inEnv {
+				if inEnv := role != 0; inEnv {
 					if err := group.UpdateGroupRoleInEnvironment(tx, p.Key, env.Name, g.Name, groupProject.Permission); err != nil {
 						return sdk.WrapError(err, "AddGroupInProject: Cannot update group %s on environment %s", g.Name, env.Name)
 					}
 				} else if err := group.InsertGroupInEnvironment(tx, env.ID, g.ID, groupProject.Permission); err != nil {
 					return sdk.WrapError(err, "AddGroupInProject: Cannot insert group %s on environment %s", g.Name, env.Name)
 				}
+				evtsPerm, err := api.auditPermission(ctx, tx, key, "environment/"+env.Name, g.Name, role, groupProject.Permission)
+				if err != nil {
+					return sdk.WrapError(err, "AddGroupInProject: Cannot audit permission")
+				}
+				evts = append(evts, evtsPerm...)
 			}
 		}
 
@@ -294,6 +351,7 @@ func (api *API) addGroupInProjectHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "AddGroupInProject: Cannot commit transaction")
 		}
+		audit.Publish(evts...)
 
 		if err := group.LoadGroupByProject(api.mustDB(), p); err != nil {
 			return sdk.WrapError(err, "AddGroupInProject: Cannot load groups on project %s", p.Key)
@@ -0,0 +1,806 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// importChange is a change planned by the import of a project tree with the function applying it, which
+// returns the events of its audits
+type importChange struct {
+	sdk.ImportChange
+	apply func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error)
+}
+
+// postProjectImportHandler imports a project tree in a project. The changes are planned by comparing
+// the tree with the project, and only applied when it's not a dry run. The pipelines and workflows
+// of the tree replace the existing ones, but the variables, the permissions and the pipelines
+// attached to the applications are only added or updated: the import never deletes anything
+func (api *API) postProjectImportHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		dryRun := FormBool(r, "dryRun")
+
+		var tree exportentities.ProjectTree
+		if err := UnmarshalBody(r, &tree); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot read body")
+		}
+
+		// The secrets can only be decrypted with the key of the project they were exported from
+		if err := tree.Values(func(name string, v exportentities.VariableValue) (exportentities.VariableValue, error) {
+			clear, err := decryptSecret(key, v.Type, v.Value)
+			if err != nil {
+				return v, sdk.WrapError(err, "postProjectImportHandler> Cannot decrypt %s", name)
+			}
+			return exportentities.VariableValue{Type: v.Type, Value: clear}, nil
+		}); err != nil {
+			return err
+		}
+
+		tx, errT := api.mustDB().Begin()
+		if errT != nil {
+			return sdk.WrapError(errT, "postProjectImportHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		current, err := api.loadProjectTree(ctx, tx, key)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot load project %s", key)
+		}
+
+		changes, err := api.planProjectImport(ctx, key, current, &tree)
+		if err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot plan the import in project %s", key)
+		}
+
+		plan := make([]sdk.ImportChange, len(changes))
+		for i := range changes {
+			plan[i] = changes[i].ImportChange
+		}
+		if dryRun {
+			return WriteJSON(w, r, plan, http.StatusOK)
+		}
+
+		allMsg := []sdk.Message{}
+		msgChan := make(chan sdk.Message, 10)
+		done := make(chan bool)
+		go func() {
+			for msg := range msgChan {
+				allMsg = append(allMsg, msg)
+			}
+			done <- true
+		}()
+
+		evts := []sdk.EventAudit{}
+		var errApply error
+		for _, c := range changes {
+			if c.Action == sdk.ImportUnchanged {
+				continue
+			}
+			evtsChange, err := c.apply(tx, msgChan)
+			if err != nil {
+				errApply = sdk.WrapError(err, "postProjectImportHandler> Cannot import %s %s", c.EntityType, c.EntityName)
+				break
+			}
+			evts = append(evts, evtsChange...)
+		}
+		close(msgChan)
+		<-done
+
+		for _, m := range allMsg {
+			log.Debug("postProjectImportHandler> %s", m.String(r.Header.Get("Accept-Language")))
+		}
+		if errApply != nil {
+			return errApply
+		}
+
+		proj, err := project.Load(tx, api.Cache, key, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot load project %s", key)
+		}
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), proj); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot update project last modified date")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postProjectImportHandler> Cannot commit transaction")
+		}
+		audit.Publish(evts...)
+		return WriteJSON(w, r, plan, http.StatusOK)
+	}
+}
+
+// planProjectImport compares a project tree with the current one of a project, and returns the
+// changes of each entity in the order they must be applied
+func (api *API) planProjectImport(ctx context.Context, key string, current, tree *exportentities.ProjectTree) ([]importChange, error) {
+	changes := []importChange{}
+	add := func(entityType, name string, before, after interface{}, apply func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error)) error {
+		diff, err := importDiff(key, before, after)
+		if err != nil {
+			return sdk.WrapError(err, "planProjectImport> Cannot compare %s %s", entityType, name)
+		}
+		c := importChange{
+			ImportChange: sdk.ImportChange{EntityType: entityType, EntityName: name, Diff: diff},
+			apply:        apply,
+		}
+		switch {
+		case before == nil:
+			c.Action = sdk.ImportAdd
+		case len(diff) > 0:
+			c.Action = sdk.ImportUpdate
+		default:
+			c.Action = sdk.ImportUnchanged
+		}
+		changes = append(changes, c)
+		return nil
+	}
+
+	// Project
+	proj := current.Project
+	merged := tree.Project
+	merged.Key = key
+	if merged.Name == "" {
+		merged.Name = proj.Name
+	}
+	merged.Permissions = mergePermissions(proj.Permissions, merged.Permissions)
+	merged.Variables = mergeVariables(proj.Variables, merged.Variables)
+	if err := add("project", key, proj, merged, func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+		return api.importProject(ctx, tx, &merged)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Environments
+	envs := map[string]bool{}
+	for i := range current.Environments {
+		envs[current.Environments[i].Name] = true
+	}
+	for i := range tree.Environments {
+		e := tree.Environments[i]
+		var before interface{}
+		for j := range current.Environments {
+			if cur := current.Environments[j]; cur.Name == e.Name {
+				e.Permissions = mergePermissions(cur.Permissions, e.Permissions)
+				e.Values = mergeVariables(cur.Values, e.Values)
+				before = cur
+			}
+		}
+		envs[e.Name] = true
+		if err := add("environment", e.Name, before, e, func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+			return api.importEnvironment(ctx, tx, key, &e)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Pipelines
+	pips := map[string]bool{}
+	for i := range current.Pipelines {
+		pips[current.Pipelines[i].Name] = true
+	}
+	for i := range tree.Pipelines {
+		p := tree.Pipelines[i]
+		if _, err := p.Pipeline(); err != nil {
+			return nil, sdk.WrapError(sdk.ErrInvalidPipeline, "planProjectImport> Invalid pipeline %s: %s", p.Name, err)
+		}
+		var before interface{}
+		for j := range current.Pipelines {
+			if cur := current.Pipelines[j]; cur.Name == p.Name {
+				p.Permissions = mergePermissions(cur.Permissions, p.Permissions)
+				before = cur
+			}
+		}
+		exists := before != nil
+		pips[p.Name] = true
+		if err := add("pipeline", p.Name, before, p, func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+			return api.importPipeline(ctx, tx, key, &p, exists, msgChan)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Applications
+	apps := map[string]bool{}
+	for i := range current.Applications {
+		apps[current.Applications[i].Name] = true
+	}
+	for i := range tree.Applications {
+		a := tree.Applications[i]
+		var before interface{}
+		for j := range current.Applications {
+			if cur := current.Applications[j]; cur.Name == a.Name {
+				a.Permissions = mergePermissions(cur.Permissions, a.Permissions)
+				a.Variables = mergeVariables(cur.Variables, a.Variables)
+				pipelines := make(map[string]exportentities.ApplicationPipeline, len(cur.Pipelines))
+				for name, ap := range cur.Pipelines {
+					pipelines[name] = exportentities.ApplicationPipeline{Parameters: ap.Parameters}
+				}
+				for name, ap := range a.Pipelines {
+					pipelines[name] = exportentities.ApplicationPipeline{Parameters: ap.Parameters}
+				}
+				a.Pipelines = pipelines
+				if a.RepositoryManager == "" {
+					a.RepositoryManager, a.RepositoryName = cur.RepositoryManager, cur.RepositoryName
+				}
+				before = cur
+			}
+		}
+		for name := range a.Pipelines {
+			if !pips[name] {
+				return nil, sdk.WrapError(sdk.ErrPipelineNotFound, "planProjectImport> Unknown pipeline %s in application %s", name, a.Name)
+			}
+		}
+		exists := before != nil
+		apps[a.Name] = true
+		if err := add("application", a.Name, before, a, func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+			return api.importApplication(ctx, tx, key, &a, exists)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Workflows
+	for i := range tree.Workflows {
+		wf := tree.Workflows[i]
+		if _, err := wf.Workflow(); err != nil {
+			return nil, sdk.WrapError(err, "planProjectImport> Invalid workflow %s", wf.Name)
+		}
+		for _, n := range wf.Nodes {
+			switch {
+			case !pips[n.Pipeline]:
+				return nil, sdk.WrapError(sdk.ErrPipelineNotFound, "planProjectImport> Unknown pipeline %s in workflow %s", n.Pipeline, wf.Name)
+			case n.Application != "" && !apps[n.Application]:
+				return nil, sdk.WrapError(sdk.ErrApplicationNotFound, "planProjectImport> Unknown application %s in workflow %s", n.Application, wf.Name)
+			case n.Environment != "" && !envs[n.Environment]:
+				return nil, sdk.WrapError(sdk.ErrNoEnvironment, "planProjectImport> Unknown environment %s in workflow %s", n.Environment, wf.Name)
+			}
+		}
+		var before interface{}
+		for j := range current.Workflows {
+			if cur := current.Workflows[j]; cur.Name == wf.Name {
+				before = workflowNodesByName(cur)
+			}
+		}
+		exists := before != nil
+		if err := add("workflow", wf.Name, before, workflowNodesByName(wf), func(tx gorp.SqlExecutor, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+			return api.importWorkflow(ctx, tx, key, &wf, exists)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// mergePermissions returns the current roles updated with the imported ones
+func mergePermissions(current, imported map[string]int) map[string]int {
+	res := make(map[string]int, len(current)+len(imported))
+	for k, v := range current {
+		res[k] = v
+	}
+	for k, v := range imported {
+		res[k] = v
+	}
+	return res
+}
+
+// mergeVariables returns the current variables updated with the imported ones
+func mergeVariables(current, imported map[string]exportentities.VariableValue) map[string]exportentities.VariableValue {
+	res := make(map[string]exportentities.VariableValue, len(current)+len(imported))
+	for k, v := range current {
+		res[k] = v
+	}
+	for k, v := range imported {
+		res[k] = v
+	}
+	return res
+}
+
+// workflowNodesByName returns a workflow with its nodes indexed by name, so that its diff doesn't
+// depend on the order of the nodes
+func workflowNodesByName(w exportentities.Workflow) interface{} {
+	nodes := make(map[string]exportentities.WorkflowNode, len(w.Nodes))
+	for _, n := range w.Nodes {
+		n.DependsOn = append([]string{}, n.DependsOn...)
+		sort.Strings(n.DependsOn)
+		nodes[n.Name] = n
+	}
+	return map[string]interface{}{
+		"name":        w.Name,
+		"description": w.Description,
+		"nodes":       nodes,
+	}
+}
+
+// importDiff returns the differences between the current and the imported versions of an entity,
+// the values of the secrets are replaced by their fingerprints
+func importDiff(key string, before, after interface{}) ([]sdk.AuditDiff, error) {
+	b, err := fingerprintSecrets(key, before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fingerprintSecrets(key, after)
+	if err != nil {
+		return nil, err
+	}
+	return audit.Diff(b, a)
+}
+
+func fingerprintSecrets(key string, i interface{}) (interface{}, error) {
+	if i == nil {
+		return nil, nil
+	}
+	btes, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(btes))
+	dec.UseNumber()
+	var res interface{}
+	if err := dec.Decode(&res); err != nil {
+		return nil, err
+	}
+	return res, fingerprintValue(key, res)
+}
+
+func fingerprintValue(key string, i interface{}) error {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		// The variables have a value, and the parameters of the pipelines a default value
+		if t, ok := v["type"].(string); ok && sdk.NeedPlaceholder(t) {
+			for _, k := range []string{"value", "default"} {
+				if s, ok := v[k].(string); ok {
+					f, err := secret.Fingerprint(key, s)
+					if err != nil {
+						return err
+					}
+					v[k] = "fingerprint:" + f
+				}
+			}
+		}
+		for _, c := range v {
+			if err := fingerprintValue(key, c); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, c := range v {
+			if err := fingerprintValue(key, c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importVariables adds the missing variables and updates the ones which differ, it returns the events
+// of their audits
+func importVariables(current []sdk.Variable, values map[string]exportentities.VariableValue, insert, update func(v *sdk.Variable) (sdk.EventAudit, error)) ([]sdk.EventAudit, error) {
+	byName := make(map[string]sdk.Variable, len(current))
+	for _, v := range current {
+		byName[v.Name] = v
+	}
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	evts := []sdk.EventAudit{}
+	for _, name := range names {
+		v := &sdk.Variable{Name: name, Type: values[name].Type, Value: values[name].Value}
+		old, has := byName[name]
+		switch {
+		case !has:
+			evt, err := insert(v)
+			if err != nil {
+				return nil, sdk.WrapError(err, "importVariables> Cannot insert variable %s", name)
+			}
+			evts = append(evts, evt)
+		case old.Type != v.Type || old.Value != v.Value:
+			v.ID = old.ID
+			evt, err := update(v)
+			if err != nil {
+				return nil, sdk.WrapError(err, "importVariables> Cannot update variable %s", name)
+			}
+			evts = append(evts, evt)
+		}
+	}
+	return evts, nil
+}
+
+// importPermissions adds the missing groups and updates the roles which differ, the changes are
+// recorded in the audit log and the events of their audits are returned
+func (api *API) importPermissions(ctx context.Context, tx gorp.SqlExecutor, key, name string, current []sdk.GroupPermission, roles map[string]int, insert, update func(g *sdk.Group, role int) error) ([]sdk.EventAudit, error) {
+	before := make(map[string]int, len(current))
+	for _, gp := range current {
+		before[gp.Group.Name] = gp.Permission
+	}
+	names := make([]string, 0, len(roles))
+	for k := range roles {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	evts := []sdk.EventAudit{}
+	for _, groupName := range names {
+		role := roles[groupName]
+		if before[groupName] == role {
+			continue
+		}
+		g, err := group.LoadGroup(tx, groupName)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importPermissions> Cannot load group %s", groupName)
+		}
+		if _, has := before[groupName]; has {
+			err = update(g, role)
+		} else {
+			err = insert(g, role)
+		}
+		if err != nil {
+			return nil, sdk.WrapError(err, "importPermissions> Cannot set role of group %s on %s", groupName, name)
+		}
+		evtsPerm, err := api.auditPermission(ctx, tx, key, name, groupName, before[groupName], role)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importPermissions> Cannot audit permission of group %s on %s", groupName, name)
+		}
+		evts = append(evts, evtsPerm...)
+	}
+	return evts, nil
+}
+
+func (api *API) importProject(ctx context.Context, tx gorp.SqlExecutor, p *exportentities.Project) ([]sdk.EventAudit, error) {
+	u := getUser(ctx)
+	proj, err := project.Load(tx, api.Cache, p.Key, u, project.LoadOptions.WithGroups)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importProject> Cannot load project %s", p.Key)
+	}
+	if proj.Name != p.Name {
+		proj.Name = p.Name
+		if err := project.Update(tx, api.Cache, proj, u); err != nil {
+			return nil, sdk.WrapError(err, "importProject> Cannot update project %s", p.Key)
+		}
+	}
+
+	vars, err := project.GetAllVariableInProject(tx, proj.ID, project.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "importProject> Cannot load variables of project %s", p.Key)
+	}
+	evts, err := importVariables(vars, p.Variables, func(v *sdk.Variable) (sdk.EventAudit, error) {
+		return project.InsertVariable(tx, proj, v, u)
+	}, func(v *sdk.Variable) (sdk.EventAudit, error) {
+		return project.UpdateVariable(tx, proj, v, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	evtsPerm, err := api.importPermissions(ctx, tx, p.Key, "project", proj.ProjectGroups, p.Permissions, func(g *sdk.Group, role int) error {
+		return group.InsertGroupInProject(tx, proj.ID, g.ID, role)
+	}, func(g *sdk.Group, role int) error {
+		return group.UpdateGroupRoleInProject(tx, proj.ID, g.ID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(evts, evtsPerm...), nil
+}
+
+func (api *API) importEnvironment(ctx context.Context, tx gorp.SqlExecutor, key string, e *exportentities.Environment) ([]sdk.EventAudit, error) {
+	u := getUser(ctx)
+	proj, err := project.Load(tx, api.Cache, key, u, project.LoadOptions.WithGroups)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importEnvironment> Cannot load project %s", key)
+	}
+
+	roles := e.Permissions
+	env, err := environment.LoadEnvironmentByName(tx, key, e.Name)
+	if err == sdk.ErrNoEnvironment {
+		env = &sdk.Environment{Name: e.Name, ProjectID: proj.ID, ProjectKey: key}
+		if err := environment.InsertEnvironment(tx, env); err != nil {
+			return nil, sdk.WrapError(err, "importEnvironment> Cannot insert environment %s", e.Name)
+		}
+		// The new environments inherit the permissions of the project
+		if len(roles) == 0 {
+			roles = exportentities.NewProject(proj).Permissions
+		}
+	} else if err != nil {
+		return nil, sdk.WrapError(err, "importEnvironment> Cannot load environment %s", e.Name)
+	}
+
+	vars, err := environment.GetAllVariableByID(tx, env.ID, environment.WithClearPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "importEnvironment> Cannot load variables of environment %s", e.Name)
+	}
+	evts, err := importVariables(vars, e.Values, func(v *sdk.Variable) (sdk.EventAudit, error) {
+		return environment.InsertVariable(tx, env.ID, v, u)
+	}, func(v *sdk.Variable) (sdk.EventAudit, error) {
+		return environment.UpdateVariable(tx, env.ID, v, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	evtsPerm, err := api.importPermissions(ctx, tx, key, "environment/"+env.Name, env.EnvironmentGroups, roles, func(g *sdk.Group, role int) error {
+		return group.InsertGroupInEnvironment(tx, env.ID, g.ID, role)
+	}, func(g *sdk.Group, role int) error {
+		return group.UpdateGroupRoleInEnvironment(tx, key, env.Name, g.Name, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(evts, evtsPerm...), nil
+}
+
+func (api *API) importPipeline(ctx context.Context, tx gorp.SqlExecutor, key string, p *exportentities.Pipeline, exists bool, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+	u := getUser(ctx)
+	proj, err := project.Load(tx, api.Cache, key, u, project.LoadOptions.WithGroups)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importPipeline> Cannot load project %s", key)
+	}
+
+	pip, err := p.Pipeline()
+	if err != nil {
+		return nil, sdk.WrapError(err, "importPipeline> Cannot read pipeline %s", p.Name)
+	}
+	if err := decryptPipelineSecrets(key, pip); err != nil {
+		return nil, err
+	}
+	for i := range pip.GroupPermission {
+		gp := &pip.GroupPermission[i]
+		g, err := group.LoadGroup(tx, gp.Group.Name)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importPipeline> Cannot load group %s", gp.Group.Name)
+		}
+		gp.Group = *g
+	}
+
+	if !exists {
+		if err := pipeline.Import(tx, proj, pip, msgChan, u); err != nil {
+			return nil, sdk.WrapError(err, "importPipeline> Cannot insert pipeline %s", p.Name)
+		}
+		evt, err := api.auditPipeline(ctx, tx, key, p.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []sdk.EventAudit{evt}, nil
+	}
+
+	before, err := pipeline.LoadPipeline(tx, key, p.Name, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importPipeline> Cannot load pipeline %s", p.Name)
+	}
+	if err := pipeline.ImportUpdate(tx, proj, pip, msgChan, u); err != nil {
+		return nil, sdk.WrapError(err, "importPipeline> Cannot update pipeline %s", p.Name)
+	}
+	evt, err := api.auditPipeline(ctx, tx, key, p.Name, before)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.EventAudit{evt}, nil
+}
+
+func (api *API) importApplication(ctx context.Context, tx gorp.SqlExecutor, key string, a *exportentities.Application, exists bool) ([]sdk.EventAudit, error) {
+	u := getUser(ctx)
+	proj, err := project.Load(tx, api.Cache, key, u, project.LoadOptions.WithGroups)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importApplication> Cannot load project %s", key)
+	}
+
+	roles := a.Permissions
+	var app *sdk.Application
+	if exists {
+		app, err = application.LoadByName(tx, api.Cache, key, a.Name, u, application.LoadOptions.WithVariablesWithClearPassword,
+			application.LoadOptions.WithPipelines, application.LoadOptions.WithGroups, application.LoadOptions.WithRepositoryManager)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importApplication> Cannot load application %s", a.Name)
+		}
+	} else {
+		app = &sdk.Application{Name: a.Name}
+		if err := application.Insert(tx, api.Cache, proj, app, u); err != nil {
+			return nil, sdk.WrapError(err, "importApplication> Cannot insert application %s", a.Name)
+		}
+		// The new applications inherit the permissions of the project
+		if len(roles) == 0 {
+			roles = exportentities.NewProject(proj).Permissions
+		}
+	}
+
+	evts, err := importVariables(app.Variable, a.Variables, func(v *sdk.Variable) (sdk.EventAudit, error) {
+		return application.InsertVariable(tx, api.Cache, app, *v, u)
+	}, func(v *sdk.Variable) (sdk.EventAudit, error) {
+		return application.UpdateVariable(tx, api.Cache, app, v, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	evtsPerm, err := api.importPermissions(ctx, tx, key, "application/"+app.Name, app.ApplicationGroups, roles, func(g *sdk.Group, role int) error {
+		return application.AddGroup(tx, api.Cache, proj, app, u, sdk.GroupPermission{Group: *g, Permission: role})
+	}, func(g *sdk.Group, role int) error {
+		return group.UpdateGroupRoleInApplication(tx, key, app.Name, g.Name, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	evts = append(evts, evtsPerm...)
+
+	names := make([]string, 0, len(a.Pipelines))
+	for k := range a.Pipelines {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	params := a.Application().Pipelines
+	for _, name := range names {
+		var ap *sdk.ApplicationPipeline
+		for i := range app.Pipelines {
+			if app.Pipelines[i].Pipeline.Name == name {
+				ap = &app.Pipelines[i]
+			}
+		}
+		if ap == nil {
+			pip, err := pipeline.LoadPipeline(tx, key, name, false)
+			if err != nil {
+				return nil, sdk.WrapError(err, "importApplication> Cannot load pipeline %s", name)
+			}
+			if _, err := application.AttachPipeline(tx, app.ID, pip.ID); err != nil {
+				return nil, sdk.WrapError(err, "importApplication> Cannot attach pipeline %s to application %s", name, app.Name)
+			}
+			ap = &sdk.ApplicationPipeline{Pipeline: *pip}
+		}
+		for _, p := range params {
+			if p.Pipeline.Name != name || parametersEqual(ap.Parameters, p.Parameters) {
+				continue
+			}
+			sort.Slice(p.Parameters, func(i, j int) bool { return p.Parameters[i].Name < p.Parameters[j].Name })
+			if err := application.UpdatePipelineApplication(tx, api.Cache, app, ap.Pipeline.ID, p.Parameters, u); err != nil {
+				return nil, sdk.WrapError(err, "importApplication> Cannot update parameters of pipeline %s in application %s", name, app.Name)
+			}
+		}
+	}
+
+	if a.RepositoryManager != "" && (app.RepositoriesManager == nil || app.RepositoriesManager.Name != a.RepositoryManager || app.RepositoryFullname != a.RepositoryName) {
+		rm, err := repositoriesmanager.LoadForProject(tx, key, a.RepositoryManager, api.Cache)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importApplication> Cannot load repositories manager %s", a.RepositoryManager)
+		}
+		app.RepositoriesManager = rm
+		app.RepositoryFullname = a.RepositoryName
+		if err := repositoriesmanager.InsertForApplication(tx, app, key); err != nil {
+			return nil, sdk.WrapError(err, "importApplication> Cannot attach repository %s to application %s", a.RepositoryName, app.Name)
+		}
+	}
+	return evts, nil
+}
+
+func parametersEqual(p1, p2 []sdk.Parameter) bool {
+	if len(p1) != len(p2) {
+		return false
+	}
+	values := make(map[string]sdk.Parameter, len(p1))
+	for _, p := range p1 {
+		values[p.Name] = p
+	}
+	for _, p := range p2 {
+		if v, has := values[p.Name]; !has || v.Type != p.Type || v.Value != p.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func (api *API) importWorkflow(ctx context.Context, tx gorp.SqlExecutor, key string, w *exportentities.Workflow, exists bool) ([]sdk.EventAudit, error) {
+	u := getUser(ctx)
+	proj, err := project.Load(tx, api.Cache, key, u, project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importWorkflow> Cannot load project %s", key)
+	}
+
+	wf, err := w.Workflow()
+	if err != nil {
+		return nil, sdk.WrapError(err, "importWorkflow> Invalid workflow %s", w.Name)
+	}
+	wf.ProjectID = proj.ID
+	wf.ProjectKey = key
+
+	var resolve func(n *sdk.WorkflowNode) error
+	resolve = func(n *sdk.WorkflowNode) error {
+		var found bool
+		for _, p := range proj.Pipelines {
+			if p.Name == n.Pipeline.Name {
+				n.Pipeline = p
+				n.PipelineID = p.ID
+				found = true
+			}
+		}
+		if !found {
+			return sdk.WrapError(sdk.ErrPipelineNotFound, "importWorkflow> Unknown pipeline %s", n.Pipeline.Name)
+		}
+		if a := n.Context.Application; a != nil {
+			n.Context.Application = nil
+			for i := range proj.Applications {
+				if proj.Applications[i].Name == a.Name {
+					n.Context.Application = &proj.Applications[i]
+					n.Context.ApplicationID = proj.Applications[i].ID
+				}
+			}
+			if n.Context.Application == nil {
+				return sdk.WrapError(sdk.ErrApplicationNotFound, "importWorkflow> Unknown application %s", a.Name)
+			}
+		}
+		if e := n.Context.Environment; e != nil {
+			n.Context.Environment = nil
+			for i := range proj.Environments {
+				if proj.Environments[i].Name == e.Name {
+					n.Context.Environment = &proj.Environments[i]
+					n.Context.EnvironmentID = proj.Environments[i].ID
+				}
+			}
+			if n.Context.Environment == nil {
+				return sdk.WrapError(sdk.ErrNoEnvironment, "importWorkflow> Unknown environment %s", e.Name)
+			}
+		}
+		for i := range n.Triggers {
+			if err := resolve(&n.Triggers[i].WorkflowDestNode); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := resolve(wf.Root); err != nil {
+		return nil, err
+	}
+	for i := range wf.Joins {
+		for j := range wf.Joins[i].Triggers {
+			if err := resolve(&wf.Joins[i].Triggers[j].WorkflowDestNode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !exists {
+		if err := workflow.Insert(tx, api.Cache, wf, proj, u); err != nil {
+			return nil, sdk.WrapError(err, "importWorkflow> Cannot insert workflow %s", w.Name)
+		}
+		evt, err := api.auditWorkflow(ctx, tx, key, wf.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []sdk.EventAudit{evt}, nil
+	}
+
+	old, err := workflow.Load(tx, api.Cache, key, w.Name, u)
+	if err != nil {
+		return nil, sdk.WrapError(err, "importWorkflow> Cannot load workflow %s", w.Name)
+	}
+	wf.ID = old.ID
+	wf.RootID = old.RootID
+	wf.Root.ID = old.RootID
+	if err := workflow.Update(tx, api.Cache, wf, old, proj, u); err != nil {
+		return nil, sdk.WrapError(err, "importWorkflow> Cannot update workflow %s", w.Name)
+	}
+	evt, err := api.auditWorkflow(ctx, tx, key, wf.ID, old)
+	if err != nil {
+		return nil, err
+	}
+	return []sdk.EventAudit{evt}, nil
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/loopfz/gadgeto/iffy"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func Test_postProjectImportHandler(t *testing.T) {
+	api, db, router := newTestAPI(t)
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	tester := iffy.NewTester(t, router.Mux)
+
+	pkey := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, pkey, pkey, u)
+	v := sdk.Variable{Name: "password", Type: sdk.SecretVariable, Value: "secret"}
+	if _, err := project.InsertVariable(api.mustDB(), proj, &v, u); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]string{"permProjectKey": proj.Key}
+	headers := assets.AuthHeaders(t, u, pass)
+
+	var tree exportentities.ProjectTree
+	route := router.GetRoute("GET", api.getProjectExportHandler, vars)
+	tester.AddCall("Test_postProjectImportHandler_export", "GET", route, nil).Headers(headers).Checkers(iffy.ExpectStatus(200), iffy.UnmarshalResponse(&tree))
+	tester.Run()
+	tester.Reset()
+
+	exported := tree.Project.Variables["password"]
+	enc, ok := exportentities.ParseSecret(exported.Value)
+	assert.True(t, ok)
+
+	// Importing the exported tree doesn't change anything
+	var changes []sdk.ImportChange
+	route = router.GetRoute("POST", api.postProjectImportHandler, vars)
+	tester.AddCall("Test_postProjectImportHandler_unchanged", "POST", route+"?dryRun=true", tree).Headers(headers).Checkers(iffy.ExpectStatus(200), iffy.UnmarshalResponse(&changes))
+	tester.Run()
+	tester.Reset()
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, sdk.ImportUnchanged, changes[0].Action)
+	}
+
+	// Add a variable and an environment
+	tree.Project.Variables["foo"] = exportentities.VariableValue{Type: sdk.StringVariable, Value: "bar"}
+	tree.Environments = []exportentities.Environment{{Name: "prod", Values: map[string]exportentities.VariableValue{"url": {Type: sdk.StringVariable, Value: "http://prod"}}}}
+	tester.AddCall("Test_postProjectImportHandler_import", "POST", route, tree).Headers(headers).Checkers(iffy.ExpectStatus(200), iffy.UnmarshalResponse(&changes))
+	tester.Run()
+	tester.Reset()
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, sdk.ImportUpdate, changes[0].Action)
+		assert.Equal(t, sdk.ImportAdd, changes[1].Action)
+		assert.Equal(t, "prod", changes[1].EntityName)
+	}
+
+	vs, err := project.GetAllVariableInProject(db, proj.ID, project.WithClearPassword())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, vs, 2)
+
+	// A secret encrypted for another project can't be imported
+	tree.Project.Variables["password"] = exportentities.VariableValue{Type: sdk.SecretVariable, Value: exportentities.Secret("x" + enc)}
+	tester.AddCall("Test_postProjectImportHandler_invalid", "POST", route+"?dryRun=true", tree).Headers(headers).Checkers(iffy.ExpectStatus(400))
+	tester.Run()
+}
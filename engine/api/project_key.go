@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/sdk"
@@ -46,11 +47,17 @@ func (api *API) deleteKeyInProjectHandler() Handler {
 			return sdk.WrapError(errT, "deleteKeyInProjectHandler> Cannot start transaction")
 		}
 		defer tx.Rollback()
+		evts := []sdk.EventAudit{}
 		for _, k := range p.Keys {
 			if k.Name == keyName {
 				if err := project.DeleteProjectKey(tx, p.ID, keyName); err != nil {
 					return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot delete key %s", k.Name)
 				}
+				evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityKey, Name: "project/" + k.Name}, k, nil)
+				if err != nil {
+					return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot audit key %s", k.Name)
+				}
+				evts = append(evts, evt)
 				if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 					return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot update project last modified date")
 				}
@@ -60,6 +67,7 @@ func (api *API) deleteKeyInProjectHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteKeyInProjectHandler> Cannot commit transaction")
 		}
+		audit.Publish(evts...)
 
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -117,6 +125,11 @@ func (api *API) addKeyInProjectHandler() Handler {
 			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot insert project key")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityKey, Name: "project/" + newKey.Name}, nil, newKey)
+		if err != nil {
+			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot audit key %s", newKey.Name)
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot update project last modified date")
 		}
@@ -124,6 +137,7 @@ func (api *API) addKeyInProjectHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "addKeyInProjectHandler> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		return WriteJSON(w, r, newKey, http.StatusOK)
 	}
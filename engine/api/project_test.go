@@ -21,20 +21,20 @@ func TestVariableInProject(t *testing.T) {
 		Value: "value1",
 		Type:  "PASSWORD",
 	}
-	err := project.InsertVariable(api.mustDB(), project1, var1, &sdk.User{Username: "foo"})
+	_, err := project.InsertVariable(api.mustDB(), project1, var1, &sdk.User{Username: "foo"})
 	if err != nil {
 		t.Fatalf("cannot insert var1 in project1: %s", err)
 	}
 
 	// 3. Test Update variable
 	var1.Value = "value1Updated"
-	err = project.UpdateVariable(api.mustDB(), project1, var1, &sdk.User{Username: "foo"})
+	_, err = project.UpdateVariable(api.mustDB(), project1, var1, &sdk.User{Username: "foo"})
 	if err != nil {
 		t.Fatalf("cannot update var1 in project1: %s", err)
 	}
 
 	// 4. Delete variable
-	err = project.DeleteVariable(api.mustDB(), project1, var1, &sdk.User{Username: "foo"})
+	_, err = project.DeleteVariable(api.mustDB(), project1, var1, &sdk.User{Username: "foo"})
 	if err != nil {
 		t.Fatalf("cannot delete var1 from project: %s", err)
 	}
@@ -49,7 +49,7 @@ func TestVariableInProject(t *testing.T) {
 		Value: "value2",
 		Type:  "STRING",
 	}
-	err = project.InsertVariable(api.mustDB(), project1, var2, &sdk.User{Username: "foo"})
+	_, err = project.InsertVariable(api.mustDB(), project1, var2, &sdk.User{Username: "foo"})
 	if err != nil {
 		t.Fatalf("cannot insert var1 in project1: %s", err)
 	}
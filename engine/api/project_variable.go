@@ -7,6 +7,8 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/impact"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
 	"github.com/ovh/cds/engine/api/secret"
@@ -65,6 +67,8 @@ func (api *API) restoreProjectVariableAuditHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		if err := project.DeleteAllVariable(tx, p.ID); err != nil {
 			log.Warning("restoreProjectVariableAuditHandler: Cannot delete variables for project %s:  %s\n", key, err)
 			return sdk.ErrUnknownError
@@ -81,10 +85,12 @@ func (api *API) restoreProjectVariableAuditHandler() Handler {
 				}
 				v.Value = string(value)
 			}
-			if err := project.InsertVariable(tx, p, &v, getUser(ctx)); err != nil {
+			evt, err := project.InsertVariable(tx, p, &v, getUser(ctx))
+			if err != nil {
 				log.Warning("restoreProjectVariableAuditHandler: Cannot insert variable %s for project %s:  %s\n", v.Name, key, err)
 				return err
 			}
+			evts = append(evts, evt)
 		}
 
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
@@ -96,6 +102,7 @@ func (api *API) restoreProjectVariableAuditHandler() Handler {
 			log.Warning("restoreProjectVariableAuditHandler: Cannot commit transaction:  %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
@@ -136,6 +143,14 @@ func (api *API) deleteVariableFromProjectHandler() Handler {
 			return err
 		}
 
+		impacts, errImpact := impact.Variable(api.mustDB(), api.Cache, getUser(ctx), key, "cds.proj."+varName)
+		if errImpact != nil {
+			return sdk.WrapError(errImpact, "deleteVariableFromProject> Cannot load impact of variable %s", varName)
+		}
+		if err := checkImpacts(r, impacts); err != nil {
+			return sdk.WrapError(err, "deleteVariableFromProject> Variable %s is used by %d entities", varName, len(impacts))
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			log.Warning("deleteVariableFromProject: Cannot start transaction: %s\n", err)
@@ -148,7 +163,8 @@ func (api *API) deleteVariableFromProjectHandler() Handler {
 			return sdk.WrapError(errV, "deleteVariableFromProject> Cannot load variable %s", varName)
 		}
 
-		if err := project.DeleteVariable(tx, p, varToDelete, getUser(ctx)); err != nil {
+		evt, err := project.DeleteVariable(tx, p, varToDelete, getUser(ctx))
+		if err != nil {
 			log.Warning("deleteVariableFromProject: Cannot delete %s: %s\n", varName, err)
 			return err
 		}
@@ -162,6 +178,7 @@ func (api *API) deleteVariableFromProjectHandler() Handler {
 			log.Warning("deleteVariableFromProject: Cannot commit transaction: %s\n", err)
 			return err
 		}
+		audit.Publish(evt)
 
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -194,6 +211,8 @@ func (api *API) updateVariablesInProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
+
 		// Preload values, if one password variable has a password placeholder, we can't just insert
 		// the placeholder !
 		preload, err := project.GetAllVariableInProject(tx, p.ID, project.WithClearPassword())
@@ -219,43 +238,47 @@ func (api *API) updateVariablesInProjectHandler() Handler {
 						}
 					}
 				}
-				err = project.InsertVariable(tx, p, &v, getUser(ctx))
+				evt, err := project.InsertVariable(tx, p, &v, getUser(ctx))
 				if err != nil {
 					log.Warning("updateVariablesInProjectHandler: Cannot insert variable %s in project %s: %s\n", v.Name, p.Key, err)
 					return err
 
 				}
+				evts = append(evts, evt)
 				break
 			// In case of a key variable, if empty, generate a pair and add them as variable
 			case sdk.KeyVariable:
 				if v.Value == "" {
-					err := project.AddKeyPair(tx, p, v.Name, getUser(ctx))
+					evtsKey, err := project.AddKeyPair(tx, p, v.Name, getUser(ctx))
 					if err != nil {
 						log.Warning("updateVariablesInProjectHandler> cannot generate keypair: %s\n", err)
 						return err
 
 					}
+					evts = append(evts, evtsKey...)
 				} else if v.Value == sdk.PasswordPlaceholder {
 					for _, p := range preload {
 						if p.ID == v.ID {
 							v.Value = p.Value
 						}
 					}
-					err = project.InsertVariable(tx, p, &v, getUser(ctx))
+					evt, err := project.InsertVariable(tx, p, &v, getUser(ctx))
 					if err != nil {
 						log.Warning("updateVariablesInProjectHandler: Cannot insert variable %s in project %s: %s\n", v.Name, p.Key, err)
 						return err
 
 					}
+					evts = append(evts, evt)
 				}
 				break
 			default:
-				err = project.InsertVariable(tx, p, &v, getUser(ctx))
+				evt, err := project.InsertVariable(tx, p, &v, getUser(ctx))
 				if err != nil {
 					log.Warning("updateVariablesInProjectHandler: Cannot insert variable %s in project %s: %s\n", v.Name, p.Key, err)
 					return err
 
 				}
+				evts = append(evts, evt)
 			}
 		}
 
@@ -268,6 +291,7 @@ func (api *API) updateVariablesInProjectHandler() Handler {
 			log.Warning("updateVariablesInProjectHandler: Cannot commit transaction: %s\n", err)
 			return sdk.ErrNotFound
 		}
+		audit.Publish(evts...)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
@@ -310,7 +334,8 @@ func (api *API) updateVariableInProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
-		if err := project.UpdateVariable(tx, p, &newVar, getUser(ctx)); err != nil {
+		evt, err := project.UpdateVariable(tx, p, &newVar, getUser(ctx))
+		if err != nil {
 			log.Warning("updateVariableInProject: Cannot update variable %s in project %s:  %s\n", varName, p.Name, err)
 			return err
 		}
@@ -325,6 +350,7 @@ func (api *API) updateVariableInProjectHandler() Handler {
 			return err
 
 		}
+		audit.Publish(evt)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
@@ -401,12 +427,15 @@ func (api *API) addVariableInProjectHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		evts := []sdk.EventAudit{}
 		switch newVar.Type {
 		case sdk.KeyVariable:
-			err = project.AddKeyPair(tx, p, newVar.Name, getUser(ctx))
+			evts, err = project.AddKeyPair(tx, p, newVar.Name, getUser(ctx))
 			break
 		default:
-			err = project.InsertVariable(tx, p, &newVar, getUser(ctx))
+			var evt sdk.EventAudit
+			evt, err = project.InsertVariable(tx, p, &newVar, getUser(ctx))
+			evts = append(evts, evt)
 			break
 		}
 		if err != nil {
@@ -424,6 +453,7 @@ func (api *API) addVariableInProjectHandler() Handler {
 			log.Warning("addVariableInProjectHandler: cannot commit tx: %s\n", err)
 			return err
 		}
+		audit.Publish(evts...)
 
 		go func() {
 			if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, p); err != nil {
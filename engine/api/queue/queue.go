@@ -196,6 +196,14 @@ func addJobsToQueue(tx gorp.SqlExecutor, stage *sdk.Stage, pb *sdk.PipelineBuild
 			pbJob.Status = sdk.StatusDisabled.String()
 		} else if !prerequisitesOK {
 			pbJob.Status = sdk.StatusSkipped.String()
+		} else if pbJob.Job.Action.Condition != "" {
+			jobConditionOK, errc := sdk.EvaluateExpression(pbJob.Job.Action.Condition, sdk.ParametersToMap(pbJobParams))
+			if errc != nil {
+				log.Warning("addJobsToQueue> Cannot evaluate condition of job %s: %s", pbJob.Job.Action.Name, errc)
+				pbJob.Status = sdk.StatusFail.String()
+			} else if !jobConditionOK {
+				pbJob.Status = sdk.StatusSkipped.String()
+			}
 		}
 		if err := pipeline.InsertPipelineBuildJob(tx, &pbJob); err != nil {
 			return sdk.WrapError(err, "addJobToQueue> Cannot insert job in queue for pipeline build %d", pb.ID)
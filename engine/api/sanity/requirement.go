@@ -134,6 +134,16 @@ func checkNoWorkerModelMatchRequirement(proj string, pip string, a *sdk.Action,
 				break
 			}
 
+			// Labels are declared in the capabilities of the model, os-arch too but it's optional
+			if ar.Type == sdk.LabelRequirement && !sdk.MatchLabelRequirements(wm.Capabilities, []sdk.Requirement{ar}) {
+				ok = false
+				break
+			}
+			if ar.Type == sdk.OSArchRequirement && wm.OSArch() != "" && wm.OSArch() != ar.Value {
+				ok = false
+				break
+			}
+
 			// We are only checkins binary requirement matching with binary capabilities
 			// so let's skip this other types of requirements
 			if ar.Type != sdk.BinaryRequirement {
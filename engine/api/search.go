@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/search"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getSearchHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f := sdk.SearchFilter{
+			Query:      r.FormValue("q"),
+			ProjectKey: r.FormValue("project"),
+		}
+		if t := r.FormValue("type"); t != "" {
+			f.Types = strings.Split(t, ",")
+		}
+		if s := r.FormValue("limit"); s != "" {
+			var err error
+			if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit < 0 {
+				return sdk.WrapError(sdk.ErrWrongRequest, "getSearchHandler> Invalid limit %s", s)
+			}
+		}
+
+		results, err := search.Search(api.mustDB(), getUser(ctx), f)
+		if err != nil {
+			return sdk.WrapError(err, "getSearchHandler> Cannot search %s", f.Query)
+		}
+		return WriteJSON(w, r, results, http.StatusOK)
+	}
+}
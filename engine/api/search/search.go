@@ -0,0 +1,238 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+)
+
+// DefaultLimit is the max number of results by type returned when the filter has no limit
+const DefaultLimit = 50
+
+// maxExcerpt is the max length of the script lines returned as match
+const maxExcerpt = 120
+
+// searcher looks for one kind of result. Its query must select the project key, the entity and
+// the match, it receives the text search query as $1 and the project filter as %s
+type searcher struct {
+	resultType string
+	query      string
+	excerpt    bool
+}
+
+// jobs is the join from the joined actions to the pipelines which use them as jobs
+const jobs = `JOIN pipeline_action ON pipeline_action.action_id = job.id
+	JOIN pipeline_stage ON pipeline_stage.id = pipeline_action.pipeline_stage_id
+	JOIN pipeline ON pipeline.id = pipeline_stage.pipeline_id
+	JOIN project ON project.id = pipeline.project_id`
+
+var searchers = []searcher{
+	{
+		resultType: sdk.SearchTypeProject,
+		query: `SELECT project.projectkey, 'project', project.name FROM project
+			WHERE search_vector(project.projectkey || ' ' || project.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeApplication,
+		query: `SELECT project.projectkey, 'application/' || application.name, application.name FROM application
+			JOIN project ON project.id = application.project_id
+			WHERE search_vector(application.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypePipeline,
+		query: `SELECT project.projectkey, 'pipeline/' || pipeline.name, pipeline.name FROM pipeline
+			JOIN project ON project.id = pipeline.project_id
+			WHERE search_vector(pipeline.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeEnvironment,
+		query: `SELECT project.projectkey, 'environment/' || environment.name, environment.name FROM environment
+			JOIN project ON project.id = environment.project_id
+			WHERE search_vector(environment.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeWorkflow,
+		query: `SELECT project.projectkey, 'workflow/' || workflow.name, workflow.name FROM workflow
+			JOIN project ON project.id = workflow.project_id
+			WHERE search_vector(workflow.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeVariable,
+		query: `SELECT project.projectkey, 'project', project_variable.var_name FROM project_variable
+			JOIN project ON project.id = project_variable.project_id
+			WHERE search_vector(project_variable.var_name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeVariable,
+		query: `SELECT project.projectkey, 'application/' || application.name, application_variable.var_name FROM application_variable
+			JOIN application ON application.id = application_variable.application_id
+			JOIN project ON project.id = application.project_id
+			WHERE search_vector(application_variable.var_name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeVariable,
+		query: `SELECT project.projectkey, 'environment/' || environment.name, environment_variable.name FROM environment_variable
+			JOIN environment ON environment.id = environment_variable.environment_id
+			JOIN project ON project.id = environment.project_id
+			WHERE search_vector(environment_variable.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeVariable,
+		query: `SELECT project.projectkey, 'pipeline/' || pipeline.name, pipeline_parameter.name FROM pipeline_parameter
+			JOIN pipeline ON pipeline.id = pipeline_parameter.pipeline_id
+			JOIN project ON project.id = pipeline.project_id
+			WHERE search_vector(pipeline_parameter.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeRepository,
+		query: `SELECT project.projectkey, 'application/' || application.name, application.repo_fullname FROM application
+			JOIN project ON project.id = application.project_id
+			WHERE search_vector(application.repo_fullname) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeAction,
+		query: `SELECT DISTINCT project.projectkey, 'pipeline/' || pipeline.name || '/' || job.name, action.name FROM action
+			JOIN action_edge ON action_edge.child_id = action.id
+			JOIN action job ON job.id = action_edge.parent_id
+			` + jobs + `
+			WHERE search_vector(action.name) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeRequirement,
+		query: `SELECT project.projectkey, 'pipeline/' || pipeline.name || '/' || job.name, action_requirement.type || ': ' || action_requirement.value FROM action_requirement
+			JOIN action job ON job.id = action_requirement.action_id
+			` + jobs + `
+			WHERE search_vector(action_requirement.value) @@ to_tsquery('simple', $1) %s`,
+	},
+	{
+		resultType: sdk.SearchTypeScript,
+		query: `SELECT project.projectkey, 'pipeline/' || pipeline.name || '/' || job.name, action_edge_parameter.value FROM action_edge_parameter
+			JOIN action_edge ON action_edge.id = action_edge_parameter.action_edge_id
+			JOIN action job ON job.id = action_edge.parent_id
+			` + jobs + `
+			WHERE action_edge_parameter.name = 'script' AND search_vector(action_edge_parameter.value) @@ to_tsquery('simple', $1) %s`,
+		excerpt: true,
+	},
+}
+
+// Search looks for the entities matching the filter in the projects the user can read
+func Search(db gorp.SqlExecutor, u *sdk.User, f sdk.SearchFilter) ([]sdk.SearchResult, error) {
+	terms := Terms(f.Query)
+	if len(terms) == 0 {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "search.Search> Empty query")
+	}
+
+	known := map[string]bool{}
+	for _, t := range sdk.SearchTypes {
+		known[t] = true
+	}
+	types := map[string]bool{}
+	for _, t := range f.Types {
+		if !known[t] {
+			return nil, sdk.WrapError(sdk.ErrWrongRequest, "search.Search> Unknown type %s", t)
+		}
+		types[t] = true
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	args := []interface{}{TextQuery(terms)}
+	var filter string
+	if u != nil && !u.Admin {
+		args = append(args, groupIDs(u), group.SharedInfraGroup.ID)
+		filter += `AND project.id IN (
+			SELECT project_group.project_id
+			FROM project_group
+			WHERE
+				project_group.group_id = ANY(string_to_array($2, ',')::int[])
+				OR
+				$3 = ANY(string_to_array($2, ',')::int[])
+		)`
+	}
+	if f.ProjectKey != "" {
+		args = append(args, f.ProjectKey)
+		filter += fmt.Sprintf(" AND project.projectkey = $%d", len(args))
+	}
+
+	results := []sdk.SearchResult{}
+	for _, s := range searchers {
+		if len(types) > 0 && !types[s.resultType] {
+			continue
+		}
+
+		query := fmt.Sprintf(s.query, filter) + fmt.Sprintf(" ORDER BY 1, 2, 3 LIMIT %d", limit)
+		rows, err := db.Query(query, args...)
+		if err != nil {
+			return nil, sdk.WrapError(err, "search.Search> Unable to search %s", s.resultType)
+		}
+		for rows.Next() {
+			r := sdk.SearchResult{Type: s.resultType}
+			if err := rows.Scan(&r.ProjectKey, &r.Entity, &r.Match); err != nil {
+				rows.Close()
+				return nil, sdk.WrapError(err, "search.Search> Unable to read %s", s.resultType)
+			}
+			if s.excerpt {
+				r.Match = Excerpt(r.Match, terms)
+			}
+			results = append(results, r)
+		}
+		rows.Close()
+	}
+	return results, nil
+}
+
+func groupIDs(u *sdk.User) string {
+	ids := make([]string, len(u.Groups))
+	for i, g := range u.Groups {
+		ids[i] = fmt.Sprintf("%d", g.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+// Terms splits a query in lower case words, the same way the names are indexed by the search_vector function
+func Terms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// TextQuery returns the text search query matching the entities containing words starting with all the terms
+func TextQuery(terms []string) string {
+	q := make([]string, len(terms))
+	for i, t := range terms {
+		q[i] = t + ":*"
+	}
+	return strings.Join(q, " & ")
+}
+
+// Excerpt returns the first line of a content containing one of the terms
+func Excerpt(content string, terms []string) string {
+	lines := strings.Split(content, "\n")
+	res := strings.TrimSpace(lines[0])
+	for _, l := range lines {
+		lower := strings.ToLower(l)
+		found := false
+		for _, t := range terms {
+			if strings.Contains(lower, t) {
+				found = true
+				break
+			}
+		}
+		if found {
+			res = strings.TrimSpace(l)
+			break
+		}
+	}
+	if r := []rune(res); len(r) > maxExcerpt {
+		res = string(r[:maxExcerpt]) + "..."
+	}
+	return res
+}
@@ -0,0 +1,20 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextQuery(t *testing.T) {
+	terms := Terms("Docker.Image ovh/cds  'foo'&")
+	assert.Equal(t, []string{"docker", "image", "ovh", "cds", "foo"}, terms)
+	assert.Equal(t, "docker:* & image:* & ovh:* & cds:* & foo:*", TextQuery(terms))
+	assert.Empty(t, Terms(" & | ! "))
+}
+
+func TestExcerpt(t *testing.T) {
+	script := "#!/bin/bash\nset -e\n  docker build -t {{.cds.application}} .\ndocker push"
+	assert.Equal(t, "docker build -t {{.cds.application}} .", Excerpt(script, []string{"docker"}))
+	assert.Equal(t, "#!/bin/bash", Excerpt(script, []string{"make"}))
+}
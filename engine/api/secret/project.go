@@ -0,0 +1,91 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// fingerprintSize is the number of bytes of the digest kept in a fingerprint
+const fingerprintSize = 8
+
+// The usages of the keys derived for the projects, each usage has its own key
+const (
+	usageEncryption  = "encryption"
+	usageFingerprint = "fingerprint"
+)
+
+// keyOfProject derives a key of a project for a usage from the instance key. A value encrypted for
+// a project can't be decrypted for another one, nor on an instance with another key
+func keyOfProject(projectKey, usage string) ([]byte, error) {
+	if key == nil {
+		log.Error("Missing key, init failed?")
+		return nil, sdk.ErrSecretKeyFetchFailed
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte("project/" + projectKey + "/" + usage))
+	return h.Sum(nil), nil
+}
+
+func projectCipher(projectKey string) (cipher.AEAD, error) {
+	k, err := keyOfProject(projectKey, usageEncryption)
+	if err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
+
+// EncryptValue encrypts a value with the key of a project, the result is encoded in base64 to be
+// stored in text files. Init() must be called before any encryption
+func EncryptValue(projectKey, value string) (string, error) {
+	gcm, err := projectCipher(projectKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+// DecryptValue decrypts a value encrypted by EncryptValue with the key of the same project
+func DecryptValue(projectKey, value string) (string, error) {
+	gcm, err := projectCipher(projectKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", sdk.ErrCannotDecryptSecret
+	}
+	clear, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", sdk.ErrCannotDecryptSecret
+	}
+	return string(clear), nil
+}
+
+// Fingerprint returns a digest of a secret value of a project. It shows whether two values differ
+// without revealing them, and can't be brute forced without the instance key
+func Fingerprint(projectKey, value string) (string, error) {
+	k, err := keyOfProject(projectKey, usageFingerprint)
+	if err != nil {
+		return "", err
+	}
+	h := hmac.New(sha256.New, k)
+	h.Write([]byte(value))
+	return hex.EncodeToString(h.Sum(nil)[:fingerprintSize]), nil
+}
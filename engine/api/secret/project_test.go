@@ -0,0 +1,69 @@
+package secret
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestEncryptValue(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+
+	ct, err := EncryptValue("KEY", "Hello world !")
+	if err != nil {
+		t.Fatalf("EncryptValue failed: %s", err)
+	}
+
+	clear, err := DecryptValue("KEY", ct)
+	if err != nil {
+		t.Fatalf("DecryptValue failed: %s", err)
+	}
+	if clear != "Hello world !" {
+		t.Fatalf("Fail: Expected 'Hello world !', got '%s'", clear)
+	}
+
+	if _, err := DecryptValue("OTHER", ct); err != sdk.ErrCannotDecryptSecret {
+		t.Fatalf("DecryptValue should have failed with the key of another project, got %v", err)
+	}
+	if _, err := DecryptValue("KEY", "Hello world !"); err != sdk.ErrCannotDecryptSecret {
+		t.Fatalf("DecryptValue should have failed on a clear value, got %v", err)
+	}
+
+	key = []byte("ZD5AOABo1Xf78eKVxCGLm6gwoH9LAQ15")
+	if _, err := DecryptValue("KEY", ct); err != sdk.ErrCannotDecryptSecret {
+		t.Fatalf("DecryptValue should have failed with the key of another instance, got %v", err)
+	}
+}
+
+func TestKeyOfProject(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+
+	enc, _ := keyOfProject("KEY", usageEncryption)
+	fp, _ := keyOfProject("KEY", usageFingerprint)
+	if bytes.Equal(enc, fp) {
+		t.Fatalf("The keys of the encryption and of the fingerprints should differ")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	key = []byte("78eKVxCGLm6gwoH9LAQ15ZD5AOABo1Xf")
+
+	f1, err := Fingerprint("KEY", "Hello world !")
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %s", err)
+	}
+	f2, _ := Fingerprint("KEY", "Hello world !")
+	f3, _ := Fingerprint("KEY", "Hello world ?")
+	f4, _ := Fingerprint("OTHER", "Hello world !")
+
+	if f1 != f2 {
+		t.Fatalf("Fail: the fingerprints of a value differ: %s != %s", f1, f2)
+	}
+	if f1 == f3 || f1 == f4 {
+		t.Fatalf("Fail: the fingerprints of different values or projects are equal")
+	}
+	if len(f1) != 2*fingerprintSize {
+		t.Fatalf("Fail: Expected a fingerprint of %d characters, got '%s'", 2*fingerprintSize, f1)
+	}
+}
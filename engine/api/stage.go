@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/sdk"
@@ -31,6 +32,11 @@ func (api *API) addStageHandler() Handler {
 			return err
 		}
 
+		before, err := pipeline.LoadPipeline(api.mustDB(), projectKey, pipelineKey, true)
+		if err != nil {
+			return err
+		}
+
 		if err := pipeline.LoadPipelineStage(api.mustDB(), pipelineData); err != nil {
 			log.Warning("addStageHandler> Cannot load pipeline stages: %s", err)
 			return err
@@ -62,10 +68,16 @@ func (api *API) addStageHandler() Handler {
 			return err
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, projectKey, pipelineKey, before)
+		if err != nil {
+			return sdk.WrapError(err, "addStageHandler> Cannot audit pipeline %s", pipelineKey)
+		}
+
 		if err := tx.Commit(); err != nil {
 			log.Warning("addStageHandler> Cannot commit transaction: %s", err)
 			return err
 		}
+		audit.Publish(evt)
 
 		if err := pipeline.LoadPipelineStage(api.mustDB(), pipelineData); err != nil {
 			log.Warning("addStageHandler> Cannot load pipeline stages: %s", err)
@@ -128,6 +140,11 @@ func (api *API) moveStageHandler() Handler {
 			return err
 		}
 
+		before, err := pipeline.LoadPipeline(api.mustDB(), projectKey, pipelineKey, true)
+		if err != nil {
+			return err
+		}
+
 		// count stage for this pipeline
 		nbStage, err := pipeline.CountStageByPipelineID(api.mustDB(), pipelineData.ID)
 		if err != nil {
@@ -163,6 +180,12 @@ func (api *API) moveStageHandler() Handler {
 			return err
 		}
 
+		evt, err := api.auditPipeline(ctx, api.mustDB(), projectKey, pipelineKey, before)
+		if err != nil {
+			return sdk.WrapError(err, "moveStageHandler> Cannot audit pipeline %s", pipelineKey)
+		}
+		audit.Publish(evt)
+
 		return WriteJSON(w, r, pipelineData, http.StatusOK)
 	}
 }
@@ -196,6 +219,11 @@ func (api *API) updateStageHandler() Handler {
 			return err
 		}
 
+		before, err := pipeline.LoadPipeline(api.mustDB(), projectKey, pipelineKey, true)
+		if err != nil {
+			return err
+		}
+
 		// check if stage exist
 		s, err := pipeline.LoadStage(api.mustDB(), pipelineData.ID, stageData.ID)
 		if err != nil {
@@ -226,11 +254,17 @@ func (api *API) updateStageHandler() Handler {
 			return err
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, projectKey, pipelineKey, before)
+		if err != nil {
+			return sdk.WrapError(err, "updateStageHandler> Cannot audit pipeline %s", pipelineKey)
+		}
+
 		err = tx.Commit()
 		if err != nil {
 			log.Warning("addStageHandler> Cannot commit transaction: %s", err)
 			return err
 		}
+		audit.Publish(evt)
 
 		if err := pipeline.LoadPipelineStage(api.mustDB(), pipelineData); err != nil {
 			log.Warning("addStageHandler> Cannot load stages: %s", err)
@@ -256,6 +290,12 @@ func (api *API) deleteStageHandler() Handler {
 			return err
 		}
 
+		before, err := pipeline.LoadPipeline(api.mustDB(), projectKey, pipelineKey, true)
+		if err != nil {
+			log.Warning("deleteStageHandler> Cannot load pipeline %s: %s", pipelineKey, err)
+			return err
+		}
+
 		stageID, err := strconv.ParseInt(stageIDString, 10, 60)
 		if err != nil {
 			log.Warning("deleteStageHandler> Stage ID must be an int: %s", err)
@@ -291,10 +331,16 @@ func (api *API) deleteStageHandler() Handler {
 			return err
 		}
 
+		evt, err := api.auditPipeline(ctx, tx, projectKey, pipelineKey, before)
+		if err != nil {
+			return sdk.WrapError(err, "deleteStageHandler> Cannot audit pipeline %s", pipelineKey)
+		}
+
 		if err := tx.Commit(); err != nil {
 			log.Warning("deleteStageHandler> Cannot commit transaction: %s", err)
 			return err
 		}
+		audit.Publish(evt)
 
 		if err := pipeline.LoadPipelineStage(api.mustDB(), pipelineData); err != nil {
 			log.Warning("deleteStageHandler> Cannot load stages: %s", err)
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GroupUsageEvent accounts a job and its duration in CPU minutes in the daily usage of the groups
+func GroupUsageEvent(db gorp.SqlExecutor, groupIDs []int64, cpuMinutes int64) {
+	for _, id := range groupIDs {
+		query := `UPDATE stats_group_usage SET jobs = jobs + 1, cpu_minutes = cpu_minutes + $2 WHERE day = current_date AND group_id = $1`
+		res, err := db.Exec(query, id, cpuMinutes)
+		if err != nil {
+			log.Warning("GroupUsageEvent> Cannot update usage of group %d: %s", id, err)
+			continue
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			continue
+		}
+
+		query = `INSERT INTO stats_group_usage (day, group_id, jobs, cpu_minutes) VALUES (current_date, $1, 1, $2)`
+		if _, err := db.Exec(query, id, cpuMinutes); err != nil {
+			log.Warning("GroupUsageEvent> Cannot insert usage of group %d: %s", id, err)
+		}
+	}
+}
+
+// GroupCPUMinutesToday returns the CPU minutes used today by the jobs of a group
+func GroupCPUMinutesToday(db gorp.SqlExecutor, groupID int64) (int64, error) {
+	var minutes int64
+	query := `SELECT cpu_minutes FROM stats_group_usage WHERE day = current_date AND group_id = $1`
+	if err := db.QueryRow(query, groupID).Scan(&minutes); err != nil && err != sql.ErrNoRows {
+		return 0, sdk.WrapError(err, "GroupCPUMinutesToday> Cannot load usage of group %d", groupID)
+	}
+	return minutes, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/variableset"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -30,6 +31,12 @@ func (api *API) getVariablesHandler() Handler {
 		if err != nil {
 			return sdk.WrapError(err, "getVariablesHandler> Cannot Load project variables: %s", err)
 		}
+		// Load the variables of the sets attached to the project
+		projectSetVar, err := variableset.LoadVariableNamesByProjectKey(api.mustDB(), projectKey)
+		if err != nil {
+			return sdk.WrapError(err, "getVariablesHandler> Cannot Load project variable sets: %s", err)
+		}
+		projectVar = appendMissingNames(projectVar, projectSetVar)
 		for i := range projectVar {
 			projectVar[i] = fmt.Sprintf("{{.cds.proj.%s}}", projectVar[i])
 		}
@@ -58,7 +65,7 @@ func (api *API) getVariablesHandler() Handler {
 				return sdk.WrapError(sdk.ErrForbidden, "getVariablesHandler> Not allow to access to this application: %s", appName)
 			}
 
-			for _, v := range app.Variable {
+			for _, v := range sdk.VariablesWithSets(app.Variable, app.VariableSets) {
 				appVar = append(appVar, fmt.Sprintf("{{.cds.app.%s}}", v.Name))
 			}
 
@@ -83,8 +90,17 @@ func (api *API) getVariablesHandler() Handler {
 				if err != nil {
 					return sdk.WrapError(err, "getVariablesHandler> Cannot scan results: %s", err)
 				}
-				appVar = append(appVar, fmt.Sprintf("{{.cds.app.%s}}", name))
+				appVar = append(appVar, name)
+			}
 
+			// Load the variables of the sets attached to the applications
+			appSetVar, err := variableset.LoadApplicationVariableNamesByProjectKey(api.mustDB(), projectKey)
+			if err != nil {
+				return sdk.WrapError(err, "getVariablesHandler> Cannot Load applications variable sets: %s", err)
+			}
+			appVar = appendMissingNames(appVar, appSetVar)
+			for i := range appVar {
+				appVar[i] = fmt.Sprintf("{{.cds.app.%s}}", appVar[i])
 			}
 		}
 		allVariables = append(allVariables, appVar...)
@@ -140,3 +156,18 @@ func (api *API) getVariablesHandler() Handler {
 		return WriteJSON(w, r, allVariables, http.StatusOK)
 	}
 }
+
+// appendMissingNames appends to names the other names which are not already in names
+func appendMissingNames(names []string, others []string) []string {
+	m := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		m[n] = struct{}{}
+	}
+	for _, n := range others {
+		if _, ok := m[n]; !ok {
+			names = append(names, n)
+			m[n] = struct{}{}
+		}
+	}
+	return names
+}
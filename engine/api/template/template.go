@@ -4,10 +4,14 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sessionstore"
 	"github.com/ovh/cds/engine/api/templateextension"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -72,7 +76,8 @@ func ApplyTemplate(db *gorp.DbMap, store cache.Store, proj *sdk.Project, opts sd
 		}
 	}(&msgList)
 
-	if err := application.Import(tx, store, proj, app, app.RepositoriesManager, user, msgChan); err != nil {
+	evts, err := application.Import(tx, store, proj, app, app.RepositoriesManager, user, msgChan)
+	if err != nil {
 		log.Warning("ApplyTemplate> error applying template : %s", err)
 		close(msgChan)
 		return msgList, err
@@ -92,6 +97,7 @@ func ApplyTemplate(db *gorp.DbMap, store cache.Store, proj *sdk.Project, opts sd
 		log.Warning("ApplyTemplate> error commiting transaction : %s", err)
 		return msgList, err
 	}
+	audit.Publish(evts...)
 
 	log.Debug("ApplyTemplate> Done")
 
@@ -150,7 +156,8 @@ func ApplyTemplateOnApplication(db *gorp.DbMap, store cache.Store, proj *sdk.Pro
 	}(&msgList)
 
 	//Import the pipelines
-	if err := application.ImportPipelines(tx, store, proj, app, user, msgChan); err != nil {
+	evts, err := application.ImportPipelines(tx, store, proj, app, user, msgChan)
+	if err != nil {
 		log.Warning("ApplyTemplateOnApplication> error applying template : %s", err)
 		close(msgChan)
 		return msgList, err
@@ -164,9 +171,216 @@ func ApplyTemplateOnApplication(db *gorp.DbMap, store cache.Store, proj *sdk.Pro
 		log.Warning("ApplyTemplateOnApplication> error commiting transaction : %s", err)
 		return msgList, err
 	}
+	audit.Publish(evts...)
 
 	deferFunc()
 	log.Debug("ApplyTemplateOnApplication> Done")
 
 	return msgList, nil
 }
+
+// ApplyWorkflowTemplate creates a workflow, its pipelines, applications and environments with given template.
+// If dryRun is true, nothing is persisted and the generated entities are only returned
+func ApplyWorkflowTemplate(db *gorp.DbMap, store cache.Store, proj *sdk.Project, opts sdk.ApplyWorkflowTemplateOptions, user *sdk.User, sessionKey sessionstore.SessionKey, apiURL string, dryRun bool) (*sdk.TemplateWorkflow, []sdk.Message, error) {
+	//Get the template
+	sdktmpl, errl := templateextension.LoadByName(db, opts.TemplateName)
+	if errl != nil {
+		return nil, nil, errl
+	}
+
+	// Get the go-plugin instance
+	templ, deferFunc, erri := templateextension.Instance(sdktmpl, user, sessionKey, apiURL)
+	if deferFunc != nil {
+		defer deferFunc()
+	}
+	if erri != nil {
+		log.Warning("ApplyWorkflowTemplate> error getting template Extension instance : %s", erri)
+		return nil, nil, erri
+	}
+
+	// Apply the template
+	res, erra := templateextension.ApplyWorkflow(templ, proj, opts.TemplateParams, opts.WorkflowName)
+	if erra != nil {
+		log.Warning("ApplyWorkflowTemplate> error applying template : %s", erra)
+		return nil, nil, erra
+	}
+
+	// Check conflicts before writing anything
+	for _, w := range proj.Workflows {
+		if w.Name == res.Workflow.Name {
+			return nil, nil, sdk.ErrWorkflowAlreadyExists
+		}
+	}
+	for _, pip := range res.Pipelines {
+		for _, p := range proj.Pipelines {
+			if p.Name == pip.Name {
+				return nil, nil, sdk.ErrPipelineAlreadyExists
+			}
+		}
+	}
+	for _, app := range res.Applications {
+		for _, a := range proj.Applications {
+			if a.Name == app.Name {
+				return nil, nil, sdk.ErrApplicationExist
+			}
+		}
+	}
+
+	if dryRun {
+		return res, nil, nil
+	}
+
+	//Start a new transaction
+	tx, errb := db.Begin()
+	if errb != nil {
+		log.Warning("ApplyWorkflowTemplate> error beginning transaction : %s", errb)
+		return nil, nil, errb
+	}
+
+	defer tx.Rollback()
+
+	done := make(chan bool)
+	msgChan := make(chan sdk.Message)
+	msgList := []sdk.Message{}
+	go func(array *[]sdk.Message) {
+		for {
+			m, more := <-msgChan
+			if !more {
+				done <- true
+				return
+			}
+			*array = append(*array, m)
+		}
+	}(&msgList)
+
+	evts, err := importTemplateWorkflow(tx, store, proj, res, user, msgChan)
+	if err != nil {
+		log.Warning("ApplyWorkflowTemplate> error applying template : %s", err)
+		close(msgChan)
+		<-done
+		return nil, msgList, err
+	}
+
+	close(msgChan)
+	<-done
+
+	log.Debug("ApplyWorkflowTemplate> Commit the transaction")
+	if err := tx.Commit(); err != nil {
+		log.Warning("ApplyWorkflowTemplate> error commiting transaction : %s", err)
+		return nil, msgList, err
+	}
+	audit.Publish(evts...)
+
+	log.Debug("ApplyWorkflowTemplate> Done")
+
+	return res, msgList, nil
+}
+
+func importTemplateWorkflow(db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, res *sdk.TemplateWorkflow, user *sdk.User, msgChan chan<- sdk.Message) ([]sdk.EventAudit, error) {
+	evts := []sdk.EventAudit{}
+	for i := range res.Environments {
+		env := &res.Environments[i]
+		evtsEnv, err := environment.Import(db, proj, env, msgChan, user)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importTemplateWorkflow> Cannot import environment %s", env.Name)
+		}
+		evts = append(evts, evtsEnv...)
+		proj.Environments = append(proj.Environments, *env)
+	}
+
+	for i := range res.Pipelines {
+		pip := &res.Pipelines[i]
+		if err := pipeline.Import(db, proj, pip, msgChan, user); err != nil {
+			return nil, sdk.WrapError(err, "importTemplateWorkflow> Cannot import pipeline %s", pip.Name)
+		}
+		proj.Pipelines = append(proj.Pipelines, *pip)
+	}
+
+	for i := range res.Applications {
+		app := &res.Applications[i]
+		evtsApp, err := application.Import(db, store, proj, app, nil, user, msgChan)
+		if err != nil {
+			return nil, sdk.WrapError(err, "importTemplateWorkflow> Cannot import application %s", app.Name)
+		}
+		evts = append(evts, evtsApp...)
+		proj.Applications = append(proj.Applications, *app)
+	}
+
+	w := &res.Workflow
+	if w.Root == nil {
+		return nil, sdk.ErrWorkflowInvalidRoot
+	}
+	if err := resolveWorkflowNode(proj, w.Root); err != nil {
+		return nil, err
+	}
+	for i := range w.Joins {
+		for j := range w.Joins[i].Triggers {
+			if err := resolveWorkflowNode(proj, &w.Joins[i].Triggers[j].WorkflowDestNode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := workflow.Insert(db, store, w, proj, user); err != nil {
+		return nil, sdk.WrapError(err, "importTemplateWorkflow> Cannot insert workflow %s", w.Name)
+	}
+
+	if err := project.UpdateLastModified(db, store, user, proj); err != nil {
+		return nil, err
+	}
+	return evts, nil
+}
+
+// resolveWorkflowNode sets the pipeline, application and environment IDs of the node
+// and its children from their names, as a template doesn't know them
+func resolveWorkflowNode(proj *sdk.Project, n *sdk.WorkflowNode) error {
+	var pipFound bool
+	for _, p := range proj.Pipelines {
+		if p.Name == n.Pipeline.Name {
+			n.Pipeline = p
+			n.PipelineID = p.ID
+			pipFound = true
+			break
+		}
+	}
+	if !pipFound {
+		return sdk.WrapError(sdk.ErrPipelineNotFound, "resolveWorkflowNode> Unknown pipeline %s", n.Pipeline.Name)
+	}
+
+	if n.Context != nil && n.Context.Application != nil {
+		var appFound bool
+		for i := range proj.Applications {
+			if proj.Applications[i].Name == n.Context.Application.Name {
+				n.Context.Application = &proj.Applications[i]
+				n.Context.ApplicationID = proj.Applications[i].ID
+				appFound = true
+				break
+			}
+		}
+		if !appFound {
+			return sdk.WrapError(sdk.ErrApplicationNotFound, "resolveWorkflowNode> Unknown application %s", n.Context.Application.Name)
+		}
+	}
+
+	if n.Context != nil && n.Context.Environment != nil {
+		var envFound bool
+		for i := range proj.Environments {
+			if proj.Environments[i].Name == n.Context.Environment.Name {
+				n.Context.Environment = &proj.Environments[i]
+				n.Context.EnvironmentID = proj.Environments[i].ID
+				envFound = true
+				break
+			}
+		}
+		if !envFound {
+			return sdk.WrapError(sdk.ErrNoEnvironment, "resolveWorkflowNode> Unknown environment %s", n.Context.Environment.Name)
+		}
+	}
+
+	for i := range n.Triggers {
+		if err := resolveWorkflowNode(proj, &n.Triggers[i].WorkflowDestNode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -185,6 +185,57 @@ func Apply(db gorp.SqlExecutor, store cache.Store, templ template.Interface, pro
 	return &app, err
 }
 
+//ApplyWorkflow will call the ApplyWorkflow function of the template and returns a fresh new workflow with all its components
+func ApplyWorkflow(templ template.Interface, proj *sdk.Project, params []sdk.TemplateParam, workflowName string) (*sdk.TemplateWorkflow, error) {
+	regexp := regexp.MustCompile(sdk.NamePattern)
+	if !regexp.MatchString(workflowName) {
+		return nil, sdk.ErrInvalidName
+	}
+
+	wtempl, ok := templ.(template.WorkflowInterface)
+	if !ok {
+		return nil, sdk.ErrTemplateWorkflowNotSupported
+	}
+
+	parameters := map[string]string{}
+	for _, p := range params {
+		parameters[p.Name] = p.Value
+	}
+	templParameters := template.NewParameters(parameters)
+	applyOptions := template.NewApplyWorkflowOptions(proj.Key, workflowName, *templParameters)
+	res, err := wtempl.ApplyWorkflow(applyOptions)
+	if err != nil {
+		if err == template.ErrWorkflowNotSupported {
+			return nil, sdk.ErrTemplateWorkflowNotSupported
+		}
+		return nil, err
+	}
+
+	for i := range res.Pipelines {
+		if !regexp.MatchString(res.Pipelines[i].Name) {
+			return nil, sdk.ErrInvalidPipelinePattern
+		}
+		res.Pipelines[i].ProjectKey = proj.Key
+	}
+	for i := range res.Applications {
+		if !regexp.MatchString(res.Applications[i].Name) {
+			return nil, sdk.ErrInvalidApplicationPattern
+		}
+		res.Applications[i].ProjectKey = proj.Key
+	}
+	for i := range res.Environments {
+		if !regexp.MatchString(res.Environments[i].Name) {
+			return nil, sdk.ErrInvalidName
+		}
+		res.Environments[i].ProjectKey = proj.Key
+	}
+	res.Workflow.Name = workflowName
+	res.Workflow.ProjectKey = proj.Key
+	res.Workflow.ProjectID = proj.ID
+
+	return &res, nil
+}
+
 //All returns all template extensions
 func All(dbmap *gorp.DbMap) ([]sdk.TemplateExtension, error) {
 	tmpls := []TemplateExtension{}
@@ -374,3 +374,74 @@ func (api *API) applyTemplateOnApplicationHandler() Handler {
 		return WriteJSON(w, r, msgList, http.StatusOK)
 	}
 }
+
+func (api *API) applyWorkflowTemplateHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.workflowTemplate(ctx, w, r, false)
+	}
+}
+
+func (api *API) previewWorkflowTemplateHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.workflowTemplate(ctx, w, r, true)
+	}
+}
+
+func (api *API) workflowTemplate(ctx context.Context, w http.ResponseWriter, r *http.Request, dryRun bool) error {
+	vars := mux.Vars(r)
+	projectKey := vars["permProjectKey"]
+
+	// Load the project
+	proj, errload := project.Load(api.mustDB(), api.Cache, projectKey, getUser(ctx),
+		project.LoadOptions.Default,
+		project.LoadOptions.WithApplications,
+		project.LoadOptions.WithPipelines,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithWorkflows,
+		project.LoadOptions.WithGroups)
+	if errload != nil {
+		return sdk.WrapError(errload, "workflowTemplate> Cannot load project %s", projectKey)
+	}
+
+	// Parse body to sdk.ApplyWorkflowTemplateOptions
+	var opts sdk.ApplyWorkflowTemplateOptions
+	if err := UnmarshalBody(r, &opts); err != nil {
+		return err
+	}
+
+	// Create a session for current user
+	sessionKey, errnew := auth.NewSession(api.Router.AuthDriver, getUser(ctx))
+	if errnew != nil {
+		return sdk.WrapError(errnew, "workflowTemplate> Error while creating new session")
+	}
+
+	// Apply the template
+	res, msg, errapply := template.ApplyWorkflowTemplate(api.mustDB(), api.Cache, proj, opts, getUser(ctx), sessionKey, api.Config.URL.API, dryRun)
+
+	al := r.Header.Get("Accept-Language")
+	msgList := []string{}
+	for _, m := range msg {
+		msgList = append(msgList, m.String(al))
+	}
+
+	if errapply != nil {
+		// The messages tell which component of the template failed to be imported
+		if len(msgList) > 0 {
+			log.Warning("workflowTemplate> Error while applying workflow template: %s", errapply)
+			_, code := sdk.ProcessError(errapply, al)
+			return WriteJSON(w, r, msgList, code)
+		}
+		return sdk.WrapError(errapply, "workflowTemplate> Error while applying workflow template")
+	}
+
+	if dryRun {
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+
+	log.Debug("workflowTemplate> Check warnings on project")
+	if err := sanity.CheckProjectPipelines(api.mustDB(), api.Cache, proj); err != nil {
+		return sdk.WrapError(err, "workflowTemplate> Cannot check warnings")
+	}
+
+	return WriteJSON(w, r, msgList, http.StatusCreated)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/token"
 	"github.com/ovh/cds/sdk"
@@ -39,6 +40,15 @@ func (api *API) generateTokenHandler() Handler {
 		if err := token.InsertToken(api.mustDB(), g.ID, tk, exp); err != nil {
 			return sdk.WrapError(err, "generateTokenHandler> cannot insert new key")
 		}
+
+		// The value of the token is never recorded in the audit log
+		after := map[string]interface{}{"group": g.Name, "expiration": exp.String(), "created": now}
+		evt, err := audit.Add(api.mustDB(), getUser(ctx), audit.Entity{Type: sdk.AuditEntityToken, Name: g.Name}, nil, after)
+		if err != nil {
+			return sdk.WrapError(err, "generateTokenHandler> cannot audit new key")
+		}
+		audit.Publish(evt)
+
 		token := sdk.Token{
 			GroupID:    g.ID,
 			Token:      tk,
@@ -0,0 +1,498 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/variableset"
+	"github.com/ovh/cds/sdk"
+)
+
+// loadGroupVariableSet loads a variable set owned by the group of the route
+func (api *API) loadGroupVariableSet(r *http.Request) (*sdk.Group, *sdk.VariableSet, error) {
+	vars := mux.Vars(r)
+	groupName := vars["permGroupName"]
+	name := vars["name"]
+
+	g, errg := group.LoadGroup(api.mustDB(), groupName)
+	if errg != nil {
+		return nil, nil, sdk.WrapError(errg, "loadGroupVariableSet> Cannot load group %s", groupName)
+	}
+
+	s, errs := variableset.LoadByName(api.mustDB(), name)
+	if errs != nil {
+		return nil, nil, sdk.WrapError(errs, "loadGroupVariableSet> Cannot load variable set %s", name)
+	}
+	if s.GroupID != g.ID {
+		return nil, nil, sdk.WrapError(sdk.ErrVariableSetNotFound, "loadGroupVariableSet> Variable set %s is not owned by group %s", name, groupName)
+	}
+	return g, s, nil
+}
+
+// loadAttachableVariableSet loads a variable set which can be attached by the current user, who has to be
+// a member of the group owning the set, so that the secrets of a group cannot be used by any project
+func (api *API) loadAttachableVariableSet(ctx context.Context, name string) (*sdk.VariableSet, error) {
+	s, err := variableset.LoadByName(api.mustDB(), name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadAttachableVariableSet> Cannot load variable set %s", name)
+	}
+
+	u := getUser(ctx)
+	if u.Admin {
+		return s, nil
+	}
+	for _, g := range u.Groups {
+		if g.ID == s.GroupID {
+			return s, nil
+		}
+	}
+	return nil, sdk.WrapError(sdk.ErrForbidden, "loadAttachableVariableSet> User %s is not a member of group %s", u.Username, s.GroupName)
+}
+
+func (api *API) getVariableSetsInGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		groupName := vars["permGroupName"]
+
+		g, errg := group.LoadGroup(api.mustDB(), groupName)
+		if errg != nil {
+			return sdk.WrapError(errg, "getVariableSetsInGroupHandler> Cannot load group %s", groupName)
+		}
+
+		sets, errs := variableset.LoadAllByGroup(api.mustDB(), g.ID)
+		if errs != nil {
+			return sdk.WrapError(errs, "getVariableSetsInGroupHandler> Cannot load variable sets of group %s", groupName)
+		}
+		return WriteJSON(w, r, sets, http.StatusOK)
+	}
+}
+
+func (api *API) postVariableSetInGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		groupName := vars["permGroupName"]
+
+		var s sdk.VariableSet
+		if err := UnmarshalBody(r, &s); err != nil {
+			return sdk.WrapError(err, "postVariableSetInGroupHandler> Cannot unmarshal variable set")
+		}
+		if s.Name == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postVariableSetInGroupHandler> Variable set name is mandatory")
+		}
+
+		g, errg := group.LoadGroup(api.mustDB(), groupName)
+		if errg != nil {
+			return sdk.WrapError(errg, "postVariableSetInGroupHandler> Cannot load group %s", groupName)
+		}
+
+		if _, err := variableset.LoadByName(api.mustDB(), s.Name); err == nil {
+			return sdk.WrapError(sdk.ErrVariableSetExists, "postVariableSetInGroupHandler> Variable set %s already exists", s.Name)
+		} else if err != sdk.ErrVariableSetNotFound {
+			return sdk.WrapError(err, "postVariableSetInGroupHandler> Cannot check variable set %s", s.Name)
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "postVariableSetInGroupHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		evts := []sdk.EventAudit{}
+
+		s.GroupID = g.ID
+		s.GroupName = g.Name
+		if err := variableset.Insert(tx, &s); err != nil {
+			return sdk.WrapError(err, "postVariableSetInGroupHandler> Cannot insert variable set %s", s.Name)
+		}
+		variables := s.Variables
+		s.Variables = nil
+		for i := range variables {
+			v := &variables[i]
+			evt, err := variableset.InsertVariable(tx, &s, v, getUser(ctx))
+			if err != nil {
+				return sdk.WrapError(err, "postVariableSetInGroupHandler> Cannot insert variable %s", v.Name)
+			}
+			evts = append(evts, evt)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postVariableSetInGroupHandler> Cannot commit transaction")
+		}
+		audit.Publish(evts...)
+
+		res, errl := variableset.LoadByName(api.mustDB(), s.Name)
+		if errl != nil {
+			return sdk.WrapError(errl, "postVariableSetInGroupHandler> Cannot load variable set %s", s.Name)
+		}
+		return WriteJSON(w, r, res, http.StatusCreated)
+	}
+}
+
+func (api *API) getVariableSetInGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "getVariableSetInGroupHandler>")
+		}
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
+
+func (api *API) putVariableSetInGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "putVariableSetInGroupHandler>")
+		}
+
+		var update sdk.VariableSet
+		if err := UnmarshalBody(r, &update); err != nil {
+			return sdk.WrapError(err, "putVariableSetInGroupHandler> Cannot unmarshal variable set")
+		}
+
+		s.Description = update.Description
+		if err := variableset.Update(api.mustDB(), s); err != nil {
+			return sdk.WrapError(err, "putVariableSetInGroupHandler> Cannot update variable set %s", s.Name)
+		}
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
+
+func (api *API) deleteVariableSetInGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInGroupHandler>")
+		}
+
+		attached, erra := variableset.IsAttached(api.mustDB(), s.ID)
+		if erra != nil {
+			return sdk.WrapError(erra, "deleteVariableSetInGroupHandler>")
+		}
+		if attached {
+			return sdk.WrapError(sdk.ErrVariableSetAttached, "deleteVariableSetInGroupHandler> Cannot delete variable set %s", s.Name)
+		}
+
+		if err := variableset.Delete(api.mustDB(), s); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInGroupHandler>")
+		}
+		return nil
+	}
+}
+
+func (api *API) getVariableSetAuditInGroupHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "getVariableSetAuditInGroupHandler>")
+		}
+
+		audits, erra := variableset.LoadAudits(api.mustDB(), s.ID)
+		if erra != nil {
+			return sdk.WrapError(erra, "getVariableSetAuditInGroupHandler>")
+		}
+		return WriteJSON(w, r, audits, http.StatusOK)
+	}
+}
+
+func (api *API) postVariableInVariableSetHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		varName := mux.Vars(r)["varName"]
+
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "postVariableInVariableSetHandler>")
+		}
+
+		var v sdk.Variable
+		if err := UnmarshalBody(r, &v); err != nil {
+			return sdk.WrapError(err, "postVariableInVariableSetHandler> Cannot unmarshal variable")
+		}
+		if v.Name != varName {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postVariableInVariableSetHandler> Variable name does not match %s", varName)
+		}
+
+		for _, existing := range s.Variables {
+			if existing.Name == v.Name {
+				return sdk.WrapError(sdk.ErrVariableExists, "postVariableInVariableSetHandler> Variable %s already exists in %s", v.Name, s.Name)
+			}
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "postVariableInVariableSetHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		evt, err := variableset.InsertVariable(tx, s, &v, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "postVariableInVariableSetHandler> Cannot insert variable %s", v.Name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postVariableInVariableSetHandler> Cannot commit transaction")
+		}
+		audit.Publish(evt)
+
+		if sdk.NeedPlaceholder(v.Type) {
+			v.Value = sdk.PasswordPlaceholder
+		}
+		return WriteJSON(w, r, v, http.StatusCreated)
+	}
+}
+
+func (api *API) putVariableInVariableSetHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		varName := mux.Vars(r)["varName"]
+
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "putVariableInVariableSetHandler>")
+		}
+
+		var v sdk.Variable
+		if err := UnmarshalBody(r, &v); err != nil {
+			return sdk.WrapError(err, "putVariableInVariableSetHandler> Cannot unmarshal variable")
+		}
+		if v.Name != varName {
+			return sdk.WrapError(sdk.ErrWrongRequest, "putVariableInVariableSetHandler> Variable name does not match %s", varName)
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "putVariableInVariableSetHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		evt, err := variableset.UpdateVariable(tx, s, &v, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "putVariableInVariableSetHandler> Cannot update variable %s", v.Name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "putVariableInVariableSetHandler> Cannot commit transaction")
+		}
+		audit.Publish(evt)
+
+		if sdk.NeedPlaceholder(v.Type) {
+			v.Value = sdk.PasswordPlaceholder
+		}
+		return WriteJSON(w, r, v, http.StatusOK)
+	}
+}
+
+func (api *API) deleteVariableInVariableSetHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		varName := mux.Vars(r)["varName"]
+
+		_, s, err := api.loadGroupVariableSet(r)
+		if err != nil {
+			return sdk.WrapError(err, "deleteVariableInVariableSetHandler>")
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "deleteVariableInVariableSetHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		evt, err := variableset.DeleteVariable(tx, s, varName, getUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "deleteVariableInVariableSetHandler> Cannot delete variable %s", varName)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "deleteVariableInVariableSetHandler> Cannot commit transaction")
+		}
+		audit.Publish(evt)
+		return nil
+	}
+}
+
+func (api *API) getVariableSetsInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)["permProjectKey"]
+
+		p, errp := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errp != nil {
+			return sdk.WrapError(errp, "getVariableSetsInProjectHandler> Cannot load project %s", key)
+		}
+
+		sets, errs := variableset.LoadAllByProject(api.mustDB(), p.ID)
+		if errs != nil {
+			return sdk.WrapError(errs, "getVariableSetsInProjectHandler>")
+		}
+		return WriteJSON(w, r, sets, http.StatusOK)
+	}
+}
+
+func (api *API) postVariableSetInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["name"]
+
+		p, errp := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errp != nil {
+			return sdk.WrapError(errp, "postVariableSetInProjectHandler> Cannot load project %s", key)
+		}
+
+		s, errs := api.loadAttachableVariableSet(ctx, name)
+		if errs != nil {
+			return sdk.WrapError(errs, "postVariableSetInProjectHandler>")
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "postVariableSetInProjectHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := variableset.AttachToProject(tx, s.ID, p.ID); err != nil {
+			return sdk.WrapError(err, "postVariableSetInProjectHandler>")
+		}
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
+			return sdk.WrapError(err, "postVariableSetInProjectHandler> Cannot update last modified")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postVariableSetInProjectHandler> Cannot commit transaction")
+		}
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
+
+func (api *API) deleteVariableSetInProjectHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["name"]
+
+		p, errp := project.Load(api.mustDB(), api.Cache, key, getUser(ctx))
+		if errp != nil {
+			return sdk.WrapError(errp, "deleteVariableSetInProjectHandler> Cannot load project %s", key)
+		}
+
+		s, errs := variableset.LoadByName(api.mustDB(), name)
+		if errs != nil {
+			return sdk.WrapError(errs, "deleteVariableSetInProjectHandler>")
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "deleteVariableSetInProjectHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := variableset.DetachFromProject(tx, s.ID, p.ID); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInProjectHandler>")
+		}
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInProjectHandler> Cannot update last modified")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInProjectHandler> Cannot commit transaction")
+		}
+		return nil
+	}
+}
+
+func (api *API) getVariableSetsInApplicationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		appName := vars["permApplicationName"]
+
+		app, erra := application.LoadByName(api.mustDB(), api.Cache, key, appName, getUser(ctx))
+		if erra != nil {
+			return sdk.WrapError(erra, "getVariableSetsInApplicationHandler> Cannot load application %s", appName)
+		}
+
+		sets, errs := variableset.LoadAllByApplication(api.mustDB(), app.ID)
+		if errs != nil {
+			return sdk.WrapError(errs, "getVariableSetsInApplicationHandler>")
+		}
+		return WriteJSON(w, r, sets, http.StatusOK)
+	}
+}
+
+func (api *API) postVariableSetInApplicationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		appName := vars["permApplicationName"]
+		name := vars["name"]
+
+		app, erra := application.LoadByName(api.mustDB(), api.Cache, key, appName, getUser(ctx))
+		if erra != nil {
+			return sdk.WrapError(erra, "postVariableSetInApplicationHandler> Cannot load application %s", appName)
+		}
+
+		s, errs := api.loadAttachableVariableSet(ctx, name)
+		if errs != nil {
+			return sdk.WrapError(errs, "postVariableSetInApplicationHandler>")
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "postVariableSetInApplicationHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := variableset.AttachToApplication(tx, s.ID, app.ID); err != nil {
+			return sdk.WrapError(err, "postVariableSetInApplicationHandler>")
+		}
+		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "postVariableSetInApplicationHandler> Cannot update last modified")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postVariableSetInApplicationHandler> Cannot commit transaction")
+		}
+		return WriteJSON(w, r, s, http.StatusOK)
+	}
+}
+
+func (api *API) deleteVariableSetInApplicationHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		appName := vars["permApplicationName"]
+		name := vars["name"]
+
+		app, erra := application.LoadByName(api.mustDB(), api.Cache, key, appName, getUser(ctx))
+		if erra != nil {
+			return sdk.WrapError(erra, "deleteVariableSetInApplicationHandler> Cannot load application %s", appName)
+		}
+
+		s, errs := variableset.LoadByName(api.mustDB(), name)
+		if errs != nil {
+			return sdk.WrapError(errs, "deleteVariableSetInApplicationHandler>")
+		}
+
+		tx, errb := api.mustDB().Begin()
+		if errb != nil {
+			return sdk.WrapError(errb, "deleteVariableSetInApplicationHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := variableset.DetachFromApplication(tx, s.ID, app.ID); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInApplicationHandler>")
+		}
+		if err := application.UpdateLastModified(tx, api.Cache, app, getUser(ctx)); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInApplicationHandler> Cannot update last modified")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "deleteVariableSetInApplicationHandler> Cannot commit transaction")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_postVariableSetInGroupHandlerAsGroupMember(t *testing.T) {
+	api, _, router := newTestAPI(t, bootstrap.InitiliazeDB)
+
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	u, pass := assets.InsertLambdaUser(api.mustDB(), g)
+
+	uri := router.GetRoute("POST", api.postVariableSetInGroupHandler, map[string]string{"permGroupName": g.Name})
+	test.NotEmpty(t, uri)
+
+	s := sdk.VariableSet{Name: sdk.RandomString(10)}
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, s)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// The admins of the group can write its variable sets
+	test.NoError(t, group.SetUserGroupAdmin(api.mustDB(), g.ID, u.ID))
+
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, s)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+}
+
+func Test_getVariableSetInGroupHandlerMasksSecrets(t *testing.T) {
+	api, _, router := newTestAPI(t, bootstrap.InitiliazeDB)
+
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	u, pass := assets.InsertLambdaUser(api.mustDB(), g)
+	test.NoError(t, group.SetUserGroupAdmin(api.mustDB(), g.ID, u.ID))
+
+	s := sdk.VariableSet{
+		Name:      sdk.RandomString(10),
+		Variables: []sdk.Variable{{Name: "password", Type: sdk.SecretVariable, Value: "my-secret"}},
+	}
+	uri := router.GetRoute("POST", api.postVariableSetInGroupHandler, map[string]string{"permGroupName": g.Name})
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, s)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+
+	v := sdk.Variable{Name: "token", Type: sdk.SecretVariable, Value: "my-token"}
+	uri = router.GetRoute("POST", api.postVariableInVariableSetHandler, map[string]string{"permGroupName": g.Name, "name": s.Name, "varName": v.Name})
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, v)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &v))
+	assert.Equal(t, sdk.PasswordPlaceholder, v.Value)
+
+	uri = router.GetRoute("GET", api.getVariableSetInGroupHandler, map[string]string{"permGroupName": g.Name, "name": s.Name})
+	req = assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var res sdk.VariableSet
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Len(t, res.Variables, 2)
+	for _, v := range res.Variables {
+		assert.Equal(t, sdk.PasswordPlaceholder, v.Value, "variable %s", v.Name)
+	}
+}
+
+func Test_postVariableSetInProjectHandlerAsOwningGroupMember(t *testing.T) {
+	api, db, router := newTestAPI(t, bootstrap.InitiliazeDB)
+
+	owner := &sdk.Group{Name: sdk.RandomString(10)}
+	admin, adminPass := assets.InsertLambdaUser(api.mustDB(), owner)
+	test.NoError(t, group.SetUserGroupAdmin(api.mustDB(), owner.ID, admin.ID))
+
+	s := sdk.VariableSet{Name: sdk.RandomString(10)}
+	uri := router.GetRoute("POST", api.postVariableSetInGroupHandler, map[string]string{"permGroupName": owner.Name})
+	req := assets.NewAuthentifiedRequest(t, admin, adminPass, "POST", uri, s)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code)
+
+	// The user can write the project, but is not a member of the group owning the set
+	u, pass := assets.InsertLambdaUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	test.NoError(t, group.InsertUserInGroup(api.mustDB(), proj.ProjectGroups[0].Group.ID, u.ID, false))
+
+	uri = router.GetRoute("POST", api.postVariableSetInProjectHandler, map[string]string{"permProjectKey": key, "name": s.Name})
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	test.NoError(t, group.InsertUserInGroup(api.mustDB(), owner.ID, u.ID, false))
+
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+}
@@ -0,0 +1,223 @@
+package variableset
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const selectQuery = `
+	SELECT variable_set.id, variable_set.name, variable_set.description, variable_set.group_id, "group".name, variable_set.last_modified
+	FROM variable_set
+	JOIN "group" ON "group".id = variable_set.group_id`
+
+func loadAll(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.VariableSet, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []sdk.VariableSet{}
+	for rows.Next() {
+		var s sdk.VariableSet
+		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &s.GroupID, &s.GroupName, &s.LastModified); err != nil {
+			return nil, err
+		}
+		sets = append(sets, s)
+	}
+	return sets, rows.Err()
+}
+
+func loadAllWithVariables(db gorp.SqlExecutor, args []GetAllVariableFuncArg, query string, queryArgs ...interface{}) ([]sdk.VariableSet, error) {
+	sets, err := loadAll(db, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sets {
+		vars, err := GetAllVariables(db, sets[i].ID, args...)
+		if err != nil {
+			return nil, err
+		}
+		sets[i].Variables = vars
+	}
+	return sets, nil
+}
+
+// LoadByName loads a variable set with its variables, the secret values are replaced by placeholders
+func LoadByName(db gorp.SqlExecutor, name string) (*sdk.VariableSet, error) {
+	sets, err := loadAllWithVariables(db, nil, selectQuery+` WHERE variable_set.name = $1`, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "variableset.LoadByName> Unable to load variable set %s", name)
+	}
+	if len(sets) == 0 {
+		return nil, sdk.ErrVariableSetNotFound
+	}
+	return &sets[0], nil
+}
+
+// LoadAllByGroup loads the variable sets owned by a group, without their variables
+func LoadAllByGroup(db gorp.SqlExecutor, groupID int64) ([]sdk.VariableSet, error) {
+	sets, err := loadAll(db, selectQuery+` WHERE variable_set.group_id = $1 ORDER BY variable_set.name`, groupID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "variableset.LoadAllByGroup> Unable to load variable sets of group %d", groupID)
+	}
+	return sets, nil
+}
+
+// LoadAllByProject loads the variable sets attached to a project with their variables, ordered by precedence
+func LoadAllByProject(db gorp.SqlExecutor, projectID int64, args ...GetAllVariableFuncArg) ([]sdk.VariableSet, error) {
+	query := selectQuery + `
+	JOIN project_variable_set ON project_variable_set.variable_set_id = variable_set.id
+	WHERE project_variable_set.project_id = $1
+	ORDER BY variable_set.name`
+	sets, err := loadAllWithVariables(db, args, query, projectID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "variableset.LoadAllByProject> Unable to load variable sets of project %d", projectID)
+	}
+	return sets, nil
+}
+
+// LoadAllByApplication loads the variable sets attached to an application with their variables, ordered by precedence
+func LoadAllByApplication(db gorp.SqlExecutor, appID int64, args ...GetAllVariableFuncArg) ([]sdk.VariableSet, error) {
+	query := selectQuery + `
+	JOIN application_variable_set ON application_variable_set.variable_set_id = variable_set.id
+	WHERE application_variable_set.application_id = $1
+	ORDER BY variable_set.name`
+	sets, err := loadAllWithVariables(db, args, query, appID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "variableset.LoadAllByApplication> Unable to load variable sets of application %d", appID)
+	}
+	return sets, nil
+}
+
+// Insert inserts a variable set, without its variables
+func Insert(db gorp.SqlExecutor, s *sdk.VariableSet) error {
+	s.LastModified = time.Now()
+	query := `INSERT INTO variable_set (name, description, group_id, last_modified) VALUES ($1, $2, $3, $4) RETURNING id`
+	if err := db.QueryRow(query, s.Name, s.Description, s.GroupID, s.LastModified).Scan(&s.ID); err != nil {
+		return sdk.WrapError(err, "variableset.Insert> Unable to insert variable set %s", s.Name)
+	}
+	return nil
+}
+
+// Update updates the description of a variable set
+func Update(db gorp.SqlExecutor, s *sdk.VariableSet) error {
+	s.LastModified = time.Now()
+	query := `UPDATE variable_set SET description = $2, last_modified = $3 WHERE id = $1`
+	if _, err := db.Exec(query, s.ID, s.Description, s.LastModified); err != nil {
+		return sdk.WrapError(err, "variableset.Update> Unable to update variable set %s", s.Name)
+	}
+	return nil
+}
+
+// Delete deletes a variable set with its variables and its audits
+func Delete(db gorp.SqlExecutor, s *sdk.VariableSet) error {
+	if _, err := db.Exec(`DELETE FROM variable_set WHERE id = $1`, s.ID); err != nil {
+		return sdk.WrapError(err, "variableset.Delete> Unable to delete variable set %s", s.Name)
+	}
+	return nil
+}
+
+func updateLastModified(db gorp.SqlExecutor, s *sdk.VariableSet) error {
+	s.LastModified = time.Now()
+	if _, err := db.Exec(`UPDATE variable_set SET last_modified = $2 WHERE id = $1`, s.ID, s.LastModified); err != nil {
+		return sdk.WrapError(err, "variableset.updateLastModified> Unable to update variable set %s", s.Name)
+	}
+	return nil
+}
+
+// IsAttached returns true if the variable set is attached to a project or an application
+func IsAttached(db gorp.SqlExecutor, setID int64) (bool, error) {
+	query := `
+	SELECT (SELECT COUNT(*) FROM project_variable_set WHERE variable_set_id = $1) +
+		(SELECT COUNT(*) FROM application_variable_set WHERE variable_set_id = $1)`
+	n, err := db.SelectInt(query, setID)
+	if err != nil {
+		return false, sdk.WrapError(err, "variableset.IsAttached> Unable to count attachments of variable set %d", setID)
+	}
+	return n > 0, nil
+}
+
+// AttachToProject attaches a variable set to a project
+func AttachToProject(db gorp.SqlExecutor, setID, projectID int64) error {
+	query := `
+	INSERT INTO project_variable_set (project_id, variable_set_id) SELECT $1, $2
+	WHERE NOT EXISTS (SELECT 1 FROM project_variable_set WHERE project_id = $1 AND variable_set_id = $2)`
+	if _, err := db.Exec(query, projectID, setID); err != nil {
+		return sdk.WrapError(err, "variableset.AttachToProject> Unable to attach variable set %d to project %d", setID, projectID)
+	}
+	return nil
+}
+
+// DetachFromProject detaches a variable set from a project
+func DetachFromProject(db gorp.SqlExecutor, setID, projectID int64) error {
+	return detach(db, `DELETE FROM project_variable_set WHERE project_id = $1 AND variable_set_id = $2`, projectID, setID)
+}
+
+// AttachToApplication attaches a variable set to an application
+func AttachToApplication(db gorp.SqlExecutor, setID, appID int64) error {
+	query := `
+	INSERT INTO application_variable_set (application_id, variable_set_id) SELECT $1, $2
+	WHERE NOT EXISTS (SELECT 1 FROM application_variable_set WHERE application_id = $1 AND variable_set_id = $2)`
+	if _, err := db.Exec(query, appID, setID); err != nil {
+		return sdk.WrapError(err, "variableset.AttachToApplication> Unable to attach variable set %d to application %d", setID, appID)
+	}
+	return nil
+}
+
+// DetachFromApplication detaches a variable set from an application
+func DetachFromApplication(db gorp.SqlExecutor, setID, appID int64) error {
+	return detach(db, `DELETE FROM application_variable_set WHERE application_id = $1 AND variable_set_id = $2`, appID, setID)
+}
+
+func detach(db gorp.SqlExecutor, query string, id, setID int64) error {
+	res, err := db.Exec(query, id, setID)
+	if err != nil {
+		return sdk.WrapError(err, "variableset.detach> Unable to detach variable set %d", setID)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WrapError(err, "variableset.detach> Unable to detach variable set %d", setID)
+	}
+	if n == 0 {
+		return sdk.ErrVariableSetNotFound
+	}
+	return nil
+}
+
+// LoadVariableNamesByProjectKey returns the names of the variables of the sets attached to a project
+func LoadVariableNamesByProjectKey(db gorp.SqlExecutor, projectKey string) ([]string, error) {
+	query := `
+	SELECT DISTINCT variable_set_variable.var_name
+	FROM variable_set_variable
+	JOIN project_variable_set ON project_variable_set.variable_set_id = variable_set_variable.variable_set_id
+	JOIN project ON project.id = project_variable_set.project_id
+	WHERE project.projectkey = $1
+	ORDER BY variable_set_variable.var_name`
+	return loadNames(db, query, projectKey)
+}
+
+// LoadApplicationVariableNamesByProjectKey returns the names of the variables of the sets attached to the applications of a project
+func LoadApplicationVariableNamesByProjectKey(db gorp.SqlExecutor, projectKey string) ([]string, error) {
+	query := `
+	SELECT DISTINCT variable_set_variable.var_name
+	FROM variable_set_variable
+	JOIN application_variable_set ON application_variable_set.variable_set_id = variable_set_variable.variable_set_id
+	JOIN application ON application.id = application_variable_set.application_id
+	JOIN project ON project.id = application.project_id
+	WHERE project.projectkey = $1
+	ORDER BY variable_set_variable.var_name`
+	return loadNames(db, query, projectKey)
+}
+
+func loadNames(db gorp.SqlExecutor, query string, args ...interface{}) ([]string, error) {
+	var names []string
+	if _, err := db.Select(&names, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "variableset.loadNames> Unable to load variable names")
+	}
+	return names, nil
+}
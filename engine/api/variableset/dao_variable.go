@@ -0,0 +1,219 @@
+package variableset
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+type structarg struct {
+	clearsecret   bool
+	encryptsecret bool
+}
+
+// GetAllVariableFuncArg defines the base type for functional argument of GetAllVariables
+type GetAllVariableFuncArg func(args *structarg)
+
+// WithClearPassword is a function argument to GetAllVariables
+func WithClearPassword() GetAllVariableFuncArg {
+	return func(args *structarg) {
+		args.clearsecret = true
+	}
+}
+
+// WithEncryptPassword is a function argument to GetAllVariables
+func WithEncryptPassword() GetAllVariableFuncArg {
+	return func(args *structarg) {
+		args.encryptsecret = true
+	}
+}
+
+// GetAllVariables gets all the variables of a variable set
+func GetAllVariables(db gorp.SqlExecutor, setID int64, args ...GetAllVariableFuncArg) ([]sdk.Variable, error) {
+	c := structarg{}
+	for _, f := range args {
+		f(&c)
+	}
+
+	query := `SELECT id, var_name, var_value, cipher_value, var_type
+	          FROM variable_set_variable
+	          WHERE variable_set_id = $1
+	          ORDER BY var_name`
+	rows, err := db.Query(query, setID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variables := []sdk.Variable{}
+	for rows.Next() {
+		var v sdk.Variable
+		var clearVal sql.NullString
+		var cipherVal []byte
+		if err := rows.Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &v.Type); err != nil {
+			return nil, err
+		}
+		if c.encryptsecret && sdk.NeedPlaceholder(v.Type) {
+			v.Value = string(cipherVal)
+		} else {
+			v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+			if err != nil {
+				return nil, err
+			}
+		}
+		variables = append(variables, v)
+	}
+	return variables, rows.Err()
+}
+
+// GetVariable gets a variable of a variable set given its name
+func GetVariable(db gorp.SqlExecutor, setID int64, name string, args ...GetAllVariableFuncArg) (*sdk.Variable, error) {
+	c := structarg{}
+	for _, f := range args {
+		f(&c)
+	}
+
+	query := `SELECT id, var_name, var_value, cipher_value, var_type
+	          FROM variable_set_variable
+	          WHERE variable_set_id = $1 AND var_name = $2`
+	v := &sdk.Variable{}
+	var clearVal sql.NullString
+	var cipherVal []byte
+	if err := db.QueryRow(query, setID, name).Scan(&v.ID, &v.Name, &clearVal, &cipherVal, &v.Type); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNoVariable
+		}
+		return nil, sdk.WrapError(err, "variableset.GetVariable> Unable to load variable %s", name)
+	}
+
+	var err error
+	v.Value, err = secret.DecryptS(v.Type, clearVal, cipherVal, c.clearsecret)
+	return v, err
+}
+
+// InsertVariable inserts a variable in a variable set, it returns the event of the audit to publish once committed
+func InsertVariable(db gorp.SqlExecutor, s *sdk.VariableSet, v *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
+	clear, cipher, err := secret.EncryptS(v.Type, v.Value)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.InsertVariable> Cannot encrypt secret %s", v.Name)
+	}
+
+	query := `INSERT INTO variable_set_variable (variable_set_id, var_name, var_value, cipher_value, var_type)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	if err := db.QueryRow(query, s.ID, v.Name, clear, cipher, v.Type).Scan(&v.ID); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.InsertVariable> Cannot insert variable %s", v.Name)
+	}
+
+	after := *v
+	evt, err := insertAudit(db, s, sdk.AuditAdd, v.ID, nil, &after, u)
+	if err != nil {
+		return sdk.EventAudit{}, err
+	}
+	if err := updateLastModified(db, s); err != nil {
+		return sdk.EventAudit{}, err
+	}
+	return evt, nil
+}
+
+// UpdateVariable updates a variable of a variable set, a secret with a placeholder value keeps its previous value.
+// It returns the event of the audit to publish once committed
+func UpdateVariable(db gorp.SqlExecutor, s *sdk.VariableSet, v *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
+	previous, err := GetVariable(db, s.ID, v.Name, WithClearPassword())
+	if err != nil {
+		return sdk.EventAudit{}, err
+	}
+	v.ID = previous.ID
+
+	value := v.Value
+	if sdk.NeedPlaceholder(v.Type) && v.Value == sdk.PasswordPlaceholder {
+		value = previous.Value
+	}
+
+	clear, cipher, err := secret.EncryptS(v.Type, value)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.UpdateVariable> Cannot encrypt secret %s", v.Name)
+	}
+
+	query := `UPDATE variable_set_variable SET var_value = $2, cipher_value = $3, var_type = $4 WHERE id = $1`
+	if _, err := db.Exec(query, v.ID, clear, cipher, v.Type); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.UpdateVariable> Cannot update variable %s", v.Name)
+	}
+
+	after := *v
+	after.Value = value
+	evt, err := insertAudit(db, s, sdk.AuditUpdate, v.ID, previous, &after, u)
+	if err != nil {
+		return sdk.EventAudit{}, err
+	}
+	if err := updateLastModified(db, s); err != nil {
+		return sdk.EventAudit{}, err
+	}
+	return evt, nil
+}
+
+// DeleteVariable deletes a variable of a variable set, it returns the event of the audit to publish once committed
+func DeleteVariable(db gorp.SqlExecutor, s *sdk.VariableSet, name string, u *sdk.User) (sdk.EventAudit, error) {
+	previous, err := GetVariable(db, s.ID, name, WithClearPassword())
+	if err != nil {
+		return sdk.EventAudit{}, err
+	}
+
+	if _, err := db.Exec(`DELETE FROM variable_set_variable WHERE id = $1`, previous.ID); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.DeleteVariable> Cannot delete variable %s", name)
+	}
+
+	evt, err := insertAudit(db, s, sdk.AuditDelete, previous.ID, previous, nil, u)
+	if err != nil {
+		return sdk.EventAudit{}, err
+	}
+	if err := updateLastModified(db, s); err != nil {
+		return sdk.EventAudit{}, err
+	}
+	return evt, nil
+}
+
+func insertAudit(db gorp.SqlExecutor, s *sdk.VariableSet, auditType string, varID int64, before, after *sdk.Variable, u *sdk.User) (sdk.EventAudit, error) {
+	name := after
+	if name == nil {
+		name = before
+	}
+	e := audit.Entity{Type: sdk.AuditEntityVariable, Name: "variableset/" + s.Name + "/" + name.Name}
+	evt, err := audit.Add(db, u, e, before, after)
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.insertAudit> Cannot audit variable %d", varID)
+	}
+
+	a := dbVariableSetAudit{
+		VariableSetID:  s.ID,
+		VariableID:     varID,
+		Type:           auditType,
+		VariableBefore: before,
+		VariableAfter:  after,
+		Author:         u.Username,
+		Versionned:     time.Now(),
+	}
+	if err := db.Insert(&a); err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "variableset.insertAudit> Cannot insert audit for variable %d", varID)
+	}
+	return evt, nil
+}
+
+// LoadAudits loads the audits of the variables of a variable set
+func LoadAudits(db gorp.SqlExecutor, setID int64) ([]sdk.VariableSetAudit, error) {
+	var res []dbVariableSetAudit
+	query := "SELECT * FROM variable_set_audit WHERE variable_set_id = $1 ORDER BY versionned DESC"
+	if _, err := db.Select(&res, query, setID); err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "variableset.LoadAudits> Cannot load audits of variable set %d", setID)
+	}
+
+	audits := make([]sdk.VariableSetAudit, len(res))
+	for i := range res {
+		audits[i] = sdk.VariableSetAudit(res[i])
+	}
+	return audits, nil
+}
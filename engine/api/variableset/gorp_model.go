@@ -0,0 +1,96 @@
+package variableset
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbVariableSetAudit sdk.VariableSetAudit
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbVariableSetAudit{}, "variable_set_audit", true, "id"))
+}
+
+// PostGet is a db hook
+func (vsa *dbVariableSetAudit) PostGet(db gorp.SqlExecutor) error {
+	var before, after sql.NullString
+	query := "SELECT variable_before, variable_after from variable_set_audit WHERE id = $1"
+	if err := db.QueryRow(query, vsa.ID).Scan(&before, &after); err != nil {
+		return err
+	}
+
+	if before.Valid {
+		vBefore := &sdk.Variable{}
+		if err := json.Unmarshal([]byte(before.String), vBefore); err != nil {
+			return err
+		}
+		if sdk.NeedPlaceholder(vBefore.Type) {
+			vBefore.Value = sdk.PasswordPlaceholder
+		}
+		vsa.VariableBefore = vBefore
+	}
+
+	if after.Valid {
+		vAfter := &sdk.Variable{}
+		if err := json.Unmarshal([]byte(after.String), vAfter); err != nil {
+			return err
+		}
+		if sdk.NeedPlaceholder(vAfter.Type) {
+			vAfter.Value = sdk.PasswordPlaceholder
+		}
+		vsa.VariableAfter = vAfter
+	}
+
+	return nil
+}
+
+// PostInsert is a db hook
+func (vsa *dbVariableSetAudit) PostInsert(db gorp.SqlExecutor) error {
+	var vB, vA sql.NullString
+
+	if vsa.VariableBefore != nil {
+		v, err := json.Marshal(vsa.VariableBefore)
+		if err != nil {
+			return err
+		}
+		vB.Valid = true
+		vB.String = string(v)
+	}
+
+	if vsa.VariableAfter != nil {
+		v, err := json.Marshal(vsa.VariableAfter)
+		if err != nil {
+			return err
+		}
+		vA.Valid = true
+		vA.String = string(v)
+	}
+
+	query := "update variable_set_audit set variable_before = $2, variable_after = $3 where id = $1"
+	if _, err := db.Exec(query, vsa.ID, vB, vA); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PreInsert is a db hook, it encrypts the secret values
+func (vsa *dbVariableSetAudit) PreInsert(s gorp.SqlExecutor) error {
+	for _, v := range []*sdk.Variable{vsa.VariableBefore, vsa.VariableAfter} {
+		if v == nil || !sdk.NeedPlaceholder(v.Type) {
+			continue
+		}
+		secret, err := secret.Encrypt([]byte(v.Value))
+		if err != nil {
+			return err
+		}
+		v.Value = base64.StdEncoding.EncodeToString(secret)
+	}
+	return nil
+}
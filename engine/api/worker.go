@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/hatchery"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -31,19 +32,47 @@ func (api *API) registerWorkerHandler() Handler {
 			}
 		}
 
+		var osArch string
+		if params.OS != "" && params.Arch != "" {
+			osArch = params.OS + "/" + params.Arch
+		}
+
+		// A worker spawned with a version of its model which is not the current one doesn't
+		// update the capabilities of the model
+		binaryCapabilities := params.BinaryCapabilities
+		var version *sdk.ModelVersion
+		if params.ModelID != 0 && params.ModelVersion != 0 {
+			var errV error
+			version, errV = worker.LoadModelVersion(api.mustDB(), params.ModelID, params.ModelVersion)
+			if errV != nil {
+				return sdk.WrapError(errV, "registerWorkerHandler> Unable to load version %d of model %d", params.ModelVersion, params.ModelID)
+			}
+			if version.Status != sdk.ModelVersionActive {
+				binaryCapabilities = nil
+			}
+		}
+
 		// Try to register worker
-		worker, err := worker.RegisterWorker(api.mustDB(), params.Name, params.Token, params.ModelID, h, params.BinaryCapabilities)
+		wk, err := worker.RegisterWorker(api.mustDB(), params.Name, params.Token, params.ModelID, h, binaryCapabilities, params.Labels, osArch)
 		if err != nil {
 			err = sdk.NewError(sdk.ErrUnauthorized, err)
 			return sdk.WrapError(err, "registerWorkerHandler> [%s] Registering failed", params.Name)
 		}
 
-		worker.Uptodate = params.Version == sdk.VERSION
+		// The registration check of a version records its capabilities
+		if version != nil && version.Status == sdk.ModelVersionPending && params.RegisterOnly {
+			if err := worker.UpdateModelVersionCheck(api.mustDB(), version, params.BinaryCapabilities, params.ToolVersions); err != nil {
+				return sdk.WrapError(err, "registerWorkerHandler> [%s] Unable to record the check of version %d of model %d", params.Name, version.Version, params.ModelID)
+			}
+			log.Info("registerWorkerHandler> version %d of model %d checked by %s", version.Version, params.ModelID, params.Name)
+		}
+
+		wk.Uptodate = params.Version == sdk.VERSION
 
-		log.Debug("New worker: [%s] - %s", worker.ID, worker.Name)
+		log.Debug("New worker: [%s] - %s", wk.ID, wk.Name)
 
 		// Return worker info to worker itself
-		return WriteJSON(w, r, worker, http.StatusOK)
+		return WriteJSON(w, r, wk, http.StatusOK)
 	}
 }
 
@@ -143,10 +172,48 @@ func (api *API) refreshWorkerHandler() Handler {
 		if err := worker.RefreshWorker(api.mustDB(), getWorker(ctx).ID); err != nil && (err != sql.ErrNoRows || err != worker.ErrNoWorker) {
 			return sdk.WrapError(err, "refreshWorkerHandler> cannot refresh last beat of %s", getWorker(ctx).ID)
 		}
+		// The worker gets its maintenance mode with its heartbeat
+		return WriteJSON(w, r, getWorker(ctx), http.StatusOK)
+	}
+}
+
+func (api *API) postWorkerMaintenanceHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := mux.Vars(r)["id"]
+
+		if err := worker.SetMaintenance(api.mustDB(), id, true); err != nil {
+			if err == worker.ErrNoWorker {
+				return sdk.WrapError(sdk.ErrNotFound, "postWorkerMaintenanceHandler> worker %s does not exist", id)
+			}
+			return sdk.WrapError(err, "postWorkerMaintenanceHandler> cannot set worker %s in maintenance", id)
+		}
+		api.resetWorkersCache([]string{id})
 		return nil
 	}
 }
 
+func (api *API) deleteWorkerMaintenanceHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := mux.Vars(r)["id"]
+
+		if err := worker.SetMaintenance(api.mustDB(), id, false); err != nil {
+			if err == worker.ErrNoWorker {
+				return sdk.WrapError(sdk.ErrNotFound, "deleteWorkerMaintenanceHandler> worker %s does not exist", id)
+			}
+			return sdk.WrapError(err, "deleteWorkerMaintenanceHandler> cannot end maintenance of worker %s", id)
+		}
+		api.resetWorkersCache([]string{id})
+		return nil
+	}
+}
+
+// resetWorkersCache removes the workers from the cache of the authentication, they are loaded again with their last status
+func (api *API) resetWorkersCache(ids []string) {
+	for _, id := range ids {
+		api.Cache.Delete(cache.Key("worker", id))
+	}
+}
+
 func (api *API) unregisterWorkerHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if err := worker.DeleteWorker(api.mustDB(), getWorker(ctx).ID); err != nil {
@@ -52,7 +52,7 @@ func (m *WorkerModel) PreDelete(s gorp.SqlExecutor) error {
 	return nil
 }
 
-//PostSelect load capabilitites and createdBy user
+//PostSelect load capabilitites, next version and createdBy user
 func (m *WorkerModel) PostSelect(s gorp.SqlExecutor) error {
 	//Load capabilities
 	var capabilities = []struct {
@@ -74,6 +74,13 @@ func (m *WorkerModel) PostSelect(s gorp.SqlExecutor) error {
 		})
 	}
 
+	//Load the version waiting for its promotion
+	next, err := loadNextModelVersion(s, m.ID, m.Version)
+	if err != nil {
+		return err
+	}
+	m.NextVersion = next
+
 	//Load created_by
 	m.CreatedBy = sdk.User{}
 	str, errSelect := s.SelectNullStr("select created_by from worker_model where id = $1", &m.ID)
@@ -30,6 +30,7 @@ const columns = `
 	worker_model.last_spawn_err,
 	worker_model.nb_spawn_err,
 	worker_model.date_last_spawn_err,
+	worker_model.version,
 	"group".name as groupname`
 
 type dbResultWMS struct {
@@ -37,14 +38,21 @@ type dbResultWMS struct {
 	GroupName string `db:"groupname"`
 }
 
-// InsertWorkerModel insert a new worker model in database
+// InsertWorkerModel insert a new worker model in database, with its first version
 func InsertWorkerModel(db gorp.SqlExecutor, model *sdk.Model) error {
 	dbmodel := WorkerModel(*model)
 	if err := db.Insert(&dbmodel); err != nil {
 		return err
 	}
 	*model = sdk.Model(dbmodel)
-	return nil
+
+	v := sdk.ModelVersion{
+		Image:     model.Image,
+		Template:  model.Template,
+		Status:    sdk.ModelVersionActive,
+		CreatedBy: model.CreatedBy.Username,
+	}
+	return InsertModelVersion(db, model, &v)
 }
 
 // UpdateWorkerModel update a worker model. If worker model have SpawnErr -> clear them
@@ -60,3 +60,16 @@ func GetModelCapabilities(db gorp.SqlExecutor, store cache.Store, modelID int64)
 	}
 	return req, nil
 }
+
+//MatchLabelRequirements checks the label and os-arch requirements against the capabilities declared by the worker and its model
+func MatchLabelRequirements(db gorp.SqlExecutor, store cache.Store, w *sdk.Worker, requirements []sdk.Requirement) (bool, error) {
+	capas := w.Capabilities()
+	if w.ModelID != 0 {
+		modelCapas, err := GetModelCapabilities(db, store, w.ModelID)
+		if err != nil {
+			return false, err
+		}
+		capas = append(capas, modelCapas...)
+	}
+	return sdk.MatchLabelRequirements(capas, requirements), nil
+}
@@ -0,0 +1,225 @@
+package worker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const versionColumns = `id, worker_model_id, version, image, template, status, rollout, capabilities, tool_versions, created, created_by, checked`
+
+func loadModelVersions(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.ModelVersion, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadModelVersions> Unable to load versions")
+	}
+	defer rows.Close()
+
+	versions := []sdk.ModelVersion{}
+	for rows.Next() {
+		var v sdk.ModelVersion
+		var template, capabilities, toolVersions []byte
+		var checked *time.Time
+		if err := rows.Scan(&v.ID, &v.ModelID, &v.Version, &v.Image, &template, &v.Status, &v.Rollout, &capabilities, &toolVersions, &v.Created, &v.CreatedBy, &checked); err != nil {
+			return nil, sdk.WrapError(err, "loadModelVersions> Unable to scan version")
+		}
+		v.Checked = checked
+		if len(template) > 0 && string(template) != "null" {
+			if err := json.Unmarshal(template, &v.Template); err != nil {
+				return nil, sdk.WrapError(err, "loadModelVersions> Unable to unmarshal template of version %d", v.ID)
+			}
+		}
+		if len(capabilities) > 0 {
+			if err := json.Unmarshal(capabilities, &v.Capabilities); err != nil {
+				return nil, sdk.WrapError(err, "loadModelVersions> Unable to unmarshal capabilities of version %d", v.ID)
+			}
+		}
+		if len(toolVersions) > 0 {
+			if err := json.Unmarshal(toolVersions, &v.ToolVersions); err != nil {
+				return nil, sdk.WrapError(err, "loadModelVersions> Unable to unmarshal tool versions of version %d", v.ID)
+			}
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// LoadModelVersions returns the history of the versions of a worker model, the last version first
+func LoadModelVersions(db gorp.SqlExecutor, modelID int64) ([]sdk.ModelVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM worker_model_version WHERE worker_model_id = $1 ORDER BY version DESC`
+	return loadModelVersions(db, query, modelID)
+}
+
+// LoadModelVersion returns a version of a worker model
+func LoadModelVersion(db gorp.SqlExecutor, modelID, version int64) (*sdk.ModelVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM worker_model_version WHERE worker_model_id = $1 AND version = $2`
+	versions, err := loadModelVersions(db, query, modelID, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, sdk.ErrNotFound
+	}
+	return &versions[0], nil
+}
+
+// loadNextModelVersion returns the version which is checked or rolled out before its promotion, nil if there is none
+func loadNextModelVersion(db gorp.SqlExecutor, modelID, current int64) (*sdk.ModelVersion, error) {
+	query := `SELECT ` + versionColumns + ` FROM worker_model_version
+	WHERE worker_model_id = $1 AND version > $2 AND status = ANY(string_to_array($3, ','))
+	ORDER BY version DESC LIMIT 1`
+	versions, err := loadModelVersions(db, query, modelID, current, sdk.ModelVersionPending+","+sdk.ModelVersionChecked)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return &versions[0], nil
+}
+
+// InsertModelVersion adds a version to a worker model. The previous versions which were not promoted are retired.
+// An active version becomes the current version of the model
+func InsertModelVersion(db gorp.SqlExecutor, m *sdk.Model, v *sdk.ModelVersion) error {
+	var last sql.NullInt64
+	if err := db.QueryRow(`SELECT max(version) FROM worker_model_version WHERE worker_model_id = $1`, m.ID).Scan(&last); err != nil {
+		return sdk.WrapError(err, "InsertModelVersion> Unable to load last version of model %d", m.ID)
+	}
+
+	v.ModelID = m.ID
+	v.Version = last.Int64 + 1
+	v.Created = time.Now()
+
+	retired := []string{sdk.ModelVersionPending, sdk.ModelVersionChecked}
+	if v.Status == sdk.ModelVersionActive {
+		retired = append(retired, sdk.ModelVersionActive)
+	}
+	if err := retireModelVersions(db, m.ID, retired); err != nil {
+		return err
+	}
+
+	template, err := json.Marshal(v.Template)
+	if err != nil {
+		return sdk.WrapError(err, "InsertModelVersion> Unable to marshal template")
+	}
+	capabilities, err := json.Marshal(v.Capabilities)
+	if err != nil {
+		return sdk.WrapError(err, "InsertModelVersion> Unable to marshal capabilities")
+	}
+	toolVersions, err := json.Marshal(v.ToolVersions)
+	if err != nil {
+		return sdk.WrapError(err, "InsertModelVersion> Unable to marshal tool versions")
+	}
+
+	query := `INSERT INTO worker_model_version (worker_model_id, version, image, template, status, rollout, capabilities, tool_versions, created, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	if err := db.QueryRow(query, v.ModelID, v.Version, v.Image, template, v.Status, v.Rollout, capabilities, toolVersions, v.Created, v.CreatedBy).Scan(&v.ID); err != nil {
+		return sdk.WrapError(err, "InsertModelVersion> Unable to insert version %d of model %d", v.Version, m.ID)
+	}
+
+	if v.Status == sdk.ModelVersionActive {
+		if _, err := db.Exec(`UPDATE worker_model SET version = $1 WHERE id = $2`, v.Version, m.ID); err != nil {
+			return sdk.WrapError(err, "InsertModelVersion> Unable to update version of model %d", m.ID)
+		}
+		m.Version = v.Version
+	}
+	return nil
+}
+
+func retireModelVersions(db gorp.SqlExecutor, modelID int64, status []string) error {
+	query := `UPDATE worker_model_version SET status = $1, rollout = 0 WHERE worker_model_id = $2 AND status = ANY(string_to_array($3, ','))`
+	if _, err := db.Exec(query, sdk.ModelVersionRetired, modelID, strings.Join(status, ",")); err != nil {
+		return sdk.WrapError(err, "retireModelVersions> Unable to retire versions of model %d", modelID)
+	}
+	return nil
+}
+
+// UpdateModelVersionCheck records the result of the registration check of a version: the binaries found by the worker
+// and their versions
+func UpdateModelVersionCheck(db gorp.SqlExecutor, v *sdk.ModelVersion, binaryCapabilities []string, toolVersions map[string]string) error {
+	now := time.Now()
+	v.Status = sdk.ModelVersionChecked
+	v.Checked = &now
+	v.ToolVersions = toolVersions
+	v.Capabilities = make([]sdk.Requirement, 0, len(binaryCapabilities))
+	for _, b := range binaryCapabilities {
+		v.Capabilities = append(v.Capabilities, sdk.Requirement{Name: b, Type: sdk.BinaryRequirement, Value: b})
+	}
+
+	capabilities, err := json.Marshal(v.Capabilities)
+	if err != nil {
+		return sdk.WrapError(err, "UpdateModelVersionCheck> Unable to marshal capabilities")
+	}
+	tools, err := json.Marshal(v.ToolVersions)
+	if err != nil {
+		return sdk.WrapError(err, "UpdateModelVersionCheck> Unable to marshal tool versions")
+	}
+
+	query := `UPDATE worker_model_version SET status = $1, checked = $2, capabilities = $3, tool_versions = $4 WHERE id = $5`
+	if _, err := db.Exec(query, v.Status, now, capabilities, tools, v.ID); err != nil {
+		return sdk.WrapError(err, "UpdateModelVersionCheck> Unable to update version %d", v.ID)
+	}
+	return nil
+}
+
+// UpdateModelVersionRollout sets the percent of the spawns using a checked version
+func UpdateModelVersionRollout(db gorp.SqlExecutor, v *sdk.ModelVersion, rollout int64) error {
+	if v.Status != sdk.ModelVersionChecked {
+		return sdk.WrapError(sdk.ErrWorkerModelVersionNotChecked, "UpdateModelVersionRollout> version %d is %s", v.Version, v.Status)
+	}
+	if rollout < 0 || rollout > 100 {
+		return sdk.WrapError(sdk.ErrWrongRequest, "UpdateModelVersionRollout> invalid rollout %d", rollout)
+	}
+	v.Rollout = rollout
+	if _, err := db.Exec(`UPDATE worker_model_version SET rollout = $1 WHERE id = $2`, rollout, v.ID); err != nil {
+		return sdk.WrapError(err, "UpdateModelVersionRollout> Unable to update version %d", v.ID)
+	}
+	return nil
+}
+
+// PromoteModelVersion makes a version the current version of its model. A checked version is promoted,
+// a retired version is a rollback. The binary capabilities of the model are the ones recorded by the
+// registration check of the version, the model needs a registration if the version has no recorded capabilities
+func PromoteModelVersion(db gorp.SqlExecutor, m *sdk.Model, v *sdk.ModelVersion) error {
+	if v.Status != sdk.ModelVersionChecked && v.Status != sdk.ModelVersionRetired {
+		return sdk.WrapError(sdk.ErrWorkerModelVersionNotChecked, "PromoteModelVersion> version %d is %s", v.Version, v.Status)
+	}
+
+	if err := retireModelVersions(db, m.ID, []string{sdk.ModelVersionActive}); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`UPDATE worker_model_version SET status = $1, rollout = 0 WHERE id = $2`, sdk.ModelVersionActive, v.ID); err != nil {
+		return sdk.WrapError(err, "PromoteModelVersion> Unable to update version %d", v.ID)
+	}
+	v.Status = sdk.ModelVersionActive
+	v.Rollout = 0
+
+	capabilities := []sdk.Requirement{}
+	for _, c := range m.Capabilities {
+		if c.Type != sdk.BinaryRequirement {
+			capabilities = append(capabilities, c)
+		}
+	}
+	capabilities = append(capabilities, v.Capabilities...)
+
+	*m = m.WithVersion(*v)
+	m.Capabilities = capabilities
+	m.NeedRegistration = len(v.Capabilities) == 0
+	if v.Checked != nil {
+		m.LastRegistration = *v.Checked
+	}
+	m.UserLastModified = time.Now()
+	m.NbSpawnErr = 0
+	m.LastSpawnErr = ""
+
+	dbmodel := WorkerModel(*m)
+	if _, err := db.Update(&dbmodel); err != nil {
+		return sdk.WrapError(err, "PromoteModelVersion> Unable to update model %d", m.ID)
+	}
+	return nil
+}
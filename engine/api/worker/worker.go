@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
 	"time"
@@ -82,8 +83,12 @@ func DeleteWorker(db *gorp.DbMap, id string) error {
 
 // InsertWorker inserts worker representation into database
 func InsertWorker(db gorp.SqlExecutor, w *sdk.Worker, groupID int64) error {
-	query := `INSERT INTO worker (id, name, last_beat, model, status, hatchery_id, hatchery_name, group_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := db.Exec(query, w.ID, w.Name, time.Now(), w.ModelID, w.Status.String(), w.HatcheryID, w.HatcheryName, groupID)
+	labels, errM := json.Marshal(w.Labels)
+	if errM != nil {
+		return errM
+	}
+	query := `INSERT INTO worker (id, name, last_beat, model, status, hatchery_id, hatchery_name, group_id, labels, os_arch, maintenance) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := db.Exec(query, w.ID, w.Name, time.Now(), w.ModelID, w.Status.String(), w.HatcheryID, w.HatcheryName, groupID, labels, w.OSArch, w.Maintenance)
 	return err
 }
 
@@ -91,13 +96,19 @@ func InsertWorker(db gorp.SqlExecutor, w *sdk.Worker, groupID int64) error {
 func LoadWorker(db gorp.SqlExecutor, id string) (*sdk.Worker, error) {
 	w := &sdk.Worker{}
 	var statusS string
-	query := `SELECT id, name, last_beat, group_id, model, status, hatchery_id, hatchery_name, group_id FROM worker WHERE worker.id = $1 FOR UPDATE`
+	var labels []byte
+	query := `SELECT id, name, last_beat, group_id, model, status, hatchery_id, hatchery_name, group_id, labels, os_arch, maintenance FROM worker WHERE worker.id = $1 FOR UPDATE`
 
-	err := db.QueryRow(query, id).Scan(&w.ID, &w.Name, &w.LastBeat, &w.GroupID, &w.ModelID, &statusS, &w.HatcheryID, &w.HatcheryName, &w.GroupID)
+	err := db.QueryRow(query, id).Scan(&w.ID, &w.Name, &w.LastBeat, &w.GroupID, &w.ModelID, &statusS, &w.HatcheryID, &w.HatcheryName, &w.GroupID, &labels, &w.OSArch, &w.Maintenance)
 	if err != nil {
 		return nil, err
 	}
 	w.Status = sdk.StatusFromString(statusS)
+	if len(labels) > 0 {
+		if err := json.Unmarshal(labels, &w.Labels); err != nil {
+			return nil, err
+		}
+	}
 
 	return w, nil
 }
@@ -131,7 +142,7 @@ func LoadWorkersByPipelineJobID(db gorp.SqlExecutor, pipJobID int64) ([]sdk.Work
 func LoadWorkers(db gorp.SqlExecutor) ([]sdk.Worker, error) {
 	w := []sdk.Worker{}
 	var statusS string
-	query := `SELECT id, name, last_beat, group_id, model, status, hatchery_id, hatchery_name FROM worker WHERE 1 = 1 ORDER BY name ASC`
+	query := `SELECT id, name, last_beat, group_id, model, status, hatchery_id, hatchery_name, labels, os_arch, maintenance FROM worker WHERE 1 = 1 ORDER BY name ASC`
 
 	rows, err := db.Query(query)
 	if err != nil {
@@ -141,11 +152,17 @@ func LoadWorkers(db gorp.SqlExecutor) ([]sdk.Worker, error) {
 
 	for rows.Next() {
 		var worker sdk.Worker
-		err = rows.Scan(&worker.ID, &worker.Name, &worker.LastBeat, &worker.GroupID, &worker.ModelID, &statusS, &worker.HatcheryID, &worker.HatcheryName)
+		var labels []byte
+		err = rows.Scan(&worker.ID, &worker.Name, &worker.LastBeat, &worker.GroupID, &worker.ModelID, &statusS, &worker.HatcheryID, &worker.HatcheryName, &labels, &worker.OSArch, &worker.Maintenance)
 		if err != nil {
 			return nil, err
 		}
 		worker.Status = sdk.StatusFromString(statusS)
+		if len(labels) > 0 {
+			if err := json.Unmarshal(labels, &worker.Labels); err != nil {
+				return nil, err
+			}
+		}
 		w = append(w, worker)
 	}
 
@@ -227,9 +244,13 @@ type RegistrationForm struct {
 	Hatchery           int64
 	HatcheryName       string
 	BinaryCapabilities []string
+	Labels             []string
 	Version            string
 	OS                 string
 	Arch               string
+	ModelVersion       int64
+	RegisterOnly       bool
+	ToolVersions       map[string]string
 }
 
 // TakeForm contains booked JobID if exists
@@ -239,7 +260,7 @@ type TakeForm struct {
 }
 
 // RegisterWorker  Register new worker
-func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *sdk.Hatchery, binaryCapabilities []string) (*sdk.Worker, error) {
+func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *sdk.Hatchery, binaryCapabilities []string, labels []string, osArch string) (*sdk.Worker, error) {
 	if name == "" {
 		return nil, fmt.Errorf("cannot register worker with empty name")
 	}
@@ -296,11 +317,15 @@ func RegisterWorker(db *gorp.DbMap, name string, key string, modelID int64, h *s
 		Model:   m,
 		Status:  sdk.StatusWaiting,
 		GroupID: t.GroupID,
+		Labels:  labels,
+		OSArch:  osArch,
 	}
 
 	if h != nil {
 		w.HatcheryID = h.ID
 		w.HatcheryName = h.Name
+		// a worker registered by a hatchery in maintenance is drained
+		w.Maintenance = h.Maintenance
 	}
 
 	tx, errTx := db.Begin()
@@ -379,6 +404,42 @@ func SetStatus(db gorp.SqlExecutor, workerID string, status sdk.Status) error {
 	return err
 }
 
+// SetMaintenance enables or disables the maintenance mode of a worker
+func SetMaintenance(db gorp.SqlExecutor, workerID string, maintenance bool) error {
+	res, err := db.Exec(`UPDATE worker SET maintenance = $1 WHERE id = $2`, maintenance, workerID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNoWorker
+	}
+	return nil
+}
+
+// SetMaintenanceByHatchery enables or disables the maintenance mode of the workers of a hatchery, it returns their ids
+func SetMaintenanceByHatchery(db gorp.SqlExecutor, hatcheryID int64, maintenance bool) ([]string, error) {
+	rows, err := db.Query(`UPDATE worker SET maintenance = $1 WHERE hatchery_id = $2 RETURNING id`, maintenance, hatcheryID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "SetMaintenanceByHatchery> Unable to update workers of hatchery %d", hatcheryID)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, sdk.WrapError(err, "SetMaintenanceByHatchery> Unable to scan worker id")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // SetToBuilding sets action_build_id and status to building on given worker
 func SetToBuilding(db gorp.SqlExecutor, workerID string, actionBuildID int64) error {
 	query := `UPDATE worker SET status = $1, action_build_id = $2 WHERE id = $3`
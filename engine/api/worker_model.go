@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"net/http"
+	"reflect"
 
 	"github.com/ovh/cds/engine/api/action"
-
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/impact"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/sanity"
@@ -62,11 +64,27 @@ func (api *API) addWorkerModelHandler() Handler {
 			Origin:   getUser(ctx).Origin,
 		}
 
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "addWorkerModel> unable to start transaction")
+		}
+		defer tx.Rollback()
+
 		// Insert model in db
-		if err := worker.InsertWorkerModel(api.mustDB(), &model); err != nil {
+		if err := worker.InsertWorkerModel(tx, &model); err != nil {
 			return sdk.WrapError(err, "addWorkerModel> cannot add worker model")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{Type: sdk.AuditEntityWorkerModel, Name: model.Name}, nil, model)
+		if err != nil {
+			return sdk.WrapError(err, "addWorkerModel> cannot audit worker model")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "addWorkerModel> unable to commit transaction")
+		}
+		audit.Publish(evt)
+
 		return WriteJSON(w, r, model, http.StatusOK)
 	}
 }
@@ -160,6 +178,9 @@ func (api *API) updateWorkerModelHandler() Handler {
 			model.ID = old.ID
 		}
 
+		//The version is changed by a new version of the image or the template only
+		model.Version = old.Version
+
 		//User must be admin of the group set in the new model
 		var ok bool
 		for _, g := range getUser(ctx).Groups {
@@ -188,11 +209,29 @@ func (api *API) updateWorkerModelHandler() Handler {
 
 		defer tx.Rollback()
 
+		// keep the history of the images and templates
+		if model.Image != old.Image || !reflect.DeepEqual(model.Template, old.Template) {
+			v := sdk.ModelVersion{
+				Image:     model.Image,
+				Template:  model.Template,
+				Status:    sdk.ModelVersionActive,
+				CreatedBy: getUser(ctx).Username,
+			}
+			if err := worker.InsertModelVersion(tx, &model, &v); err != nil {
+				return sdk.WrapError(err, "updateWorkerModel> cannot add worker model version")
+			}
+		}
+
 		// update model in db
 		if err := worker.UpdateWorkerModel(tx, model); err != nil {
 			return sdk.WrapError(err, "updateWorkerModel> cannot update worker model")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{Type: sdk.AuditEntityWorkerModel, Name: model.Name}, old, model)
+		if err != nil {
+			return sdk.WrapError(err, "updateWorkerModel> cannot audit worker model")
+		}
+
 		// update requirements if needed
 		if renamed {
 			actionsID, erru := action.UpdateAllRequirements(tx, old.Name, model.Name, sdk.ModelRequirement)
@@ -233,6 +272,7 @@ func (api *API) updateWorkerModelHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "updateWorkerModel> unable to commit transaction")
 		}
+		audit.Publish(evt)
 
 		// Recompute warnings
 		go func() {
@@ -257,18 +297,38 @@ func (api *API) deleteWorkerModelHandler() Handler {
 			return sdk.WrapError(errr, "deleteWorkerModel> Invalid permModelID")
 		}
 
+		old, errLoad := worker.LoadWorkerModelByID(api.mustDB(), workerModelID)
+		if errLoad != nil {
+			return sdk.WrapError(errLoad, "deleteWorkerModel> cannot load worker model by id")
+		}
+
+		impacts, errImpact := impact.Model(api.mustDB(), api.Cache, getUser(ctx), old)
+		if errImpact != nil {
+			return sdk.WrapError(errImpact, "deleteWorkerModel> cannot load impact of worker model %s", old.Name)
+		}
+		if err := checkImpacts(r, impacts); err != nil {
+			return sdk.WrapError(err, "deleteWorkerModel> worker model %s is used by %d entities", old.Name, len(impacts))
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return sdk.WrapError(err, "deleteWorkerModel> Cannot start transaction")
 		}
+		defer tx.Rollback()
 
 		if err := worker.DeleteWorkerModel(tx, workerModelID); err != nil {
 			return sdk.WrapError(err, "deleteWorkerModel: cannot delete worker model")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{Type: sdk.AuditEntityWorkerModel, Name: old.Name}, old, nil)
+		if err != nil {
+			return sdk.WrapError(err, "deleteWorkerModel> cannot audit worker model")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "deleteWorkerModel> Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		return nil
 	}
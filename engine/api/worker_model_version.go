@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkerModelVersionsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "getWorkerModelVersionsHandler> Invalid permModelID")
+		}
+
+		versions, err := worker.LoadModelVersions(api.mustDB(), workerModelID)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkerModelVersionsHandler> cannot load versions of worker model %d", workerModelID)
+		}
+		return WriteJSON(w, r, versions, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkerModelVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "postWorkerModelVersionHandler> Invalid permModelID")
+		}
+
+		var v sdk.ModelVersion
+		if err := UnmarshalBody(r, &v); err != nil {
+			return sdk.WrapError(err, "postWorkerModelVersionHandler> cannot unmarshal body")
+		}
+		if v.Image == "" && v.Template == nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkerModelVersionHandler> image or template is mandatory")
+		}
+
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "postWorkerModelVersionHandler> unable to start transaction")
+		}
+		defer tx.Rollback()
+
+		model, errLoad := worker.LoadWorkerModelByID(tx, workerModelID)
+		if errLoad != nil {
+			return sdk.WrapError(errLoad, "postWorkerModelVersionHandler> cannot load worker model by id")
+		}
+		if !model.CanCheckVersions() {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkerModelVersionHandler> versions of %s models can't be checked, update the model", model.Type)
+		}
+
+		// The version is spawned by the hatcheries for a registration check before it can be promoted
+		version := sdk.ModelVersion{
+			Image:     v.Image,
+			Template:  v.Template,
+			Status:    sdk.ModelVersionPending,
+			CreatedBy: getUser(ctx).Username,
+		}
+		if err := worker.InsertModelVersion(tx, model, &version); err != nil {
+			return sdk.WrapError(err, "postWorkerModelVersionHandler> cannot add version to worker model %d", workerModelID)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkerModelVersionHandler> unable to commit transaction")
+		}
+
+		return WriteJSON(w, r, version, http.StatusCreated)
+	}
+}
+
+func (api *API) postPromoteWorkerModelVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "postPromoteWorkerModelVersionHandler> Invalid permModelID")
+		}
+		number, errv := requestVarInt(r, "version")
+		if errv != nil {
+			return sdk.WrapError(errv, "postPromoteWorkerModelVersionHandler> Invalid version")
+		}
+
+		tx, errtx := api.mustDB().Begin()
+		if errtx != nil {
+			return sdk.WrapError(errtx, "postPromoteWorkerModelVersionHandler> unable to start transaction")
+		}
+		defer tx.Rollback()
+
+		model, errLoad := worker.LoadWorkerModelByID(tx, workerModelID)
+		if errLoad != nil {
+			return sdk.WrapError(errLoad, "postPromoteWorkerModelVersionHandler> cannot load worker model by id")
+		}
+
+		v, errLoadV := worker.LoadModelVersion(tx, workerModelID, number)
+		if errLoadV != nil {
+			return sdk.WrapError(errLoadV, "postPromoteWorkerModelVersionHandler> cannot load version %d of worker model %d", number, workerModelID)
+		}
+
+		before := *model
+		if err := worker.PromoteModelVersion(tx, model, v); err != nil {
+			return sdk.WrapError(err, "postPromoteWorkerModelVersionHandler> cannot promote version %d of worker model %d", number, workerModelID)
+		}
+
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{Type: sdk.AuditEntityWorkerModel, Name: model.Name}, before, model)
+		if err != nil {
+			return sdk.WrapError(err, "postPromoteWorkerModelVersionHandler> cannot audit worker model %d", workerModelID)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postPromoteWorkerModelVersionHandler> unable to commit transaction")
+		}
+		audit.Publish(evt)
+
+		return WriteJSON(w, r, model, http.StatusOK)
+	}
+}
+
+func (api *API) putRolloutWorkerModelVersionHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		workerModelID, errr := requestVarInt(r, "permModelID")
+		if errr != nil {
+			return sdk.WrapError(errr, "putRolloutWorkerModelVersionHandler> Invalid permModelID")
+		}
+		number, errv := requestVarInt(r, "version")
+		if errv != nil {
+			return sdk.WrapError(errv, "putRolloutWorkerModelVersionHandler> Invalid version")
+		}
+
+		var form sdk.ModelVersionRolloutForm
+		if err := UnmarshalBody(r, &form); err != nil {
+			return sdk.WrapError(err, "putRolloutWorkerModelVersionHandler> cannot unmarshal body")
+		}
+
+		v, errLoad := worker.LoadModelVersion(api.mustDB(), workerModelID, number)
+		if errLoad != nil {
+			return sdk.WrapError(errLoad, "putRolloutWorkerModelVersionHandler> cannot load version %d of worker model %d", number, workerModelID)
+		}
+
+		if err := worker.UpdateModelVersionRollout(api.mustDB(), v, form.Rollout); err != nil {
+			return sdk.WrapError(err, "putRolloutWorkerModelVersionHandler> cannot roll out version %d of worker model %d", number, workerModelID)
+		}
+
+		return WriteJSON(w, r, v, http.StatusOK)
+	}
+}
@@ -81,7 +81,7 @@ func Test_workerCheckingHandler(t *testing.T) {
 		t.Fatalf("Error inserting token : %s", err)
 	}
 
-	workr, err := worker.RegisterWorker(api.mustDB(), "test-worker", "test-key", model.ID, &h, nil)
+	workr, err := worker.RegisterWorker(api.mustDB(), "test-worker", "test-key", model.ID, &h, nil, nil, "")
 	if err != nil {
 		t.Fatalf("Error Registering worker : %s", err)
 	}
@@ -174,7 +174,7 @@ func Test_workerWaitingHandler(t *testing.T) {
 		t.Fatalf("Error inserting token : %s", err)
 	}
 
-	workr, err := worker.RegisterWorker(api.mustDB(), "test-worker", "test-key", model.ID, &h, nil)
+	workr, err := worker.RegisterWorker(api.mustDB(), "test-worker", "test-key", model.ID, &h, nil, nil, "")
 	if err != nil {
 		t.Fatalf("Error Registering worker : %s", err)
 	}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/audit"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
@@ -68,6 +70,11 @@ func (api *API) postWorkflowHandler() Handler {
 			return sdk.WrapError(err, "Cannot insert workflow")
 		}
 
+		evt, err := api.auditWorkflow(ctx, tx, key, wf.ID, nil)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot audit workflow")
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "Cannot update project last modified date")
 		}
@@ -75,6 +82,7 @@ func (api *API) postWorkflowHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		wf1, errl := workflow.LoadByID(api.mustDB(), api.Cache, wf.ID, getUser(ctx))
 		if errl != nil {
@@ -124,6 +132,11 @@ func (api *API) putWorkflowHandler() Handler {
 			return sdk.WrapError(err, "Cannot update workflow")
 		}
 
+		evt, err := api.auditWorkflow(ctx, tx, key, wf.ID, oldW)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot audit workflow")
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "Cannot update project last modified date")
 		}
@@ -131,6 +144,7 @@ func (api *API) putWorkflowHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "Cannot commit transaction")
 		}
+		audit.Publish(evt)
 
 		wf1, errl := workflow.LoadByID(api.mustDB(), api.Cache, wf.ID, getUser(ctx))
 		if errl != nil {
@@ -168,6 +182,11 @@ func (api *API) deleteWorkflowHandler() Handler {
 			return sdk.WrapError(err, "Cannot delete workflow")
 		}
 
+		evt, err := audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityWorkflow, Name: name}, oldW, nil)
+		if err != nil {
+			return sdk.WrapError(err, "Cannot audit workflow")
+		}
+
 		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), p); err != nil {
 			return sdk.WrapError(err, "Cannot update project last modified date")
 		}
@@ -175,6 +194,17 @@ func (api *API) deleteWorkflowHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(errT, "Cannot commit transaction")
 		}
+		audit.Publish(evt)
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
 }
+
+// auditWorkflow records the workflow as it is in the transaction in the audit log, it returns the event of the audit
+// to publish once committed
+func (api *API) auditWorkflow(ctx context.Context, tx gorp.SqlExecutor, key string, id int64, before *sdk.Workflow) (sdk.EventAudit, error) {
+	after, err := workflow.LoadByID(tx, api.Cache, id, getUser(ctx))
+	if err != nil {
+		return sdk.EventAudit{}, sdk.WrapError(err, "auditWorkflow> Cannot load workflow %d", id)
+	}
+	return audit.Add(tx, getUser(ctx), audit.Entity{ProjectKey: key, Type: sdk.AuditEntityWorkflow, Name: after.Name}, before, after)
+}
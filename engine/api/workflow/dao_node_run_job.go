@@ -24,20 +24,17 @@ func LoadNodeJobRunQueue(db gorp.SqlExecutor, store cache.Store, groupsID []int6
 		statuses = []string{sdk.StatusWaiting.String()}
 	}
 
-	query := `select distinct workflow_node_run_job.*
+	// The waiting jobs are sorted in the queue order, the other ones are at the end of the queue
+	query := `select workflow_node_run_job.*, positions.position as queue_position
 	from workflow_node_run_job
 	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
 	join workflow_node on workflow_node.id = workflow_node_run.workflow_node_id
 	join workflow on workflow.id = workflow_node.workflow_id
-	join project on project.id = workflow.project_id
-	join project_group on project_group.project_id = project.id
-	where (
-		project_group.group_id = ANY(string_to_array($1, ',')::int[])
-		or
-		true = $4
-	)
-	and workflow_node_run_job.queued >= $2
-	and workflow_node_run_job.status = ANY(string_to_array($3, ','))`
+	left join (` + queuePositionsQuery + `) positions on positions.id = workflow_node_run_job.id
+	where workflow.project_id in (` + queueProjectsQuery + `)
+	and workflow_node_run_job.queued >= $4
+	and workflow_node_run_job.status = ANY(string_to_array($5, ','))
+	order by positions.position nulls last, workflow_node_run_job.queued, workflow_node_run_job.id`
 
 	var groupID string
 	var isSharedInfraGroup bool
@@ -53,18 +50,38 @@ func LoadNodeJobRunQueue(db gorp.SqlExecutor, store cache.Store, groupsID []int6
 		}
 	}
 
-	sqlJobs := []JobRun{}
-	if _, err := db.Select(&sqlJobs, query, groupID, *since, strings.Join(statuses, ","), isSharedInfraGroup); err != nil {
+	// The positions are only computed when the waiting jobs are loaded
+	var withPositions bool
+	for _, s := range statuses {
+		if s == sdk.StatusWaiting.String() {
+			withPositions = true
+		}
+	}
+
+	sqlJobs := []queuedJobRun{}
+	if _, err := db.Select(&sqlJobs, query, groupID, isSharedInfraGroup, withPositions, *since, strings.Join(statuses, ",")); err != nil {
 		return nil, sdk.WrapError(err, "workflow.LoadNodeJobRun> Unable to load job runs")
 	}
 
+	var started int64
+	if withPositions && len(sqlJobs) > 0 {
+		var err error
+		started, err = countStartedJobs(db, time.Now().Add(-queueETAWindow))
+		if err != nil {
+			return nil, sdk.WrapError(err, "workflow.LoadNodeJobRun> Unable to estimate the queue")
+		}
+	}
+
 	jobs := make([]sdk.WorkflowNodeJobRun, len(sqlJobs))
 	for i := range sqlJobs {
-		getHatcheryInfo(store, &sqlJobs[i])
+		getHatcheryInfo(store, &sqlJobs[i].JobRun)
 		if err := sqlJobs[i].PostGet(db); err != nil {
 			return nil, sdk.WrapError(err, "workflow.LoadNodeJobRun> Unable to load job runs")
 		}
-		jobs[i] = sdk.WorkflowNodeJobRun(sqlJobs[i])
+		jobs[i] = sdk.WorkflowNodeJobRun(sqlJobs[i].JobRun)
+		if sqlJobs[i].Position.Valid {
+			jobs[i].SetQueuePosition(int(sqlJobs[i].Position.Int64), started, queueETAWindow)
+		}
 	}
 
 	return jobs, nil
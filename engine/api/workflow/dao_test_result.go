@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertTestResults stores the result of each test case of a node run, they are used to compute the tests trends of the workflow
+func InsertTestResults(db gorp.SqlExecutor, nodeRun *sdk.WorkflowNodeRun, tests *venom.Tests) error {
+	workflowID, err := db.SelectInt("SELECT workflow_id FROM workflow_run WHERE id = $1", nodeRun.WorkflowRunID)
+	if err != nil {
+		return sdk.WrapError(err, "InsertTestResults> Unable to load workflow run %d", nodeRun.WorkflowRunID)
+	}
+
+	gitHash := sdk.ParameterValue(nodeRun.BuildParameters, "git.hash")
+	now := time.Now()
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			duration, _ := strconv.ParseFloat(tc.Time, 64)
+			res := TestResult{
+				WorkflowID:        workflowID,
+				WorkflowRunID:     nodeRun.WorkflowRunID,
+				WorkflowNodeRunID: nodeRun.ID,
+				Number:            nodeRun.Number,
+				GitHash:           gitHash,
+				TestSuite:         ts.Name,
+				TestCase:          tc.Name,
+				Status:            testCaseStatus(tc).String(),
+				Duration:          duration,
+				Created:           now,
+			}
+			if err := db.Insert(&res); err != nil {
+				return sdk.WrapError(err, "InsertTestResults> Unable to insert test result %s/%s", ts.Name, tc.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func testCaseStatus(tc venom.TestCase) sdk.Status {
+	switch {
+	case len(tc.Failures) > 0 || len(tc.Errors) > 0:
+		return sdk.StatusFail
+	case tc.Skipped > 0:
+		return sdk.StatusSkipped
+	}
+	return sdk.StatusSuccess
+}
+
+// LoadTestResults loads the test case results of the last runs of a workflow, from the oldest to the newest
+func LoadTestResults(db gorp.SqlExecutor, projectKey, workflowName string, runs int) ([]sdk.WorkflowTestCaseResult, error) {
+	return loadTestResults(db, projectKey, workflowName, runs, "", "")
+}
+
+// LoadTestCaseResults loads the results of a test case on the last runs of a workflow, from the oldest to the newest
+func LoadTestCaseResults(db gorp.SqlExecutor, projectKey, workflowName string, runs int, suite, testCase string) ([]sdk.WorkflowTestCaseResult, error) {
+	return loadTestResults(db, projectKey, workflowName, runs, suite, testCase)
+}
+
+func loadTestResults(db gorp.SqlExecutor, projectKey, workflowName string, runs int, suite, testCase string) ([]sdk.WorkflowTestCaseResult, error) {
+	query := `
+		SELECT workflow_node_run_test.*
+		FROM workflow_node_run_test
+		JOIN workflow ON workflow.id = workflow_node_run_test.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE project.projectkey = $1
+		AND workflow.name = $2
+		AND workflow_node_run_test.num > (
+			SELECT COALESCE(MAX(workflow_run.num), 0) - $3
+			FROM workflow_run
+			WHERE workflow_run.workflow_id = workflow.id
+		)
+		AND ($4 = '' OR workflow_node_run_test.test_suite = $4)
+		AND ($5 = '' OR workflow_node_run_test.test_case = $5)
+		ORDER BY workflow_node_run_test.num, workflow_node_run_test.id`
+
+	var resGorp []TestResult
+	if _, err := db.Select(&resGorp, query, projectKey, workflowName, runs, suite, testCase); err != nil {
+		return nil, sdk.WrapError(err, "loadTestResults> Unable to load test results of workflow %s", workflowName)
+	}
+
+	res := make([]sdk.WorkflowTestCaseResult, len(resGorp))
+	for i := range resGorp {
+		res[i] = sdk.WorkflowTestCaseResult(resGorp[i])
+	}
+	return res, nil
+}
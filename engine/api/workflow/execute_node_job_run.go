@@ -12,6 +12,7 @@ import (
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/engine/api/variableset"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -44,6 +45,11 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		}
 		job.Done = time.Now()
 		job.Status = status.String()
+		if currentStatus == sdk.StatusBuilding.String() {
+			if err := accountGroupUsage(db, job); err != nil {
+				log.Warning("workflow.UpdateNodeJobRunStatus> Unable to account usage of job %d: %s", job.ID, err)
+			}
+		}
 	default:
 		return fmt.Errorf("workflow.UpdateNodeJobRunStatus> Cannot update WorkflowNodeJobRun %d to status %v", job.ID, status.String())
 	}
@@ -241,6 +247,12 @@ func LoadNodeJobRunKeys(db gorp.SqlExecutor, store cache.Store, job *sdk.Workflo
 func LoadNodeJobRunSecrets(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, nodeRun *sdk.WorkflowNodeRun, w *sdk.WorkflowRun, pv []sdk.Variable) ([]sdk.Variable, error) {
 	var secrets []sdk.Variable
 
+	//Project variables, completed with the variable sets attached to the project
+	projSets, err := variableset.LoadAllByProject(db, w.ProjectID, variableset.WithEncryptPassword())
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadNodeJobRunSecrets> Unable to load project variable sets")
+	}
+	pv = sdk.VariablesWithSets(pv, projSets)
 	pv = sdk.VariablesFilter(pv, sdk.SecretVariable, sdk.KeyVariable)
 	pv = sdk.VariablesPrefix(pv, "cds.proj.")
 	secrets = append(secrets, pv...)
@@ -254,7 +266,12 @@ func LoadNodeJobRunSecrets(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, nod
 	//Application variables
 	av := []sdk.Variable{}
 	if n.Context != nil && n.Context.Application != nil {
-		av = sdk.VariablesFilter(n.Context.Application.Variable, sdk.SecretVariable, sdk.KeyVariable)
+		appSets, err := variableset.LoadAllByApplication(db, n.Context.Application.ID, variableset.WithEncryptPassword())
+		if err != nil {
+			return nil, sdk.WrapError(err, "LoadNodeJobRunSecrets> Unable to load application variable sets")
+		}
+		av = sdk.VariablesWithSets(n.Context.Application.Variable, appSets)
+		av = sdk.VariablesFilter(av, sdk.SecretVariable, sdk.KeyVariable)
 		av = sdk.VariablesPrefix(av, "cds.app.")
 	}
 	secrets = append(secrets, av...)
@@ -289,6 +306,32 @@ func BookNodeJobRun(store cache.Store, id int64, hatchery *sdk.Hatchery) (*sdk.H
 	return &h, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookNodeJobRun> job %d already booked by %s (%d)", id, h.Name, h.ID)
 }
 
+// UnbookNodeJobRuns releases the waiting jobs booked by the given hatcheries, so that other hatcheries
+// can book them. It returns the number of released jobs
+func UnbookNodeJobRuns(db gorp.SqlExecutor, store cache.Store, hatcheryIDs []int64) (int, error) {
+	var ids []int64
+	if _, err := db.Select(&ids, `SELECT id FROM workflow_node_run_job WHERE status = $1`, sdk.StatusWaiting.String()); err != nil {
+		return 0, sdk.WrapError(err, "UnbookNodeJobRuns> Unable to load waiting jobs")
+	}
+
+	var n int
+	for _, id := range ids {
+		k := keyBookJob(id)
+		h := sdk.Hatchery{}
+		if !store.Get(k, &h) {
+			continue
+		}
+		for _, hID := range hatcheryIDs {
+			if h.ID == hID {
+				store.Delete(k)
+				n++
+				break
+			}
+		}
+	}
+	return n, nil
+}
+
 //AddLog adds a build log
 func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log) error {
 	if job != nil {
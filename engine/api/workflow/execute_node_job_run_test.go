@@ -0,0 +1,60 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/variableset"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLoadNodeJobRunSecretsWithVariableSets(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key, u)
+
+	_, err := project.InsertVariable(db, proj, &sdk.Variable{Name: "password", Type: sdk.SecretVariable, Value: "from-project"}, u)
+	test.NoError(t, err)
+
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	test.NoError(t, group.InsertGroup(db, g))
+	s := &sdk.VariableSet{Name: sdk.RandomString(10), GroupID: g.ID}
+	test.NoError(t, variableset.Insert(db, s))
+	for _, v := range []sdk.Variable{
+		{Name: "password", Type: sdk.SecretVariable, Value: "from-set"},
+		{Name: "token", Type: sdk.SecretVariable, Value: "token-from-set"},
+	} {
+		_, err := variableset.InsertVariable(db, s, &v, u)
+		test.NoError(t, err)
+	}
+	test.NoError(t, variableset.AttachToProject(db, s.ID, proj.ID))
+
+	w := &sdk.WorkflowRun{
+		ProjectID: proj.ID,
+		Workflow:  sdk.Workflow{Root: &sdk.WorkflowNode{ID: 1}},
+	}
+	nodeRun := &sdk.WorkflowNodeRun{WorkflowNodeID: 1}
+
+	pv, err := project.GetAllVariableInProject(db, proj.ID, project.WithEncryptPassword())
+	test.NoError(t, err)
+	secrets, err := workflow.LoadNodeJobRunSecrets(db, &sdk.WorkflowNodeJobRun{}, nodeRun, w, pv)
+	test.NoError(t, err)
+
+	// The variables of the project take precedence over the ones of its sets
+	values := map[string]string{}
+	for _, v := range secrets {
+		values[v.Name] = v.Value
+	}
+	assert.Equal(t, map[string]string{
+		"cds.proj.password": "from-project",
+		"cds.proj.token":    "token-from-set",
+	}, values)
+}
@@ -149,6 +149,11 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 		stage.Status = sdk.StatusDisabled
 	}
 
+	priority, err := loadNodeRunPriority(db, run.WorkflowNodeID)
+	if err != nil {
+		return err
+	}
+
 	//Browse the jobs
 	for _, job := range stage.Jobs {
 		//Process variables for the jobs
@@ -161,6 +166,7 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 			Queued:            time.Now(),
 			Status:            sdk.StatusWaiting.String(),
 			Parameters:        jobParams,
+			Priority:          priority,
 			Job: sdk.ExecutedJob{
 				Job: job,
 			},
@@ -170,6 +176,13 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 			job.Status = sdk.StatusDisabled.String()
 		} else if !conditionsOK {
 			job.Status = sdk.StatusSkipped.String()
+		} else if errParam == nil && job.Job.Action.Condition != "" {
+			jobConditionOK, errc := sdk.EvaluateExpression(job.Job.Action.Condition, sdk.ParametersToMap(jobParams))
+			if errc != nil {
+				errParam = errc
+			} else if !jobConditionOK {
+				job.Status = sdk.StatusSkipped.String()
+			}
 		}
 
 		if errParam != nil {
@@ -0,0 +1,4 @@
+package workflow
+
+// SortNode is exported for the tests of the package workflow_test
+var SortNode = sortNode
@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// queueETAWindow is the duration on which the number of started jobs is computed to estimate the queue ETA
+const queueETAWindow = 15 * time.Minute
+
+// queueProjectsQuery returns the projects of the groups given by the first parameter, or all the projects if the
+// second parameter is true
+const queueProjectsQuery = `select project_group.project_id
+	from project_group
+	where project_group.group_id = ANY(string_to_array($1, ',')::int[]) or true = $2`
+
+// queuePositionsQuery returns the position of the waiting jobs of the projects given by queueProjectsQuery, it
+// returns nothing if the third parameter is false. The jobs are sorted by priority, then the jobs of the same
+// priority are shared between the projects: the share of a job is its rank in the jobs of its project with the
+// same priority plus the number of building jobs of the project, so that a project which already has building
+// jobs is served last. The oldest job wins if several projects have the same share
+const queuePositionsQuery = `select queue.id, row_number() over (order by queue.priority desc, queue.share, queue.queued, queue.id) as position
+	from (
+		select workflow_node_run_job.id, workflow_node_run_job.priority, workflow_node_run_job.queued,
+		row_number() over (
+			partition by workflow.project_id, workflow_node_run_job.priority
+			order by workflow_node_run_job.queued, workflow_node_run_job.id
+		) + coalesce(building.count, 0) as share
+		from workflow_node_run_job
+		join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+		join workflow_node on workflow_node.id = workflow_node_run.workflow_node_id
+		join workflow on workflow.id = workflow_node.workflow_id
+		left join (
+			select workflow.project_id, count(workflow_node_run_job.id) as count
+			from workflow_node_run_job
+			join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+			join workflow_node on workflow_node.id = workflow_node_run.workflow_node_id
+			join workflow on workflow.id = workflow_node.workflow_id
+			where workflow_node_run_job.status = 'Building'
+			and workflow.project_id in (` + queueProjectsQuery + `)
+			group by workflow.project_id
+		) building on building.project_id = workflow.project_id
+		where workflow_node_run_job.status = 'Waiting'
+		and workflow.project_id in (` + queueProjectsQuery + `)
+		and true = $3
+	) queue`
+
+// queuedJobRun is a job loaded from the queue with its position
+type queuedJobRun struct {
+	JobRun
+	Position sql.NullInt64 `db:"queue_position"`
+}
+
+// loadNodeRunPriority returns the priority of the jobs of a node: the priority of the node context
+// if it's set, the priority of the project otherwise
+func loadNodeRunPriority(db gorp.SqlExecutor, nodeID int64) (int64, error) {
+	query := `select coalesce(nullif(workflow_node_context.priority, 0), project.priority, 0)
+	from workflow_node
+	join workflow on workflow.id = workflow_node.workflow_id
+	join project on project.id = workflow.project_id
+	left join workflow_node_context on workflow_node_context.workflow_node_id = workflow_node.id
+	where workflow_node.id = $1`
+
+	var priority int64
+	if err := db.QueryRow(query, nodeID).Scan(&priority); err != nil {
+		return 0, sdk.WrapError(err, "loadNodeRunPriority> Unable to load priority of node %d", nodeID)
+	}
+	return priority, nil
+}
+
+// countStartedJobs returns the number of jobs started since the given time
+func countStartedJobs(db gorp.SqlExecutor, since time.Time) (int64, error) {
+	var n int64
+	if err := db.QueryRow("select count(1) from workflow_node_run_job where start >= $1", since).Scan(&n); err != nil {
+		return 0, sdk.WrapError(err, "countStartedJobs> Unable to count started jobs")
+	}
+	return n, nil
+}
+
+// QueuePosition returns the position and the ETA of a waiting job in the whole queue
+func QueuePosition(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun) error {
+	if job.Status != sdk.StatusWaiting.String() {
+		return nil
+	}
+
+	var position int
+	query := `select position from (` + queuePositionsQuery + `) positions where id = $4`
+	if err := db.QueryRow(query, "", true, true, job.ID).Scan(&position); err != nil {
+		// The job has been taken since it was loaded
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WrapError(err, "QueuePosition> Unable to compute position of job %d", job.ID)
+	}
+	started, err := countStartedJobs(db, time.Now().Add(-queueETAWindow))
+	if err != nil {
+		return err
+	}
+
+	job.SetQueuePosition(position, started, queueETAWindow)
+	return nil
+}
@@ -0,0 +1,122 @@
+package workflow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// insertQueueProject inserts a project with a workflow running one job by name, and returns the waiting jobs by name
+func insertQueueProject(t *testing.T, db *gorp.DbMap, store cache.Store, u *sdk.User, names ...string) (*sdk.Project, map[string]sdk.WorkflowNodeJobRun) {
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, store, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+		Type:       sdk.BuildPipeline,
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, proj, &pip, u))
+
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	test.NoError(t, pipeline.InsertStage(db, s))
+	for _, name := range names {
+		j := &sdk.Job{
+			Enabled: true,
+			Action: sdk.Action{
+				Enabled: true,
+				Name:    name,
+			},
+		}
+		test.NoError(t, pipeline.InsertJob(db, j, s.ID, &pip))
+		s.Jobs = append(s.Jobs, *j)
+	}
+	pip.Stages = append(pip.Stages, *s)
+
+	w := sdk.Workflow{
+		Name:       "test_queue",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Root: &sdk.WorkflowNode{
+			Pipeline: pip,
+		},
+	}
+	test.NoError(t, workflow.Insert(db, store, &w, proj, u))
+	w1, err := workflow.Load(db, store, key, "test_queue", u)
+	test.NoError(t, err)
+
+	_, err = workflow.ManualRun(db, store, proj, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	test.NoError(t, err)
+
+	jobs, err := workflow.LoadNodeJobRunQueue(db, store, []int64{proj.ProjectGroups[0].Group.ID}, nil)
+	test.NoError(t, err)
+	res := map[string]sdk.WorkflowNodeJobRun{}
+	for _, j := range jobs {
+		res[j.Job.Job.Action.Name] = j
+	}
+	assert.Len(t, res, len(names))
+	return proj, res
+}
+
+func TestLoadNodeJobRunQueueOrder(t *testing.T) {
+	db, store := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+
+	projA, jobsA := insertQueueProject(t, db, store, u, "a1", "a2", "a3", "a4")
+	projB, jobsB := insertQueueProject(t, db, store, u, "b1", "b2")
+
+	now := time.Now()
+	set := func(j sdk.WorkflowNodeJobRun, priority int64, queued time.Time, status sdk.Status) {
+		_, err := db.Exec("update workflow_node_run_job set priority = $1, queued = $2, status = $3 where id = $4", priority, queued, status.String(), j.ID)
+		test.NoError(t, err)
+	}
+	set(jobsA["a1"], 0, now.Add(-10*time.Minute), sdk.StatusWaiting)
+	set(jobsA["a2"], 0, now.Add(-9*time.Minute), sdk.StatusWaiting)
+	set(jobsA["a3"], 0, now.Add(-8*time.Minute), sdk.StatusBuilding)
+	set(jobsA["a4"], 10, now, sdk.StatusWaiting)
+	set(jobsB["b1"], 0, now.Add(-2*time.Minute), sdk.StatusWaiting)
+	set(jobsB["b2"], 0, now.Add(-1*time.Minute), sdk.StatusWaiting)
+
+	groups := []int64{projA.ProjectGroups[0].Group.ID, projB.ProjectGroups[0].Group.ID}
+	names := func(jobs []sdk.WorkflowNodeJobRun) []string {
+		res := []string{}
+		for _, j := range jobs {
+			res = append(res, j.Job.Job.Action.Name)
+		}
+		return res
+	}
+
+	// The job with the highest priority is first, then the projects share the queue: the building job of the
+	// project A delays its waiting jobs, but its job of higher priority doesn't
+	jobs, err := workflow.LoadNodeJobRunQueue(db, store, groups, nil)
+	test.NoError(t, err)
+	assert.Equal(t, []string{"a4", "b1", "a1", "b2", "a2"}, names(jobs))
+	for i, j := range jobs {
+		assert.Equal(t, i+1, j.QueuePosition)
+	}
+
+	// The jobs which are not waiting are at the end of the queue, without position
+	jobs, err = workflow.LoadNodeJobRunQueue(db, store, groups, nil, sdk.StatusWaiting.String(), sdk.StatusBuilding.String())
+	test.NoError(t, err)
+	assert.Equal(t, []string{"a4", "b1", "a1", "b2", "a2", "a3"}, names(jobs))
+	assert.Equal(t, 0, jobs[5].QueuePosition)
+
+	// The positions are computed in the queue of the groups
+	jobs, err = workflow.LoadNodeJobRunQueue(db, store, groups[1:], nil)
+	test.NoError(t, err)
+	assert.Equal(t, []string{"b1", "b2"}, names(jobs))
+	assert.Equal(t, 2, jobs[1].QueuePosition)
+}
@@ -0,0 +1,209 @@
+package workflow
+
+import (
+	"math"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/stats"
+	"github.com/ovh/cds/sdk"
+)
+
+// loadNodeJobRunGroups returns the groups charged for a job: the groups which can execute the project of the job
+func loadNodeJobRunGroups(db gorp.SqlExecutor, jobID int64) ([]int64, error) {
+	query := `select project_group.group_id
+	from workflow_node_run_job
+	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	join workflow_node on workflow_node.id = workflow_node_run.workflow_node_id
+	join workflow on workflow.id = workflow_node.workflow_id
+	join project_group on project_group.project_id = workflow.project_id
+	where workflow_node_run_job.id = $1 and project_group.role = $2
+	order by project_group.group_id`
+
+	rows, err := db.Query(query, jobID, permission.PermissionReadWriteExecute)
+	if err != nil {
+		return nil, sdk.WrapError(err, "loadNodeJobRunGroups> Unable to load groups of job %d", jobID)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, sdk.WrapError(err, "loadNodeJobRunGroups> Unable to scan")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// countGroupWorkers returns the number of workers used by the jobs of a group: the building jobs and the booked jobs,
+// in total and by worker model
+func countGroupWorkers(db gorp.SqlExecutor, store cache.Store, groupID int64) (int64, map[string]int64, error) {
+	query := `select distinct workflow_node_run_job.id, workflow_node_run_job.status, workflow_node_run_job.model
+	from workflow_node_run_job
+	join workflow_node_run on workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	join workflow_node on workflow_node.id = workflow_node_run.workflow_node_id
+	join workflow on workflow.id = workflow_node.workflow_id
+	join project_group on project_group.project_id = workflow.project_id
+	where project_group.group_id = $1 and project_group.role = $2
+	and workflow_node_run_job.status = ANY(string_to_array($3, ','))`
+
+	rows, err := db.Query(query, groupID, permission.PermissionReadWriteExecute, sdk.StatusWaiting.String()+","+sdk.StatusBuilding.String())
+	if err != nil {
+		return 0, nil, sdk.WrapError(err, "countGroupWorkers> Unable to load jobs of group %d", groupID)
+	}
+	defer rows.Close()
+
+	var total int64
+	byModel := map[string]int64{}
+	for rows.Next() {
+		var id int64
+		var status string
+		var model *string
+		if err := rows.Scan(&id, &status, &model); err != nil {
+			return 0, nil, sdk.WrapError(err, "countGroupWorkers> Unable to scan")
+		}
+
+		var modelName string
+		if status == sdk.StatusWaiting.String() {
+			// A waiting job uses a worker as soon as it's booked by a hatchery
+			h := sdk.Hatchery{}
+			if !store.Get(keyBookJob(id), &h) {
+				continue
+			}
+			modelName = h.Model.Name
+		} else if model != nil {
+			modelName = *model
+		}
+
+		total++
+		if modelName != "" {
+			byModel[modelName]++
+		}
+	}
+	return total, byModel, nil
+}
+
+// GroupQuotaUsage returns the current usage of the quota of a group
+func GroupQuotaUsage(db gorp.SqlExecutor, store cache.Store, g *sdk.Group, q *sdk.GroupQuota) (*sdk.GroupQuotaUsage, error) {
+	workers, byModel, err := countGroupWorkers(db, store, g.ID)
+	if err != nil {
+		return nil, err
+	}
+	minutes, err := stats.GroupCPUMinutesToday(db, g.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &sdk.GroupQuotaUsage{
+		Group:           g.Name,
+		Quota:           *q,
+		Workers:         workers,
+		ModelWorkers:    byModel,
+		CPUMinutesToday: minutes,
+	}, nil
+}
+
+// BookNodeJobRunWithQuotas books a job for a hatchery if the quotas of the groups of the job allow to spawn a worker
+// for it. It must be called in a transaction committed once the job is booked: the quotas stay locked until the end
+// of the transaction, so that the concurrent bookings of jobs of the same groups count this one
+func BookNodeJobRunWithQuotas(db gorp.SqlExecutor, store cache.Store, id int64, hatchery *sdk.Hatchery) error {
+	job, err := LoadNodeJobRun(db, store, id)
+	if err != nil {
+		return sdk.WrapError(err, "BookNodeJobRunWithQuotas> Unable to load job %d", id)
+	}
+
+	// The model of the worker to spawn is stored with the booking to count the workers of the groups by model
+	h := *hatchery
+	model, err := bookedModel(job, h.Model.Name)
+	if err != nil {
+		return err
+	}
+	if model != h.Model.Name {
+		h.Model = sdk.Model{Name: model}
+	}
+
+	if err := checkGroupQuotas(db, store, id, model); err != nil {
+		return err
+	}
+	_, err = BookNodeJobRun(store, id, &h)
+	return err
+}
+
+// bookedModel returns the model of the worker spawned for a job: the model required by the job if it requires one,
+// the model chosen by the hatchery otherwise. A job requiring a model can't be booked for another one, so that the
+// quotas by model can't be bypassed
+func bookedModel(job *sdk.WorkflowNodeJobRun, model string) (string, error) {
+	for _, r := range job.Job.Action.Requirements {
+		if r.Type != sdk.ModelRequirement {
+			continue
+		}
+		if model != "" && model != r.Value {
+			return "", sdk.WrapError(sdk.ErrWrongRequest, "bookedModel> job %d requires model %s, not %s", job.ID, r.Value, model)
+		}
+		return r.Value, nil
+	}
+	return model, nil
+}
+
+// checkGroupQuotas checks that the quotas of the groups of the job allow to spawn a worker of the model for the job.
+// The quotas stay locked until the end of the transaction
+func checkGroupQuotas(db gorp.SqlExecutor, store cache.Store, jobID int64, model string) error {
+	groupIDs, err := loadNodeJobRunGroups(db, jobID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range groupIDs {
+		q, err := group.LoadAndLockQuota(db, id)
+		if err != nil {
+			return err
+		}
+		if q == nil {
+			continue
+		}
+
+		if q.CPUMinutesPerDay > 0 {
+			minutes, err := stats.GroupCPUMinutesToday(db, id)
+			if err != nil {
+				return err
+			}
+			if minutes >= q.CPUMinutesPerDay {
+				return sdk.WrapError(sdk.ErrGroupQuotaExceeded, "checkGroupQuotas> group %d has used its %d CPU minutes today", id, q.CPUMinutesPerDay)
+			}
+		}
+
+		modelQuota := q.ModelQuota(model)
+		if q.MaxWorkers == 0 && modelQuota == 0 {
+			continue
+		}
+		workers, byModel, err := countGroupWorkers(db, store, id)
+		if err != nil {
+			return err
+		}
+		if q.MaxWorkers > 0 && workers >= q.MaxWorkers {
+			return sdk.WrapError(sdk.ErrGroupQuotaExceeded, "checkGroupQuotas> group %d already uses %d workers", id, workers)
+		}
+		if modelQuota > 0 && byModel[model] >= modelQuota {
+			return sdk.WrapError(sdk.ErrGroupQuotaExceeded, "checkGroupQuotas> group %d already uses %d workers of model %s", id, byModel[model], model)
+		}
+	}
+	return nil
+}
+
+// accountGroupUsage accounts the duration of a job in the usage of its groups, a worker counts for one CPU
+func accountGroupUsage(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun) error {
+	if job.Start.IsZero() || job.Done.Before(job.Start) {
+		return nil
+	}
+	groupIDs, err := loadNodeJobRunGroups(db, job.ID)
+	if err != nil {
+		return err
+	}
+	minutes := int64(math.Ceil(job.Done.Sub(job.Start).Minutes()))
+	stats.GroupUsageEvent(db, groupIDs, minutes)
+	return nil
+}
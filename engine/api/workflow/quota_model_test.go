@@ -0,0 +1,35 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_bookedModel(t *testing.T) {
+	job := &sdk.WorkflowNodeJobRun{}
+	job.Job.Action.Requirements = []sdk.Requirement{
+		{Name: "git", Type: sdk.BinaryRequirement, Value: "git"},
+	}
+
+	// The hatchery chooses the model of a job which doesn't require one
+	model, err := bookedModel(job, "docker")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker", model)
+
+	job.Job.Action.Requirements = append(job.Job.Action.Requirements, sdk.Requirement{Name: "model", Type: sdk.ModelRequirement, Value: "docker"})
+	model, err = bookedModel(job, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker", model)
+
+	model, err = bookedModel(job, "docker")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker", model)
+
+	// A hatchery can't book a job for another model than the required one
+	_, err = bookedModel(job, "other")
+	assert.Equal(t, sdk.ErrWrongRequest, errors.Cause(err))
+}
@@ -0,0 +1,110 @@
+package workflow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/stats"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestBookNodeJobRunWithQuotas(t *testing.T) {
+	db, store := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+
+	proj, jobs := insertQueueProject(t, db, store, u, "j1", "j2", "j3")
+	groupID := proj.ProjectGroups[0].Group.ID
+	h := &sdk.Hatchery{ID: 1, Name: "hatchery", Model: sdk.Model{Name: "docker"}}
+
+	book := func(j sdk.WorkflowNodeJobRun, model string) error {
+		tx, err := db.Begin()
+		test.NoError(t, err)
+		defer tx.Rollback()
+		h := *h
+		h.Model.Name = model
+		if err := workflow.BookNodeJobRunWithQuotas(tx, store, j.ID, &h); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	unbook := func() {
+		_, err := workflow.UnbookNodeJobRuns(db, store, []int64{h.ID})
+		test.NoError(t, err)
+	}
+	setQuota := func(q sdk.GroupQuota) {
+		q.GroupID = groupID
+		test.NoError(t, group.InsertOrUpdateQuota(db, &q))
+	}
+
+	// The building job uses a worker of its model
+	_, err := db.Exec("update workflow_node_run_job set status = $1, model = $2 where id = $3", sdk.StatusBuilding.String(), "docker", jobs["j1"].ID)
+	test.NoError(t, err)
+
+	// Max workers
+	setQuota(sdk.GroupQuota{MaxWorkers: 2})
+	test.NoError(t, book(jobs["j2"], "docker"))
+	assert.Equal(t, sdk.ErrGroupQuotaExceeded, errors.Cause(book(jobs["j3"], "docker")))
+	unbook()
+
+	// Max workers by model
+	setQuota(sdk.GroupQuota{Models: []sdk.GroupModelQuota{{Model: "docker", MaxWorkers: 1}}})
+	assert.Equal(t, sdk.ErrGroupQuotaExceeded, errors.Cause(book(jobs["j2"], "docker")))
+	test.NoError(t, book(jobs["j2"], "openstack"))
+	unbook()
+
+	// CPU minutes per day
+	setQuota(sdk.GroupQuota{CPUMinutesPerDay: 10})
+	test.NoError(t, book(jobs["j2"], "docker"))
+	unbook()
+	stats.GroupUsageEvent(db, []int64{groupID}, 10)
+	assert.Equal(t, sdk.ErrGroupQuotaExceeded, errors.Cause(book(jobs["j2"], "docker")))
+	test.NoError(t, group.DeleteQuota(db, groupID))
+}
+
+func TestBookNodeJobRunWithQuotasLocksQuotas(t *testing.T) {
+	db, store := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(db)
+
+	proj, jobs := insertQueueProject(t, db, store, u, "j1", "j2")
+	q := sdk.GroupQuota{GroupID: proj.ProjectGroups[0].Group.ID, MaxWorkers: 1}
+	test.NoError(t, group.InsertOrUpdateQuota(db, &q))
+	h := &sdk.Hatchery{ID: 1, Name: "hatchery", Model: sdk.Model{Name: "docker"}}
+
+	tx1, err := db.Begin()
+	test.NoError(t, err)
+	defer tx1.Rollback()
+	test.NoError(t, workflow.BookNodeJobRunWithQuotas(tx1, store, jobs["j1"].ID, h))
+
+	// The second booking waits for the end of the first one, then counts its worker
+	res := make(chan error)
+	go func() {
+		tx2, err := db.Begin()
+		if err != nil {
+			res <- err
+			return
+		}
+		defer tx2.Rollback()
+		res <- workflow.BookNodeJobRunWithQuotas(tx2, store, jobs["j2"].ID, h)
+	}()
+
+	select {
+	case err := <-res:
+		t.Fatalf("The second booking should wait for the first one, got %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	test.NoError(t, tx1.Commit())
+	assert.Equal(t, sdk.ErrGroupQuotaExceeded, errors.Cause(<-res))
+
+	_, err = workflow.UnbookNodeJobRuns(db, store, []int64{h.ID})
+	test.NoError(t, err)
+	test.NoError(t, group.DeleteQuota(db, q.GroupID))
+}
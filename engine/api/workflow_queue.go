@@ -55,6 +55,10 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 			return sdk.WrapError(err, "postTakeWorkflowJobHandler> cannot unmarshal request")
 		}
 
+		if getWorker(ctx).Maintenance {
+			return sdk.WrapError(sdk.ErrWorkerMaintenance, "postTakeWorkflowJobHandler> worker %s cannot take job %d", getWorker(ctx).Name, id)
+		}
+
 		p, errP := project.LoadProjectByNodeJobRunID(api.mustDB(), api.Cache, id, getUser(ctx), project.LoadOptions.WithVariables)
 		if errP != nil {
 			return sdk.WrapError(errP, "postTakeWorkflowJobHandler> Cannot load project")
@@ -95,6 +99,15 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 			return sdk.WrapError(errTake, "postTakeWorkflowJobHandler> Cannot take job %d", id)
 		}
 
+		//Check labels of the worker and its model
+		ok, errM := worker.MatchLabelRequirements(tx, api.Cache, getWorker(ctx), job.Job.Action.Requirements)
+		if errM != nil {
+			return sdk.WrapError(errM, "postTakeWorkflowJobHandler> Cannot check requirements of job %d", id)
+		}
+		if !ok {
+			return sdk.WrapError(sdk.ErrWorkerRequirementsNotMatched, "postTakeWorkflowJobHandler> Worker %s cannot take job %d", getWorker(ctx).Name, id)
+		}
+
 		//Change worker status
 		if err := worker.SetToBuilding(tx, getWorker(ctx).ID, job.ID); err != nil {
 			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot update worker status")
@@ -163,8 +176,27 @@ func (api *API) postBookWorkflowJobHandler() Handler {
 			return sdk.WrapError(errc, "postBookWorkflowJobHandler> invalid id")
 		}
 
-		if _, err := workflow.BookNodeJobRun(api.Cache, id, getHatchery(ctx)); err != nil {
-			return sdk.WrapError(err, "postBookWorkflowJobHandler> job already booked")
+		h := *getHatchery(ctx)
+		if h.Maintenance {
+			return sdk.WrapError(sdk.ErrHatcheryMaintenance, "postBookWorkflowJobHandler> hatchery %s cannot book job %d", h.Name, id)
+		}
+		if model := r.FormValue("model"); model != "" {
+			h.Model = sdk.Model{Name: model}
+		}
+
+		tx, errBegin := api.mustDB().Begin()
+		if errBegin != nil {
+			return sdk.WrapError(errBegin, "postBookWorkflowJobHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if err := workflow.BookNodeJobRunWithQuotas(tx, api.Cache, id, &h); err != nil {
+			return sdk.WrapError(err, "postBookWorkflowJobHandler> Cannot book job %d", id)
+		}
+
+		// The quotas are unlocked once the job is booked
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postBookWorkflowJobHandler> Cannot commit transaction")
 		}
 		return WriteJSON(w, r, nil, http.StatusOK)
 	}
@@ -180,6 +212,9 @@ func (api *API) getWorkflowJobHandler() Handler {
 		if err != nil {
 			return sdk.WrapError(err, "getWorkflowJobHandler> job not found")
 		}
+		if err := workflow.QueuePosition(api.mustDB(), j); err != nil {
+			return sdk.WrapError(err, "getWorkflowJobHandler> Cannot compute queue position")
+		}
 		return WriteJSON(w, r, j, http.StatusOK)
 	}
 }
@@ -408,6 +443,10 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot load node job")
 		}
 
+		if err := workflow.InsertTestResults(tx, wnjr, &new); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot insert test results")
+		}
+
 		if wnjr.Tests == nil {
 			wnjr.Tests = &venom.Tests{}
 		}
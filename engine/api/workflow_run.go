@@ -341,6 +341,10 @@ func (api *API) postWorkflowRunHandler() Handler {
 			return sdk.WrapError(errl, "postWorkflowRunHandler> Unable to load workflow")
 		}
 
+		if opts.FromNodeID != nil && opts.Number == nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postWorkflowRunHandler> the number of the run is mandatory to run from a node")
+		}
+
 		var lastRun *sdk.WorkflowRun
 		if opts.Number != nil {
 			var errlr error
@@ -362,10 +366,10 @@ func (api *API) postWorkflowRunHandler() Handler {
 		} else {
 			//Default manual run
 			if opts.Manual == nil {
-				opts.Manual = &sdk.WorkflowNodeRunManual{
-					User: *getUser(ctx),
-				}
+				opts.Manual = &sdk.WorkflowNodeRunManual{}
 			}
+			//The manual run is triggered by the current user
+			opts.Manual.User = *getUser(ctx)
 
 			//If payload is not set, keep the default payload
 			if opts.Manual.Payload == interface{}(nil) {
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+const defaultTestsRuns = 50
+
+// requestTestsRuns returns the number of runs used to compute the tests trends
+func requestTestsRuns(r *http.Request) (int, error) {
+	runsS := r.FormValue("runs")
+	if runsS == "" {
+		return defaultTestsRuns, nil
+	}
+	runs, err := strconv.Atoi(runsS)
+	if err != nil || runs <= 0 {
+		return 0, sdk.WrapError(sdk.ErrWrongRequest, "requestTestsRuns> Invalid runs %s", runsS)
+	}
+	return runs, nil
+}
+
+func (api *API) getWorkflowTestsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		runs, err := requestTestsRuns(r)
+		if err != nil {
+			return err
+		}
+
+		results, err := workflow.LoadTestResults(api.mustDB(), key, name, runs)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTestsHandler> Unable to load test results")
+		}
+
+		stats := sdk.ComputeTestCaseStats(results, false)
+		if r.FormValue("flaky") == "true" {
+			flaky := []sdk.WorkflowTestCaseStats{}
+			for _, s := range stats {
+				if s.Flaky {
+					flaky = append(flaky, s)
+				}
+			}
+			stats = flaky
+		}
+
+		return WriteJSON(w, r, stats, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowTestHistoryHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		name := vars["workflowName"]
+
+		suite := r.FormValue("suite")
+		testCase := r.FormValue("case")
+		if suite == "" || testCase == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowTestHistoryHandler> suite and case are mandatory")
+		}
+
+		runs, err := requestTestsRuns(r)
+		if err != nil {
+			return err
+		}
+
+		results, err := workflow.LoadTestCaseResults(api.mustDB(), key, name, runs, suite, testCase)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTestHistoryHandler> Unable to load test results")
+		}
+
+		stats := sdk.ComputeTestCaseStats(results, true)
+		if len(stats) == 0 {
+			return sdk.WrapError(sdk.ErrNotFound, "getWorkflowTestHistoryHandler> No result for test %s/%s", suite, testCase)
+		}
+
+		return WriteJSON(w, r, stats[0], http.StatusOK)
+	}
+}
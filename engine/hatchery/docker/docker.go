@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os/exec"
+	"runtime"
 	"strings"
 	"time"
 
@@ -95,6 +96,10 @@ func (h *HatcheryDocker) CanSpawn(model *sdk.Model, jobID int64, requirements []
 		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
 			return false
 		}
+		// containers run on the host of the hatchery
+		if r.Type == sdk.OSArchRequirement && model.OSArch() == "" && r.Value != runtime.GOOS+"/"+runtime.GOARCH {
+			return false
+		}
 	}
 	return true
 }
@@ -249,6 +254,7 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	args = append(args, "-e", fmt.Sprintf("CDS_NAME=%s", name))
 	args = append(args, "-e", fmt.Sprintf("CDS_TOKEN=%s", viper.GetString("token")))
 	args = append(args, "-e", fmt.Sprintf("CDS_MODEL=%d", wm.ID))
+	args = append(args, "-e", fmt.Sprintf("CDS_MODEL_VERSION=%d", wm.Version))
 	args = append(args, "-e", fmt.Sprintf("CDS_HATCHERY=%d", h.hatch.ID))
 	args = append(args, "-e", fmt.Sprintf("CDS_HATCHERY_NAME=%s", h.hatch.Name))
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"time"
 
@@ -112,6 +113,10 @@ func (h *HatcheryLocal) CanSpawn(model *sdk.Model, jobID int64, requirements []s
 		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
 			return false
 		}
+		// workers run on the host of the hatchery
+		if r.Type == sdk.OSArchRequirement && r.Value != runtime.GOOS+"/"+runtime.GOARCH {
+			return false
+		}
 	}
 	log.Debug("CanSpawn true for job %d", jobID)
 	return true
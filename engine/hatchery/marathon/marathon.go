@@ -156,6 +156,10 @@ func (h *HatcheryMarathon) CanSpawn(model *sdk.Model, jobID int64, requirements
 			log.Debug("CanSpawn> Job %d has a service requirement. Marathon can't spawn a worker for this job", jobID)
 			return false
 		}
+		if r.Type == sdk.OSArchRequirement && model.OSArch() == "" {
+			log.Debug("CanSpawn> Job %d has an os-arch requirement and model %s doesn't declare its os-arch", jobID, model.Name)
+			return false
+		}
 	}
 
 	deployments, errd := h.marathonClient.Deployments()
@@ -212,6 +216,7 @@ func (h *HatcheryMarathon) SpawnWorker(model *sdk.Model, jobID int64, requiremen
 		"CDS_TOKEN":         h.token,
 		"CDS_NAME":          workerName,
 		"CDS_MODEL":         fmt.Sprintf("%d", model.ID),
+		"CDS_MODEL_VERSION": fmt.Sprintf("%d", model.Version),
 		"CDS_HATCHERY":      fmt.Sprintf("%d", h.hatch.ID),
 		"CDS_HATCHERY_NAME": fmt.Sprintf("%s", h.hatch.Name),
 		"CDS_SINGLE_USE":    "1",
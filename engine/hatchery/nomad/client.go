@@ -0,0 +1,185 @@
+package nomad
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Statuses of a nomad job
+const (
+	jobStatusPending = "pending"
+	jobStatusRunning = "running"
+	jobStatusDead    = "dead"
+)
+
+// nomadJob is a job of the Nomad HTTP API, only the fields used by the hatchery are declared
+type nomadJob struct {
+	ID          string
+	Name        string
+	Type        string
+	Region      string            `json:",omitempty"`
+	Namespace   string            `json:",omitempty"`
+	Datacenters []string          `json:",omitempty"`
+	Meta        map[string]string `json:",omitempty"`
+	TaskGroups  []nomadTaskGroup  `json:",omitempty"`
+}
+
+type nomadTaskGroup struct {
+	Name             string
+	Count            int
+	RestartPolicy    *nomadRestartPolicy    `json:",omitempty"`
+	ReschedulePolicy *nomadReschedulePolicy `json:",omitempty"`
+	Networks         []nomadNetwork         `json:",omitempty"`
+	Tasks            []nomadTask
+}
+
+type nomadRestartPolicy struct {
+	Attempts int
+	Mode     string
+}
+
+type nomadReschedulePolicy struct {
+	Attempts  int
+	Unlimited bool
+}
+
+type nomadNetwork struct {
+	Mode string
+}
+
+type nomadTask struct {
+	Name      string
+	Driver    string
+	Leader    bool                   `json:",omitempty"`
+	Config    map[string]interface{} `json:",omitempty"`
+	Env       map[string]string      `json:",omitempty"`
+	Resources *nomadResources        `json:",omitempty"`
+	Lifecycle *nomadLifecycle        `json:",omitempty"`
+}
+
+type nomadResources struct {
+	CPU      int
+	MemoryMB int
+}
+
+type nomadLifecycle struct {
+	Hook    string
+	Sidecar bool
+}
+
+// nomadJobStub is a job listed by the Nomad HTTP API
+type nomadJobStub struct {
+	ID         string
+	Name       string
+	Type       string
+	Status     string
+	SubmitTime int64
+}
+
+// nomadClient is a client of the Nomad HTTP API
+type nomadClient struct {
+	url        string
+	token      string
+	region     string
+	namespace  string
+	httpClient *http.Client
+}
+
+func newNomadClient(nomadURL, token, region, namespace string, insecure bool) *nomadClient {
+	return &nomadClient{
+		url:       strings.TrimSuffix(nomadURL, "/"),
+		token:     token,
+		region:    region,
+		namespace: namespace,
+		httpClient: &http.Client{
+			Timeout: time.Minute,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		},
+	}
+}
+
+func (c *nomadClient) do(method, path string, query url.Values, in, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.region != "" {
+		query.Set("region", c.region)
+	}
+	if c.namespace != "" {
+		query.Set("namespace", c.namespace)
+	}
+
+	var body *bytes.Buffer
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(b)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+
+	u := c.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("X-Nomad-Token", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("nomad %s %s: HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	if out != nil && len(b) > 0 {
+		return json.Unmarshal(b, out)
+	}
+	return nil
+}
+
+// registerJob submits a job
+func (c *nomadClient) registerJob(job *nomadJob) error {
+	return c.do(http.MethodPut, "/v1/jobs", nil, map[string]interface{}{"Job": job}, nil)
+}
+
+// listJobs lists the jobs which ID starts with the prefix
+func (c *nomadClient) listJobs(prefix string) ([]nomadJobStub, error) {
+	query := url.Values{}
+	query.Set("prefix", prefix)
+	jobs := []nomadJobStub{}
+	if err := c.do(http.MethodGet, "/v1/jobs", query, nil, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// deregisterJob stops a job and purges it
+func (c *nomadClient) deregisterJob(id string) error {
+	query := url.Values{}
+	query.Set("purge", "true")
+	return c.do(http.MethodDelete, "/v1/job/"+url.PathEscape(id), query, nil, nil)
+}
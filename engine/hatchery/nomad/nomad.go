@@ -0,0 +1,471 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/namesgenerator"
+	"github.com/spf13/viper"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Drivers supported to start the workers
+const (
+	driverDocker = "docker"
+	driverExec   = "exec"
+)
+
+// New instanciates a new Hatchery Nomad
+func New() *HatcheryNomad {
+	return new(HatcheryNomad)
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryNomad) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	h.nomadClient = newNomadClient(h.Config.NomadURL, h.Config.NomadToken, h.Config.Region, h.Config.Namespace, h.Config.API.HTTP.Insecure)
+	return nil
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryNomad) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if hconfig.API.HTTP.URL == "" {
+		return fmt.Errorf("API HTTP(s) URL is mandatory")
+	}
+
+	if hconfig.API.Token == "" {
+		return fmt.Errorf("API Token URL is mandatory")
+	}
+
+	if hconfig.NomadURL == "" {
+		return fmt.Errorf("Nomad URL is mandatory")
+	}
+
+	if hconfig.JobPrefix == "" {
+		return fmt.Errorf("Nomad Job Prefix is mandatory")
+	}
+
+	if hconfig.Driver != driverDocker && hconfig.Driver != driverExec {
+		return fmt.Errorf("Invalid Nomad driver %s, expected %s or %s", hconfig.Driver, driverDocker, driverExec)
+	}
+
+	return nil
+}
+
+// Serve start the HatcheryNomad server
+func (h *HatcheryNomad) Serve(ctx context.Context) error {
+	hatchery.Create(h)
+	return nil
+}
+
+// ID must returns hatchery id
+func (h *HatcheryNomad) ID() int64 {
+	if h.hatch == nil {
+		return 0
+	}
+	return h.hatch.ID
+}
+
+// Hatchery returns hatchery instance
+func (h *HatcheryNomad) Hatchery() *sdk.Hatchery {
+	return h.hatch
+}
+
+// Client returns cdsclient instance
+func (h *HatcheryNomad) Client() cdsclient.Interface {
+	return h.client
+}
+
+// Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryNomad) Configuration() hatchery.CommonConfiguration {
+	return h.Config.CommonConfiguration
+}
+
+// ModelType returns type of hatchery: docker models with the docker driver, host process models with the exec driver
+func (h *HatcheryNomad) ModelType() string {
+	if h.Config.Driver == driverExec {
+		return sdk.HostProcess
+	}
+	return sdk.Docker
+}
+
+// CanSpawn return wether or not hatchery can spawn model
+// requirements services are supported with the docker driver only
+func (h *HatcheryNomad) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	for _, r := range requirements {
+		if r.Type == sdk.ServiceRequirement && h.Config.Driver != driverDocker {
+			log.Debug("CanSpawn> Job %d has a service requirement. Nomad can't spawn a worker for this job with driver %s", jobID, h.Config.Driver)
+			return false
+		}
+		if r.Type == sdk.OSArchRequirement && model.OSArch() == "" {
+			log.Debug("CanSpawn> Job %d has an os-arch requirement and model %s doesn't declare its os-arch", jobID, model.Name)
+			return false
+		}
+	}
+
+	jobs, err := h.listWorkerJobs()
+	if err != nil {
+		log.Info("CanSpawn> Error on list jobs: %s", err)
+		return false
+	}
+	if len(jobs) >= h.Configuration().Provision.MaxWorker {
+		log.Info("CanSpawn> max number of jobs reached, aborting. Current: %d. Max: %d", len(jobs), h.Configuration().Provision.MaxWorker)
+		return false
+	}
+
+	return true
+}
+
+// SpawnWorker submits a batch job on nomad, with a task for the worker and a sidecar task for each service
+func (h *HatcheryNomad) SpawnWorker(model *sdk.Model, jobID int64, requirements []sdk.Requirement, registerOnly bool, logInfo string) (string, error) {
+	if jobID > 0 {
+		log.Info("spawnWorker> spawning worker %s (%s) for job %d - %s", model.Name, model.Image, jobID, logInfo)
+	} else {
+		log.Info("spawnWorker> spawning worker %s (%s) - %s", model.Name, model.Image, logInfo)
+	}
+
+	workerName := fmt.Sprintf("%s-%s", strings.ToLower(model.Name), strings.Replace(namesgenerator.GetRandomName(0), "_", "-", -1))
+	if registerOnly {
+		workerName = "register-" + workerName
+	}
+
+	job, err := h.workerJob(model, workerName, jobID, requirements, registerOnly)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.nomadClient.registerJob(job); err != nil {
+		return "", fmt.Errorf("spawnWorker> unable to submit job %s: %s", job.ID, err)
+	}
+
+	return workerName, nil
+}
+
+// workerJob returns the nomad job which starts the worker
+func (h *HatcheryNomad) workerJob(model *sdk.Model, workerName string, jobID int64, requirements []sdk.Requirement, registerOnly bool) (*nomadJob, error) {
+	cmd := "rm -f worker && curl ${CDS_API}/download/worker/$(uname -m) -o worker && chmod +x worker && exec ./worker"
+	if registerOnly {
+		cmd += " register"
+	}
+
+	env := map[string]string{
+		"CDS_API":           h.Client().APIURL(),
+		"CDS_TOKEN":         h.Configuration().API.Token,
+		"CDS_NAME":          workerName,
+		"CDS_MODEL":         fmt.Sprintf("%d", model.ID),
+		"CDS_MODEL_VERSION": fmt.Sprintf("%d", model.Version),
+		"CDS_HATCHERY":      fmt.Sprintf("%d", h.hatch.ID),
+		"CDS_HATCHERY_NAME": h.hatch.Name,
+		"CDS_SINGLE_USE":    "1",
+		"CDS_TTL":           fmt.Sprintf("%d", h.Config.WorkerTTL),
+	}
+
+	if viper.GetString("worker_graylog_host") != "" {
+		env["CDS_GRAYLOG_HOST"] = viper.GetString("worker_graylog_host")
+	}
+	if viper.GetString("worker_graylog_port") != "" {
+		env["CDS_GRAYLOG_PORT"] = viper.GetString("worker_graylog_port")
+	}
+	if viper.GetString("worker_graylog_extra_key") != "" {
+		env["CDS_GRAYLOG_EXTRA_KEY"] = viper.GetString("worker_graylog_extra_key")
+	}
+	if viper.GetString("worker_graylog_extra_value") != "" {
+		env["CDS_GRAYLOG_EXTRA_VALUE"] = viper.GetString("worker_graylog_extra_value")
+	}
+	if viper.GetString("grpc_api") != "" && model.Communication == sdk.GRPC {
+		env["CDS_GRPC_API"] = viper.GetString("grpc_api")
+		env["CDS_GRPC_INSECURE"] = strconv.FormatBool(viper.GetBool("grpc_insecure"))
+	}
+
+	memory := h.Config.DefaultMemory
+	services := []nomadTask{}
+	hosts := []string{}
+
+	if jobID > 0 {
+		env["CDS_BOOKED_JOB_ID"] = fmt.Sprintf("%d", jobID)
+
+		for _, r := range requirements {
+			switch r.Type {
+			case sdk.MemoryRequirement:
+				var err error
+				memory, err = strconv.Atoi(r.Value)
+				if err != nil {
+					log.Warning("spawnWorker> unable to parse memory requirement %s: %s", r.Value, err)
+					return nil, err
+				}
+			case sdk.ServiceRequirement:
+				//name= <alias> => the name of the host put in /etc/hosts of the worker
+				//value= "postgres:latest env_1=blabla env_2=blabla"" => we can add env variables in requirement name
+				tuple := strings.Split(r.Value, " ")
+				serviceEnv := map[string]string{}
+				serviceMemory := h.Config.ServiceMemory
+				for _, e := range tuple[1:] {
+					t := strings.SplitN(e, "=", 2)
+					if len(t) != 2 {
+						continue
+					}
+					//option for power user : set the service memory with CDS_SERVICE_MEMORY=1024
+					if t[0] == "CDS_SERVICE_MEMORY" {
+						i, err := strconv.Atoi(t[1])
+						if err != nil {
+							log.Warning("spawnWorker> Unable to parse service option %s : %s", e, err)
+							continue
+						}
+						serviceMemory = i
+						continue
+					}
+					serviceEnv[t[0]] = t[1]
+				}
+
+				// Services are sidecars started before the worker, in the same network namespace
+				services = append(services, nomadTask{
+					Name:   r.Name,
+					Driver: driverDocker,
+					Config: map[string]interface{}{
+						"image":      tuple[0],
+						"force_pull": strings.HasSuffix(tuple[0], ":latest"),
+					},
+					Env:       serviceEnv,
+					Resources: &nomadResources{CPU: h.Config.DefaultCPU, MemoryMB: serviceMemory},
+					Lifecycle: &nomadLifecycle{Hook: "prestart", Sidecar: true},
+				})
+				hosts = append(hosts, r.Name+":127.0.0.1")
+			}
+		}
+	}
+
+	worker := nomadTask{
+		Name:      "worker",
+		Driver:    h.Config.Driver,
+		Leader:    true,
+		Env:       env,
+		Resources: &nomadResources{CPU: h.Config.DefaultCPU, MemoryMB: memory},
+	}
+	switch h.Config.Driver {
+	case driverDocker:
+		worker.Config = map[string]interface{}{
+			"image":      model.Image,
+			"force_pull": strings.HasSuffix(model.Image, ":latest"),
+			"command":    "sh",
+			"args":       []string{"-c", cmd},
+		}
+		if len(hosts) > 0 {
+			worker.Config["extra_hosts"] = hosts
+		}
+	case driverExec:
+		worker.Config = map[string]interface{}{
+			"command": "sh",
+			"args":    []string{"-c", cmd},
+		}
+	}
+
+	group := nomadTaskGroup{
+		Name:  "worker",
+		Count: 1,
+		// A worker is spawned for one job, nomad must not restart it
+		RestartPolicy:    &nomadRestartPolicy{Attempts: 0, Mode: "fail"},
+		ReschedulePolicy: &nomadReschedulePolicy{Attempts: 0, Unlimited: false},
+		Tasks:            append([]nomadTask{worker}, services...),
+	}
+	if len(services) > 0 {
+		group.Networks = []nomadNetwork{{Mode: "bridge"}}
+	}
+
+	datacenters := []string{}
+	for _, dc := range strings.Split(h.Config.Datacenters, ",") {
+		if dc = strings.TrimSpace(dc); dc != "" {
+			datacenters = append(datacenters, dc)
+		}
+	}
+
+	id := h.Config.JobPrefix + "-" + workerName
+	return &nomadJob{
+		ID:          id,
+		Name:        id,
+		Type:        "batch",
+		Region:      h.Config.Region,
+		Namespace:   h.Config.Namespace,
+		Datacenters: datacenters,
+		Meta: map[string]string{
+			"worker":   workerName,
+			"model":    model.Name,
+			"hatchery": h.hatch.Name,
+		},
+		TaskGroups: []nomadTaskGroup{group},
+	}, nil
+}
+
+// listWorkerJobs returns the jobs of the workers which are not dead
+func (h *HatcheryNomad) listWorkerJobs() ([]nomadJobStub, error) {
+	jobs, err := h.nomadClient.listJobs(h.Config.JobPrefix + "-")
+	if err != nil {
+		return nil, err
+	}
+	res := make([]nomadJobStub, 0, len(jobs))
+	for _, j := range jobs {
+		if j.Status != jobStatusDead {
+			res = append(res, j)
+		}
+	}
+	return res, nil
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryNomad) WorkersStarted() int {
+	jobs, err := h.listWorkerJobs()
+	if err != nil {
+		log.Warning("WorkersStarted> error on list jobs err:%s", err)
+		return 0
+	}
+	return len(jobs)
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryNomad) WorkersStartedByModel(model *sdk.Model) int {
+	jobs, err := h.listWorkerJobs()
+	if err != nil {
+		log.Warning("WorkersStartedByModel> error on list jobs err:%s", err)
+		return 0
+	}
+
+	var x int
+	for _, j := range jobs {
+		if strings.Contains(j.ID, "-"+strings.ToLower(model.Name)+"-") {
+			x++
+		}
+	}
+	return x
+}
+
+// Init registers the hatchery and starts the garbage collection of workers jobs
+func (h *HatcheryNomad) Init() error {
+	h.hatch = &sdk.Hatchery{
+		Name:    hatchery.GenerateName("nomad", h.Configuration().Name),
+		Version: sdk.VERSION,
+	}
+
+	h.client = cdsclient.NewHatchery(
+		h.Configuration().API.HTTP.URL,
+		h.Configuration().API.Token,
+		h.Configuration().Provision.RegisterFrequency,
+		h.Configuration().API.HTTP.Insecure,
+		h.hatch.Name,
+	)
+	if err := hatchery.Register(h); err != nil {
+		return fmt.Errorf("Cannot register: %s", err)
+	}
+
+	h.startKillAwolWorkerRoutine()
+	return nil
+}
+
+func (h *HatcheryNomad) startKillAwolWorkerRoutine() {
+	go func() {
+		for {
+			time.Sleep(10 * time.Second)
+			if err := h.killDeadJobs(); err != nil {
+				log.Warning("Cannot purge dead jobs: %s", err)
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			time.Sleep(10 * time.Second)
+			if err := h.killAwolWorkers(); err != nil {
+				log.Warning("Cannot kill awol workers: %s", err)
+			}
+		}
+	}()
+}
+
+// killDeadJobs purges the jobs which allocations are dead: the worker has exited
+func (h *HatcheryNomad) killDeadJobs() error {
+	jobs, err := h.nomadClient.listJobs(h.Config.JobPrefix + "-")
+	if err != nil {
+		return err
+	}
+
+	for _, j := range jobs {
+		if j.Status != jobStatusDead {
+			continue
+		}
+		log.Debug("killDeadJobs> purge dead job %s", j.ID)
+		if err := h.nomadClient.deregisterJob(j.ID); err != nil {
+			log.Warning("killDeadJobs> Error while purge job %s err:%s", j.ID, err)
+			// continue to next job
+		}
+	}
+	return nil
+}
+
+// killAwolWorkers stops the jobs of the disabled workers, and the jobs running for more than
+// one minute without a worker registered
+func (h *HatcheryNomad) killAwolWorkers() error {
+	workers, err := h.Client().WorkerList()
+	if err != nil {
+		return err
+	}
+
+	jobs, err := h.listWorkerJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, j := range jobs {
+		var found, disabled bool
+		for _, w := range workers {
+			if strings.HasSuffix(j.ID, "-"+w.Name) {
+				found = true
+				disabled = w.Status == sdk.StatusDisabled
+				break
+			}
+		}
+
+		if disabled {
+			log.Info("killAwolWorkers> killing disabled worker %s", j.ID)
+		} else if !found && j.Status == jobStatusRunning && time.Since(time.Unix(0, j.SubmitTime)) > 1*time.Minute {
+			log.Info("killAwolWorkers> killing awol worker %s", j.ID)
+		} else {
+			continue
+		}
+
+		if err := h.nomadClient.deregisterJob(j.ID); err != nil {
+			log.Warning("killAwolWorkers> Error while delete job %s err:%s", j.ID, err)
+			// continue to next job
+		}
+	}
+
+	return nil
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryNomad) NeedRegistration(wm *sdk.Model) bool {
+	if wm.NeedRegistration || wm.LastRegistration.Unix() < wm.UserLastModified.Unix() {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// fakeNomad is an in-process fake of the Nomad HTTP API, it also serves the list of workers of the CDS API
+type fakeNomad struct {
+	mutex   sync.Mutex
+	jobs    map[string]nomadJob
+	status  map[string]string
+	submit  map[string]int64
+	workers []sdk.Worker
+}
+
+func (f *fakeNomad) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/jobs":
+		var body struct{ Job nomadJob }
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.jobs[body.Job.ID] = body.Job
+		f.status[body.Job.ID] = jobStatusRunning
+		f.submit[body.Job.ID] = time.Now().UnixNano()
+		json.NewEncoder(w).Encode(map[string]string{"EvalID": "1"})
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/jobs":
+		stubs := []nomadJobStub{}
+		for id, j := range f.jobs {
+			if strings.HasPrefix(id, r.URL.Query().Get("prefix")) {
+				stubs = append(stubs, nomadJobStub{ID: id, Name: j.Name, Type: j.Type, Status: f.status[id], SubmitTime: f.submit[id]})
+			}
+		}
+		json.NewEncoder(w).Encode(stubs)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1/job/"):
+		id := strings.TrimPrefix(r.URL.Path, "/v1/job/")
+		if _, ok := f.jobs[id]; !ok {
+			http.Error(w, "job not found", http.StatusNotFound)
+			return
+		}
+		delete(f.jobs, id)
+		json.NewEncoder(w).Encode(map[string]string{"EvalID": "2"})
+	case r.Method == http.MethodGet && r.URL.Path == "/worker":
+		json.NewEncoder(w).Encode(f.workers)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestHatchery(t *testing.T, driver string) (*HatcheryNomad, *fakeNomad, *httptest.Server) {
+	f := &fakeNomad{jobs: map[string]nomadJob{}, status: map[string]string{}, submit: map[string]int64{}}
+	srv := httptest.NewServer(f)
+
+	h := New()
+	cfg := HatcheryConfiguration{
+		NomadURL:      srv.URL,
+		Datacenters:   "dc1, dc2",
+		JobPrefix:     "cds-worker",
+		Driver:        driver,
+		DefaultMemory: 1024,
+		DefaultCPU:    500,
+		ServiceMemory: 512,
+		WorkerTTL:     10,
+	}
+	cfg.API.HTTP.URL = srv.URL
+	cfg.API.Token = "token"
+	cfg.Provision.MaxWorker = 2
+	if err := h.ApplyConfiguration(cfg); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+
+	h.hatch = &sdk.Hatchery{ID: 1, Name: hatchery.GenerateName("nomad", "test")}
+	h.client = cdsclient.NewHatchery(srv.URL, "token", 10, false, h.hatch.Name)
+	return h, f, srv
+}
+
+func TestHatcheryNomadSpawnWorker(t *testing.T) {
+	h, f, srv := newTestHatchery(t, driverDocker)
+	defer srv.Close()
+
+	model := &sdk.Model{ID: 1, Name: "Golang", Image: "golang:latest", Type: sdk.Docker}
+	requirements := []sdk.Requirement{
+		{Name: "Memory", Type: sdk.MemoryRequirement, Value: "4096"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.6 POSTGRES_PASSWORD=pwd CDS_SERVICE_MEMORY=2048"},
+	}
+	assert.True(t, h.CanSpawn(model, 42, requirements))
+
+	name, err := h.SpawnWorker(model, 42, requirements, false, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(name, "golang-"))
+
+	job, ok := f.jobs["cds-worker-"+name]
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	assert.Equal(t, "batch", job.Type)
+	assert.Equal(t, []string{"dc1", "dc2"}, job.Datacenters)
+	if !assert.Len(t, job.TaskGroups, 1) {
+		t.FailNow()
+	}
+
+	group := job.TaskGroups[0]
+	assert.Equal(t, 0, group.RestartPolicy.Attempts)
+	if !assert.Len(t, group.Networks, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "bridge", group.Networks[0].Mode)
+	if !assert.Len(t, group.Tasks, 2) {
+		t.FailNow()
+	}
+
+	worker := group.Tasks[0]
+	assert.True(t, worker.Leader)
+	assert.Equal(t, driverDocker, worker.Driver)
+	assert.Equal(t, 4096, worker.Resources.MemoryMB)
+	assert.Equal(t, "golang:latest", worker.Config["image"])
+	assert.Equal(t, true, worker.Config["force_pull"])
+	assert.Equal(t, []interface{}{"pg:127.0.0.1"}, worker.Config["extra_hosts"])
+	assert.Equal(t, name, worker.Env["CDS_NAME"])
+	assert.Equal(t, "42", worker.Env["CDS_BOOKED_JOB_ID"])
+
+	service := group.Tasks[1]
+	assert.Equal(t, "pg", service.Name)
+	assert.Equal(t, "postgres:9.6", service.Config["image"])
+	assert.Equal(t, 2048, service.Resources.MemoryMB)
+	assert.Equal(t, map[string]string{"POSTGRES_PASSWORD": "pwd"}, service.Env)
+	if !assert.NotNil(t, service.Lifecycle) {
+		t.FailNow()
+	}
+	assert.True(t, service.Lifecycle.Sidecar)
+	assert.Equal(t, "prestart", service.Lifecycle.Hook)
+
+	assert.Equal(t, 1, h.WorkersStarted())
+	assert.Equal(t, 1, h.WorkersStartedByModel(model))
+	assert.Equal(t, 0, h.WorkersStartedByModel(&sdk.Model{Name: "Java"}))
+
+	// The max number of workers is reached
+	_, err = h.SpawnWorker(model, 0, nil, false, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, h.CanSpawn(model, 43, nil))
+}
+
+func TestHatcheryNomadExecDriver(t *testing.T) {
+	h, f, srv := newTestHatchery(t, driverExec)
+	defer srv.Close()
+	assert.Equal(t, sdk.HostProcess, h.ModelType())
+
+	model := &sdk.Model{ID: 2, Name: "shell", Type: sdk.HostProcess}
+	assert.False(t, h.CanSpawn(model, 42, []sdk.Requirement{{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres"}}))
+
+	name, err := h.SpawnWorker(model, 0, nil, true, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(name, "register-shell-"))
+
+	job := f.jobs["cds-worker-"+name]
+	if !assert.Len(t, job.TaskGroups, 1) {
+		t.FailNow()
+	}
+	assert.Empty(t, job.TaskGroups[0].Networks)
+	if !assert.Len(t, job.TaskGroups[0].Tasks, 1) {
+		t.FailNow()
+	}
+	worker := job.TaskGroups[0].Tasks[0]
+	assert.Equal(t, driverExec, worker.Driver)
+	assert.Equal(t, 1024, worker.Resources.MemoryMB)
+	assert.Nil(t, worker.Config["image"])
+	args, _ := worker.Config["args"].([]interface{})
+	if !assert.Len(t, args, 2) {
+		t.FailNow()
+	}
+	assert.True(t, strings.HasSuffix(args[1].(string), "./worker register"))
+}
+
+func TestHatcheryNomadGarbageCollection(t *testing.T) {
+	h, f, srv := newTestHatchery(t, driverDocker)
+	defer srv.Close()
+
+	f.jobs["cds-worker-dead"] = nomadJob{ID: "cds-worker-dead"}
+	f.status["cds-worker-dead"] = jobStatusDead
+	f.jobs["cds-worker-disabled"] = nomadJob{ID: "cds-worker-disabled"}
+	f.status["cds-worker-disabled"] = jobStatusRunning
+	f.submit["cds-worker-disabled"] = time.Now().UnixNano()
+	f.jobs["cds-worker-awol"] = nomadJob{ID: "cds-worker-awol"}
+	f.status["cds-worker-awol"] = jobStatusRunning
+	f.submit["cds-worker-awol"] = time.Now().Add(-2 * time.Minute).UnixNano()
+	f.jobs["cds-worker-starting"] = nomadJob{ID: "cds-worker-starting"}
+	f.status["cds-worker-starting"] = jobStatusRunning
+	f.submit["cds-worker-starting"] = time.Now().UnixNano()
+	f.jobs["cds-worker-building"] = nomadJob{ID: "cds-worker-building"}
+	f.status["cds-worker-building"] = jobStatusRunning
+	f.submit["cds-worker-building"] = time.Now().Add(-2 * time.Minute).UnixNano()
+	f.jobs["other-job"] = nomadJob{ID: "other-job"}
+	f.status["other-job"] = jobStatusDead
+	f.workers = []sdk.Worker{
+		{Name: "disabled", Status: sdk.StatusDisabled},
+		{Name: "building", Status: sdk.StatusBuilding},
+	}
+
+	assert.Equal(t, 4, h.WorkersStarted())
+
+	if err := h.killDeadJobs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.killAwolWorkers(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for id := range f.jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	assert.Equal(t, []string{"cds-worker-building", "cds-worker-starting", "other-job"}, ids)
+}
@@ -0,0 +1,54 @@
+package nomad
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// HatcheryConfiguration is the configuration for hatchery
+type HatcheryConfiguration struct {
+	hatchery.CommonConfiguration `toml:"commonConfiguration"`
+
+	// NomadURL "nomad-api"
+	NomadURL string `toml:"url" default:"http://localhost:4646" commented:"true" comment:"URL of your Nomad HTTP API"`
+
+	// NomadToken "nomad-token"
+	NomadToken string `toml:"token" default:"" commented:"true" comment:"Nomad ACL token, used to call Nomad URL"`
+
+	// Region Nomad region
+	Region string `toml:"region" default:"" commented:"true" comment:"Nomad region of the workers jobs. Empty means the region of the agent"`
+
+	// Datacenters Nomad datacenters
+	Datacenters string `toml:"datacenters" default:"dc1" commented:"true" comment:"Nomad datacenters of the workers jobs, comma separated"`
+
+	// Namespace Nomad namespace
+	Namespace string `toml:"namespace" default:"" commented:"true" comment:"Nomad namespace of the workers jobs. Empty means the default namespace"`
+
+	// JobPrefix prefix of the workers jobs
+	JobPrefix string `toml:"jobPrefix" default:"cds-worker" commented:"true" comment:"Prefix of id for workers jobs submitted on Nomad"`
+
+	// Driver Nomad task driver
+	Driver string `toml:"driver" default:"docker" commented:"true" comment:"Nomad task driver used to start workers: docker or exec. Service requirements need the docker driver"`
+
+	// DefaultMemory Worker default memory
+	DefaultMemory int `toml:"defaultMemory" default:"1024" commented:"true" comment:"Worker default memory in Mo"`
+
+	// DefaultCPU Worker default CPU
+	DefaultCPU int `toml:"defaultCPU" default:"500" commented:"true" comment:"Worker default CPU in MHz"`
+
+	// ServiceMemory Service default memory
+	ServiceMemory int `toml:"serviceMemory" default:"512" commented:"true" comment:"Service default memory in Mo"`
+
+	// WorkerTTL Worker TTL (minutes)
+	WorkerTTL int `toml:"workerTTL" default:"10" commented:"true" comment:"Worker TTL (minutes)"`
+}
+
+// HatcheryNomad implements HatcheryMode interface for nomad mode
+type HatcheryNomad struct {
+	Config HatcheryConfiguration
+	hatch  *sdk.Hatchery
+
+	nomadClient *nomadClient
+	client      cdsclient.Interface
+}
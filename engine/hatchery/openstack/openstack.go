@@ -139,6 +139,10 @@ func (h *HatcheryOpenstack) CanSpawn(model *sdk.Model, jobID int64, requirements
 		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
 			return false
 		}
+		// os-arch requirement is supported only if the model declares its os-arch
+		if r.Type == sdk.OSArchRequirement && model.OSArch() == "" {
+			return false
+		}
 	}
 	return true
 }
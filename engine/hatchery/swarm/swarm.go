@@ -304,6 +304,7 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 		"CDS_NAME" + "=" + name,
 		"CDS_TOKEN" + "=" + viper.GetString("token"),
 		"CDS_MODEL" + "=" + strconv.FormatInt(model.ID, 10),
+		"CDS_MODEL_VERSION" + "=" + strconv.FormatInt(model.Version, 10),
 		"CDS_HATCHERY" + "=" + strconv.FormatInt(h.hatch.ID, 10),
 		"CDS_HATCHERY_NAME" + "=" + h.hatch.Name,
 		"CDS_TTL" + "=" + strconv.Itoa(h.Config.WorkerTTL),
@@ -414,6 +415,13 @@ func (*HatcherySwarm) ModelType() string {
 
 // CanSpawn checks if the model can be spawned by this hatchery
 func (h *HatcherySwarm) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	for _, r := range requirements {
+		if r.Type == sdk.OSArchRequirement && model.OSArch() == "" {
+			log.Debug("CanSpawn> Job %d has an os-arch requirement and model %s doesn't declare its os-arch", jobID, model.Name)
+			return false
+		}
+	}
+
 	//List all containers to check if we can spawn a new one
 	cs, errList := h.getContainers()
 	if errList != nil {
@@ -7,6 +7,7 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/fsamin/go-dump"
@@ -250,7 +251,11 @@ func (s *Service) doScheduledTaskExecution(t *TaskExecution) (*sdk.WorkflowNodeR
 		switch k {
 		case "project", "workflow", "cron", "timezone":
 		default:
-			payloadValues[k] = v
+			value, err := interpolateTaskConfig(t, v)
+			if err != nil {
+				return nil, sdk.WrapError(err, "Hooks> Unable to interpolate %s", k)
+			}
+			payloadValues[k] = value
 		}
 	}
 	h.Payload = payloadValues
@@ -327,7 +332,11 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 		switch k {
 		case "project", "workflow", "method":
 		default:
-			payloadValues[k] = v
+			value, err := interpolateTaskConfig(t, v)
+			if err != nil {
+				return nil, sdk.WrapError(err, "Hooks> Unable to interpolate %s", k)
+			}
+			payloadValues[k] = value
 		}
 	}
 	//try to find some specific values
@@ -349,6 +358,25 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 	return &h, nil
 }
 
+// interpolateTaskConfig interpolates a value of the configuration of a task with cds.project, cds.workflow,
+// cds.hook.uuid and cds.hook.date, which is the RFC3339 date of the execution
+func interpolateTaskConfig(t *TaskExecution, value string) (string, error) {
+	vars := map[string]string{
+		"cds.project":   t.Config["project"],
+		"cds.workflow":  t.Config["workflow"],
+		"cds.hook.uuid": t.UUID,
+		"cds.hook.date": time.Unix(0, t.Timestamp).Format(time.RFC3339),
+	}
+	s, unknown, err := sdk.InterpolateWithUnknown(value, vars)
+	if err != nil {
+		return "", err
+	}
+	if len(unknown) > 0 {
+		log.Warning("Hooks> Unknown variables %s in the configuration of task %s", strings.Join(unknown, ", "), t.UUID)
+	}
+	return s, nil
+}
+
 func copyValues(dst, src url.Values) {
 	for k, vs := range src {
 		for _, value := range vs {
@@ -19,6 +19,7 @@ import (
 	"github.com/ovh/cds/engine/hatchery/docker"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
+	"github.com/ovh/cds/engine/hatchery/nomad"
 	"github.com/ovh/cds/engine/hatchery/openstack"
 	"github.com/ovh/cds/engine/hatchery/vsphere"
 	"github.com/ovh/cds/engine/hooks"
@@ -104,6 +105,7 @@ Comming soon...`,
 		conf.Hatchery.VSphere.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hatchery.Swarm.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hatchery.Marathon.API.Token = conf.API.Auth.SharedInfraToken
+		conf.Hatchery.Nomad.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hooks.API.Token = conf.API.Auth.SharedInfraToken
 
 		if !configNewAsEnvFlag {
@@ -171,6 +173,13 @@ var configCheckCmd = &cobra.Command{
 			}
 		}
 
+		if conf.Hatchery.Nomad.API.HTTP.URL != "" {
+			if err := nomad.New().CheckConfiguration(conf.Hatchery.Nomad); err != nil {
+				fmt.Println(err)
+				hasError = true
+			}
+		}
+
 		if conf.Hatchery.Openstack.API.HTTP.URL != "" {
 			if err := openstack.New().CheckConfiguration(conf.Hatchery.Openstack); err != nil {
 				fmt.Println(err)
@@ -210,7 +219,7 @@ Start CDS Engine Services:
  	This component operates CDS workflow hooks
 
 Start all of this with a single command:
-	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hooks]
+	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:marathon] [hatchery:nomad] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hooks]
 All the services are using the same configuration file format.
 You have to specify where the toml configuration is. It can be a local file, provided by consul or vault.
 You can also use or override toml file with environment variable.
@@ -261,6 +270,9 @@ See $ engine config command for more details.
 			case "hatchery:marathon":
 				s = local.New()
 				cfg = conf.Hatchery.Marathon
+			case "hatchery:nomad":
+				s = nomad.New()
+				cfg = conf.Hatchery.Nomad
 			case "hatchery:openstack":
 				s = local.New()
 				cfg = conf.Hatchery.Openstack
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_test" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT,
+    workflow_run_id BIGINT,
+    workflow_node_run_id BIGINT,
+    num BIGINT,
+    git_hash VARCHAR(256),
+    test_suite TEXT,
+    test_case TEXT,
+    status VARCHAR(64),
+    duration DOUBLE PRECISION,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TEST_WORKFLOW', 'workflow_node_run_test', 'workflow', 'workflow_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TEST_WORKFLOW_RUN', 'workflow_node_run_test', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TEST_WORKFLOW_NODE_RUN', 'workflow_node_run_test', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_index('workflow_node_run_test', 'IDX_WORKFLOW_NODE_RUN_TEST_CASE', 'workflow_id, test_suite, test_case');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_test;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE action ADD COLUMN condition TEXT NOT NULL DEFAULT '';
+ALTER TABLE action_edge ADD COLUMN condition TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE action DROP COLUMN condition;
+ALTER TABLE action_edge DROP COLUMN condition;
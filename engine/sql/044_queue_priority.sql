@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE project ADD COLUMN priority BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE workflow_node_context ADD COLUMN priority BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN priority BIGINT NOT NULL DEFAULT 0;
+SELECT create_index('workflow_node_run_job', 'IDX_WORKFLOW_NODE_RUN_JOB_PRIORITY', 'status,priority');
+
+-- +migrate Down
+ALTER TABLE project DROP COLUMN priority;
+ALTER TABLE workflow_node_context DROP COLUMN priority;
+ALTER TABLE workflow_node_run_job DROP COLUMN priority;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "group_quota" (
+    group_id BIGINT PRIMARY KEY,
+    max_workers BIGINT NOT NULL DEFAULT 0,
+    cpu_minutes_per_day BIGINT NOT NULL DEFAULT 0,
+    models JSONB
+);
+SELECT create_foreign_key_idx_cascade('FK_GROUP_QUOTA_GROUP', 'group_quota', 'group', 'group_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "stats_group_usage" (
+    day DATE NOT NULL,
+    group_id BIGINT NOT NULL,
+    jobs BIGINT NOT NULL DEFAULT 0,
+    cpu_minutes BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, group_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_STATS_GROUP_USAGE_GROUP', 'stats_group_usage', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE stats_group_usage;
+DROP TABLE group_quota;
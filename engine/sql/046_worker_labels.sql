@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE worker ADD COLUMN labels JSONB;
+ALTER TABLE worker ADD COLUMN os_arch VARCHAR(64) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE worker DROP COLUMN labels;
+ALTER TABLE worker DROP COLUMN os_arch;
@@ -0,0 +1,27 @@
+-- +migrate Up
+ALTER TABLE worker_model ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "worker_model_version" (
+    id BIGSERIAL PRIMARY KEY,
+    worker_model_id BIGINT NOT NULL,
+    version BIGINT NOT NULL,
+    image TEXT NOT NULL DEFAULT '',
+    template JSONB,
+    status VARCHAR(32) NOT NULL,
+    rollout BIGINT NOT NULL DEFAULT 0,
+    capabilities JSONB,
+    tool_versions JSONB,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    created_by TEXT NOT NULL DEFAULT '',
+    checked TIMESTAMP WITH TIME ZONE
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKER_MODEL_VERSION_WORKER_MODEL', 'worker_model_version', 'worker_model', 'worker_model_id', 'id');
+SELECT create_unique_index('worker_model_version', 'IDX_WORKER_MODEL_VERSION', 'worker_model_id, version');
+
+INSERT INTO worker_model_version (worker_model_id, version, image, template, status, created)
+SELECT id, 1, coalesce(image, ''), template, 'Active', coalesce(user_last_modified, now()) FROM worker_model;
+UPDATE worker_model SET version = 1;
+
+-- +migrate Down
+DROP TABLE worker_model_version;
+ALTER TABLE worker_model DROP COLUMN version;
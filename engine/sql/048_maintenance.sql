@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE hatchery ADD COLUMN maintenance BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE worker ADD COLUMN maintenance BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE hatchery DROP COLUMN maintenance;
+ALTER TABLE worker DROP COLUMN maintenance;
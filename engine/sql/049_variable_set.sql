@@ -0,0 +1,56 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "variable_set" (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    group_id BIGINT NOT NULL,
+    last_modified TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+SELECT create_foreign_key_idx_cascade('FK_VARIABLE_SET_GROUP', 'variable_set', 'group', 'group_id', 'id');
+SELECT create_unique_index('variable_set', 'IDX_VARIABLE_SET_NAME', 'name');
+
+CREATE TABLE IF NOT EXISTS "variable_set_variable" (
+    id BIGSERIAL PRIMARY KEY,
+    variable_set_id BIGINT NOT NULL,
+    var_name TEXT NOT NULL,
+    var_value TEXT,
+    cipher_value BYTEA,
+    var_type TEXT NOT NULL
+);
+SELECT create_foreign_key_idx_cascade('FK_VARIABLE_SET_VARIABLE_VARIABLE_SET', 'variable_set_variable', 'variable_set', 'variable_set_id', 'id');
+SELECT create_unique_index('variable_set_variable', 'IDX_VARIABLE_SET_VARIABLE_NAME', 'variable_set_id, var_name');
+
+CREATE TABLE IF NOT EXISTS "variable_set_audit" (
+    id BIGSERIAL PRIMARY KEY,
+    variable_set_id BIGINT,
+    variable_id BIGINT,
+    type TEXT,
+    variable_before JSONB,
+    variable_after JSONB,
+    versionned TIMESTAMP WITH TIME ZONE,
+    author TEXT
+);
+SELECT create_foreign_key_idx_cascade('FK_VARIABLE_SET_AUDIT_VARIABLE_SET', 'variable_set_audit', 'variable_set', 'variable_set_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "project_variable_set" (
+    project_id BIGINT NOT NULL,
+    variable_set_id BIGINT NOT NULL,
+    PRIMARY KEY (project_id, variable_set_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_VARIABLE_SET_PROJECT', 'project_variable_set', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_VARIABLE_SET_VARIABLE_SET', 'project_variable_set', 'variable_set', 'variable_set_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "application_variable_set" (
+    application_id BIGINT NOT NULL,
+    variable_set_id BIGINT NOT NULL,
+    PRIMARY KEY (application_id, variable_set_id)
+);
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_VARIABLE_SET_APPLICATION', 'application_variable_set', 'application', 'application_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_VARIABLE_SET_VARIABLE_SET', 'application_variable_set', 'variable_set', 'variable_set_id', 'id');
+
+-- +migrate Down
+DROP TABLE application_variable_set;
+DROP TABLE project_variable_set;
+DROP TABLE variable_set_audit;
+DROP TABLE variable_set_variable;
+DROP TABLE variable_set;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "audit" (
+    id BIGSERIAL PRIMARY KEY,
+    created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    username TEXT NOT NULL DEFAULT '',
+    project_key TEXT NOT NULL DEFAULT '',
+    entity_type TEXT NOT NULL,
+    entity_name TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    diff JSONB
+);
+SELECT create_index('audit', 'IDX_AUDIT_CREATED', 'created');
+SELECT create_index('audit', 'IDX_AUDIT_PROJECT_KEY', 'project_key,created');
+SELECT create_index('audit', 'IDX_AUDIT_USERNAME', 'username,created');
+SELECT create_index('audit', 'IDX_AUDIT_ENTITY', 'entity_type,entity_name');
+
+-- +migrate Down
+DROP TABLE audit;
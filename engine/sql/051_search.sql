@@ -0,0 +1,37 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION search_vector(content text) RETURNS tsvector AS $$
+    -- Split the words on the separators used in names, ie: my-app, docker.image or ovh/cds
+    SELECT to_tsvector('simple', translate(coalesce(content, ''), '._-/:', '     '));
+$$ LANGUAGE SQL IMMUTABLE;
+-- +migrate StatementEnd
+
+CREATE INDEX IDX_PROJECT_SEARCH ON project USING GIN (search_vector(projectkey || ' ' || name));
+CREATE INDEX IDX_APPLICATION_SEARCH ON application USING GIN (search_vector(name));
+CREATE INDEX IDX_APPLICATION_REPO_SEARCH ON application USING GIN (search_vector(repo_fullname));
+CREATE INDEX IDX_PIPELINE_SEARCH ON pipeline USING GIN (search_vector(name));
+CREATE INDEX IDX_ENVIRONMENT_SEARCH ON environment USING GIN (search_vector(name));
+CREATE INDEX IDX_WORKFLOW_SEARCH ON workflow USING GIN (search_vector(name));
+CREATE INDEX IDX_PROJECT_VARIABLE_SEARCH ON project_variable USING GIN (search_vector(var_name));
+CREATE INDEX IDX_APPLICATION_VARIABLE_SEARCH ON application_variable USING GIN (search_vector(var_name));
+CREATE INDEX IDX_ENVIRONMENT_VARIABLE_SEARCH ON environment_variable USING GIN (search_vector(name));
+CREATE INDEX IDX_PIPELINE_PARAMETER_SEARCH ON pipeline_parameter USING GIN (search_vector(name));
+CREATE INDEX IDX_ACTION_SEARCH ON action USING GIN (search_vector(name));
+CREATE INDEX IDX_ACTION_REQUIREMENT_SEARCH ON action_requirement USING GIN (search_vector(value));
+CREATE INDEX IDX_ACTION_EDGE_SCRIPT_SEARCH ON action_edge_parameter USING GIN (search_vector(value)) WHERE name = 'script';
+
+-- +migrate Down
+DROP INDEX IDX_PROJECT_SEARCH;
+DROP INDEX IDX_APPLICATION_SEARCH;
+DROP INDEX IDX_APPLICATION_REPO_SEARCH;
+DROP INDEX IDX_PIPELINE_SEARCH;
+DROP INDEX IDX_ENVIRONMENT_SEARCH;
+DROP INDEX IDX_WORKFLOW_SEARCH;
+DROP INDEX IDX_PROJECT_VARIABLE_SEARCH;
+DROP INDEX IDX_APPLICATION_VARIABLE_SEARCH;
+DROP INDEX IDX_ENVIRONMENT_VARIABLE_SEARCH;
+DROP INDEX IDX_PIPELINE_PARAMETER_SEARCH;
+DROP INDEX IDX_ACTION_SEARCH;
+DROP INDEX IDX_ACTION_REQUIREMENT_SEARCH;
+DROP INDEX IDX_ACTION_EDGE_SCRIPT_SEARCH;
+DROP FUNCTION search_vector(text);
@@ -3,13 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
 	"path"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
 	"github.com/ovh/cds/sdk/log"
-	"github.com/ovh/cds/sdk/plugin"
 )
 
 var mapBuiltinActions = map[string]BuiltInActionFunc{}
@@ -22,6 +22,8 @@ func init() {
 	mapBuiltinActions[sdk.GitCloneAction] = runGitClone
 	mapBuiltinActions[sdk.GitTagAction] = runGitTag
 	mapBuiltinActions[sdk.ReleaseAction] = runRelease
+	mapBuiltinActions[sdk.SignAction] = runSign
+	mapBuiltinActions[sdk.HTTPRequestAction] = runHTTPRequest
 }
 
 // BuiltInAction defines builtin action signature
@@ -48,6 +50,11 @@ func (w *currentWorker) runBuiltin(ctx context.Context, a *sdk.Action, buildID i
 	//Define a loggin function
 	sendLog := getLogger(w, buildID, stepOrder)
 
+	if w.local.enabled && !localBuiltinActions[a.Name] {
+		sendLog(fmt.Sprintf("%s is not available with worker exec, step skipped", a.Name))
+		return sdk.Result{Status: sdk.StatusSuccess.String()}
+	}
+
 	f, ok := mapBuiltinActions[a.Name]
 	if !ok {
 		res := sdk.Result{
@@ -61,79 +68,88 @@ func (w *currentWorker) runBuiltin(ctx context.Context, a *sdk.Action, buildID i
 }
 
 func (w *currentWorker) runPlugin(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, sendLog LoggerFunc) sdk.Result {
-	chanRes := make(chan sdk.Result)
-
-	go func(buildID int64, params []sdk.Parameter) {
-		res := sdk.Result{Status: sdk.StatusFail.String()}
+	chanRes := make(chan sdk.Result, 1)
 
+	go func(buildID int64) {
 		//For the moment we consider that plugin name = action name = plugin binary file name
 		pluginName := a.Name
 		//The binary file has been downloaded during requirement check in /tmp
 		pluginBinary := path.Join(w.basedir, a.Name)
 
-		var tlsskipverify bool
-		if os.Getenv("CDS_SKIP_VERIFY") != "" {
-			tlsskipverify = true
-		}
-
-		//Create the rpc server
-		pluginClient := plugin.NewClient(ctx, pluginName, pluginBinary, w.id, w.apiEndpoint, tlsskipverify)
-		defer pluginClient.Kill()
-
-		//Get the plugin interface
-		_plugin, err := pluginClient.Instance()
+		//Start the plugin, its handshake tells which protocol it speaks
+		proc, err := grpcplugin.Start(ctx, pluginBinary)
 		if err != nil {
 			result := sdk.Result{
 				Status: sdk.StatusFail.String(),
-				Reason: fmt.Sprintf("Unable to init plugin %s: %s\n", pluginName, err),
+				Reason: fmt.Sprintf("Unable to start plugin %s: %s\n", pluginName, err),
 			}
 			sendLog(result.Reason)
 			chanRes <- result
+			return
 		}
+		defer proc.Kill()
 
 		//Manage all parameters
-		pluginSecrets := plugin.Secrets{
-			Data: map[string]string{},
-		}
-
-		pluginArgs := plugin.Arguments{
-			Data: map[string]string{},
+		query := actionplugin.ActionQuery{
+			Options:   map[string]string{},
+			Secrets:   map[string]string{},
+			JobId:     buildID,
+			StepOrder: int64(stepOrder),
 		}
 		for _, p := range a.Parameters {
-			pluginArgs.Data[p.Name] = p.Value
+			query.Options[p.Name] = p.Value
 		}
-		for _, p := range params {
-			pluginArgs.Data[p.Name] = p.Value
+		for _, p := range *params {
+			query.Options[p.Name] = p.Value
 			if sdk.NeedPlaceholder(p.Type) {
-				pluginSecrets.Data[p.Name] = p.Value
+				query.Secrets[p.Name] = p.Value
 			}
 		}
 		for _, v := range w.currentJob.buildVariables {
-			pluginArgs.Data[v.Name] = v.Value
+			query.Options[v.Name] = v.Value
 		}
 
-		//Call the Run function on the plugin interface
-		id := w.currentJob.pbJob.PipelineBuildID
-		if w.currentJob.wJob != nil {
-			id = w.currentJob.wJob.WorkflowNodeRunID
-		}
-
-		pluginAction := plugin.Job{
-			IDPipelineBuild:    id,
-			IDPipelineJobBuild: buildID,
-			OrderStep:          stepOrder,
-			Args:               pluginArgs,
-			Secrts:             pluginSecrets,
-		}
-
-		pluginResult := _plugin.Run(pluginAction)
-
-		if pluginResult == plugin.Success {
-			res.Status = sdk.StatusSuccess.String()
+		var events pluginEventsFunc
+		switch proc.Version {
+		case actionplugin.ProtocolVersion:
+			c, err := actionplugin.NewClient(proc)
+			if err != nil {
+				result := sdk.Result{
+					Status: sdk.StatusFail.String(),
+					Reason: fmt.Sprintf("Unable to init plugin %s: %s\n", pluginName, err),
+				}
+				sendLog(result.Reason)
+				chanRes <- result
+				return
+			}
+			defer c.Close()
+
+			stream, err := c.Run(ctx, &query)
+			if err != nil {
+				result := sdk.Result{
+					Status: sdk.StatusFail.String(),
+					Reason: fmt.Sprintf("Unable to run plugin %s: %s\n", pluginName, err),
+				}
+				sendLog(result.Reason)
+				chanRes <- result
+				return
+			}
+			events = stream.Recv
+		default:
+			events, err = w.gobPluginEvents(proc, pluginName, &query)
+			if err != nil {
+				result := sdk.Result{
+					Status: sdk.StatusFail.String(),
+					Reason: fmt.Sprintf("Unable to init plugin %s: %s\n", pluginName, err),
+				}
+				sendLog(result.Reason)
+				chanRes <- result
+				return
+			}
 		}
 
-		chanRes <- res
-	}(buildID, *params)
+		chanRes <- w.handlePluginEvents(events, buildID, params, sendLog)
+	}(buildID)
 
 	for {
 		select {
@@ -115,7 +115,7 @@ func runArtifactUpload(w *currentWorker) BuiltInAction {
 		for _, filePath := range filesPath {
 			filename := filepath.Base(filePath)
 			sendLog(fmt.Sprintf("Uploading '%s'\n", filename))
-			if err := w.client.QueueArtifactUpload(buildID, tag.Value, filePath); err != nil {
+			if err := w.uploadArtifact(buildID, tag.Value, filePath, *params); err != nil {
 				res.Status = sdk.StatusFail.String()
 				res.Reason = fmt.Sprintf("Error while uploading artefact: %s\n", err)
 				sendLog(res.Reason)
@@ -140,6 +140,7 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 			pipeline := sdk.ParameterValue(a.Parameters, "pipeline")
 			path := sdk.ParameterValue(a.Parameters, "path")
 			tag := sdk.ParameterValue(a.Parameters, "tag")
+			verifyKey := sdk.ParameterValue(a.Parameters, "verifyKey")
 
 			if !enabled {
 				sendLog("Artifact Download is disabled.")
@@ -171,6 +172,27 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 				return res
 			}
 
+			if verifyKey != "" {
+				arts, err := sdk.ListArtifacts(project, application, pipeline, tag, environment)
+				if err != nil {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = err.Error()
+					log.Warning("Cannot list artifacts: %s", err)
+					sendLog(res.Reason)
+					return res
+				}
+				names := make([]string, len(arts))
+				for i := range arts {
+					names[i] = arts[i].Name
+				}
+				if err := verifyArtifactsWithKey(*params, path, names, verifyKey, sendLog); err != nil {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = err.Error()
+					sendLog(res.Reason)
+					return res
+				}
+			}
+
 			return res
 		}
 	}
@@ -185,6 +207,7 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 
 		path := sdk.ParameterValue(a.Parameters, "path")
 		tag := sdk.ParameterValue(a.Parameters, "tag")
+		verifyKey := sdk.ParameterValue(a.Parameters, "verifyKey")
 
 		if !enabled {
 			sendLog("Artifact Download is disabled.")
@@ -197,6 +220,25 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 
 		sendLog(fmt.Sprintf("Downloading artifacts from workflow into '%s'...", path))
 
+		if w.local.enabled {
+			names, err := downloadLocalArtifacts(w.local.artifactsDir, path)
+			if err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = err.Error()
+				sendLog(res.Reason)
+				return res
+			}
+			if verifyKey != "" {
+				if err := verifyArtifactsWithKey(*params, path, names, verifyKey, sendLog); err != nil {
+					res.Status = sdk.StatusFail.String()
+					res.Reason = err.Error()
+					sendLog(res.Reason)
+					return res
+				}
+			}
+			return res
+		}
+
 		n, err := strconv.ParseInt(number, 10, 64)
 		if err != nil {
 			res.Status = sdk.StatusFail.String()
@@ -239,6 +281,48 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 			}
 		}
 
+		if verifyKey != "" {
+			names := make([]string, len(artifacts))
+			for i := range artifacts {
+				names[i] = artifacts[i].Name
+			}
+			if err := verifyArtifactsWithKey(*params, ".", names, verifyKey, sendLog); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = err.Error()
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
 		return res
 	}
 }
+
+func verifyArtifactsWithKey(params []sdk.Parameter, dir string, names []string, verifyKey string, sendLog LoggerFunc) error {
+	publicKey := sdk.ParameterFind(params, fmt.Sprintf("%s.pub", verifyKey))
+	if publicKey == nil {
+		return fmt.Errorf("Cannot find pgp public key %s", verifyKey)
+	}
+	sendLog(fmt.Sprintf("Verifying artifact signatures with key %s...", verifyKey))
+	return verifyArtifactSignatures(dir, names, publicKey.Value, sendLog)
+}
+
+// uploadArtifact uploads a file produced by the step as an artifact of the current job
+func (w *currentWorker) uploadArtifact(buildID int64, tag, filePath string, params []sdk.Parameter) error {
+	if w.local.enabled {
+		return copyFile(filePath, filepath.Join(w.local.artifactsDir, filepath.Base(filePath)))
+	}
+	if w.currentJob.wJob != nil {
+		return w.client.QueueArtifactUpload(buildID, tag, filePath)
+	}
+
+	pipeline := sdk.ParameterValue(params, "cds.pipeline")
+	project := sdk.ParameterValue(params, "cds.project")
+	application := sdk.ParameterValue(params, "cds.application")
+	environment := sdk.ParameterValue(params, "cds.environment")
+	buildNumber, errBN := strconv.Atoi(sdk.ParameterValue(params, "cds.buildNumber"))
+	if errBN != nil {
+		return fmt.Errorf("BuilNumber is not an integer %s", errBN)
+	}
+	return sdk.UploadArtifact(project, pipeline, application, tag, filePath, buildNumber, environment)
+}
@@ -26,6 +26,20 @@ func runGitClone(w *currentWorker) BuiltInAction {
 		directory := sdk.ParameterFind(a.Parameters, "directory")
 		cdsVersion := sdk.ParameterFind(*params, "cds.version")
 
+		// worker exec always clones the repository it has been launched from
+		if w.local.enabled {
+			if w.local.gitDir == "" {
+				res := sdk.Result{
+					Status: sdk.StatusFail.String(),
+					Reason: "worker exec has not been launched from a git repository. Unable to perform git clone",
+				}
+				sendLog(res.Reason)
+				return res
+			}
+			sendLog(fmt.Sprintf("Cloning local repository %s", w.local.gitDir))
+			url = &sdk.Parameter{Name: "url", Type: sdk.StringParameter, Value: w.local.gitDir}
+		}
+
 		if url == nil {
 			res := sdk.Result{
 				Status: sdk.StatusFail.String(),
@@ -59,7 +73,7 @@ func runGitClone(w *currentWorker) BuiltInAction {
 		}
 
 		//If url is not http(s), a key must be found
-		if !strings.HasPrefix(url.Value, "http") {
+		if !strings.HasPrefix(url.Value, "http") && !w.local.enabled {
 			if errK == sdk.ErrKeyNotFound || key == nil {
 				res := sdk.Result{
 					Status: sdk.StatusFail.String(),
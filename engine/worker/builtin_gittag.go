@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"strings"
 	"time"
 
@@ -136,30 +137,19 @@ func runGitTag(w *currentWorker) BuiltInAction {
 		}
 
 		if signKey != nil && signKey.Value != "" {
-			privateKey := sdk.ParameterFind(*params, fmt.Sprintf("%s.priv", signKey.Value))
-			publicKey := sdk.ParameterFind(*params, fmt.Sprintf("%s.pub", signKey.Value))
-			keyID := sdk.ParameterFind(*params, fmt.Sprintf("%s.id", signKey.Value))
-
-			if privateKey == nil {
-				res := sdk.Result{
-					Status: sdk.StatusFail.String(),
-					Reason: fmt.Sprintf("Cannot find pgp private key."),
-				}
-				sendLog(res.Reason)
-				return res
-			}
-			if keyID == nil {
+			privateKey, publicKey, keyID, errK := pgpKeyFromParams(*params, signKey.Value)
+			if errK != nil {
 				res := sdk.Result{
 					Status: sdk.StatusFail.String(),
-					Reason: fmt.Sprintf("Cannot find pgp key id."),
+					Reason: errK.Error(),
 				}
 				sendLog(res.Reason)
 				return res
 			}
-			tagOpts.SignKey = privateKey.Value
-			tagOpts.SignID = keyID.Value
+			tagOpts.SignKey = privateKey
+			tagOpts.SignID = keyID
 
-			if err := ioutil.WriteFile("pgp.pub.key", []byte(publicKey.Value), 0600); err != nil {
+			if err := ioutil.WriteFile("pgp.pub.key", []byte(publicKey), 0600); err != nil {
 				res := sdk.Result{
 					Status: sdk.StatusFail.String(),
 					Reason: fmt.Sprintf("Cannot create pgp key file."),
@@ -167,6 +157,7 @@ func runGitTag(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				return res
 			}
+			defer os.Remove("pgp.pub.key")
 			if err := ioutil.WriteFile("pgp.key", []byte(tagOpts.SignKey), 0600); err != nil {
 				res := sdk.Result{
 					Status: sdk.StatusFail.String(),
@@ -175,6 +166,8 @@ func runGitTag(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				return res
 			}
+			defer os.Remove("pgp.key")
+			sendLog(fmt.Sprintf("Tag %s will be signed with key %s (%s)", tagOpts.Name, signKey.Value, keyID))
 		}
 
 		if key != nil {
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runHTTPRequest(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusFail.String()}
+
+		method := strings.ToUpper(sdk.ParameterValue(a.Parameters, "method"))
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		url := sdk.ParameterValue(a.Parameters, "url")
+		if url == "" {
+			res.Reason = fmt.Sprintf("url variable is empty. aborting")
+			sendLog(res.Reason)
+			return res
+		}
+
+		headers, err := parseHTTPHeaders(sdk.ParameterValue(a.Parameters, "headers"))
+		if err != nil {
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		expected, err := parseExpectedStatus(sdk.ParameterValue(a.Parameters, "expectedStatus"))
+		if err != nil {
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		extracts, err := parseHTTPExtracts(sdk.ParameterValue(a.Parameters, "extract"))
+		if err != nil {
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		client, err := newHTTPRequestClient(a.Parameters)
+		if err != nil {
+			res.Reason = err.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		retry, _ := strconv.Atoi(sdk.ParameterValue(a.Parameters, "retry"))
+		retryDelay, _ := strconv.Atoi(sdk.ParameterValue(a.Parameters, "retryDelay"))
+		if retryDelay <= 0 {
+			retryDelay = 1
+		}
+
+		body := sdk.ParameterValue(a.Parameters, "body")
+		user := sdk.ParameterValue(a.Parameters, "basicAuthUser")
+		password := sdk.ParameterValue(a.Parameters, "basicAuthPassword")
+		token := sdk.ParameterValue(a.Parameters, "bearerToken")
+
+		var respBody []byte
+		var lastErr error
+		for attempt := 0; attempt <= retry; attempt++ {
+			if attempt > 0 {
+				// Exponential backoff between attempts
+				delay := time.Duration(retryDelay) * time.Second * time.Duration(1<<uint(attempt-1))
+				sendLog(fmt.Sprintf("Retrying in %s (%d/%d)", delay, attempt, retry))
+				select {
+				case <-ctx.Done():
+					res.Reason = "HTTP request canceled"
+					sendLog(res.Reason)
+					return res
+				case <-time.After(delay):
+				}
+			}
+
+			req, err := http.NewRequest(method, url, strings.NewReader(body))
+			if err != nil {
+				res.Reason = fmt.Sprintf("Invalid HTTP request: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+			req = req.WithContext(ctx)
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			if user != "" || password != "" {
+				req.SetBasicAuth(user, password)
+			}
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				lastErr = err
+				sendLog(fmt.Sprintf("%s %s: %s", method, url, err))
+				continue
+			}
+			respBody, err = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				lastErr = err
+				sendLog(fmt.Sprintf("%s %s: cannot read response: %s", method, url, err))
+				continue
+			}
+
+			sendLog(fmt.Sprintf("%s %s: HTTP %d", method, url, resp.StatusCode))
+			if !expected(resp.StatusCode) {
+				lastErr = fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+				sendLog(string(respBody))
+				continue
+			}
+			lastErr = nil
+			break
+		}
+
+		if lastErr != nil {
+			res.Reason = fmt.Sprintf("HTTP request failed: %s", lastErr)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if len(extracts) > 0 {
+			var data interface{}
+			if err := json.Unmarshal(respBody, &data); err != nil {
+				res.Reason = fmt.Sprintf("Cannot extract variables, response is not JSON: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+			for _, e := range extracts {
+				value, err := jsonPathValue(data, e.path)
+				if err != nil {
+					res.Reason = fmt.Sprintf("Cannot extract %s: %s", e.path, err)
+					sendLog(res.Reason)
+					return res
+				}
+				v := sdk.Variable{Name: "cds.build." + e.name, Type: sdk.StringVariable, Value: value}
+				if _, err := w.addVariableInPipelineBuild(v, params); err != nil {
+					res.Reason = fmt.Sprintf("Cannot set variable %s: %s", v.Name, err)
+					sendLog(res.Reason)
+					return res
+				}
+				sendLog(fmt.Sprintf("Variable %s set from %s", v.Name, e.path))
+			}
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
+
+func newHTTPRequestClient(params []sdk.Parameter) (*http.Client, error) {
+	timeout, _ := strconv.Atoi(sdk.ParameterValue(params, "timeout"))
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: sdk.ParameterValue(params, "insecureSkipVerify") == "true",
+	}
+	if ca := sdk.ParameterValue(params, "caCertificate"); ca != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return nil, fmt.Errorf("Invalid CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Timeout: time.Duration(timeout) * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// parseHTTPHeaders parses headers written one per line: Name: value
+func parseHTTPHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid header '%s', format is Name: value", line)
+		}
+		headers[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return headers, nil
+}
+
+// parseExpectedStatus parses a comma separated list of status codes and ranges, ie. 200,201,300-399.
+// Any 2xx status is expected by default
+func parseExpectedStatus(s string) (func(int) bool, error) {
+	if strings.TrimSpace(s) == "" {
+		s = "200-299"
+	}
+
+	type statusRange struct{ min, max int }
+	ranges := []statusRange{}
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		bounds := strings.SplitN(e, "-", 2)
+		min, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid expected status '%s'", e)
+		}
+		max := min
+		if len(bounds) == 2 {
+			max, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || max < min {
+				return nil, fmt.Errorf("Invalid expected status '%s'", e)
+			}
+		}
+		ranges = append(ranges, statusRange{min, max})
+	}
+
+	return func(code int) bool {
+		for _, r := range ranges {
+			if code >= r.min && code <= r.max {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+type httpExtract struct {
+	name string
+	path string
+}
+
+// parseHTTPExtracts parses the variables to extract from the response, one per line: name=json.path
+func parseHTTPExtracts(s string) ([]httpExtract, error) {
+	extracts := []httpExtract{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i <= 0 || i == len(line)-1 {
+			return nil, fmt.Errorf("Invalid extract '%s', format is variable=json.path", line)
+		}
+		extracts = append(extracts, httpExtract{
+			name: strings.TrimPrefix(strings.TrimSpace(line[:i]), "cds.build."),
+			path: strings.TrimSpace(line[i+1:]),
+		})
+	}
+	return extracts, nil
+}
+
+// jsonPathValue returns the value at path in the unmarshalled JSON data. The path is dot separated,
+// array items are selected with their index: items[0].id or items.0.id. Objects and arrays are
+// returned as JSON
+func jsonPathValue(data interface{}, path string) (string, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.Replace(path, "[", ".", -1)
+	path = strings.Replace(path, "]", "", -1)
+
+	current := data
+	if path != "" {
+		for _, k := range strings.Split(path, ".") {
+			switch v := current.(type) {
+			case map[string]interface{}:
+				item, ok := v[k]
+				if !ok {
+					return "", fmt.Errorf("key %s not found", k)
+				}
+				current = item
+			case []interface{}:
+				i, err := strconv.Atoi(k)
+				if err != nil || i < 0 || i >= len(v) {
+					return "", fmt.Errorf("invalid index %s", k)
+				}
+				current = v[i]
+			default:
+				return "", fmt.Errorf("cannot select %s in a scalar value", k)
+			}
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", nil
+	}
+	b, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
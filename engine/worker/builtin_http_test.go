@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_runHTTPRequest(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 42})
+	}))
+	defer ts.Close()
+
+	a := &sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "method", Value: "post"},
+			{Name: "url", Value: ts.URL},
+			{Name: "headers", Value: "Content-Type: application/json"},
+			{Name: "body", Value: `{"version": "1"}`},
+			{Name: "bearerToken", Value: "mytoken"},
+			{Name: "retry", Value: "2"},
+			{Name: "retryDelay", Value: "0"},
+			{Name: "expectedStatus", Value: "201"},
+		},
+	}
+
+	w := &currentWorker{}
+	params := []sdk.Parameter{}
+	res := runHTTPRequest(w)(context.Background(), a, 1, &params, func(string) {})
+	assert.Equal(t, sdk.StatusSuccess.String(), res.Status)
+	assert.Equal(t, 3, calls)
+
+	// Not enough retries
+	calls = 0
+	a.Parameters[5].Value = "1"
+	res = runHTTPRequest(w)(context.Background(), a, 1, &params, func(string) {})
+	assert.Equal(t, sdk.StatusFail.String(), res.Status)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_parseExpectedStatus(t *testing.T) {
+	expected, err := parseExpectedStatus("")
+	assert.NoError(t, err)
+	assert.True(t, expected(204))
+	assert.False(t, expected(301))
+
+	expected, err = parseExpectedStatus("201, 300-399")
+	assert.NoError(t, err)
+	assert.True(t, expected(201))
+	assert.True(t, expected(302))
+	assert.False(t, expected(200))
+
+	_, err = parseExpectedStatus("2xx")
+	assert.Error(t, err)
+}
+
+func Test_jsonPathValue(t *testing.T) {
+	var data interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"data": {"id": 42, "name": "foo", "items": [{"id": "a"}, {"id": "b"}]}}`), &data))
+
+	tests := map[string]string{
+		"data.id":          "42",
+		"$.data.name":      "foo",
+		"data.items[1].id": "b",
+		"data.items.0.id":  "a",
+		"data.items[0]":    `{"id":"a"}`,
+	}
+	for path, expected := range tests {
+		v, err := jsonPathValue(data, path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, expected, v, path)
+	}
+
+	_, err := jsonPathValue(data, "data.unknown")
+	assert.Error(t, err)
+	_, err = jsonPathValue(data, "data.items[2]")
+	assert.Error(t, err)
+}
+
+func Test_parseHTTPExtracts(t *testing.T) {
+	extracts, err := parseHTTPExtracts("deployID=data.id\n\ncds.build.name = data.name\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []httpExtract{{"deployID", "data.id"}, {"name", "data.name"}}, extracts)
+
+	_, err = parseHTTPExtracts("deployID")
+	assert.Error(t, err)
+}
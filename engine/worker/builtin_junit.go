@@ -18,12 +18,6 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 		var res sdk.Result
 		res.Status = sdk.StatusFail.String()
 
-		pip := sdk.ParameterValue(*params, "cds.pipeline")
-		proj := sdk.ParameterValue(*params, "cds.project")
-		app := sdk.ParameterValue(*params, "cds.application")
-		envName := sdk.ParameterValue(*params, "cds.environment")
-		bnS := sdk.ParameterValue(*params, "cds.buildNumber")
-
 		p := sdk.ParameterValue(a.Parameters, "path")
 		if p == "" {
 			res.Reason = fmt.Sprintf("UnitTest parser: path not provided")
@@ -69,35 +63,45 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 			sendLog(r)
 		}
 
-		data, err := json.Marshal(tests)
-		if err != nil {
+		if err := w.sendTests(*params, tests); err != nil {
 			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
 			res.Status = sdk.StatusFail.String()
 			sendLog(res.Reason)
 			return res
 		}
 
-		var uri string
-		if w.currentJob.wJob != nil {
-			uri = fmt.Sprintf("/queue/workflows/%d/test", w.currentJob.wJob.ID)
-		} else {
-			uri = fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%s/test?envName=%s", proj, app, pip, bnS, url.QueryEscape(envName))
-		}
+		return res
+	}
+}
 
-		_, code, err := sdk.Request("POST", uri, data)
-		if err == nil && code > 300 {
-			err = fmt.Errorf("HTTP %d", code)
-		}
+// sendTests sends the tests results of the current job to the API
+func (w *currentWorker) sendTests(params []sdk.Parameter, tests venom.Tests) error {
+	if w.local.enabled {
+		return nil
+	}
 
-		if err != nil {
-			res.Reason = fmt.Sprintf("JUnit parse: failed to send tests details: %s", err)
-			res.Status = sdk.StatusFail.String()
-			sendLog(res.Reason)
-			return res
-		}
+	data, err := json.Marshal(tests)
+	if err != nil {
+		return err
+	}
 
-		return res
+	var uri string
+	if w.currentJob.wJob != nil {
+		uri = fmt.Sprintf("/queue/workflows/%d/test", w.currentJob.wJob.ID)
+	} else {
+		pip := sdk.ParameterValue(params, "cds.pipeline")
+		proj := sdk.ParameterValue(params, "cds.project")
+		app := sdk.ParameterValue(params, "cds.application")
+		envName := sdk.ParameterValue(params, "cds.environment")
+		bnS := sdk.ParameterValue(params, "cds.buildNumber")
+		uri = fmt.Sprintf("/project/%s/application/%s/pipeline/%s/build/%s/test?envName=%s", proj, app, pip, bnS, url.QueryEscape(envName))
+	}
+
+	_, code, err := sdk.Request("POST", uri, data)
+	if err == nil && code > 300 {
+		err = fmt.Errorf("HTTP %d", code)
 	}
+	return err
 }
 
 // computeStats computes failures / errors on testSuites,
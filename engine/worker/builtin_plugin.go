@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
+	"github.com/ovh/cds/sdk/plugin"
+)
+
+// pluginEventsFunc returns the events of a running plugin, io.EOF once the run is over
+type pluginEventsFunc func() (*actionplugin.ActionEvent, error)
+
+// gobPluginEvents runs a plugin speaking the net/rpc protocol. These plugins send their logs
+// by themselves and only return a status, so the only event is the result
+func (w *currentWorker) gobPluginEvents(p *grpcplugin.Plugin, pluginName string, query *actionplugin.ActionQuery) (pluginEventsFunc, error) {
+	var tlsskipverify bool
+	if os.Getenv("CDS_SKIP_VERIFY") != "" {
+		tlsskipverify = true
+	}
+
+	reattach, err := p.ReattachConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	//Create the rpc client
+	pluginClient := plugin.NewReattachClient(pluginName, p.Binary, reattach, w.id, w.apiEndpoint, tlsskipverify)
+
+	//Get the plugin interface
+	_plugin, err := pluginClient.Instance()
+	if err != nil {
+		pluginClient.Kill()
+		return nil, err
+	}
+
+	id := w.currentJob.pbJob.PipelineBuildID
+	if w.currentJob.wJob != nil {
+		id = w.currentJob.wJob.WorkflowNodeRunID
+	}
+
+	pluginAction := plugin.Job{
+		IDPipelineBuild:    id,
+		IDPipelineJobBuild: query.JobId,
+		OrderStep:          int(query.StepOrder),
+		Args:               plugin.Arguments{Data: query.Options},
+		Secrts:             plugin.Secrets{Data: query.Secrets},
+	}
+
+	var done bool
+	return func() (*actionplugin.ActionEvent, error) {
+		if done {
+			return nil, io.EOF
+		}
+		done = true
+		defer pluginClient.Kill()
+
+		//Call the Run function on the plugin interface
+		status := actionplugin.Fail
+		if _plugin.Run(pluginAction) == plugin.Success {
+			status = actionplugin.Success
+		}
+		return &actionplugin.ActionEvent{Result: &actionplugin.ActionResult{Status: status}}, nil
+	}, nil
+}
+
+// handlePluginEvents processes the events streamed by a plugin until its result
+func (w *currentWorker) handlePluginEvents(events pluginEventsFunc, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+	res := sdk.Result{Status: sdk.StatusFail.String()}
+
+	for {
+		e, err := events()
+		if err == io.EOF {
+			res.Reason = "Plugin exited without result"
+			sendLog(res.Reason)
+			return res
+		}
+		if err != nil {
+			res.Reason = fmt.Sprintf("Plugin failed: %s", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		switch {
+		case e.Result != nil:
+			if e.Result.Details != "" {
+				sendLog(e.Result.Details)
+			}
+			if e.Result.Status == actionplugin.Success {
+				res.Status = sdk.StatusSuccess.String()
+			} else {
+				res.Reason = e.Result.Details
+			}
+			return res
+		case e.Variable != nil:
+			name := e.Variable.Name
+			if !strings.HasPrefix(name, "cds.build.") {
+				name = "cds.build." + name
+			}
+			v := sdk.Variable{Name: name, Type: sdk.StringVariable, Value: e.Variable.Value}
+			if _, err := w.addVariableInPipelineBuild(v, params); err != nil {
+				res.Reason = fmt.Sprintf("Unable to set variable %s: %s", name, err)
+				sendLog(res.Reason)
+				return res
+			}
+		case e.Artifact != nil:
+			tag := e.Artifact.Tag
+			if tag == "" {
+				tag = sdk.ParameterValue(*params, "cds.version")
+			}
+			sendLog(fmt.Sprintf("Uploading artifact '%s' with tag %s", e.Artifact.Path, tag))
+			if err := w.uploadArtifact(buildID, tag, e.Artifact.Path, *params); err != nil {
+				res.Reason = fmt.Sprintf("Error while uploading artifact: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+		case e.Tests != nil:
+			tests := testsReportToVenom(e.Tests)
+			var testsRes sdk.Result
+			for _, r := range computeStats(&testsRes, &tests) {
+				sendLog(r)
+			}
+			if err := w.sendTests(*params, tests); err != nil {
+				res.Reason = fmt.Sprintf("Failed to send tests details: %s", err)
+				sendLog(res.Reason)
+				return res
+			}
+		default:
+			sendLog(e.Log)
+		}
+	}
+}
+
+// testsReportToVenom converts the tests reported by a plugin to the format parsed from junit files
+func testsReportToVenom(r *actionplugin.TestsReport) venom.Tests {
+	var tests venom.Tests
+	for _, s := range r.Suites {
+		ts := venom.TestSuite{Name: s.Name}
+		for _, c := range s.Cases {
+			tc := venom.TestCase{
+				Name:      c.Name,
+				Classname: c.Classname,
+				Time:      c.Time,
+				Systemout: venom.InnerResult{Value: c.Systemout},
+			}
+			for _, f := range c.Failures {
+				tc.Failures = append(tc.Failures, venom.Failure{Value: f})
+			}
+			for _, e := range c.Errors {
+				tc.Errors = append(tc.Errors, venom.Failure{Value: e})
+			}
+			if c.Skipped {
+				tc.Skipped = 1
+				ts.Skipped++
+			}
+			if len(tc.Failures) > 0 {
+				ts.Failures++
+			}
+			if len(tc.Errors) > 0 {
+				ts.Errors++
+			}
+			ts.TestCases = append(ts.TestCases, tc)
+		}
+		ts.Total = len(ts.TestCases) - ts.Skipped
+		tests.TestSuites = append(tests.TestSuites, ts)
+	}
+	return tests
+}
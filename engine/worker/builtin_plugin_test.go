@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
+)
+
+func Test_handlePluginEvents(t *testing.T) {
+	w := &currentWorker{}
+	logs := []string{}
+	sendLog := func(s string) { logs = append(logs, s) }
+
+	events := []*actionplugin.ActionEvent{
+		{Log: "first line"},
+		{Result: &actionplugin.ActionResult{Status: actionplugin.Success}},
+	}
+	next := func() (*actionplugin.ActionEvent, error) {
+		if len(events) == 0 {
+			return nil, io.EOF
+		}
+		e := events[0]
+		events = events[1:]
+		return e, nil
+	}
+
+	params := []sdk.Parameter{}
+	res := w.handlePluginEvents(next, 1, &params, sendLog)
+	assert.Equal(t, sdk.StatusSuccess.String(), res.Status)
+	assert.Equal(t, []string{"first line"}, logs)
+
+	// A plugin exiting without result fails
+	res = w.handlePluginEvents(next, 1, &params, sendLog)
+	assert.Equal(t, sdk.StatusFail.String(), res.Status)
+}
+
+func Test_testsReportToVenom(t *testing.T) {
+	tests := testsReportToVenom(&actionplugin.TestsReport{
+		Suites: []*actionplugin.TestSuite{
+			{
+				Name: "suite",
+				Cases: []*actionplugin.TestCase{
+					{Name: "ok"},
+					{Name: "ko", Failures: []string{"expected 1, got 2"}},
+					{Name: "skipped", Skipped: true},
+				},
+			},
+		},
+	})
+
+	res := sdk.Result{}
+	computeStats(&res, &tests)
+	assert.Equal(t, sdk.StatusFail.String(), res.Status)
+	assert.Equal(t, 3, tests.Total)
+	assert.Equal(t, 2, tests.TotalOK)
+	assert.Equal(t, 1, tests.TotalKO)
+	assert.Equal(t, 1, tests.TotalSkipped)
+}
@@ -19,7 +19,7 @@ import (
 
 func runScriptAction(w *currentWorker) BuiltInAction {
 	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
-		chanRes := make(chan sdk.Result)
+		chanRes := make(chan sdk.Result, 1)
 
 		go func() {
 			res := sdk.Result{Status: sdk.StatusSuccess.String()}
@@ -35,6 +35,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				res.Reason = fmt.Sprintf("script content not provided, aborting\n")
 				sendLog(res.Reason)
 				chanRes <- res
+				return
 			}
 
 			// Default shell is sh
@@ -70,6 +71,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			// Put script in file
@@ -84,6 +86,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			oldPath := tmpscript.Name()
@@ -117,10 +120,12 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			log.Info("runScriptAction> %s %s", shell, strings.Trim(fmt.Sprint(opts), "[]"))
-			cmd := exec.CommandContext(ctx, shell, opts...)
+			cmd := exec.Command(shell, opts...)
+			setProcessGroup(cmd)
 			res.Status = sdk.StatusUnknown.String()
 
 			env := os.Environ()
@@ -177,6 +182,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			log.Info("Worker binary path: %s", path.Dir(workerpath))
@@ -194,6 +200,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			stderr, err := cmd.StderrPipe()
@@ -203,6 +210,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			stdoutreader := bufio.NewReader(stdout)
@@ -239,15 +247,33 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
+			// Kill the script and all its children when the step is canceled or timed out
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				select {
+				case <-ctx.Done():
+					if err := killProcessGroup(cmd); err != nil {
+						log.Warning("runScriptAction> cannot kill script: %s", err)
+					}
+				case <-done:
+				}
+			}()
+
 			<-outchan
 			<-errchan
 			if err := cmd.Wait(); err != nil {
 				res.Reason = fmt.Sprintf("%s\n", err)
-				sendLog(res.Reason)
+				// The step result has already been returned if the script has been killed
+				if ctx.Err() == nil {
+					sendLog(res.Reason)
+				}
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
+				return
 			}
 
 			res.Status = sdk.StatusSuccess.String()
@@ -259,6 +285,13 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 		for {
 			select {
 			case <-ctx.Done():
+				if ctx.Err() == context.DeadlineExceeded {
+					sendLog("CDS Worker execution timed out")
+					return sdk.Result{
+						Status: sdk.StatusTimeout.String(),
+						Reason: "CDS Worker execution timed out",
+					}
+				}
 				log.Error("CDS Worker execution canceled: %v", ctx.Err())
 				sendLog("CDS Worker execution canceled")
 				return sdk.Result{
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is not supported on windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the process of the command
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runSign(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		path := sdk.ParameterValue(a.Parameters, "path")
+		signKey := sdk.ParameterValue(a.Parameters, "signKey")
+
+		if path == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("path variable is empty. aborting")
+			sendLog(res.Reason)
+			return res
+		}
+
+		if signKey == "" {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("signKey variable is empty. aborting")
+			sendLog(res.Reason)
+			return res
+		}
+
+		tag := sdk.ParameterFind(a.Parameters, "tag")
+		if tag == nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("tag variable is empty. aborting")
+			sendLog(res.Reason)
+			return res
+		}
+		tag.Value = strings.Replace(tag.Value, "/", "-", -1)
+		tag.Value = url.QueryEscape(tag.Value)
+
+		privateKey, _, _, errK := pgpKeyFromParams(*params, signKey)
+		if errK != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = errK.Error()
+			sendLog(res.Reason)
+			return res
+		}
+
+		// Global all files matching filePath
+		filesPath, err := filepath.Glob(path)
+		if err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("cannot perform globbing of pattern '%s': %s", path, err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if len(filesPath) == 0 {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Pattern '%s' matched no file", path)
+			sendLog(res.Reason)
+			return res
+		}
+
+		for _, filePath := range filesPath {
+			sendLog(fmt.Sprintf("Signing '%s' with key %s", filepath.Base(filePath), signKey))
+			sigPath, err := signFile(privateKey, filePath)
+			if err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Error while signing artifact: %s\n", err)
+				sendLog(res.Reason)
+				return res
+			}
+
+			sendLog(fmt.Sprintf("Uploading '%s'", filepath.Base(sigPath)))
+			if err := w.uploadArtifact(buildID, tag.Value, sigPath, *params); err != nil {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Error while uploading signature: %s\n", err)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		return res
+	}
+}
+
+// pgpKeyFromParams returns the armored private key, public key and key id of the
+// pgp key named keyName (ie. cds.proj.mykey) from the job parameters
+func pgpKeyFromParams(params []sdk.Parameter, keyName string) (string, string, string, error) {
+	privateKey := sdk.ParameterFind(params, fmt.Sprintf("%s.priv", keyName))
+	publicKey := sdk.ParameterFind(params, fmt.Sprintf("%s.pub", keyName))
+	keyID := sdk.ParameterFind(params, fmt.Sprintf("%s.id", keyName))
+
+	if privateKey == nil {
+		return "", "", "", fmt.Errorf("Cannot find pgp private key %s", keyName)
+	}
+	if publicKey == nil {
+		return "", "", "", fmt.Errorf("Cannot find pgp public key %s", keyName)
+	}
+	if keyID == nil {
+		return "", "", "", fmt.Errorf("Cannot find pgp key id %s", keyName)
+	}
+	return privateKey.Value, publicKey.Value, keyID.Value, nil
+}
+
+// signFile writes an armored detached signature of the file next to it and returns its path
+func signFile(privateKey, filePath string) (string, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(privateKey))
+	if err != nil {
+		return "", fmt.Errorf("Cannot read pgp private key: %s", err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return "", fmt.Errorf("No pgp private key found")
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	sigPath := filePath + sdk.ArtifactSignatureSuffix
+	sig, err := os.OpenFile(sigPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer sig.Close()
+
+	if err := openpgp.ArmoredDetachSign(sig, entities[0], f, nil); err != nil {
+		return "", fmt.Errorf("Cannot sign %s: %s", filePath, err)
+	}
+	return sigPath, nil
+}
+
+// verifyFile checks the armored detached signature sigPath of filePath against the public key
+func verifyFile(publicKey, filePath, sigPath string) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+	if err != nil {
+		return fmt.Errorf("Cannot read pgp public key: %s", err)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sig, err := os.Open(sigPath)
+	if err != nil {
+		return err
+	}
+	defer sig.Close()
+
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, f, sig); err != nil {
+		return fmt.Errorf("Invalid signature for %s: %s", filepath.Base(filePath), err)
+	}
+	return nil
+}
+
+// verifyArtifactSignatures checks that every downloaded artifact in dir has a valid detached signature
+func verifyArtifactSignatures(dir string, names []string, publicKey string, sendLog LoggerFunc) error {
+	downloaded := make(map[string]bool, len(names))
+	for _, n := range names {
+		downloaded[n] = true
+	}
+
+	for _, n := range names {
+		if strings.HasSuffix(n, sdk.ArtifactSignatureSuffix) {
+			continue
+		}
+		if !downloaded[n+sdk.ArtifactSignatureSuffix] {
+			return fmt.Errorf("Artifact %s is not signed", n)
+		}
+		if err := verifyFile(publicKey, filepath.Join(dir, n), filepath.Join(dir, n+sdk.ArtifactSignatureSuffix)); err != nil {
+			return err
+		}
+		sendLog(fmt.Sprintf("Signature of artifact %s verified", n))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_signAndVerifyArtifacts(t *testing.T) {
+	_, pub, priv, err := keys.GeneratePGPKeyPair("mykey")
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "Test_signAndVerifyArtifacts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "artifact.tar.gz")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("my artifact"), 0644))
+
+	sigPath, err := signFile(priv, file)
+	assert.NoError(t, err)
+	assert.Equal(t, file+sdk.ArtifactSignatureSuffix, sigPath)
+
+	sendLog := func(string) {}
+	names := []string{"artifact.tar.gz", "artifact.tar.gz" + sdk.ArtifactSignatureSuffix}
+	assert.NoError(t, verifyArtifactSignatures(dir, names, pub, sendLog))
+
+	// An artifact without signature is refused
+	assert.Error(t, verifyArtifactSignatures(dir, names[:1], pub, sendLog))
+
+	// A tampered artifact is refused
+	assert.NoError(t, ioutil.WriteFile(file, []byte("my tampered artifact"), 0644))
+	assert.Error(t, verifyArtifactSignatures(dir, names, pub, sendLog))
+}
+
+func Test_pgpKeyFromParams(t *testing.T) {
+	params := []sdk.Parameter{
+		{Name: "cds.proj.mykey.pub", Value: "pub"},
+		{Name: "cds.proj.mykey.id", Value: "id"},
+	}
+	_, _, _, err := pgpKeyFromParams(params, "cds.proj.mykey")
+	assert.Error(t, err)
+
+	params = append(params, sdk.Parameter{Name: "cds.proj.mykey.priv", Value: "priv"})
+	priv, pub, id, err := pgpKeyFromParams(params, "cds.proj.mykey")
+	assert.NoError(t, err)
+	assert.Equal(t, "priv", priv)
+	assert.Equal(t, "pub", pub)
+	assert.Equal(t, "id", id)
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/hcl"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// localBuiltinActions are the builtin actions which can run without API. The others are skipped by worker exec
+var localBuiltinActions = map[string]bool{
+	sdk.ArtifactUpload:    true,
+	sdk.ArtifactDownload:  true,
+	sdk.ScriptAction:      true,
+	sdk.JUnitAction:       true,
+	sdk.GitCloneAction:    true,
+	sdk.SignAction:        true,
+	sdk.HTTPRequestAction: true,
+}
+
+var (
+	cmdExecParams       []string
+	cmdExecArtifactsDir string
+	cmdExecBasedir      string
+)
+
+func cmdExec(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "exec",
+		Short: "worker exec <pipeline.yml>",
+		Long: `Run a pipeline on the local machine, without any CDS API.
+
+Stages are run in their order, jobs of a stage are run one after the other. GitClone steps clone the
+git repository of the current directory, artifacts are uploaded to and downloaded from a local directory.
+GitTag and Release steps are skipped.
+
+	$ worker exec --param name=value .cds/build.pip.yml`,
+		Run: execCmd(w),
+	}
+	c.Flags().StringSliceVarP(&cmdExecParams, "param", "p", nil, "Pipeline parameter name=value, it overrides the default value of the pipeline")
+	c.Flags().StringVar(&cmdExecArtifactsDir, "artifacts-dir", "", "Directory of the artifacts (default <basedir>/artifacts)")
+	c.Flags().StringVar(&cmdExecBasedir, "basedir", "", "This directory (default TMPDIR os environment var) will contains jobs working directories")
+	c.Flags().String("log-level", "notice", "Log Level : debug, info, notice, warning, critical")
+	return c
+}
+
+func execCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			sdk.Exit("Wrong usage: See '%s'\n", cmd.Short)
+		}
+
+		level, _ := cmd.Flags().GetString("log-level")
+		log.Initialize(&log.Conf{Level: level})
+
+		pip, err := readLocalPipeline(args[0])
+		if err != nil {
+			sdk.Exit("Unable to read pipeline %s: %s\n", args[0], err)
+		}
+
+		params, err := localPipelineParameters(pip, cmdExecParams)
+		if err != nil {
+			sdk.Exit("%s\n", err)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("Unable to get current directory: %s\n", err)
+		}
+		params = append(params, localGitParameters(cwd)...)
+
+		basedir := cmdExecBasedir
+		if basedir == "" {
+			basedir, err = ioutil.TempDir("", "cds-exec")
+			if err != nil {
+				sdk.Exit("Unable to create base directory: %s\n", err)
+			}
+		}
+		artifactsDir := cmdExecArtifactsDir
+		if artifactsDir == "" {
+			artifactsDir = filepath.Join(basedir, "artifacts")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		defer func() {
+			signal.Stop(c)
+			cancel()
+		}()
+		go func() {
+			select {
+			case <-c:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		if err := w.initLocal(ctx, basedir, artifactsDir, sdk.ParameterValue(params, "git.url")); err != nil {
+			sdk.Exit("%s\n", err)
+		}
+
+		ok := w.execPipeline(ctx, pip, params)
+		fmt.Printf("Artifacts are available in %s\n", artifactsDir)
+		if !ok {
+			os.Exit(1)
+		}
+	}
+}
+
+// readLocalPipeline reads an exported pipeline from a yaml, json or hcl file
+func readLocalPipeline(filename string) (*sdk.Pipeline, error) {
+	data, format, err := exportentities.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &exportentities.Pipeline{}
+	switch format {
+	case exportentities.FormatJSON, exportentities.FormatHCL:
+		err = hcl.Unmarshal(data, payload)
+	default:
+		err = yaml.Unmarshal(data, payload)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payload.Pipeline()
+}
+
+// localPipelineParameters returns the parameters of a local run: the pipeline parameters, overridden by
+// the values given as name=value, and the cds.* parameters usually set by the API
+func localPipelineParameters(pip *sdk.Pipeline, values []string) ([]sdk.Parameter, error) {
+	params := make([]sdk.Parameter, len(pip.Parameter))
+	copy(params, pip.Parameter)
+
+	for _, v := range values {
+		t := strings.SplitN(v, "=", 2)
+		if len(t) != 2 || t[0] == "" {
+			return nil, fmt.Errorf("Invalid parameter '%s', format is name=value", v)
+		}
+		name := strings.TrimPrefix(t[0], "cds.pip.")
+		var found bool
+		for i := range params {
+			if params[i].Name == name {
+				params[i].Value = t[1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			params = append(params, sdk.Parameter{Name: name, Type: sdk.StringParameter, Value: t[1]})
+		}
+	}
+
+	// Pipeline parameters are prefixed by cds.pip like in the API
+	for i := range params {
+		params[i].Name = "cds.pip." + params[i].Name
+	}
+
+	for _, p := range []sdk.Parameter{
+		{Name: "cds.project", Value: "local"},
+		{Name: "cds.workflow", Value: "local"},
+		{Name: "cds.pipeline", Value: pip.Name},
+		{Name: "cds.version", Value: "1"},
+		{Name: "cds.run.number", Value: "1"},
+		{Name: "cds.buildNumber", Value: "1"},
+		{Name: "cds.worker", Value: "local"},
+	} {
+		p.Type = sdk.StringParameter
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// localGitParameters returns the git.* parameters of the repository containing dir
+func localGitParameters(dir string) []sdk.Parameter {
+	gitCmd := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	url := gitCmd("rev-parse", "--show-toplevel")
+	if url == "" {
+		return nil
+	}
+
+	params := []sdk.Parameter{}
+	for _, p := range []sdk.Parameter{
+		{Name: "git.url", Value: url},
+		{Name: "git.http_url", Value: url},
+		{Name: "git.branch", Value: gitCmd("rev-parse", "--abbrev-ref", "HEAD")},
+		{Name: "git.hash", Value: gitCmd("rev-parse", "HEAD")},
+		{Name: "git.author", Value: gitCmd("log", "-1", "--format=%an")},
+		{Name: "git.message", Value: gitCmd("log", "-1", "--format=%s")},
+	} {
+		p.Type = sdk.StringParameter
+		params = append(params, p)
+	}
+	return params
+}
+
+// initLocal prepares the worker to run jobs without API
+func (w *currentWorker) initLocal(ctx context.Context, basedir, artifactsDir, gitDir string) error {
+	if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		return fmt.Errorf("Unable to create artifacts directory: %s", err)
+	}
+
+	w.basedir = basedir
+	w.local.enabled = true
+	w.local.gitDir = gitDir
+	w.local.artifactsDir = artifactsDir
+	// Jobs are run as workflow jobs, the workflow builtin actions don't need the pipeline build parameters
+	w.currentJob.wJob = &sdk.WorkflowNodeJobRun{}
+
+	port, err := w.serve(ctx)
+	if err != nil {
+		return fmt.Errorf("Cannot bind port for worker export: %s", err)
+	}
+	w.exportPort = port
+	return nil
+}
+
+// execPipeline runs all the stages of the pipeline, it stops after the first stage with a failed job
+func (w *currentWorker) execPipeline(ctx context.Context, pip *sdk.Pipeline, params []sdk.Parameter) bool {
+	stages := make([]sdk.Stage, len(pip.Stages))
+	copy(stages, pip.Stages)
+	sort.Slice(stages, func(i, j int) bool {
+		return stages[i].BuildOrder < stages[j].BuildOrder
+	})
+
+	for _, s := range stages {
+		if !s.Enabled {
+			fmt.Printf("Stage %s [Disabled]\n", s.Name)
+			continue
+		}
+
+		ok, err := sdk.WorkflowCheckConditions(s.Conditions(), params)
+		if err != nil {
+			fmt.Printf("Stage %s: invalid conditions: %s\n", s.Name, err)
+			return false
+		}
+		if !ok {
+			fmt.Printf("Stage %s [Skipped]\n", s.Name)
+			continue
+		}
+
+		fmt.Printf("Stage %s\n", s.Name)
+		success := true
+		for _, j := range s.Jobs {
+			if !j.Enabled {
+				fmt.Printf("Job %s [Disabled]\n", j.Action.Name)
+				continue
+			}
+			if j.Action.Condition != "" {
+				ok, err := sdk.EvaluateExpression(j.Action.Condition, sdk.ParametersToMap(params))
+				if err != nil {
+					fmt.Printf("Job %s: invalid condition: %s\n", j.Action.Name, err)
+					return false
+				}
+				if !ok {
+					fmt.Printf("Job %s [Skipped]\n", j.Action.Name)
+					continue
+				}
+			}
+			res := w.execJob(ctx, s, j, params)
+			fmt.Printf("Job %s [%s] %s\n", j.Action.Name, res.Status, res.Reason)
+			if res.Status != sdk.StatusSuccess.String() && res.Status != sdk.StatusDisabled.String() {
+				success = false
+			}
+		}
+		if !success {
+			return false
+		}
+	}
+	return true
+}
+
+// execJob runs a job in its own working directory, like processJob does for the jobs taken from the queue
+func (w *currentWorker) execJob(ctx context.Context, s sdk.Stage, j sdk.Job, params []sdk.Parameter) sdk.Result {
+	wd := workingDirectory(w.basedir, path.Join("exec", s.Name, j.Action.Name))
+	if err := os.MkdirAll(wd, 0755); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot setup working directory: %s", err),
+		}
+	}
+	defer teardownBuildDirectory(wd)
+
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	if err := os.Chdir(wd); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot setup working directory: %s", err),
+		}
+	}
+
+	keysDirectory = workingDirectory(w.basedir, path.Join("exec", s.Name, j.Action.Name))
+	if err := os.MkdirAll(keysDirectory, 0755); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot setup keys directory: %s", err),
+		}
+	}
+	defer teardownBuildDirectory(keysDirectory)
+
+	jobParams := make([]sdk.Parameter, len(params))
+	copy(jobParams, params)
+	jobParams = append(jobParams,
+		sdk.Parameter{Name: "cds.workspace", Type: sdk.StringParameter, Value: wd},
+		sdk.Parameter{Name: "cds.stage", Type: sdk.StringParameter, Value: s.Name},
+		sdk.Parameter{Name: "cds.job", Type: sdk.StringParameter, Value: j.Action.Name},
+	)
+	// Build variables exported by the previous jobs
+	for _, v := range w.currentJob.buildVariables {
+		jobParams = append(jobParams, sdk.Parameter{Name: v.Name, Type: v.Type, Value: v.Value})
+	}
+
+	processJobParameter(&jobParams, nil)
+
+	action := j.Action
+	if err := w.processActionVariables(&action, nil, jobParams, nil); err != nil {
+		return sdk.Result{
+			Status: sdk.StatusFail.String(),
+			Reason: fmt.Sprintf("Error: cannot process action %s parameters", action.Name),
+		}
+	}
+
+	return w.startAction(ctx, &action, 0, &jobParams, -1, "")
+}
+
+// copyFile copies the file src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// downloadLocalArtifacts copies all the artifacts uploaded by worker exec into dir and returns their names
+func downloadLocalArtifacts(artifactsDir, dir string) ([]string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(artifactsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		if err := copyFile(filepath.Join(artifactsDir, f.Name()), filepath.Join(dir, f.Name())); err != nil {
+			return nil, fmt.Errorf("Cannot download artifact %s: %s", f.Name(), err)
+		}
+		names = append(names, f.Name())
+	}
+	return names, nil
+}
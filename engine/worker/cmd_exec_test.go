@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const execTestPipeline = `
+name: build
+parameters:
+  name:
+    type: string
+    default: world
+stages:
+  1|build:
+    jobs:
+      compile:
+        steps:
+        - script: echo "hello {{.cds.pip.name}}" > out.txt
+        - artifactUpload:
+            path: out.txt
+            tag: "{{.cds.version}}"
+  2|test:
+    jobs:
+      check:
+        steps:
+        - artifactDownload:
+            path: dl
+            tag: "{{.cds.version}}"
+        - script: grep "hello cds" dl/out.txt
+  3|deploy:
+    conditions:
+      cds.pip.name: prod
+    jobs:
+      deploy:
+        steps:
+        - script: exit 1
+`
+
+func Test_execPipeline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "Test_execPipeline")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "build.pip.yml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(execTestPipeline), 0644))
+
+	pip, err := readLocalPipeline(file)
+	assert.NoError(t, err)
+
+	params, err := localPipelineParameters(pip, []string{"name=cds"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cds", sdk.ParameterValue(params, "cds.pip.name"))
+	assert.Equal(t, "1", sdk.ParameterValue(params, "cds.version"))
+
+	_, err = localPipelineParameters(pip, []string{"name"})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &currentWorker{}
+	artifactsDir := filepath.Join(dir, "artifacts")
+	assert.NoError(t, w.initLocal(ctx, dir, artifactsDir, ""))
+
+	// The deploy stage is skipped by its condition
+	assert.True(t, w.execPipeline(ctx, pip, params))
+
+	b, err := ioutil.ReadFile(filepath.Join(artifactsDir, "out.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello cds\n", string(b))
+
+	params, err = localPipelineParameters(pip, []string{"name=prod"})
+	assert.NoError(t, err)
+	assert.False(t, w.execPipeline(ctx, pip, params))
+}
+
+const execTimeoutTestPipeline = `
+name: timeout
+parameters:
+  out:
+    type: string
+stages:
+  1|build:
+    jobs:
+      step:
+        steps:
+        - script: sleep 30
+          timeout: 1s
+        - script: echo step > {{.cds.pip.out}}/step.txt
+          always_executed: true
+      job:
+        timeout: 1s
+        steps:
+        - script: sleep 30
+        - script: echo job > {{.cds.pip.out}}/job.txt
+          always_executed: true
+`
+
+func Test_execPipelineTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "Test_execPipelineTimeout")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "timeout.pip.yml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(execTimeoutTestPipeline), 0644))
+
+	pip, err := readLocalPipeline(file)
+	assert.NoError(t, err)
+	for _, j := range pip.Stages[0].Jobs {
+		if j.Action.Name == "job" {
+			assert.Equal(t, int64(1), j.Action.Timeout)
+		} else {
+			assert.Equal(t, int64(1), j.Action.Actions[0].Timeout)
+		}
+	}
+
+	params, err := localPipelineParameters(pip, []string{"out=" + dir})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &currentWorker{}
+	assert.NoError(t, w.initLocal(ctx, dir, filepath.Join(dir, "artifacts"), ""))
+
+	start := time.Now()
+	assert.False(t, w.execPipeline(ctx, pip, params))
+	assert.True(t, time.Since(start) < 10*time.Second)
+
+	// The always executed steps have been run after the timeouts
+	b, err := ioutil.ReadFile(filepath.Join(dir, "step.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "step\n", string(b))
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "job.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "job\n", string(b))
+}
+
+const execConditionTestPipeline = `
+name: conditions
+parameters:
+  out:
+    type: string
+stages:
+  1|build:
+    jobs:
+      build:
+        steps:
+        - script: exit 1
+          optional: true
+        - script: echo success > {{.cds.pip.out}}/success.txt
+          if: previous.status == "Success"
+        - script: echo failure > {{.cds.pip.out}}/failure.txt
+          if: previous.status == "Fail"
+        - script: exit 1
+        - script: echo failed > {{.cds.pip.out}}/failed.txt
+          if: job.status == "Fail"
+      deploy:
+        if: cds.pip.out == ""
+        steps:
+        - script: exit 1
+`
+
+func Test_execPipelineConditions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "Test_execPipelineConditions")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "conditions.pip.yml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(execConditionTestPipeline), 0644))
+
+	pip, err := readLocalPipeline(file)
+	assert.NoError(t, err)
+
+	params, err := localPipelineParameters(pip, []string{"out=" + dir})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &currentWorker{}
+	assert.NoError(t, w.initLocal(ctx, dir, filepath.Join(dir, "artifacts"), ""))
+
+	// The build job fails, the deploy job is skipped
+	assert.False(t, w.execPipeline(ctx, pip, params))
+
+	_, err = os.Stat(filepath.Join(dir, "failure.txt"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "success.txt"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "failed.txt"))
+	assert.NoError(t, err)
+}
@@ -100,6 +100,10 @@ func (wk *currentWorker) addVariableInPipelineBuild(v sdk.Variable, params *[]sd
 		})
 	}
 
+	if wk.local.enabled {
+		return http.StatusOK, nil
+	}
+
 	// - add it in current building Action
 	data, errm := json.Marshal(v)
 	if errm != nil {
@@ -41,12 +41,18 @@ func cmdMain(w *currentWorker) *cobra.Command {
 	pflags.Int("model", 0, "Model of worker")
 	viper.BindPFlag("model", pflags.Lookup("model"))
 
+	pflags.Int64("model-version", 0, "Version of the model of worker")
+	viper.BindPFlag("model_version", pflags.Lookup("model-version"))
+
 	pflags.Int("hatchery", 0, "Hatchery ID spawing worker")
 	viper.BindPFlag("hatchery", pflags.Lookup("hatchery"))
 
 	pflags.String("hatchery-name", "", "Hatchery Name spawing worker")
 	viper.BindPFlag("hatchery_name", pflags.Lookup("hatchery-name"))
 
+	pflags.String("labels", "", "Labels declared by the worker, comma separated. Ex: --labels=pool=high-cpu,gpu")
+	viper.BindPFlag("labels", pflags.Lookup("labels"))
+
 	flags := mainCmd.Flags()
 
 	flags.Bool("single-use", false, "Exit after executing an action")
@@ -181,7 +187,7 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				return
 			}
 
-			if !w.alive && viper.GetBool("single_use") {
+			if !w.alive && (viper.GetBool("single_use") || w.drained) {
 				registerTick.Stop()
 				defer cancel()
 				w.drainLogsAndCloseLogger(ctx)
@@ -192,7 +198,11 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 					log.Info("Waiting 30min to be killed by hatchery, if not killed, worker will exit")
 					time.Sleep(30 * time.Minute)
 				}
-				log.Info("Exiting single-use worker")
+				if w.drained {
+					log.Info("Exiting drained worker")
+				} else {
+					log.Info("Exiting single-use worker")
+				}
 				return
 			}
 
@@ -233,6 +243,9 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				}
 
 				if !viper.GetBool("single_use") {
+					if w.drain() {
+						continue
+					}
 					//Continue
 					w.client.WorkerSetStatus(sdk.StatusWaiting)
 					continue
@@ -268,6 +281,9 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				}
 
 				if !viper.GetBool("single_use") {
+					if w.drain() {
+						continue
+					}
 					//Continue
 					w.client.WorkerSetStatus(sdk.StatusWaiting)
 					continue
@@ -283,6 +299,9 @@ func mainCommandRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 				log.Error("%v", err)
 
 			case <-registerTick.C:
+				if w.drain() {
+					continue
+				}
 				w.doRegister()
 			}
 		}
@@ -323,6 +342,28 @@ func (w *currentWorker) processBookedJob(pbjobs chan<- sdk.PipelineBuildJob) {
 	pbjobs <- *j
 }
 
+// drain unregisters the worker if it is in maintenance, the worker exits instead of taking a new job
+func (w *currentWorker) drain() bool {
+	if w.id == "" {
+		return false
+	}
+	maintenance, err := w.client.WorkerRefresh()
+	if err != nil {
+		log.Warning("drain> cannot refresh worker: %s", err)
+		return false
+	}
+	if !maintenance {
+		return false
+	}
+
+	log.Info("Worker is in maintenance. Unregistering...")
+	w.drained = true
+	if err := w.unregister(); err != nil {
+		log.Warning("drain> could not unregister: %s", err)
+	}
+	return true
+}
+
 func (w *currentWorker) doRegister() error {
 	if w.id == "" {
 		var info string
@@ -336,6 +377,7 @@ func (w *currentWorker) doRegister() error {
 			Hatchery:     w.hatchery.id,
 			HatcheryName: w.hatchery.name,
 			ModelID:      w.model.ID,
+			ModelVersion: w.model.Version,
 		}
 		if err := w.register(form); err != nil {
 			log.Info("Cannot register: %s", err)
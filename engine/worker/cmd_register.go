@@ -27,6 +27,8 @@ func cmdRegisterRun(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			Hatchery:     w.hatchery.id,
 			HatcheryName: w.hatchery.name,
 			ModelID:      w.model.ID,
+			ModelVersion: w.model.Version,
+			RegisterOnly: true,
 		}
 		if err := w.register(form); err != nil {
 			log.Error("Unable to register worker: %s", err)
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/viper"
 	"golang.org/x/net/context"
@@ -59,7 +60,13 @@ func initViper(w *currentWorker) {
 		os.Exit(4)
 	}
 
-	w.model = sdk.Model{ID: int64(viper.GetInt("model"))}
+	w.model = sdk.Model{ID: int64(viper.GetInt("model")), Version: viper.GetInt64("model_version")}
+
+	for _, l := range strings.Split(viper.GetString("labels"), ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			w.labels = append(w.labels, l)
+		}
+	}
 
 	w.basedir = viper.GetString("basedir")
 	if w.basedir == "" {
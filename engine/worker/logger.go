@@ -25,6 +25,14 @@ func (w *currentWorker) sendLog(buildID int64, value string, stepOrder int, fina
 		}
 	}
 
+	if w.local.enabled {
+		fmt.Printf("[%d] %s", stepOrder, value)
+		if !strings.HasSuffix(value, "\n") {
+			fmt.Println()
+		}
+		return nil
+	}
+
 	var id = w.currentJob.pbJob.PipelineBuildID
 	if w.currentJob.wJob != nil {
 		id = w.currentJob.wJob.WorkflowNodeRunID
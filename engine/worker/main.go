@@ -11,10 +11,12 @@ import (
 
 type currentWorker struct {
 	alive         bool
+	drained       bool
 	apiEndpoint   string
 	token         string
 	id            string
 	model         sdk.Model
+	labels        []string
 	groupID       int64
 	bookedJobID   int64
 	nbActionsDone int
@@ -46,6 +48,12 @@ type currentWorker struct {
 		Status string `json:"status"`
 	}
 	client cdsclient.Interface
+	// local is set by worker exec, the job runs without any API
+	local struct {
+		enabled      bool
+		gitDir       string
+		artifactsDir string
+	}
 }
 
 func main() {
@@ -54,6 +62,7 @@ func main() {
 	w := &currentWorker{}
 	cmd := cmdMain(w)
 	cmd.AddCommand(cmdExport)
+	cmd.AddCommand(cmdExec(w))
 	cmd.AddCommand(cmdUpload(w))
 	cmd.AddCommand(cmdTmpl(w))
 	cmd.AddCommand(cmdVersion)
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os/exec"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -23,9 +27,13 @@ func (w *currentWorker) register(form worker.RegistrationForm) error {
 
 	log.Debug("Checking %d requirements", len(requirements))
 	form.BinaryCapabilities = LoopPath(w, requirements)
+	if form.RegisterOnly {
+		form.ToolVersions = toolVersions(form.BinaryCapabilities)
+	}
 	form.Version = sdk.VERSION
 	form.OS = runtime.GOOS
-	form.Arch = runtime.GOOS
+	form.Arch = runtime.GOARCH
+	form.Labels = w.labels
 
 	worker, uptodate, err := w.client.WorkerRegister(form)
 	if err != nil {
@@ -64,6 +72,30 @@ func (w *currentWorker) unregister() error {
 	return nil
 }
 
+// toolVersions returns the version of each binary, given by its --version option
+func toolVersions(binaries []string) map[string]string {
+	versions := map[string]string{}
+	for _, b := range binaries {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		out, err := exec.CommandContext(ctx, b, "--version").CombinedOutput()
+		cancel()
+		if err != nil {
+			log.Debug("toolVersions> unable to get version of %s: %s", b, err)
+			continue
+		}
+		for _, l := range strings.Split(string(out), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				if len(l) > 128 {
+					l = l[:128]
+				}
+				versions[b] = l
+				break
+			}
+		}
+	}
+	return versions
+}
+
 // LoopPath return the list of evailable command in path
 func LoopPath(w *currentWorker, reqs []sdk.Requirement) []string {
 	binaries := []string{}
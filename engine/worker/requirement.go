@@ -7,14 +7,16 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"runtime"
 	"strconv"
 	"time"
 
 	"github.com/shirou/gopsutil/mem"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
 	"github.com/ovh/cds/sdk/log"
-	"github.com/ovh/cds/sdk/plugin"
 )
 
 var requirementCheckFuncs = map[string]func(w *currentWorker, r sdk.Requirement) (bool, error){
@@ -25,6 +27,8 @@ var requirementCheckFuncs = map[string]func(w *currentWorker, r sdk.Requirement)
 	sdk.PluginRequirement:        checkPluginRequirement,
 	sdk.ServiceRequirement:       checkServiceRequirement,
 	sdk.MemoryRequirement:        checkMemoryRequirement,
+	sdk.OSArchRequirement:        checkOSArchRequirement,
+	sdk.LabelRequirement:         checkLabelRequirement,
 }
 
 func checkRequirements(w *currentWorker, a *sdk.Action, execGroups []sdk.Group, bookedJobID int64) (bool, []sdk.Requirement) {
@@ -87,15 +91,19 @@ func checkPluginRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 		}
 	}
 
-	pluginClient := plugin.NewClient(context.Background(), r.Name, pluginBinary, "", "", false)
-	defer pluginClient.Kill()
+	p, err := grpcplugin.Start(context.Background(), pluginBinary)
+	if err != nil {
+		log.Warning("[WARNING] Error Checkin %s requirement : %s", r.Name, err)
+		return false, err
+	}
+	defer p.Kill()
 
-	_plugin, err := pluginClient.Instance()
+	manifest, err := actionplugin.Manifest(context.Background(), p, r.Name)
 	if err != nil {
 		log.Warning("[WARNING] Error Checkin %s requirement : %s", r.Name, err)
 		return false, err
 	}
-	log.Warning("[NOTICE] Plugin %s successfully started", _plugin.Name())
+	log.Warning("[NOTICE] Plugin %s successfully started (protocol version %d)", manifest.Name, p.Version)
 
 	return true, nil
 }
@@ -169,3 +177,22 @@ func checkMemoryRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
 	//If we have more than 90% of neededMemory, lets do it
 	return int64(totalMemory) >= (neededMemory*1024*1024)*90/100, nil
 }
+
+func checkOSArchRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	return r.Value == runtime.GOOS+"/"+runtime.GOARCH, nil
+}
+
+func checkLabelRequirement(w *currentWorker, r sdk.Requirement) (bool, error) {
+	for _, l := range w.labels {
+		if l == r.Value {
+			return true, nil
+		}
+	}
+	// Labels declared by the model of the worker
+	for _, c := range w.model.Capabilities {
+		if c.Type == sdk.LabelRequirement && c.Value == r.Value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
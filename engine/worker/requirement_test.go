@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"runtime"
 	"testing"
 
 	"github.com/ovh/cds/sdk"
@@ -89,3 +90,51 @@ func TestNetworkAccessRequirement(t *testing.T) {
 		t.Fatalf("Requirement should not be ok")
 	}
 }
+
+func TestCheckLabelRequirement(t *testing.T) {
+	w := &currentWorker{labels: []string{"gpu"}}
+	w.model.Capabilities = []sdk.Requirement{{Name: "pool=high-cpu", Type: sdk.LabelRequirement, Value: "pool=high-cpu"}}
+
+	for _, l := range []string{"gpu", "pool=high-cpu"} {
+		ok, err := checkRequirement(w, sdk.Requirement{Name: l, Type: sdk.LabelRequirement, Value: l})
+		if err != nil {
+			t.Fatalf("checkRequirement should not fail: %s", err)
+		}
+		if !ok {
+			t.Fatalf("Requirement %s should be ok", l)
+		}
+	}
+
+	ok, err := checkRequirement(w, sdk.Requirement{Name: "arm", Type: sdk.LabelRequirement, Value: "arm"})
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement arm should not be ok")
+	}
+}
+
+func TestCheckOSArchRequirement(t *testing.T) {
+	r := sdk.Requirement{
+		Name:  sdk.OSArchRequirement,
+		Type:  sdk.OSArchRequirement,
+		Value: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	ok, err := checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if !ok {
+		t.Fatalf("Requirement should be ok")
+	}
+
+	r.Value = "plan9/mips"
+	ok, err = checkRequirement(nil, r)
+	if err != nil {
+		t.Fatalf("checkRequirement should not fail: %s", err)
+	}
+	if ok {
+		t.Fatalf("Requirement should not be ok")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,33 +19,49 @@ import (
 	"github.com/ovh/cds/sdk/vcs"
 )
 
+// alwaysExecutedStepTimeout is the timeout of the steps which are always executed
+// after the job has timed out, when they don't have their own timeout
+var alwaysExecutedStepTimeout = 10 * time.Minute
+
 func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
 	parameters := *params
 
-	for i := range parameters {
-		keepReplacing := true
-		for keepReplacing {
-			t := parameters[i].Value
-
-			for _, p := range parameters {
-				parameters[i].Value = strings.Replace(parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
-
-			for _, p := range secrets {
-				parameters[i].Value = strings.Replace(parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
+	vars := map[string]string{}
+	for _, p := range secrets {
+		vars[p.Name] = p.Value
+	}
+	for _, p := range parameters {
+		vars[p.Name] = p.Value
+	}
 
-			// If parameters wasn't updated, consider it done
-			if parameters[i].Value == t {
-				keepReplacing = false
-			}
+	for i := range parameters {
+		s, err := interpolateValue(parameters[i].Value, vars)
+		if err != nil {
+			log.Warning("processJobParameter> Unable to interpolate parameter %s: %v", parameters[i].Name, err)
+			continue
 		}
+		parameters[i].Value = s
 	}
 
 	params = &parameters
 	return
 }
 
+// interpolateValue interpolates the value with vars until it is not updated anymore
+func interpolateValue(value string, vars map[string]string) (string, error) {
+	for {
+		s, err := sdk.Interpolate(value, vars)
+		if err != nil {
+			return value, err
+		}
+		// If value wasn't updated, consider it done
+		if s == value {
+			return s, nil
+		}
+		value = s
+	}
+}
+
 // ProcessActionVariables replaces all placeholders inside action recursively using
 // - parent parameters
 // - action build arguments
@@ -54,36 +71,31 @@ func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
 func (w *currentWorker) processActionVariables(a *sdk.Action, parent *sdk.Action, jobParameters []sdk.Parameter, secrets []sdk.Variable) error {
 	// replaces placeholder in parameters with ActionBuild variables
 	// replaces placeholder in parameters with Parent params
-	for i := range a.Parameters {
-		keepReplacing := true
-		for keepReplacing {
-			t := a.Parameters[i].Value
-
-			if parent != nil {
-				for _, p := range parent.Parameters {
-					a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-				}
-			}
-
-			for _, p := range jobParameters {
-				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
-
-			for _, p := range secrets {
-				a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+p.Name+"}}", p.Value, -1)
-			}
+	vars := map[string]string{}
+	for _, p := range secrets {
+		vars[p.Name] = p.Value
+	}
+	for _, p := range jobParameters {
+		vars[p.Name] = p.Value
+	}
+	if parent != nil {
+		for _, p := range parent.Parameters {
+			vars[p.Name] = p.Value
+		}
+	}
 
-			// If parameters wasn't updated, consider it done
-			if a.Parameters[i].Value == t {
-				keepReplacing = false
-			}
+	for i := range a.Parameters {
+		s, err := interpolateValue(a.Parameters[i].Value, vars)
+		if err != nil {
+			return fmt.Errorf("unable to interpolate parameter %s of action %s: %v", a.Parameters[i].Name, a.Name, err)
 		}
+		a.Parameters[i].Value = s
 	}
 
 	// replaces placeholder in all children recursively
 	for i := range a.Actions {
 		if err := w.processActionVariables(&a.Actions[i], a, jobParameters, secrets); err != nil {
-			return nil
+			return err
 		}
 	}
 
@@ -103,22 +115,52 @@ func (w *currentWorker) startAction(ctx context.Context, a *sdk.Action, buildID
 	return w.runJob(ctx, a, buildID, params, stepOrder, stepName)
 }
 
-func (w *currentWorker) replaceVariablesPlaceholder(a *sdk.Action, params []sdk.Parameter) {
+// replaceVariablesPlaceholder replaces the placeholders of the action parameters with the build variables
+// and the parameters, it returns the names of the variables which are still unknown
+func (w *currentWorker) replaceVariablesPlaceholder(a *sdk.Action, params []sdk.Parameter) ([]string, error) {
+	vars := map[string]string{}
+	for _, v := range params {
+		vars[v.Name] = v.Value
+	}
+	for _, v := range w.currentJob.buildVariables {
+		vars[v.Name] = v.Value
+	}
+
+	unknown := map[string]struct{}{}
 	for i := range a.Parameters {
-		for _, v := range w.currentJob.buildVariables {
-			a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+v.Name+"}}", v.Value, -1)
+		s, names, err := sdk.InterpolateWithUnknown(a.Parameters[i].Value, vars)
+		if err != nil {
+			return nil, fmt.Errorf("unable to interpolate parameter %s: %v", a.Parameters[i].Name, err)
 		}
-		for _, v := range params {
-			a.Parameters[i].Value = strings.Replace(a.Parameters[i].Value, "{{."+v.Name+"}}", v.Value, -1)
+		a.Parameters[i].Value = s
+		for _, n := range names {
+			unknown[n] = struct{}{}
 		}
 	}
+
+	var names []string
+	for n := range unknown {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, nil
 }
 
 func (w *currentWorker) runJob(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, stepName string) sdk.Result {
 	log.Debug("runJob> start run %d stepOrder:%d", buildID, stepOrder)
 	defer log.Debug("runJob> end run %d stepOrder:%d", buildID, stepOrder)
 	// Replace variable placeholder that may have been added by last step
-	w.replaceVariablesPlaceholder(a, *params)
+	unknown, err := w.replaceVariablesPlaceholder(a, *params)
+	if err != nil {
+		return sdk.Result{
+			Status:  sdk.StatusFail.String(),
+			Reason:  err.Error(),
+			BuildID: buildID,
+		}
+	}
+	if len(unknown) > 0 && stepOrder >= 0 {
+		w.sendLog(buildID, fmt.Sprintf("Warning: unknown variables %s\n", strings.Join(unknown, ", ")), stepOrder, false)
+	}
 	// Set the params
 	w.currentJob.params = *params
 	// Unset the params at the end
@@ -155,15 +197,43 @@ func (w *currentWorker) runJob(ctx context.Context, a *sdk.Action, buildID int64
 		}
 	}
 
+	// The job timeout is handled here, steps timeouts are handled in runSteps
+	if stepOrder == -1 && a.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(a.Timeout)*time.Second)
+		defer cancel()
+	}
+
 	r, nDisabled := w.runSteps(ctx, a.Actions, a, buildID, params, stepOrder, stepName, 0)
 	//If all steps are disabled, set action status to disabled
 	if nDisabled >= len(a.Actions) {
 		r.Status = sdk.StatusDisabled.String()
 	}
 
+	if stepOrder == -1 && ctx.Err() == context.DeadlineExceeded {
+		r.Status = sdk.StatusFail.String()
+		r.Reason = fmt.Sprintf("Job timed out after %s", time.Duration(a.Timeout)*time.Second)
+	}
+
 	return r
 }
 
+// stepContext returns the context of a step with its own timeout. The steps which are always
+// executed get a new context when the job has timed out, so that cleanup steps can still run
+func stepContext(ctx context.Context, step sdk.Action) (context.Context, context.CancelFunc) {
+	timeout := time.Duration(step.Timeout) * time.Second
+	if step.AlwaysExecuted && ctx.Err() == context.DeadlineExceeded {
+		if timeout == 0 {
+			timeout = alwaysExecutedStepTimeout
+		}
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk.Action, buildID int64, params *[]sdk.Parameter, stepOrder int, stepName string, stepBaseCount int) (sdk.Result, int) {
 	log.Debug("runSteps> start run %d stepOrder:%d len(steps):%d", buildID, stepOrder, len(steps))
 	defer log.Debug("runSteps> end run %d stepOrder:%d len(steps):%d", buildID, stepOrder, len(steps))
@@ -182,6 +252,7 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 		Status:  sdk.StatusFail.String(),
 		BuildID: buildID,
 	}
+	var previousStatus = sdk.StatusSuccess.String()
 
 	for i, child := range steps {
 		if stepOrder == -1 {
@@ -201,7 +272,31 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			continue
 		}
 
-		if !criticalStepFailed || child.AlwaysExecuted {
+		// A step with a condition is run if its condition is true, even after a failed step
+		runStep := !criticalStepFailed || child.AlwaysExecuted
+		if child.Condition != "" {
+			ok, err := w.checkStepCondition(child, *params, previousStatus, criticalStepFailed)
+			if err != nil {
+				w.sendLog(buildID, fmt.Sprintf("Cannot evaluate condition of step %s: %s\n", childName, err), w.currentJob.currentStep, false)
+				criticalStepFailed = true
+				previousStatus = sdk.StatusFail.String()
+				if err := w.updateStepStatus(buildID, w.currentJob.currentStep, previousStatus); err != nil {
+					log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, previousStatus, buildID, err)
+				}
+				continue
+			}
+			if !ok {
+				// Update step status and continue
+				if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusSkipped.String()); err != nil {
+					log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusSkipped.String(), buildID, err)
+				}
+				w.sendLog(buildID, fmt.Sprintf("End of Step %s [Skipped]\n", childName), w.currentJob.currentStep, true)
+				continue
+			}
+			runStep = true
+		}
+
+		if runStep {
 			log.Debug("Running %s", childName)
 			// Update step status
 			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusBuilding.String()); err != nil {
@@ -209,18 +304,24 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			}
 			w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), w.currentJob.currentStep, false)
 
-			r = w.startAction(ctx, &child, buildID, params, w.currentJob.currentStep, childName)
+			stepCtx, cancel := stepContext(ctx, child)
+			r = w.startAction(stepCtx, &child, buildID, params, w.currentJob.currentStep, childName)
+			if stepCtx.Err() == context.DeadlineExceeded {
+				r.Status = sdk.StatusTimeout.String()
+			}
+			cancel()
 			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
 				criticalStepFailed = true
 			}
 
+			previousStatus = r.Status
 			w.sendLog(buildID, fmt.Sprintf("End of step %s [%s]", childName, r.Status), w.currentJob.currentStep, true)
 
 			// Update step status
 			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, r.Status); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusDisabled.String(), buildID, err)
 			}
-		} else if criticalStepFailed && !child.AlwaysExecuted { // Update status of steps which are never built
+		} else { // Update status of steps which are never built
 			// Update step status
 			if err := w.updateStepStatus(buildID, w.currentJob.currentStep, sdk.StatusNeverBuilt.String()); err != nil {
 				log.Warning("Cannot update step (%d) status (%s) for build %d: %s", w.currentJob.currentStep, sdk.StatusNeverBuilt.String(), buildID, err)
@@ -237,7 +338,26 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 	return r, nbDisabledChildren
 }
 
+// checkStepCondition evaluates the condition of a step against the job parameters, the build variables,
+// the status of the last executed step (previous.status) and the current status of the job (job.status)
+func (w *currentWorker) checkStepCondition(step sdk.Action, params []sdk.Parameter, previousStatus string, jobFailed bool) (bool, error) {
+	vars := sdk.ParametersToMap(params)
+	for _, v := range w.currentJob.buildVariables {
+		vars[v.Name] = v.Value
+	}
+	vars["previous.status"] = previousStatus
+	vars["job.status"] = sdk.StatusSuccess.String()
+	if jobFailed {
+		vars["job.status"] = sdk.StatusFail.String()
+	}
+	return sdk.EvaluateExpression(step.Condition, vars)
+}
+
 func (w *currentWorker) updateStepStatus(pbJobID int64, stepOrder int, status string) error {
+	if w.local.enabled {
+		return nil
+	}
+
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
 		Status:    status,
@@ -19,6 +19,8 @@ type Action struct {
 	Enabled        bool          `json:"enabled" yaml:"-"`
 	Optional       bool          `json:"optional" yaml:"-"`
 	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
+	Timeout        int64         `json:"timeout,omitempty" yaml:"-"`   // in seconds, 0 means no timeout
+	Condition      string        `json:"condition,omitempty" yaml:"-"` // expression, the action is skipped when it's false
 	LastModified   int64         `json:"last_modified" cli:"modified"`
 }
 
@@ -66,11 +68,13 @@ const (
 
 // Builtin Action
 const (
-	ScriptAction   = "Script"
-	JUnitAction    = "JUnit"
-	GitCloneAction = "GitClone"
-	GitTagAction   = "GitTag"
-	ReleaseAction  = "Release"
+	ScriptAction      = "Script"
+	JUnitAction       = "JUnit"
+	GitCloneAction    = "GitClone"
+	GitTagAction      = "GitTag"
+	ReleaseAction     = "Release"
+	SignAction        = "Sign"
+	HTTPRequestAction = "HTTPRequest"
 )
 
 const (
@@ -88,6 +92,10 @@ const (
 	ServiceRequirement = "service"
 	//MemoryRequirement set memory limit on a container
 	MemoryRequirement = "memory"
+	//OSArchRequirement checks the os and the architecture of the worker, ie: linux/amd64
+	OSArchRequirement = "os-arch"
+	//LabelRequirement checks a label declared by the worker or its model, ie: pool=high-cpu
+	LabelRequirement = "label"
 )
 
 var (
@@ -100,6 +108,8 @@ var (
 		PluginRequirement,
 		ServiceRequirement,
 		MemoryRequirement,
+		OSArchRequirement,
+		LabelRequirement,
 	}
 )
 
@@ -20,6 +20,8 @@ type ActionScript struct {
 	Steps        []struct {
 		Enabled          *bool                        `json:"enabled"`
 		AlwaysExecuted   bool                         `json:"always_executed"`
+		Timeout          int64                        `json:"timeout,omitempty"`
+		If               string                       `json:"if,omitempty"`
 		ArtifactUpload   map[string]string            `json:"artifactUpload,omitempty"`
 		ArtifactDownload map[string]string            `json:"artifactDownload,omitempty"`
 		GitClone         map[string]string            `json:"gitClone,omitempty"`
@@ -93,6 +95,26 @@ func NewStepRelease(v map[string]string) Action {
 	return newAction
 }
 
+// NewStepSign returns an action (basically used as a step of a job) of Sign type
+func NewStepSign(v map[string]string) Action {
+	newAction := Action{
+		Name:       SignAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepHTTPRequest returns an action (basically used as a step of a job) of HTTPRequest type
+func NewStepHTTPRequest(v map[string]string) Action {
+	newAction := Action{
+		Name:       HTTPRequestAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewStepArtifactUpload returns an action (basically used as a step of a job) of artifact upload type
 func NewStepArtifactUpload(v map[string]string) Action {
 	newAction := Action{
@@ -243,6 +265,8 @@ func NewActionFromScript(btes []byte) (*Action, error) {
 			newAction.Enabled = true
 		}
 		newAction.AlwaysExecuted = v.AlwaysExecuted
+		newAction.Timeout = v.Timeout
+		newAction.Condition = v.If
 		a.Actions = append(a.Actions, newAction)
 	}
 
@@ -23,6 +23,7 @@ type Application struct {
 	ProjectKey          string                `json:"project_key" db:"-"`
 	ApplicationGroups   []GroupPermission     `json:"groups,omitempty" db:"-"`
 	Variable            []Variable            `json:"variables,omitempty" db:"-"`
+	VariableSets        []VariableSet         `json:"variable_sets,omitempty" db:"-"`
 	Pipelines           []ApplicationPipeline `json:"pipelines,omitempty" db:"-"`
 	PipelinesBuild      []PipelineBuild       `json:"pipelines_build,omitempty" db:"-"`
 	Permission          int                   `json:"permission" db:"-"`
@@ -60,6 +60,9 @@ const (
 	ArtifactFileName = "ARTIFACT-FILENAME"
 )
 
+// ArtifactSignatureSuffix is the suffix of the detached signature uploaded alongside a signed artifact
+const ArtifactSignatureSuffix = ".sig"
+
 // DownloadArtifacts retrieves and download artifacts related to given project-pipeline-tag
 // and download them into destdir
 func DownloadArtifacts(project string, application string, pipeline string, tag string, destdir string, env string) error {
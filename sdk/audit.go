@@ -1,8 +1,55 @@
 package sdk
 
+import "time"
+
 // Different type of Audit event
 const (
 	AuditAdd    = "add"
 	AuditUpdate = "update"
 	AuditDelete = "delete"
 )
+
+// Types of the entities recorded in the audit log
+const (
+	AuditEntityWorkflow    = "workflow"
+	AuditEntityPipeline    = "pipeline"
+	AuditEntityPermission  = "permission"
+	AuditEntityKey         = "key"
+	AuditEntityVariable    = "variable"
+	AuditEntityWorkerModel = "worker_model"
+	AuditEntityToken       = "token"
+)
+
+// Audit is an entry of the audit log, it records who changed what and when. The name of an
+// entity is a path like application/my-app/my-variable for the entities which belong to another one
+type Audit struct {
+	ID         int64       `json:"id" cli:"id,key"`
+	Created    time.Time   `json:"created" cli:"created"`
+	Username   string      `json:"username" cli:"username"`
+	ProjectKey string      `json:"project_key,omitempty" cli:"project"`
+	EntityType string      `json:"entity_type" cli:"entity_type"`
+	EntityName string      `json:"entity_name" cli:"entity"`
+	EventType  string      `json:"event_type" cli:"event"`
+	Before     interface{} `json:"before,omitempty" cli:"-"`
+	After      interface{} `json:"after,omitempty" cli:"-"`
+	Diff       []AuditDiff `json:"diff,omitempty" cli:"-"`
+}
+
+// AuditDiff is a value of an entity changed by an audited event
+type AuditDiff struct {
+	Key    string `json:"key" cli:"key"`
+	Before string `json:"before,omitempty" cli:"before"`
+	After  string `json:"after,omitempty" cli:"after"`
+}
+
+// AuditFilter filters the entries of the audit log, the zero values are ignored. The entity
+// name matches the entity and all the entities which belong to it
+type AuditFilter struct {
+	ProjectKey string    `json:"project_key,omitempty"`
+	Username   string    `json:"username,omitempty"`
+	EntityType string    `json:"entity_type,omitempty"`
+	EntityName string    `json:"entity_name,omitempty"`
+	Since      time.Time `json:"since,omitempty"`
+	Until      time.Time `json:"until,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+}
@@ -82,6 +82,8 @@ func StatusFromString(in string) Status {
 		return StatusDisabled
 	case StatusSkipped.String():
 		return StatusSkipped
+	case StatusTimeout.String():
+		return StatusTimeout
 	default:
 		return StatusUnknown
 	}
@@ -102,6 +104,7 @@ const (
 	StatusNeverBuilt Status = "Never Built"
 	StatusUnknown    Status = "Unknown"
 	StatusSkipped    Status = "Skipped"
+	StatusTimeout    Status = "Timeout"
 )
 
 // Translate translates messages in pipelineBuildJob
@@ -51,3 +51,17 @@ func (c *client) ActionList() ([]sdk.Action, error) {
 	}
 	return actions, nil
 }
+
+func (c *client) ActionImpact(actionName string) ([]sdk.Impact, error) {
+	impacts := []sdk.Impact{}
+	code, err := c.GetJSON("/action/"+actionName+"/impact", &impacts)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return impacts, nil
+}
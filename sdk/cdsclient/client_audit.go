@@ -0,0 +1,66 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) AuditList(filter sdk.AuditFilter) ([]sdk.Audit, error) {
+	q := url.Values{}
+	if filter.ProjectKey != "" {
+		q.Set("project", filter.ProjectKey)
+	}
+	if filter.Username != "" {
+		q.Set("user", filter.Username)
+	}
+	if filter.EntityType != "" {
+		q.Set("entity_type", filter.EntityType)
+	}
+	if filter.EntityName != "" {
+		q.Set("entity", filter.EntityName)
+	}
+	if !filter.Since.IsZero() {
+		q.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		q.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	path := "/audit"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	audits := []sdk.Audit{}
+	code, err := c.GetJSON(path, &audits)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+func (c *client) AuditGet(id int64) (*sdk.Audit, error) {
+	a := &sdk.Audit{}
+	code, err := c.GetJSON(fmt.Sprintf("/audit/%d", id), a)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
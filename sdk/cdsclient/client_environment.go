@@ -60,3 +60,17 @@ func (c *client) EnvironmentList(key string) ([]sdk.Environment, error) {
 	}
 	return envs, nil
 }
+
+func (c *client) EnvironmentImpact(key string, envName string) ([]sdk.Impact, error) {
+	impacts := []sdk.Impact{}
+	code, err := c.GetJSON("/project/"+key+"/environment/"+url.QueryEscape(envName)+"/impact", &impacts)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return impacts, nil
+}
@@ -59,3 +59,30 @@ func (c *client) GroupList() ([]sdk.Group, error) {
 	}
 	return groups, nil
 }
+
+func (c *client) GroupQuota(name string) (*sdk.GroupQuotaUsage, error) {
+	usage := &sdk.GroupQuotaUsage{}
+	code, err := c.GetJSON("/group/"+name+"/quota", usage)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (c *client) GroupQuotaUpdate(name string, quota *sdk.GroupQuota) error {
+	code, err := c.PutJSON("/group/"+name+"/quota", quota, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
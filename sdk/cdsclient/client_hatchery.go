@@ -3,6 +3,7 @@ package cdsclient
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -26,12 +27,29 @@ func (c *client) HatcheryRegister(h sdk.Hatchery) (*sdk.Hatchery, bool, error) {
 	return &hreceived, hreceived.Uptodate, nil
 }
 
-func (c *client) HatcheryRefresh(id int64) error {
-	code, err := c.PutJSON(fmt.Sprintf("/hatchery/%d", id), nil, nil)
+// HatcheryRefresh refreshes the last beat of the hatchery and returns its maintenance mode
+func (c *client) HatcheryRefresh(id int64) (bool, error) {
+	var h sdk.Hatchery
+	code, err := c.PutJSON(fmt.Sprintf("/hatchery/%d", id), nil, &h)
 	if code > 300 && err == nil {
-		return fmt.Errorf("HatcheryRefresh> HTTP %d", code)
+		return false, fmt.Errorf("HatcheryRefresh> HTTP %d", code)
 	} else if err != nil {
-		return sdk.WrapError(err, "HatcheryRefresh> Error")
+		return false, sdk.WrapError(err, "HatcheryRefresh> Error")
 	}
-	return nil
+	return h.Maintenance, nil
+}
+
+// HatcheryMaintenance enables or disables the maintenance mode of the hatcheries registered with the given name
+func (c *client) HatcheryMaintenance(name string, maintenance bool) error {
+	method := http.MethodPost
+	if !maintenance {
+		method = http.MethodDelete
+	}
+	code, err := c.RequestJSON(method, "/hatchery/"+url.QueryEscape(name)+"/maintenance", nil, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
 }
@@ -0,0 +1,50 @@
+package cdsclient
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func (c *client) ProjectExport(key string) (*exportentities.ProjectTree, error) {
+	tree := &exportentities.ProjectTree{}
+	code, err := c.GetJSON("/project/"+key+"/export", tree)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (c *client) ProjectImport(key string, tree *exportentities.ProjectTree, dryRun bool) ([]sdk.ImportChange, error) {
+	path := "/project/" + key + "/import"
+	if dryRun {
+		path += "?dryRun=true"
+	}
+	changes := []sdk.ImportChange{}
+	code, err := c.PostJSON(path, tree, &changes)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (c *client) ProjectVariableEncrypt(key string, v *sdk.Variable) error {
+	code, err := c.PostJSON("/project/"+key+"/encrypt", v, v)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
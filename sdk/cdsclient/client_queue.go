@@ -9,6 +9,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -148,9 +149,12 @@ func (c *client) QueueJobSendSpawnInfo(isWorkflowJob bool, id int64, in []sdk.Sp
 	return nil
 }
 
-// QueueJobBook books a job for a Hatchery
-func (c *client) QueueJobBook(isWorkflowJob bool, id int64) error {
+// QueueJobBook books a job for a Hatchery, the model is the worker model which will be spawned for the job
+func (c *client) QueueJobBook(isWorkflowJob bool, id int64, model string) error {
 	path := fmt.Sprintf("/queue/workflows/%d/book", id)
+	if model != "" {
+		path += "?model=" + url.QueryEscape(model)
+	}
 	if !isWorkflowJob {
 		// DEPRECATED code -> it's for pipelineBuildJob
 		path = fmt.Sprintf("/queue/%d/book", id)
@@ -0,0 +1,36 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) Search(filter sdk.SearchFilter) ([]sdk.SearchResult, error) {
+	q := url.Values{}
+	q.Set("q", filter.Query)
+	if filter.ProjectKey != "" {
+		q.Set("project", filter.ProjectKey)
+	}
+	if len(filter.Types) > 0 {
+		q.Set("type", strings.Join(filter.Types, ","))
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	results := []sdk.SearchResult{}
+	code, err := c.GetJSON("/search?"+q.Encode(), &results)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
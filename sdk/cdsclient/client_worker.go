@@ -22,6 +22,49 @@ func (c *client) WorkerList() ([]sdk.Worker, error) {
 	return p, nil
 }
 
+func (c *client) WorkerDisable(id string) error {
+	code, err := c.PostJSON(fmt.Sprintf("/worker/%s/disable", id), nil, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WorkerMaintenance enables or disables the maintenance mode of a worker
+func (c *client) WorkerMaintenance(id string, maintenance bool) error {
+	method := http.MethodPost
+	if !maintenance {
+		method = http.MethodDelete
+	}
+	code, err := c.RequestJSON(method, fmt.Sprintf("/worker/%s/maintenance", id), nil, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
+
+// WorkerRefresh refreshes the last beat of the worker and returns its maintenance mode
+func (c *client) WorkerRefresh() (bool, error) {
+	var w sdk.Worker
+	code, err := c.PostJSON("/worker/refresh", nil, &w)
+	if code != 200 {
+		if err == nil {
+			return false, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return w.Maintenance, nil
+}
+
 func (c *client) WorkerRegister(r worker.RegistrationForm) (*sdk.Worker, bool, error) {
 	var w sdk.Worker
 	code, err := c.PostJSON("/worker", r, &w)
@@ -41,3 +41,71 @@ func (c *client) WorkerModelSpawnError(id int64, info string) error {
 	}
 	return nil
 }
+
+// WorkerModelVersions retrieves the history of the versions of a worker model
+func (c *client) WorkerModelVersions(modelID int64) ([]sdk.ModelVersion, error) {
+	versions := []sdk.ModelVersion{}
+	code, err := c.GetJSON(fmt.Sprintf("/worker/model/%d/version", modelID), &versions)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// WorkerModelVersionAdd adds a version to a worker model, the version is checked by the hatcheries before its promotion
+func (c *client) WorkerModelVersionAdd(modelID int64, v *sdk.ModelVersion) (*sdk.ModelVersion, error) {
+	res := &sdk.ModelVersion{}
+	code, err := c.PostJSON(fmt.Sprintf("/worker/model/%d/version", modelID), v, res)
+	if code != 201 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WorkerModelVersionPromote makes a version the current version of a worker model
+func (c *client) WorkerModelVersionPromote(modelID, version int64) error {
+	code, err := c.PostJSON(fmt.Sprintf("/worker/model/%d/version/%d/promote", modelID, version), nil, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
+
+// WorkerModelVersionRollout sets the percent of the spawns using a checked version of a worker model
+func (c *client) WorkerModelVersionRollout(modelID, version, rollout int64) error {
+	form := sdk.ModelVersionRolloutForm{Rollout: rollout}
+	code, err := c.PutJSON(fmt.Sprintf("/worker/model/%d/version/%d/rollout", modelID, version), form, nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	return err
+}
+
+// WorkerModelImpact retrieves the jobs, pipelines, applications and workflow nodes depending on a worker model
+func (c *client) WorkerModelImpact(modelID int64) ([]sdk.Impact, error) {
+	impacts := []sdk.Impact{}
+	code, err := c.GetJSON(fmt.Sprintf("/worker/model/%d/impact", modelID), &impacts)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return impacts, nil
+}
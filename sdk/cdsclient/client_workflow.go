@@ -35,6 +35,15 @@ func (c *client) WorkflowRun(projectKey string, name string, number int64) (*sdk
 	return &run, nil
 }
 
+func (c *client) WorkflowRunLatest(projectKey string, name string) (*sdk.WorkflowRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/latest", projectKey, name)
+	run := sdk.WorkflowRun{}
+	if _, err := c.GetJSON(url, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
 func (c *client) WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.Artifact, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/artifacts", projectKey, name, number)
 	arts := []sdk.Artifact{}
@@ -87,6 +96,38 @@ func (c *client) WorkflowNodeRunRelease(projectKey string, workflowName string,
 	return nil
 }
 
+// WorkflowRunFromManual starts a run of a workflow from its root. If number is set, the node fromNodeID
+// of the run number is run again
+func (c *client) WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error) {
+	if c.config.Verbose {
+		log.Println("Payload: ", manual.Payload)
+	}
+
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs", projectKey, workflowName)
+	m := struct {
+		Manual     *sdk.WorkflowNodeRunManual `json:"manual,omitempty"`
+		Number     *int64                     `json:"number,omitempty"`
+		FromNodeID *int64                     `json:"from_node,omitempty"`
+	}{
+		Manual: &manual,
+	}
+	if number != 0 {
+		m.Number = &number
+	}
+	if fromNodeID != 0 {
+		m.FromNodeID = &fromNodeID
+	}
+	run := &sdk.WorkflowRun{}
+	code, err := c.PostJSON(url, &m, run)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("Cannot run workflow. HTTP code error : %d", code)
+	}
+	return run, nil
+}
+
 func (c *client) WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
 	if c.config.Verbose {
 		log.Println("Payload: ", hook.Payload)
@@ -0,0 +1,26 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) WorkflowTests(projectKey string, name string, runs int, flakyOnly bool) ([]sdk.WorkflowTestCaseStats, error) {
+	uri := fmt.Sprintf("/project/%s/workflows/%s/tests?runs=%d&flaky=%t", projectKey, name, runs, flakyOnly)
+	stats := []sdk.WorkflowTestCaseStats{}
+	if _, err := c.GetJSON(uri, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c *client) WorkflowTestHistory(projectKey string, name string, runs int, suite, testCase string) (*sdk.WorkflowTestCaseStats, error) {
+	uri := fmt.Sprintf("/project/%s/workflows/%s/tests/history?runs=%d&suite=%s&case=%s", projectKey, name, runs, url.QueryEscape(suite), url.QueryEscape(testCase))
+	stats := sdk.WorkflowTestCaseStats{}
+	if _, err := c.GetJSON(uri, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -7,14 +7,18 @@ import (
 
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 // Interface is the main interface for cdsclient package
 type Interface interface {
 	ActionDelete(actionName string) error
 	ActionGet(actionName string, mods ...RequestModifier) (*sdk.Action, error)
+	ActionImpact(actionName string) ([]sdk.Impact, error)
 	ActionList() ([]sdk.Action, error)
 	APIURL() string
+	AuditGet(id int64) (*sdk.Audit, error)
+	AuditList(filter sdk.AuditFilter) ([]sdk.Audit, error)
 	ApplicationCreate(string, *sdk.Application) error
 	ApplicationDelete(string, string) error
 	ApplicationGet(string, string, ...RequestModifier) (*sdk.Application, error)
@@ -26,6 +30,7 @@ type Interface interface {
 	EnvironmentCreate(string, *sdk.Environment) error
 	EnvironmentDelete(string, string) error
 	EnvironmentGet(string, string, ...RequestModifier) (*sdk.Environment, error)
+	EnvironmentImpact(string, string) ([]sdk.Impact, error)
 	EnvironmentList(string) ([]sdk.Environment, error)
 	EnvironmentKeysList(string, string) ([]sdk.EnvironmentKey, error)
 	EnvironmentKeyCreate(string, string, *sdk.EnvironmentKey) error
@@ -35,11 +40,14 @@ type Interface interface {
 	GroupGenerateToken(groupName, expiration string) (*sdk.Token, error)
 	GroupGet(name string, mods ...RequestModifier) (*sdk.Group, error)
 	GroupList() ([]sdk.Group, error)
+	GroupQuota(name string) (*sdk.GroupQuotaUsage, error)
+	GroupQuotaUpdate(name string, quota *sdk.GroupQuota) error
 	GroupUserAdminSet(groupname string, username string) error
 	GroupUserAdminRemove(groupname, username string) error
 	GroupUserAdd(groupname string, users []string) error
 	GroupUserRemove(groupname, username string) error
-	HatcheryRefresh(int64) error
+	HatcheryMaintenance(name string, maintenance bool) error
+	HatcheryRefresh(int64) (bool, error)
 	HatcheryRegister(sdk.Hatchery) (*sdk.Hatchery, bool, error)
 	MonStatus() ([]string, error)
 	PipelineDelete(projectKey, name string) error
@@ -48,20 +56,24 @@ type Interface interface {
 	PipelineList(projectKey string) ([]sdk.Pipeline, error)
 	ProjectCreate(*sdk.Project) error
 	ProjectDelete(string) error
+	ProjectExport(key string) (*exportentities.ProjectTree, error)
 	ProjectGet(string, ...RequestModifier) (*sdk.Project, error)
+	ProjectImport(key string, tree *exportentities.ProjectTree, dryRun bool) ([]sdk.ImportChange, error)
 	ProjectList() ([]sdk.Project, error)
 	ProjectKeysList(string) ([]sdk.ProjectKey, error)
 	ProjectKeyCreate(string, *sdk.ProjectKey) error
 	ProjectKeysDelete(string, string) error
+	ProjectVariableEncrypt(key string, v *sdk.Variable) error
 	Queue() ([]sdk.WorkflowNodeJobRun, []sdk.PipelineBuildJob, error)
 	QueuePolling(context.Context, chan<- sdk.WorkflowNodeJobRun, chan<- sdk.PipelineBuildJob, chan<- error, time.Duration) error
 	QueueTakeJob(sdk.WorkflowNodeJobRun, bool) (*worker.WorkflowNodeJobRunInfo, error)
-	QueueJobBook(isWorkflowJob bool, id int64) error
+	QueueJobBook(isWorkflowJob bool, id int64, model string) error
 	QueueJobInfo(id int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueJobSendSpawnInfo(isWorkflowJob bool, id int64, in []sdk.SpawnInfo) error
 	QueueSendResult(int64, sdk.Result) error
 	QueueArtifactUpload(id int64, tag, filePath string) error
 	Requirements() ([]sdk.Requirement, error)
+	Search(filter sdk.SearchFilter) ([]sdk.SearchResult, error)
 	ServiceRegister(sdk.Service) (string, error)
 	UserLogin(username, password string) (bool, string, error)
 	UserList() ([]sdk.User, error)
@@ -71,10 +83,18 @@ type Interface interface {
 	UserReset(username, email, callback string) error
 	UserConfirm(username, token string) (bool, string, error)
 	Version() (*sdk.Version, error)
+	WorkerDisable(id string) error
 	WorkerList() ([]sdk.Worker, error)
+	WorkerMaintenance(id string, maintenance bool) error
+	WorkerModelImpact(modelID int64) ([]sdk.Impact, error)
 	WorkerModelSpawnError(id int64, info string) error
 	WorkerModelsEnabled() ([]sdk.Model, error)
 	WorkerModels() ([]sdk.Model, error)
+	WorkerModelVersions(modelID int64) ([]sdk.ModelVersion, error)
+	WorkerModelVersionAdd(modelID int64, v *sdk.ModelVersion) (*sdk.ModelVersion, error)
+	WorkerModelVersionPromote(modelID, version int64) error
+	WorkerModelVersionRollout(modelID, version, rollout int64) error
+	WorkerRefresh() (bool, error)
 	WorkerRegister(worker.RegistrationForm) (*sdk.Worker, bool, error)
 	WorkerSetStatus(sdk.Status) error
 	WorkflowList(projectKey string) ([]sdk.Workflow, error)
@@ -82,9 +102,13 @@ type Interface interface {
 	WorkflowRun(projectKey string, name string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.Artifact, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
+	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
+	WorkflowRunLatest(projectKey string, name string) (*sdk.WorkflowRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifacts(projectKey string, name string, number int64, nodeRunID int64) ([]sdk.Artifact, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, artifactID int64, w io.Writer) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
 	WorkflowAllHooksList() ([]sdk.WorkflowNodeHook, error)
+	WorkflowTests(projectKey string, name string, runs int, flakyOnly bool) ([]sdk.WorkflowTestCaseStats, error)
+	WorkflowTestHistory(projectKey string, name string, runs int, suite, testCase string) (*sdk.WorkflowTestCaseStats, error)
 }
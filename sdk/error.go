@@ -119,6 +119,19 @@ var (
 	ErrWebhookConfigDoesNotMatch             = &Error{ID: 103, Status: http.StatusBadRequest}
 	ErrPipelineUsedByWorkflow                = &Error{ID: 104, Status: http.StatusBadRequest}
 	ErrMethodNotAllowed                      = &Error{ID: 105, Status: http.StatusMethodNotAllowed}
+	ErrTemplateWorkflowNotSupported          = &Error{ID: 106, Status: http.StatusBadRequest}
+	ErrWorkflowAlreadyExists                 = &Error{ID: 107, Status: http.StatusConflict}
+	ErrGroupQuotaExceeded                    = &Error{ID: 108, Status: http.StatusForbidden}
+	ErrWorkerRequirementsNotMatched          = &Error{ID: 109, Status: http.StatusForbidden}
+	ErrWorkerModelVersionNotChecked          = &Error{ID: 110, Status: http.StatusBadRequest}
+	ErrHatcheryMaintenance                   = &Error{ID: 111, Status: http.StatusForbidden}
+	ErrWorkerMaintenance                     = &Error{ID: 112, Status: http.StatusForbidden}
+	ErrVariableSetNotFound                   = &Error{ID: 113, Status: http.StatusNotFound}
+	ErrVariableSetExists                     = &Error{ID: 114, Status: http.StatusConflict}
+	ErrVariableSetAttached                   = &Error{ID: 115, Status: http.StatusForbidden}
+	ErrAuditNotFound                         = &Error{ID: 116, Status: http.StatusNotFound}
+	ErrEntityUsed                            = &Error{ID: 117, Status: http.StatusForbidden}
+	ErrCannotDecryptSecret                   = &Error{ID: 118, Status: http.StatusBadRequest}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -227,6 +240,19 @@ var errorsAmericanEnglish = map[int]string{
 	ErrWebhookConfigDoesNotMatch.ID:             "Webhook config does not match",
 	ErrPipelineUsedByWorkflow.ID:                "pipeline still used by a workflow",
 	ErrMethodNotAllowed.ID:                      "Method not allowed",
+	ErrTemplateWorkflowNotSupported.ID:          "template does not support workflow generation",
+	ErrWorkflowAlreadyExists.ID:                 "workflow already exists",
+	ErrGroupQuotaExceeded.ID:                    "group quota exceeded",
+	ErrWorkerRequirementsNotMatched.ID:          "worker does not match the job requirements",
+	ErrWorkerModelVersionNotChecked.ID:          "worker model version has not passed the registration check",
+	ErrHatcheryMaintenance.ID:                   "hatchery is in maintenance",
+	ErrWorkerMaintenance.ID:                     "worker is in maintenance",
+	ErrVariableSetNotFound.ID:                   "variable set not found",
+	ErrVariableSetExists.ID:                     "variable set already exists",
+	ErrVariableSetAttached.ID:                   "variable set is still attached to projects or applications",
+	ErrAuditNotFound.ID:                         "audit not found",
+	ErrEntityUsed.ID:                            "entity is still used by others, check its impact or force the deletion",
+	ErrCannotDecryptSecret.ID:                   "cannot decrypt secret, it was not encrypted with the key of this project",
 }
 
 var errorsFrench = map[int]string{
@@ -335,6 +361,19 @@ var errorsFrench = map[int]string{
 	ErrWebhookConfigDoesNotMatch.ID:             "la configuration du webhook ne correspond pas",
 	ErrPipelineUsedByWorkflow.ID:                "le pipeline est utilisé par un workflow",
 	ErrMethodNotAllowed.ID:                      "La méthode n'est pas autorisée",
+	ErrTemplateWorkflowNotSupported.ID:          "le template ne supporte pas la génération de workflow",
+	ErrWorkflowAlreadyExists.ID:                 "le workflow existe déjà",
+	ErrGroupQuotaExceeded.ID:                    "le quota du groupe est dépassé",
+	ErrWorkerRequirementsNotMatched.ID:          "le worker ne satisfait pas les pré-requis du job",
+	ErrWorkerModelVersionNotChecked.ID:          "la version du modèle de worker n'a pas passé la vérification d'enregistrement",
+	ErrHatcheryMaintenance.ID:                   "la hatchery est en maintenance",
+	ErrWorkerMaintenance.ID:                     "le worker est en maintenance",
+	ErrVariableSetNotFound.ID:                   "le jeu de variables n'existe pas",
+	ErrVariableSetExists.ID:                     "le jeu de variables existe déjà",
+	ErrVariableSetAttached.ID:                   "le jeu de variables est encore rattaché à des projets ou des applications",
+	ErrAuditNotFound.ID:                         "audit introuvable",
+	ErrEntityUsed.ID:                            "l'entité est encore utilisée par d'autres, vérifiez son impact ou forcez la suppression",
+	ErrCannotDecryptSecret.ID:                   "impossible de déchiffrer le secret, il n'a pas été chiffré avec la clé de ce projet",
 }
 
 var errorsLanguages = []map[int]string{
@@ -40,6 +40,17 @@ type EventPipelineBuild struct {
 	RepositoryFullname    string `json:"repositoryFullname,omitempty"`
 }
 
+// EventAudit contains event data for an entry of the audit log
+type EventAudit struct {
+	ID         int64       `json:"id"`
+	Username   string      `json:"username"`
+	ProjectKey string      `json:"projectKey,omitempty"`
+	EntityType string      `json:"entityType"`
+	EntityName string      `json:"entityName"`
+	EventType  string      `json:"eventType"`
+	Diff       []AuditDiff `json:"diff,omitempty"`
+}
+
 // EventJob contains event data for a job
 type EventJob struct {
 	Version         int64  `json:"version,omitempty"`
@@ -217,6 +217,52 @@ func NewApplication(app *sdk.Application) (a *Application) {
 	return
 }
 
+//Application returns a sdk.Application entity with its variables, permissions and pipelines, the
+//triggers and the options of the pipelines are ignored
+func (a *Application) Application() (app *sdk.Application) {
+	app = new(sdk.Application)
+	app.Name = a.Name
+	if a.RepositoryManager != "" {
+		app.RepositoriesManager = &sdk.RepositoriesManager{Name: a.RepositoryManager}
+		app.RepositoryFullname = a.RepositoryName
+	}
+
+	app.Variable = make([]sdk.Variable, 0, len(a.Variables))
+	for k, v := range a.Variables {
+		app.Variable = append(app.Variable, sdk.Variable{
+			Name:  k,
+			Type:  v.Type,
+			Value: v.Value,
+		})
+	}
+	app.ApplicationGroups = make([]sdk.GroupPermission, 0, len(a.Permissions))
+	for k, v := range a.Permissions {
+		app.ApplicationGroups = append(app.ApplicationGroups, sdk.GroupPermission{
+			Group: sdk.Group{
+				Name: k,
+			},
+			Permission: v,
+		})
+	}
+
+	app.Pipelines = make([]sdk.ApplicationPipeline, 0, len(a.Pipelines))
+	for k, v := range a.Pipelines {
+		ap := sdk.ApplicationPipeline{
+			Pipeline:   sdk.Pipeline{Name: k},
+			Parameters: make([]sdk.Parameter, 0, len(v.Parameters)),
+		}
+		for n, p := range v.Parameters {
+			ap.Parameters = append(ap.Parameters, sdk.Parameter{
+				Name:  n,
+				Type:  p.Type,
+				Value: p.Value,
+			})
+		}
+		app.Pipelines = append(app.Pipelines, ap)
+	}
+	return
+}
+
 //HCLTemplate returns text/template
 func (a *Application) HCLTemplate() (*template.Template, error) {
 	tmpl := `name = "{{.Name}}"
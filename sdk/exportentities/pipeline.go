@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -39,6 +40,8 @@ type Job struct {
 	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
 	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" hcl:"optional,omitempty"`
 	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" hcl:"always_executed,omitempty"`
+	Timeout        string        `json:"timeout,omitempty" yaml:"timeout,omitempty" hcl:"timeout,omitempty"`
+	If             string        `json:"if,omitempty" yaml:"if,omitempty" hcl:"if,omitempty"`
 }
 
 // Step represents exported step used in a job
@@ -48,7 +51,7 @@ type Step map[string]interface{}
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if k != "enabled" && k != "optional" && k != "always_executed" && k != "timeout" && k != "if" {
 			keys = append(keys, k)
 		}
 	}
@@ -58,7 +61,7 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if k != "enabled" && k != "optional" && k != "always_executed" && k != "timeout" && k != "if" {
 			keys = append(keys, k)
 		}
 	}
@@ -216,6 +219,84 @@ func (s Step) AsGitClone() (*sdk.Action, bool, error) {
 	return &a, true, nil
 }
 
+//AsSign returns the step a sdk.Action
+func (s Step) AsSign() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["sign"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepSign(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
+//AsHTTPRequest returns the step a sdk.Action
+func (s Step) AsHTTPRequest() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["httpRequest"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepHTTPRequest(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
 //AsArtifactUpload returns the step a sdk.Action
 func (s Step) AsArtifactUpload() (*sdk.Action, bool, error) {
 	if !s.IsValid() {
@@ -303,6 +384,62 @@ func (s Step) IsFlagged(flag string) (bool, error) {
 	return bS, nil
 }
 
+// Timeout returns the timeout of the step in seconds. It's a duration (ie. 30m) or a number of seconds
+func (s Step) Timeout() (int64, error) {
+	t, ok := s["timeout"]
+	if !ok {
+		return 0, nil
+	}
+	switch v := t.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		return parseTimeout(v)
+	}
+	return 0, fmt.Errorf("Malformatted Step : timeout must be a duration")
+}
+
+// Condition returns the condition of the step, the step is skipped when it's false
+func (s Step) Condition() (string, error) {
+	c, ok := s["if"]
+	if !ok {
+		return "", nil
+	}
+	cond, ok := c.(string)
+	if !ok {
+		return "", fmt.Errorf("Malformatted Step : if must be a string")
+	}
+	// Check the syntax of the condition
+	if _, err := sdk.EvaluateExpression(cond, nil); err != nil {
+		return "", err
+	}
+	return cond, nil
+}
+
+// parseTimeout parses a duration (ie. 1h30m) or a number of seconds
+func parseTimeout(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid timeout %s: %s", s, err)
+	}
+	return int64(d.Seconds()), nil
+}
+
+// formatTimeout returns the timeout in seconds as a duration
+func formatTimeout(t int64) string {
+	return (time.Duration(t) * time.Second).String()
+}
+
 // Requirement represents an exported sdk.Requirement
 type Requirement struct {
 	Binary   string             `json:"binary,omitempty" yaml:"binary,omitempty"`
@@ -312,6 +449,8 @@ type Requirement struct {
 	Plugin   string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service  ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory   string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	OSArch   string             `json:"os-arch,omitempty" yaml:"os-arch,omitempty"`
+	Label    string             `json:"label,omitempty" yaml:"label,omitempty"`
 }
 
 // ServiceRequirement represents an exported sdk.Requirement of type ServiceRequirement
@@ -422,6 +561,10 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{Service: ServiceRequirement{Name: r.Name, Value: r.Value}})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.OSArchRequirement:
+			res = append(res, Requirement{OSArch: r.Value})
+		case sdk.LabelRequirement:
+			res = append(res, Requirement{Label: r.Value})
 		}
 	}
 	return res
@@ -438,6 +581,10 @@ func newJobs(jobs []sdk.Job) map[string]Job {
 		if !j.Enabled {
 			jo.Enabled = &j.Enabled
 		}
+		if j.Action.Timeout > 0 {
+			jo.Timeout = formatTimeout(j.Action.Timeout)
+		}
+		jo.If = j.Action.Condition
 		jo.Steps = newSteps(j.Action)
 		jo.Description = j.Action.Description
 		jo.Requirements = newRequirements(j.Action.Requirements)
@@ -460,6 +607,12 @@ func newSteps(a sdk.Action) []Step {
 		if act.AlwaysExecuted {
 			s["always_executed"] = act.AlwaysExecuted
 		}
+		if act.Timeout > 0 {
+			s["timeout"] = formatTimeout(act.Timeout)
+		}
+		if act.Condition != "" {
+			s["if"] = act.Condition
+		}
 
 		switch act.Type {
 		case sdk.BuiltinAction:
@@ -487,6 +640,10 @@ func newSteps(a sdk.Action) []Step {
 				if pipeline != nil {
 					artifactDownloadArgs["pipeline"] = pipeline.Value
 				}
+				verifyKey := sdk.ParameterFind(act.Parameters, "verifyKey")
+				if verifyKey != nil && verifyKey.Value != "" {
+					artifactDownloadArgs["verifyKey"] = verifyKey.Value
+				}
 				s["artifactDownload"] = artifactDownloadArgs
 			case sdk.ArtifactUpload:
 				artifactUploadArgs := map[string]string{}
@@ -499,6 +656,29 @@ func newSteps(a sdk.Action) []Step {
 					artifactUploadArgs["tag"] = tag.Value
 				}
 				s["artifactUpload"] = artifactUploadArgs
+			case sdk.SignAction:
+				signArgs := map[string]string{}
+				path := sdk.ParameterFind(act.Parameters, "path")
+				if path != nil {
+					signArgs["path"] = path.Value
+				}
+				signKey := sdk.ParameterFind(act.Parameters, "signKey")
+				if signKey != nil {
+					signArgs["signKey"] = signKey.Value
+				}
+				tag := sdk.ParameterFind(act.Parameters, "tag")
+				if tag != nil {
+					signArgs["tag"] = tag.Value
+				}
+				s["sign"] = signArgs
+			case sdk.HTTPRequestAction:
+				httpRequestArgs := map[string]string{}
+				for _, p := range act.Parameters {
+					if p.Value != "" {
+						httpRequestArgs[p.Name] = p.Value
+					}
+				}
+				s["httpRequest"] = httpRequestArgs
 			case sdk.GitCloneAction:
 				gitCloneArgs := map[string]string{}
 				branch := sdk.ParameterFind(act.Parameters, "branch")
@@ -691,6 +871,17 @@ func computeSteps(steps []Step) ([]sdk.Action, error) {
 		if err != nil {
 			return nil, err
 		}
+		if a == nil {
+			return nil, fmt.Errorf("Unsupported step")
+		}
+		a.Timeout, err = s.Timeout()
+		if err != nil {
+			return nil, err
+		}
+		a.Condition, err = s.Condition()
+		if err != nil {
+			return nil, err
+		}
 		res = append(res, *a)
 	}
 	return res, nil
@@ -718,6 +909,16 @@ func computeStep(s Step) (a *sdk.Action, e error) {
 		return
 	}
 
+	a, ok, e = s.AsSign()
+	if ok {
+		return
+	}
+
+	a, ok, e = s.AsHTTPRequest()
+	if ok {
+		return
+	}
+
 	a, ok, e = s.AsGitClone()
 	if ok {
 		return
@@ -768,6 +969,14 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			name = r.Service.Name
 			val = r.Service.Value
 			tpe = sdk.ServiceRequirement
+		} else if r.OSArch != "" {
+			name = sdk.OSArchRequirement
+			val = r.OSArch
+			tpe = sdk.OSArchRequirement
+		} else if r.Label != "" {
+			name = r.Label
+			val = r.Label
+			tpe = sdk.LabelRequirement
 		}
 		res = append(res, sdk.Requirement{
 			Name:  name,
@@ -794,6 +1003,16 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	job.Action.Enabled = job.Enabled
 	job.Action.Requirements = computeJobRequirements(j.Requirements)
 
+	timeout, err := parseTimeout(j.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	job.Action.Timeout = timeout
+	if _, err := sdk.EvaluateExpression(j.If, nil); err != nil {
+		return nil, err
+	}
+	job.Action.Condition = j.If
+
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
 	if err != nil {
@@ -541,6 +541,40 @@ steps:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 7)
 }
 
+func Test_ImportPipelineWithHTTPRequest(t *testing.T) {
+	in := `name: deploy
+steps:
+- httpRequest:
+    method: POST
+    url: https://deploy.example.com/api/deploy
+    headers: |-
+      Content-Type: application/json
+    body: '{"version": "{{.cds.version}}"}'
+    bearerToken: '{{.cds.proj.deploy_token}}'
+    retry: "3"
+    expectedStatus: 200,201
+    extract: deployID=id
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions, 1)
+	a := p.Stages[0].Jobs[0].Action.Actions[0]
+	assert.Equal(t, sdk.HTTPRequestAction, a.Name)
+	assert.Len(t, a.Parameters, 8)
+	assert.Equal(t, "POST", sdk.ParameterValue(a.Parameters, "method"))
+
+	steps := newSteps(p.Stages[0].Jobs[0].Action)
+	assert.Len(t, steps, 1)
+	args, ok := steps[0]["httpRequest"].(map[string]string)
+	assert.True(t, ok)
+	assert.Equal(t, "deployID=id", args["extract"])
+}
+
 func Test_IsFlagged(t *testing.T) {
 	testc := []struct {
 		flag     string
@@ -591,3 +625,73 @@ func Test_IsFlagged(t *testing.T) {
 	}
 
 }
+
+func Test_ImportPipelineWithTimeouts(t *testing.T) {
+	in := `name: build
+stages:
+  1|build:
+    jobs:
+      compile:
+        timeout: 1h
+        steps:
+        - script: make
+          timeout: 30m
+        - script: make clean
+          timeout: 60
+          always_executed: true
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, int64(3600), job.Action.Timeout)
+	assert.Equal(t, int64(1800), job.Action.Actions[0].Timeout)
+	assert.Equal(t, int64(60), job.Action.Actions[1].Timeout)
+	assert.True(t, job.Action.Actions[1].AlwaysExecuted)
+
+	jobs := newJobs([]sdk.Job{job})
+	assert.Equal(t, "1h0m0s", jobs["compile"].Timeout)
+	assert.Equal(t, "30m0s", jobs["compile"].Steps[0]["timeout"])
+
+	payload.Stages["1|build"].Jobs["compile"] = Job{Steps: []Step{{"script": "make", "timeout": "soon"}}}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithConditions(t *testing.T) {
+	in := `name: build
+stages:
+  1|build:
+    jobs:
+      deploy:
+        if: git.branch == "master"
+        steps:
+        - script: make deploy
+        - script: make notify
+          if: previous.status == "Fail"
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, `git.branch == "master"`, job.Action.Condition)
+	assert.Equal(t, "", job.Action.Actions[0].Condition)
+	assert.Equal(t, `previous.status == "Fail"`, job.Action.Actions[1].Condition)
+
+	jobs := newJobs([]sdk.Job{job})
+	assert.Equal(t, `git.branch == "master"`, jobs["deploy"].If)
+	assert.Nil(t, jobs["deploy"].Steps[0]["if"])
+	assert.Equal(t, `previous.status == "Fail"`, jobs["deploy"].Steps[1]["if"])
+
+	payload.Stages["1|build"].Jobs["deploy"] = Job{Steps: []Step{{"script": "make", "if": `git.branch == "master`}}}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
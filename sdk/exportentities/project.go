@@ -0,0 +1,57 @@
+package exportentities
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+// Project represents exported sdk.Project with its variables and permissions
+type Project struct {
+	Key         string                   `json:"key" yaml:"key"`
+	Name        string                   `json:"name" yaml:"name"`
+	Permissions map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	Variables   map[string]VariableValue `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+//NewProject returns a Project from an sdk.Project pointer
+func NewProject(proj *sdk.Project) (p *Project) {
+	p = new(Project)
+	p.Key = proj.Key
+	p.Name = proj.Name
+	p.Variables = make(map[string]VariableValue, len(proj.Variable))
+	for _, v := range proj.Variable {
+		p.Variables[v.Name] = VariableValue{
+			Type:  string(v.Type),
+			Value: v.Value,
+		}
+	}
+	p.Permissions = make(map[string]int, len(proj.ProjectGroups))
+	for _, gp := range proj.ProjectGroups {
+		p.Permissions[gp.Group.Name] = gp.Permission
+	}
+	return
+}
+
+//Project returns a sdk.Project entity
+func (p *Project) Project() (proj *sdk.Project) {
+	proj = new(sdk.Project)
+	proj.Key = p.Key
+	proj.Name = p.Name
+	proj.Variable = make([]sdk.Variable, 0, len(p.Variables))
+	for k, v := range p.Variables {
+		proj.Variable = append(proj.Variable, sdk.Variable{
+			Name:  k,
+			Type:  v.Type,
+			Value: v.Value,
+		})
+	}
+	proj.ProjectGroups = make([]sdk.GroupPermission, 0, len(p.Permissions))
+	for k, v := range p.Permissions {
+		proj.ProjectGroups = append(proj.ProjectGroups, sdk.GroupPermission{
+			Group: sdk.Group{
+				Name: k,
+			},
+			Permission: v,
+		})
+	}
+	return
+}
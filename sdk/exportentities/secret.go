@@ -0,0 +1,25 @@
+package exportentities
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// secretRegexp matches the values encrypted with the key of a project: {{.secret "..."}}
+var secretRegexp = regexp.MustCompile(`^\{\{\s*\.secret\s+"([A-Za-z0-9+/=]+)"\s*\}\}$`)
+
+// Secret returns the exported form of a value encrypted with the key of a project. It can be
+// written in any format, and is decrypted by the API when the entity is imported in the project
+func Secret(encrypted string) string {
+	return fmt.Sprintf(`{{.secret "%s"}}`, encrypted)
+}
+
+// ParseSecret returns the encrypted value of a secret exported with Secret, and false if the
+// value is not a secret
+func ParseSecret(v string) (string, bool) {
+	m := secretRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
@@ -0,0 +1,262 @@
+package exportentities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// The files and the directories of a project tree
+const (
+	TreeProjectFile     = "project"
+	TreeApplicationsDir = "applications"
+	TreePipelinesDir    = "pipelines"
+	TreeEnvironmentsDir = "environments"
+	TreeWorkflowsDir    = "workflows"
+)
+
+// ProjectTree is a whole project exported as a directory tree: the project with its variables and
+// permissions in the project file, and a file for each application, pipeline, environment and
+// workflow in the directory of its kind
+type ProjectTree struct {
+	Project      Project       `json:"project"`
+	Applications []Application `json:"applications,omitempty"`
+	Pipelines    []Pipeline    `json:"pipelines,omitempty"`
+	Environments []Environment `json:"environments,omitempty"`
+	Workflows    []Workflow    `json:"workflows,omitempty"`
+}
+
+// Values calls a function on all the values of the variables and parameters of the tree, and
+// replaces them by the values it returns. It's used to encrypt the secrets when the tree is
+// exported, and to decrypt them when it's imported
+func (t *ProjectTree) Values(f func(name string, v VariableValue) (VariableValue, error)) error {
+	replace := func(prefix string, values map[string]VariableValue) error {
+		for k, v := range values {
+			nv, err := f(prefix+k, v)
+			if err != nil {
+				return err
+			}
+			values[k] = nv
+		}
+		return nil
+	}
+
+	if err := replace("project/", t.Project.Variables); err != nil {
+		return err
+	}
+	for _, a := range t.Applications {
+		if err := replace("application/"+a.Name+"/", a.Variables); err != nil {
+			return err
+		}
+		for name, p := range a.Pipelines {
+			if err := replace("application/"+a.Name+"/"+name+"/", p.Parameters); err != nil {
+				return err
+			}
+		}
+	}
+	for _, p := range t.Pipelines {
+		for k, v := range p.Parameters {
+			nv, err := f("pipeline/"+p.Name+"/"+k, VariableValue{Type: v.Type, Value: v.DefaultValue})
+			if err != nil {
+				return err
+			}
+			p.Parameters[k] = ParameterValue{Type: nv.Type, DefaultValue: nv.Value}
+		}
+	}
+	for _, e := range t.Environments {
+		if err := replace("environment/"+e.Name+"/", e.Values); err != nil {
+			return err
+		}
+	}
+	for _, w := range t.Workflows {
+		for _, n := range w.Nodes {
+			if err := replace("workflow/"+w.Name+"/"+n.Name+"/", n.Parameters); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteTree writes a project tree in a directory with one file by entity
+func WriteTree(dir string, t *ProjectTree, f Format) error {
+	if f != FormatYAML && f != FormatJSON {
+		return ErrUnsupportedFormat
+	}
+	ext := ".yml"
+	if f == FormatJSON {
+		ext = ".json"
+	}
+
+	write := func(subdir, name string, i interface{}) error {
+		d := filepath.Join(dir, subdir)
+		if err := os.MkdirAll(d, os.FileMode(0755)); err != nil {
+			return err
+		}
+		btes, err := Marshal(i, f)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(d, name+ext), btes, os.FileMode(0644))
+	}
+
+	if err := write("", TreeProjectFile, t.Project); err != nil {
+		return err
+	}
+	for _, a := range t.Applications {
+		if err := write(TreeApplicationsDir, a.Name, a); err != nil {
+			return err
+		}
+	}
+	for _, p := range t.Pipelines {
+		if err := write(TreePipelinesDir, p.Name, p); err != nil {
+			return err
+		}
+	}
+	for _, e := range t.Environments {
+		if err := write(TreeEnvironmentsDir, e.Name, e); err != nil {
+			return err
+		}
+	}
+	for _, w := range t.Workflows {
+		if err := write(TreeWorkflowsDir, w.Name, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadTree reads a project tree written by WriteTree, the files can be in YAML or JSON
+func ReadTree(dir string) (*ProjectTree, error) {
+	t := new(ProjectTree)
+
+	files, err := treeFiles(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	var found bool
+	for _, file := range files {
+		if strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)) == TreeProjectFile {
+			if err := readTreeFile(file, &t.Project); err != nil {
+				return nil, err
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s: project file not found", dir)
+	}
+
+	if files, err = treeFiles(dir, TreeApplicationsDir); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		var a Application
+		if err := readTreeFile(file, &a); err != nil {
+			return nil, err
+		}
+		t.Applications = append(t.Applications, a)
+	}
+
+	if files, err = treeFiles(dir, TreePipelinesDir); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		var p Pipeline
+		if err := readTreeFile(file, &p); err != nil {
+			return nil, err
+		}
+		t.Pipelines = append(t.Pipelines, p)
+	}
+
+	if files, err = treeFiles(dir, TreeEnvironmentsDir); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		var e Environment
+		if err := readTreeFile(file, &e); err != nil {
+			return nil, err
+		}
+		t.Environments = append(t.Environments, e)
+	}
+
+	if files, err = treeFiles(dir, TreeWorkflowsDir); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		var w Workflow
+		if err := readTreeFile(file, &w); err != nil {
+			return nil, err
+		}
+		t.Workflows = append(t.Workflows, w)
+	}
+	return t, nil
+}
+
+// treeFiles returns the YAML and JSON files of a directory of a tree, sorted by name
+func treeFiles(dir, subdir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(dir, subdir))
+	if err != nil {
+		if os.IsNotExist(err) && subdir != "" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	files := []string{}
+	for _, i := range infos {
+		switch filepath.Ext(i.Name()) {
+		case ".yml", ".yaml", ".json":
+			if !i.IsDir() {
+				files = append(files, filepath.Join(dir, subdir, i.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readTreeFile reads a file of a tree. The YAML files are converted to JSON first, so that the
+// entities can be sent as JSON whatever the format they were read from
+func readTreeFile(file string, i interface{}) error {
+	btes, format, err := ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if format == FormatYAML {
+		var v interface{}
+		if err := yaml.Unmarshal(btes, &v); err != nil {
+			return fmt.Errorf("%s: %s", file, err)
+		}
+		if btes, err = json.Marshal(jsonValue(v)); err != nil {
+			return fmt.Errorf("%s: %s", file, err)
+		}
+	}
+	if err := json.Unmarshal(btes, i); err != nil {
+		return fmt.Errorf("%s: %s", file, err)
+	}
+	return nil
+}
+
+// jsonValue replaces the maps decoded from YAML, which can have any type of keys, by maps with string keys
+func jsonValue(i interface{}) interface{} {
+	switch v := i.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, c := range v {
+			m[fmt.Sprintf("%v", k)] = jsonValue(c)
+		}
+		return m
+	case []interface{}:
+		for k, c := range v {
+			v[k] = jsonValue(c)
+		}
+	}
+	return i
+}
@@ -0,0 +1,95 @@
+package exportentities
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree(t *testing.T) {
+	tree := &ProjectTree{
+		Project: Project{
+			Key:         "KEY",
+			Name:        "My project",
+			Permissions: map[string]int{"devs": 7},
+			Variables: map[string]VariableValue{
+				"foo":      {Type: "string", Value: "bar"},
+				"password": {Type: "password", Value: "secret"},
+			},
+		},
+		Applications: []Application{{Name: "my-app", Variables: map[string]VariableValue{"token": {Type: "password", Value: "secret"}}}},
+		Pipelines: []Pipeline{{
+			Name:  "build",
+			Type:  "build",
+			Steps: []Step{{"script": "make"}, {"gitClone": map[string]interface{}{"branch": "master"}}},
+		}},
+		Environments: []Environment{{Name: "prod", Values: map[string]VariableValue{"url": {Type: "string", Value: "http://prod"}}, Permissions: map[string]int{"ops": 7}}},
+		Workflows:    []Workflow{{Name: "my-workflow", Nodes: []WorkflowNode{{Name: "build", Pipeline: "build", Application: "my-app"}}}},
+	}
+
+	dir, err := ioutil.TempDir("", "cds-tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range []Format{FormatYAML, FormatJSON} {
+		d := filepath.Join(dir, map[Format]string{FormatYAML: "yaml", FormatJSON: "json"}[f])
+		if err := WriteTree(d, tree, f); err != nil {
+			t.Fatal(err)
+		}
+		res, err := ReadTree(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, tree.Project, res.Project)
+		assert.Equal(t, tree.Applications, res.Applications)
+		assert.Equal(t, tree.Environments, res.Environments)
+		assert.Equal(t, tree.Workflows, res.Workflows)
+		if assert.Len(t, res.Pipelines, 1) {
+			assert.Equal(t, "build", res.Pipelines[0].Name)
+			assert.Equal(t, Step{"script": "make"}, res.Pipelines[0].Steps[0])
+			assert.Equal(t, Step{"gitClone": map[string]interface{}{"branch": "master"}}, res.Pipelines[0].Steps[1])
+		}
+	}
+
+	assert.Error(t, WriteTree(dir, tree, FormatHCL))
+	_, err = ReadTree(filepath.Join(dir, "unknown"))
+	assert.Error(t, err)
+}
+
+func TestTreeValues(t *testing.T) {
+	tree := &ProjectTree{
+		Project:      Project{Variables: map[string]VariableValue{"password": {Type: "password", Value: "secret"}}},
+		Applications: []Application{{Name: "my-app", Variables: map[string]VariableValue{"key": {Type: "key", Value: "private"}}}},
+		Pipelines:    []Pipeline{{Name: "build", Parameters: map[string]ParameterValue{"token": {Type: "password", DefaultValue: "secret"}}}},
+	}
+
+	names := []string{}
+	err := tree.Values(func(name string, v VariableValue) (VariableValue, error) {
+		names = append(names, name)
+		return VariableValue{Type: v.Type, Value: Secret("ZW5jcnlwdGVk")}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"project/password", "application/my-app/key", "pipeline/build/token"}, names)
+	assert.Equal(t, VariableValue{Type: "password", Value: `{{.secret "ZW5jcnlwdGVk"}}`}, tree.Project.Variables["password"])
+	assert.Equal(t, ParameterValue{Type: "password", DefaultValue: `{{.secret "ZW5jcnlwdGVk"}}`}, tree.Pipelines[0].Parameters["token"])
+}
+
+func TestParseSecret(t *testing.T) {
+	v, ok := ParseSecret(Secret("ZW5j/cnlw+dGVk=="))
+	assert.True(t, ok)
+	assert.Equal(t, "ZW5j/cnlw+dGVk==", v)
+
+	v, ok = ParseSecret(`{{ .secret "ZW5jcnlwdGVk" }}`)
+	assert.True(t, ok)
+	assert.Equal(t, "ZW5jcnlwdGVk", v)
+
+	for _, s := range []string{"secret", `{{.cds.secret "ZW5jcnlwdGVk"}}`, `foo {{.secret "ZW5jcnlwdGVk"}}`, `{{.secret "not base64!"}}`} {
+		_, ok := ParseSecret(s)
+		assert.False(t, ok, s)
+	}
+}
@@ -0,0 +1,247 @@
+package exportentities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Workflow represents exported sdk.Workflow. Its nodes are listed with the names of the nodes they
+// depend on: the root node depends on none, and a node depending on several nodes is run when
+// all of them are done
+type Workflow struct {
+	Name        string         `json:"name" yaml:"name"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Nodes       []WorkflowNode `json:"nodes" yaml:"nodes"`
+}
+
+// WorkflowNode represents exported sdk.WorkflowNode with the trigger leading to it
+type WorkflowNode struct {
+	Name        string                   `json:"name" yaml:"name"`
+	DependsOn   []string                 `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Pipeline    string                   `json:"pipeline" yaml:"pipeline"`
+	Application string                   `json:"application,omitempty" yaml:"application,omitempty"`
+	Environment string                   `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Parameters  map[string]VariableValue `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Payload     interface{}              `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Manual      bool                     `json:"manual,omitempty" yaml:"manual,omitempty"`
+	Conditions  []WorkflowCondition      `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Hooks       []WorkflowHook           `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+}
+
+// WorkflowCondition represents sdk.WorkflowTriggerCondition
+type WorkflowCondition struct {
+	Variable string `json:"variable" yaml:"variable"`
+	Operator string `json:"operator" yaml:"operator"`
+	Value    string `json:"value" yaml:"value"`
+}
+
+// WorkflowHook represents exported sdk.WorkflowNodeHook
+type WorkflowHook struct {
+	Model  string            `json:"model" yaml:"model"`
+	Config map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+//NewWorkflow returns a Workflow from an sdk.Workflow pointer
+func NewWorkflow(w *sdk.Workflow) (*Workflow, error) {
+	wf := &Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+		Nodes:       []WorkflowNode{},
+	}
+	if w.Root == nil {
+		return nil, sdk.ErrWorkflowInvalidRoot
+	}
+
+	wf.addNode(w.Root, nil, false, nil)
+	for _, j := range w.Joins {
+		deps := make([]string, len(j.SourceNodeIDs))
+		for i, id := range j.SourceNodeIDs {
+			n := w.GetNode(id)
+			if n == nil {
+				return nil, fmt.Errorf("Unknown node %d in join %d of workflow %s", id, j.ID, w.Name)
+			}
+			deps[i] = n.Name
+		}
+		sort.Strings(deps)
+		for i := range j.Triggers {
+			t := &j.Triggers[i]
+			wf.addNode(&t.WorkflowDestNode, deps, t.Manual, t.Conditions)
+		}
+	}
+	return wf, nil
+}
+
+// addNode adds a node and the nodes it triggers
+func (w *Workflow) addNode(n *sdk.WorkflowNode, deps []string, manual bool, conditions []sdk.WorkflowTriggerCondition) {
+	node := WorkflowNode{
+		Name:      n.Name,
+		DependsOn: deps,
+		Pipeline:  n.Pipeline.Name,
+		Manual:    manual,
+	}
+	if n.Context != nil {
+		if n.Context.Application != nil {
+			node.Application = n.Context.Application.Name
+		}
+		if n.Context.Environment != nil && n.Context.Environment.Name != sdk.DefaultEnv.Name {
+			node.Environment = n.Context.Environment.Name
+		}
+		if len(n.Context.DefaultPipelineParameters) > 0 {
+			node.Parameters = make(map[string]VariableValue, len(n.Context.DefaultPipelineParameters))
+			for _, p := range n.Context.DefaultPipelineParameters {
+				node.Parameters[p.Name] = VariableValue{Type: p.Type, Value: p.Value}
+			}
+		}
+		node.Payload = n.Context.DefaultPayload
+	}
+	for _, c := range conditions {
+		node.Conditions = append(node.Conditions, WorkflowCondition{Variable: c.Variable, Operator: c.Operator, Value: c.Value})
+	}
+	for _, h := range n.Hooks {
+		hook := WorkflowHook{Model: h.WorkflowHookModel.Name, Config: map[string]string{}}
+		for k, v := range h.Config {
+			// The project and the workflow are set when the hook is inserted
+			if k != "project" && k != "workflow" {
+				hook.Config[k] = v
+			}
+		}
+		node.Hooks = append(node.Hooks, hook)
+	}
+	w.Nodes = append(w.Nodes, node)
+
+	for i := range n.Triggers {
+		t := &n.Triggers[i]
+		w.addNode(&t.WorkflowDestNode, []string{n.Name}, t.Manual, t.Conditions)
+	}
+}
+
+//Workflow returns a sdk.Workflow entity. Its pipelines, applications, environments and hook models
+//are only named, they must be loaded by the caller
+func (w *Workflow) Workflow() (*sdk.Workflow, error) {
+	nodes := make(map[string]*WorkflowNode, len(w.Nodes))
+	children := map[string][]*WorkflowNode{}
+	var root *WorkflowNode
+	for i := range w.Nodes {
+		n := &w.Nodes[i]
+		if n.Name == "" || n.Pipeline == "" {
+			return nil, sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Nodes must have a name and a pipeline"))
+		}
+		if _, has := nodes[n.Name]; has {
+			return nil, sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Duplicate node %s", n.Name))
+		}
+		nodes[n.Name] = n
+
+		if len(n.DependsOn) == 0 {
+			if root != nil {
+				return nil, sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Nodes %s and %s have no dependency, there must be a single root", root.Name, n.Name))
+			}
+			root = n
+			continue
+		}
+		deps := append([]string{}, n.DependsOn...)
+		sort.Strings(deps)
+		k := strings.Join(deps, ",")
+		children[k] = append(children[k], n)
+	}
+	if root == nil {
+		return nil, sdk.ErrWorkflowInvalidRoot
+	}
+
+	wf := &sdk.Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+	}
+	built := map[string]bool{}
+	rootNode := w.node(root, children, built)
+	wf.Root = &rootNode
+
+	// The nodes depending on several nodes are triggered by the join of these nodes
+	keys := make([]string, 0, len(children))
+	for k := range children {
+		if strings.Contains(k, ",") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		j := sdk.WorkflowNodeJoin{SourceNodeRefs: strings.Split(k, ",")}
+		for _, ref := range j.SourceNodeRefs {
+			if _, has := nodes[ref]; !has {
+				return nil, sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Unknown node %s", ref))
+			}
+		}
+		for _, n := range children[k] {
+			j.Triggers = append(j.Triggers, sdk.WorkflowNodeJoinTrigger{
+				WorkflowDestNode: w.node(n, children, built),
+				Manual:           n.Manual,
+				Conditions:       n.conditions(),
+			})
+		}
+		wf.Joins = append(wf.Joins, j)
+	}
+
+	if len(built) != len(nodes) {
+		for name := range nodes {
+			if !built[name] {
+				return nil, sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Node %s can't be reached from the root", name))
+			}
+		}
+	}
+	return wf, nil
+}
+
+// node returns the sdk.WorkflowNode of a node with the triggers of the nodes depending only on it
+func (w *Workflow) node(n *WorkflowNode, children map[string][]*WorkflowNode, built map[string]bool) sdk.WorkflowNode {
+	built[n.Name] = true
+	node := sdk.WorkflowNode{
+		Name:     n.Name,
+		Ref:      n.Name,
+		Pipeline: sdk.Pipeline{Name: n.Pipeline},
+		Context:  &sdk.WorkflowNodeContext{DefaultPayload: n.Payload},
+	}
+	if n.Application != "" {
+		node.Context.Application = &sdk.Application{Name: n.Application}
+	}
+	if n.Environment != "" {
+		node.Context.Environment = &sdk.Environment{Name: n.Environment}
+	}
+	for k, v := range n.Parameters {
+		node.Context.DefaultPipelineParameters = append(node.Context.DefaultPipelineParameters, sdk.Parameter{Name: k, Type: v.Type, Value: v.Value})
+	}
+	sort.Slice(node.Context.DefaultPipelineParameters, func(i, j int) bool {
+		return node.Context.DefaultPipelineParameters[i].Name < node.Context.DefaultPipelineParameters[j].Name
+	})
+	for _, h := range n.Hooks {
+		config := sdk.WorkflowNodeHookConfig{}
+		for k, v := range h.Config {
+			config[k] = v
+		}
+		node.Hooks = append(node.Hooks, sdk.WorkflowNodeHook{
+			WorkflowHookModel: sdk.WorkflowHookModel{Name: h.Model},
+			Config:            config,
+		})
+	}
+
+	for _, c := range children[n.Name] {
+		if built[c.Name] {
+			continue
+		}
+		node.Triggers = append(node.Triggers, sdk.WorkflowNodeTrigger{
+			WorkflowDestNode: w.node(c, children, built),
+			Manual:           c.Manual,
+			Conditions:       c.conditions(),
+		})
+	}
+	return node
+}
+
+func (n *WorkflowNode) conditions() []sdk.WorkflowTriggerCondition {
+	var res []sdk.WorkflowTriggerCondition
+	for _, c := range n.Conditions {
+		res = append(res, sdk.WorkflowTriggerCondition{Variable: c.Variable, Operator: c.Operator, Value: c.Value})
+	}
+	return res
+}
@@ -0,0 +1,133 @@
+package exportentities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflow(t *testing.T) {
+	w := &sdk.Workflow{
+		Name: "my-workflow",
+		Root: &sdk.WorkflowNode{
+			ID:       1,
+			Name:     "build",
+			Pipeline: sdk.Pipeline{Name: "build"},
+			Context: &sdk.WorkflowNodeContext{
+				Application: &sdk.Application{Name: "my-app"},
+			},
+			Hooks: []sdk.WorkflowNodeHook{
+				{
+					WorkflowHookModel: sdk.WorkflowHookModel{Name: "Scheduler"},
+					Config:            sdk.WorkflowNodeHookConfig{"project": "KEY", "workflow": "my-workflow", "cron": "0 * * * *"},
+				},
+			},
+			Triggers: []sdk.WorkflowNodeTrigger{
+				{
+					WorkflowDestNode: sdk.WorkflowNode{
+						ID:       2,
+						Name:     "test",
+						Pipeline: sdk.Pipeline{Name: "test"},
+					},
+				},
+				{
+					Manual:     true,
+					Conditions: []sdk.WorkflowTriggerCondition{{Variable: "git.branch", Operator: "eq", Value: "master"}},
+					WorkflowDestNode: sdk.WorkflowNode{
+						ID:       3,
+						Name:     "lint",
+						Pipeline: sdk.Pipeline{Name: "lint"},
+					},
+				},
+			},
+		},
+		Joins: []sdk.WorkflowNodeJoin{
+			{
+				SourceNodeIDs: []int64{3, 2},
+				Triggers: []sdk.WorkflowNodeJoinTrigger{
+					{
+						WorkflowDestNode: sdk.WorkflowNode{
+							Name:     "deploy",
+							Pipeline: sdk.Pipeline{Name: "deploy"},
+							Context: &sdk.WorkflowNodeContext{
+								Application: &sdk.Application{Name: "my-app"},
+								Environment: &sdk.Environment{Name: "prod"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	wf, err := NewWorkflow(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, wf.Nodes, 4) {
+		t.FailNow()
+	}
+	assert.Equal(t, WorkflowNode{
+		Name:        "build",
+		Pipeline:    "build",
+		Application: "my-app",
+		Hooks:       []WorkflowHook{{Model: "Scheduler", Config: map[string]string{"cron": "0 * * * *"}}},
+	}, wf.Nodes[0])
+	assert.Equal(t, []string{"build"}, wf.Nodes[2].DependsOn)
+	assert.True(t, wf.Nodes[2].Manual)
+	assert.Equal(t, []WorkflowCondition{{Variable: "git.branch", Operator: "eq", Value: "master"}}, wf.Nodes[2].Conditions)
+	assert.Equal(t, []string{"lint", "test"}, wf.Nodes[3].DependsOn)
+	assert.Equal(t, "prod", wf.Nodes[3].Environment)
+
+	res, err := wf.Workflow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "build", res.Root.Name)
+	assert.Equal(t, "my-app", res.Root.Context.Application.Name)
+	assert.Equal(t, "Scheduler", res.Root.Hooks[0].WorkflowHookModel.Name)
+	if !assert.Len(t, res.Root.Triggers, 2) || !assert.Len(t, res.Joins, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "test", res.Root.Triggers[0].WorkflowDestNode.Name)
+	assert.True(t, res.Root.Triggers[1].Manual)
+	assert.Equal(t, []string{"lint", "test"}, res.Joins[0].SourceNodeRefs)
+	assert.Equal(t, "deploy", res.Joins[0].Triggers[0].WorkflowDestNode.Name)
+	assert.Equal(t, "prod", res.Joins[0].Triggers[0].WorkflowDestNode.Context.Environment.Name)
+
+	// The exported workflow is the same once imported and exported again
+	res.Root.ID = 1
+	res.Root.Triggers[0].WorkflowDestNode.ID = 2
+	res.Root.Triggers[1].WorkflowDestNode.ID = 3
+	res.Joins[0].SourceNodeIDs = []int64{2, 3}
+	wf2, err := NewWorkflow(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, wf, wf2)
+}
+
+func TestWorkflowInvalid(t *testing.T) {
+	wf := Workflow{Name: "my-workflow", Nodes: []WorkflowNode{
+		{Name: "build", Pipeline: "build"},
+		{Name: "test", Pipeline: "test"},
+	}}
+	_, err := wf.Workflow()
+	assert.Error(t, err, "two roots")
+
+	wf.Nodes[1].DependsOn = []string{"deploy"}
+	wf.Nodes = append(wf.Nodes, WorkflowNode{Name: "deploy", Pipeline: "deploy", DependsOn: []string{"test"}})
+	_, err = wf.Workflow()
+	assert.Error(t, err, "unreachable nodes")
+
+	wf.Nodes[2].DependsOn = []string{"build", "unknown"}
+	_, err = wf.Workflow()
+	assert.Error(t, err, "unknown dependency")
+
+	wf.Nodes[1].DependsOn = []string{"build"}
+	wf.Nodes[2].DependsOn = []string{"test"}
+	_, err = wf.Workflow()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,268 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EvaluateExpression evaluates a boolean expression against variables, ie:
+//   git.branch == "master" && previous.status == "Success"
+// Operands are variable names, "quoted strings", numbers or true/false. Unknown variables are empty.
+// Operators are ==, !=, <, <=, >, >=, =~ (regex match), !~, &&, ||, ! and parenthesis.
+// Values are compared as numbers if both are numbers, as strings otherwise.
+func EvaluateExpression(expr string, vars map[string]string) (bool, error) {
+	tokens, err := tokenizeExpression(expr)
+	if err != nil {
+		return false, err
+	}
+	if len(tokens) == 0 {
+		return true, nil
+	}
+
+	p := &expressionParser{tokens: tokens, vars: vars}
+	res, err := p.parseOr()
+	if err != nil {
+		return false, fmt.Errorf("Invalid expression %s: %s", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("Invalid expression %s: unexpected %s", expr, p.tokens[p.pos].value)
+	}
+	return res.value == "true", nil
+}
+
+const (
+	tokenOperand = iota
+	tokenString
+	tokenOperator
+)
+
+type expressionToken struct {
+	kind  int
+	value string
+}
+
+var expressionOperators = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "(", ")"}
+
+func tokenizeExpression(expr string) ([]expressionToken, error) {
+	var tokens []expressionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			var value []rune
+			j := i + 1
+			for ; j < len(runes) && runes[j] != c; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				value = append(value, runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("Invalid expression %s: unterminated string", expr)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenString, value: string(value)})
+			i = j + 1
+		default:
+			var op string
+			for _, o := range expressionOperators {
+				if strings.HasPrefix(string(runes[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op != "" {
+				tokens = append(tokens, expressionToken{kind: tokenOperator, value: op})
+				i += len([]rune(op))
+				continue
+			}
+
+			j := i
+			for ; j < len(runes) && isExpressionOperandRune(runes[j]); j++ {
+			}
+			if j == i {
+				return nil, fmt.Errorf("Invalid expression %s: unexpected character %c", expr, c)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenOperand, value: string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func isExpressionOperandRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-'
+}
+
+type expressionParser struct {
+	tokens []expressionToken
+	pos    int
+	vars   map[string]string
+}
+
+type expressionValue struct {
+	value string
+}
+
+func boolValue(b bool) expressionValue {
+	return expressionValue{value: strconv.FormatBool(b)}
+}
+
+func (p *expressionParser) peek() *expressionToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *expressionParser) isOperator(op string) bool {
+	t := p.peek()
+	return t != nil && t.kind == tokenOperator && t.value == op
+}
+
+func (p *expressionParser) parseOr() (expressionValue, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return left, err
+	}
+	for p.isOperator("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return right, err
+		}
+		left = boolValue(left.value == "true" || right.value == "true")
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (expressionValue, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return left, err
+	}
+	for p.isOperator("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return right, err
+		}
+		left = boolValue(left.value == "true" && right.value == "true")
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (expressionValue, error) {
+	if p.isOperator("!") {
+		p.pos++
+		v, err := p.parseUnary()
+		if err != nil {
+			return v, err
+		}
+		return boolValue(v.value != "true"), nil
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (expressionValue, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return left, err
+	}
+
+	t := p.peek()
+	if t == nil || t.kind != tokenOperator {
+		return left, nil
+	}
+	op := t.value
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+	default:
+		return left, nil
+	}
+	p.pos++
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return right, err
+	}
+
+	switch op {
+	case "==":
+		return boolValue(compareExpressionValues(left.value, right.value) == 0), nil
+	case "!=":
+		return boolValue(compareExpressionValues(left.value, right.value) != 0), nil
+	case "<":
+		return boolValue(compareExpressionValues(left.value, right.value) < 0), nil
+	case "<=":
+		return boolValue(compareExpressionValues(left.value, right.value) <= 0), nil
+	case ">":
+		return boolValue(compareExpressionValues(left.value, right.value) > 0), nil
+	case ">=":
+		return boolValue(compareExpressionValues(left.value, right.value) >= 0), nil
+	}
+
+	match, err := regexp.MatchString(right.value, left.value)
+	if err != nil {
+		return left, fmt.Errorf("invalid regex %s", right.value)
+	}
+	if op == "!~" {
+		match = !match
+	}
+	return boolValue(match), nil
+}
+
+func (p *expressionParser) parseOperand() (expressionValue, error) {
+	t := p.peek()
+	if t == nil {
+		return expressionValue{}, fmt.Errorf("unexpected end")
+	}
+	p.pos++
+
+	switch t.kind {
+	case tokenString:
+		return expressionValue{value: t.value}, nil
+	case tokenOperator:
+		if t.value != "(" {
+			return expressionValue{}, fmt.Errorf("unexpected %s", t.value)
+		}
+		v, err := p.parseOr()
+		if err != nil {
+			return v, err
+		}
+		if !p.isOperator(")") {
+			return v, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+
+	if t.value == "true" || t.value == "false" {
+		return expressionValue{value: t.value}, nil
+	}
+	if _, err := strconv.ParseFloat(t.value, 64); err == nil {
+		return expressionValue{value: t.value}, nil
+	}
+	return expressionValue{value: p.vars[t.value]}, nil
+}
+
+// compareExpressionValues compares values as numbers if both are numbers, as strings otherwise
+func compareExpressionValues(a, b string) int {
+	fa, erra := strconv.ParseFloat(a, 64)
+	fb, errb := strconv.ParseFloat(b, 64)
+	if erra != nil || errb != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	vars := map[string]string{
+		"git.branch":      "master",
+		"previous.status": "Success",
+		"cds.version":     "12",
+	}
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{``, true},
+		{`git.branch == "master"`, true},
+		{`git.branch == 'develop'`, false},
+		{`git.branch != "master"`, false},
+		{`git.branch == "master" && previous.status == "Success"`, true},
+		{`git.branch == "develop" || previous.status == "Success"`, true},
+		{`!(git.branch == "master")`, false},
+		{`git.branch =~ "^mas"`, true},
+		{`git.branch !~ "^feat/"`, true},
+		{`cds.version > 9`, true},
+		{`cds.version >= 12 && cds.version < 13`, true},
+		{`unknown == ""`, true},
+		{`true`, true},
+		{`git.branch == "develop" || (previous.status == "Success" && !false)`, true},
+	}
+
+	for _, tt := range tests {
+		res, err := EvaluateExpression(tt.expr, vars)
+		assert.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.expected, res, tt.expr)
+	}
+
+	for _, expr := range []string{`git.branch ==`, `(true`, `"master`, `git.branch == "a" "b"`, `git.branch =~ "("`} {
+		_, err := EvaluateExpression(expr, vars)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,35 @@
+package sdk
+
+// GroupQuota limits the workers used by the jobs of the projects of a group.
+// A zero value means no limit
+type GroupQuota struct {
+	GroupID          int64             `json:"group_id" db:"group_id" cli:"-"`
+	MaxWorkers       int64             `json:"max_workers" db:"max_workers" cli:"max_workers"`
+	CPUMinutesPerDay int64             `json:"cpu_minutes_per_day" db:"cpu_minutes_per_day" cli:"cpu_minutes_per_day"`
+	Models           []GroupModelQuota `json:"models,omitempty" db:"-" cli:"-"`
+}
+
+// GroupModelQuota limits the workers of a model used by the jobs of a group
+type GroupModelQuota struct {
+	Model      string `json:"model" cli:"model"`
+	MaxWorkers int64  `json:"max_workers" cli:"max_workers"`
+}
+
+// ModelQuota returns the max number of workers of the model, 0 if there is no limit
+func (q GroupQuota) ModelQuota(model string) int64 {
+	for _, m := range q.Models {
+		if m.Model == model {
+			return m.MaxWorkers
+		}
+	}
+	return 0
+}
+
+// GroupQuotaUsage is the quota of a group and its current usage
+type GroupQuotaUsage struct {
+	Group           string           `json:"group" cli:"group"`
+	Quota           GroupQuota       `json:"quota" cli:"-"`
+	Workers         int64            `json:"workers" cli:"workers"`
+	ModelWorkers    map[string]int64 `json:"model_workers,omitempty" cli:"-"`
+	CPUMinutesToday int64            `json:"cpu_minutes_today" cli:"cpu_minutes_today"`
+}
@@ -0,0 +1,20 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupQuotaModelQuota(t *testing.T) {
+	q := GroupQuota{
+		MaxWorkers: 10,
+		Models: []GroupModelQuota{
+			{Model: "golang", MaxWorkers: 2},
+			{Model: "docker", MaxWorkers: 5},
+		},
+	}
+	assert.Equal(t, int64(2), q.ModelQuota("golang"))
+	assert.Equal(t, int64(5), q.ModelQuota("docker"))
+	assert.Equal(t, int64(0), q.ModelQuota("unknown"))
+}
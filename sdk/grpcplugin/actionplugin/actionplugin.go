@@ -0,0 +1,144 @@
+package actionplugin
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/ovh/cds/sdk/grpcplugin"
+	"github.com/ovh/cds/sdk/plugin"
+)
+
+// ProtocolVersion is the version of the action plugin protocol served over grpc.
+// The net/rpc plugins of the sdk/plugin package are the version 1
+const ProtocolVersion = 2
+
+// Status of the ActionResult
+const (
+	Success = plugin.Success
+	Fail    = plugin.Fail
+)
+
+// Serve has to be called in main func of every plugin. It blocks until the worker stops the plugin
+func Serve(srv ActionPluginServer) error {
+	listener, err := grpcplugin.Listen(ProtocolVersion, grpcplugin.ProtocolGRPC)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	s := grpc.NewServer()
+	RegisterActionPluginServer(s, srv)
+	return s.Serve(listener)
+}
+
+// Client is a grpc client on an action plugin
+type Client struct {
+	ActionPluginClient
+	conn *grpc.ClientConn
+}
+
+// NewClient connects to a plugin started with grpcplugin.Start
+func NewClient(p *grpcplugin.Plugin) (*Client, error) {
+	if p.Version != ProtocolVersion || p.Protocol != grpcplugin.ProtocolGRPC {
+		return nil, fmt.Errorf("Plugin %s speaks the protocol %s version %d", p.Binary, p.Protocol, p.Version)
+	}
+
+	network := p.Network
+	conn, err := grpc.Dial(p.Address,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithTimeout(grpcplugin.StartTimeout),
+		grpc.WithDialer(func(addr string, timeout time.Duration) (net.Conn, error) {
+			return net.DialTimeout(network, addr, timeout)
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to connect to plugin %s: %s", p.Binary, err)
+	}
+	return &Client{NewActionPluginClient(conn), conn}, nil
+}
+
+// Close closes the connection to the plugin
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Manifest returns the manifest of a started plugin, whatever the protocol version it speaks.
+// The name is used to dispense the net/rpc plugins
+func Manifest(ctx context.Context, p *grpcplugin.Plugin, name string) (*ActionPluginManifest, error) {
+	if p.Version == ProtocolVersion {
+		c, err := NewClient(p)
+		if err != nil {
+			return nil, err
+		}
+		defer c.Close()
+		return c.ActionPluginClient.Manifest(ctx, &empty.Empty{})
+	}
+
+	reattach, err := p.ReattachConfig()
+	if err != nil {
+		return nil, err
+	}
+	client := plugin.NewReattachClient(name, p.Binary, reattach, "", "", false)
+	defer client.Kill()
+
+	_plugin, err := client.Instance()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ActionPluginManifest{
+		Name:        _plugin.Name(),
+		Author:      _plugin.Author(),
+		Description: _plugin.Description(),
+	}
+	params := _plugin.Parameters()
+	for _, name := range params.Names() {
+		m.Parameters = append(m.Parameters, &Parameter{
+			Name:        name,
+			Type:        string(params.GetType(name)),
+			Description: params.GetDescription(name),
+			Value:       params.GetValue(name),
+		})
+	}
+	return m, nil
+}
+
+// Log streams a log line to the worker
+func Log(stream ActionPlugin_RunServer, format string, args ...interface{}) error {
+	return stream.Send(&ActionEvent{Log: fmt.Sprintf(format, args...)})
+}
+
+// SetVariable streams a build variable to the worker. It will be available as cds.build.<name>
+func SetVariable(stream ActionPlugin_RunServer, name, value string) error {
+	return stream.Send(&ActionEvent{Variable: &Variable{Name: name, Value: value}})
+}
+
+// UploadArtifact asks the worker to upload the file as an artifact
+func UploadArtifact(stream ActionPlugin_RunServer, path, tag string) error {
+	return stream.Send(&ActionEvent{Artifact: &Artifact{Path: path, Tag: tag}})
+}
+
+// SendTests streams test results to the worker
+func SendTests(stream ActionPlugin_RunServer, report *TestsReport) error {
+	return stream.Send(&ActionEvent{Tests: report})
+}
+
+// SendResult ends the run with the given status, Success or Fail
+func SendResult(stream ActionPlugin_RunServer, status, details string) error {
+	return stream.Send(&ActionEvent{Result: &ActionResult{Status: status, Details: details}})
+}
+
+// PluginParameters returns the parameters of the manifest the way the
+// net/rpc plugins describe them
+func (m *ActionPluginManifest) PluginParameters() plugin.Parameters {
+	params := plugin.NewParameters()
+	for _, p := range m.Parameters {
+		params.Add(p.Name, plugin.ParameterType(p.Type), p.Description, p.Value)
+	}
+	return params
+}
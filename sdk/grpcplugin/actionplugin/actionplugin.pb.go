@@ -0,0 +1,600 @@
+// Code generated by protoc-gen-go.
+// source: actionplugin.proto
+// DO NOT EDIT!
+
+/*
+Package actionplugin is a generated protocol buffer package.
+
+It is generated from these files:
+	actionplugin.proto
+
+It has these top-level messages:
+	ActionPluginManifest
+	Parameter
+	ActionQuery
+	ActionEvent
+	Variable
+	Artifact
+	TestsReport
+	TestSuite
+	TestCase
+	ActionResult
+*/
+package actionplugin
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/empty"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ActionPluginManifest describes the plugin
+type ActionPluginManifest struct {
+	Name        string       `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Version     string       `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	Description string       `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	Author      string       `protobuf:"bytes,4,opt,name=author" json:"author,omitempty"`
+	Parameters  []*Parameter `protobuf:"bytes,5,rep,name=parameters" json:"parameters,omitempty"`
+}
+
+func (m *ActionPluginManifest) Reset()                    { *m = ActionPluginManifest{} }
+func (m *ActionPluginManifest) String() string            { return proto.CompactTextString(m) }
+func (*ActionPluginManifest) ProtoMessage()               {}
+func (*ActionPluginManifest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ActionPluginManifest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *ActionPluginManifest) GetParameters() []*Parameter {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+// Parameter is a parameter of the action registered by the plugin
+type Parameter struct {
+	Name        string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Type        string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+	Value       string `protobuf:"bytes,4,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Parameter) Reset()                    { *m = Parameter{} }
+func (m *Parameter) String() string            { return proto.CompactTextString(m) }
+func (*Parameter) ProtoMessage()               {}
+func (*Parameter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Parameter) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Parameter) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Parameter) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Parameter) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// ActionQuery is the input of the Run rpc
+type ActionQuery struct {
+	Options   map[string]string `protobuf:"bytes,1,rep,name=options" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Secrets   map[string]string `protobuf:"bytes,2,rep,name=secrets" json:"secrets,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	JobId     int64             `protobuf:"varint,3,opt,name=job_id,json=jobId" json:"job_id,omitempty"`
+	StepOrder int64             `protobuf:"varint,4,opt,name=step_order,json=stepOrder" json:"step_order,omitempty"`
+}
+
+func (m *ActionQuery) Reset()                    { *m = ActionQuery{} }
+func (m *ActionQuery) String() string            { return proto.CompactTextString(m) }
+func (*ActionQuery) ProtoMessage()               {}
+func (*ActionQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ActionQuery) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ActionQuery) GetSecrets() map[string]string {
+	if m != nil {
+		return m.Secrets
+	}
+	return nil
+}
+
+func (m *ActionQuery) GetJobId() int64 {
+	if m != nil {
+		return m.JobId
+	}
+	return 0
+}
+
+func (m *ActionQuery) GetStepOrder() int64 {
+	if m != nil {
+		return m.StepOrder
+	}
+	return 0
+}
+
+// ActionEvent is streamed by the plugin during Run. Only one field is set on each event,
+// the last event of the stream must carry the result
+type ActionEvent struct {
+	Log      string        `protobuf:"bytes,1,opt,name=log" json:"log,omitempty"`
+	Variable *Variable     `protobuf:"bytes,2,opt,name=variable" json:"variable,omitempty"`
+	Artifact *Artifact     `protobuf:"bytes,3,opt,name=artifact" json:"artifact,omitempty"`
+	Tests    *TestsReport  `protobuf:"bytes,4,opt,name=tests" json:"tests,omitempty"`
+	Result   *ActionResult `protobuf:"bytes,5,opt,name=result" json:"result,omitempty"`
+}
+
+func (m *ActionEvent) Reset()                    { *m = ActionEvent{} }
+func (m *ActionEvent) String() string            { return proto.CompactTextString(m) }
+func (*ActionEvent) ProtoMessage()               {}
+func (*ActionEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ActionEvent) GetLog() string {
+	if m != nil {
+		return m.Log
+	}
+	return ""
+}
+
+func (m *ActionEvent) GetVariable() *Variable {
+	if m != nil {
+		return m.Variable
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetArtifact() *Artifact {
+	if m != nil {
+		return m.Artifact
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetTests() *TestsReport {
+	if m != nil {
+		return m.Tests
+	}
+	return nil
+}
+
+func (m *ActionEvent) GetResult() *ActionResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+// Variable is a build variable set by the plugin
+type Variable struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Variable) Reset()                    { *m = Variable{} }
+func (m *Variable) String() string            { return proto.CompactTextString(m) }
+func (*Variable) ProtoMessage()               {}
+func (*Variable) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *Variable) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Variable) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// Artifact is a file declared by the plugin, uploaded by the worker
+type Artifact struct {
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	Tag  string `protobuf:"bytes,2,opt,name=tag" json:"tag,omitempty"`
+}
+
+func (m *Artifact) Reset()                    { *m = Artifact{} }
+func (m *Artifact) String() string            { return proto.CompactTextString(m) }
+func (*Artifact) ProtoMessage()               {}
+func (*Artifact) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Artifact) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Artifact) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+// TestsReport holds the test results reported by the plugin
+type TestsReport struct {
+	Suites []*TestSuite `protobuf:"bytes,1,rep,name=suites" json:"suites,omitempty"`
+}
+
+func (m *TestsReport) Reset()                    { *m = TestsReport{} }
+func (m *TestsReport) String() string            { return proto.CompactTextString(m) }
+func (*TestsReport) ProtoMessage()               {}
+func (*TestsReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *TestsReport) GetSuites() []*TestSuite {
+	if m != nil {
+		return m.Suites
+	}
+	return nil
+}
+
+type TestSuite struct {
+	Name  string      `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Cases []*TestCase `protobuf:"bytes,2,rep,name=cases" json:"cases,omitempty"`
+}
+
+func (m *TestSuite) Reset()                    { *m = TestSuite{} }
+func (m *TestSuite) String() string            { return proto.CompactTextString(m) }
+func (*TestSuite) ProtoMessage()               {}
+func (*TestSuite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *TestSuite) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TestSuite) GetCases() []*TestCase {
+	if m != nil {
+		return m.Cases
+	}
+	return nil
+}
+
+type TestCase struct {
+	Name      string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Classname string   `protobuf:"bytes,2,opt,name=classname" json:"classname,omitempty"`
+	Time      string   `protobuf:"bytes,3,opt,name=time" json:"time,omitempty"`
+	Failures  []string `protobuf:"bytes,4,rep,name=failures" json:"failures,omitempty"`
+	Errors    []string `protobuf:"bytes,5,rep,name=errors" json:"errors,omitempty"`
+	Skipped   bool     `protobuf:"varint,6,opt,name=skipped" json:"skipped,omitempty"`
+	Systemout string   `protobuf:"bytes,7,opt,name=systemout" json:"systemout,omitempty"`
+}
+
+func (m *TestCase) Reset()                    { *m = TestCase{} }
+func (m *TestCase) String() string            { return proto.CompactTextString(m) }
+func (*TestCase) ProtoMessage()               {}
+func (*TestCase) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *TestCase) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TestCase) GetClassname() string {
+	if m != nil {
+		return m.Classname
+	}
+	return ""
+}
+
+func (m *TestCase) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *TestCase) GetFailures() []string {
+	if m != nil {
+		return m.Failures
+	}
+	return nil
+}
+
+func (m *TestCase) GetErrors() []string {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *TestCase) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+func (m *TestCase) GetSystemout() string {
+	if m != nil {
+		return m.Systemout
+	}
+	return ""
+}
+
+// ActionResult is the final status of the plugin run
+type ActionResult struct {
+	Status  string `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+	Details string `protobuf:"bytes,2,opt,name=details" json:"details,omitempty"`
+}
+
+func (m *ActionResult) Reset()                    { *m = ActionResult{} }
+func (m *ActionResult) String() string            { return proto.CompactTextString(m) }
+func (*ActionResult) ProtoMessage()               {}
+func (*ActionResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *ActionResult) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ActionResult) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ActionPluginManifest)(nil), "actionplugin.ActionPluginManifest")
+	proto.RegisterType((*Parameter)(nil), "actionplugin.Parameter")
+	proto.RegisterType((*ActionQuery)(nil), "actionplugin.ActionQuery")
+	proto.RegisterType((*ActionEvent)(nil), "actionplugin.ActionEvent")
+	proto.RegisterType((*Variable)(nil), "actionplugin.Variable")
+	proto.RegisterType((*Artifact)(nil), "actionplugin.Artifact")
+	proto.RegisterType((*TestsReport)(nil), "actionplugin.TestsReport")
+	proto.RegisterType((*TestSuite)(nil), "actionplugin.TestSuite")
+	proto.RegisterType((*TestCase)(nil), "actionplugin.TestCase")
+	proto.RegisterType((*ActionResult)(nil), "actionplugin.ActionResult")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ActionPlugin service
+
+type ActionPluginClient interface {
+	Manifest(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*ActionPluginManifest, error)
+	Run(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (ActionPlugin_RunClient, error)
+}
+
+type actionPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewActionPluginClient(cc *grpc.ClientConn) ActionPluginClient {
+	return &actionPluginClient{cc}
+}
+
+func (c *actionPluginClient) Manifest(ctx context.Context, in *google_protobuf.Empty, opts ...grpc.CallOption) (*ActionPluginManifest, error) {
+	out := new(ActionPluginManifest)
+	err := grpc.Invoke(ctx, "/actionplugin.ActionPlugin/Manifest", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actionPluginClient) Run(ctx context.Context, in *ActionQuery, opts ...grpc.CallOption) (ActionPlugin_RunClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ActionPlugin_serviceDesc.Streams[0], c.cc, "/actionplugin.ActionPlugin/Run", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actionPluginRunClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActionPlugin_RunClient interface {
+	Recv() (*ActionEvent, error)
+	grpc.ClientStream
+}
+
+type actionPluginRunClient struct {
+	grpc.ClientStream
+}
+
+func (x *actionPluginRunClient) Recv() (*ActionEvent, error) {
+	m := new(ActionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for ActionPlugin service
+
+type ActionPluginServer interface {
+	Manifest(context.Context, *google_protobuf.Empty) (*ActionPluginManifest, error)
+	Run(*ActionQuery, ActionPlugin_RunServer) error
+}
+
+func RegisterActionPluginServer(s *grpc.Server, srv ActionPluginServer) {
+	s.RegisterService(&_ActionPlugin_serviceDesc, srv)
+}
+
+func _ActionPlugin_Manifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionPluginServer).Manifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/actionplugin.ActionPlugin/Manifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionPluginServer).Manifest(ctx, req.(*google_protobuf.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActionPlugin_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ActionQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActionPluginServer).Run(m, &actionPluginRunServer{stream})
+}
+
+type ActionPlugin_RunServer interface {
+	Send(*ActionEvent) error
+	grpc.ServerStream
+}
+
+type actionPluginRunServer struct {
+	grpc.ServerStream
+}
+
+func (x *actionPluginRunServer) Send(m *ActionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ActionPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "actionplugin.ActionPlugin",
+	HandlerType: (*ActionPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Manifest",
+			Handler:    _ActionPlugin_Manifest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Run",
+			Handler:       _ActionPlugin_Run_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "actionplugin.proto",
+}
+
+func init() { proto.RegisterFile("actionplugin.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 680 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x6a, 0xdb, 0x4a,
+	0x14, 0x8e, 0xac, 0xd8, 0x91, 0x8e, 0xbd, 0xb8, 0x0c, 0xb9, 0x8e, 0xe2, 0x7b, 0x2f, 0x18, 0x2d,
+	0x2e, 0x5e, 0x14, 0x3b, 0xb8, 0x85, 0x96, 0x2c, 0xda, 0x84, 0x12, 0x68, 0x17, 0x21, 0xe9, 0xa4,
+	0x74, 0x1b, 0xc6, 0xf6, 0xb1, 0xa3, 0x44, 0xd6, 0x88, 0x99, 0x91, 0xc1, 0x8f, 0xd2, 0x27, 0xe9,
+	0xa6, 0xd0, 0x17, 0xea, 0x43, 0x94, 0x19, 0xcd, 0x28, 0x32, 0x51, 0x69, 0xbb, 0x3b, 0x3f, 0xdf,
+	0x77, 0xf4, 0xcd, 0x99, 0xd1, 0x07, 0x84, 0xcd, 0x55, 0xc2, 0xb3, 0x3c, 0x2d, 0x56, 0x49, 0x36,
+	0xce, 0x05, 0x57, 0x9c, 0xf4, 0xea, 0xb5, 0xc1, 0x3f, 0x2b, 0xce, 0x57, 0x29, 0x4e, 0x4c, 0x6f,
+	0x56, 0x2c, 0x27, 0xb8, 0xce, 0xd5, 0xb6, 0x84, 0xc6, 0x5f, 0x3c, 0x38, 0x3c, 0x37, 0xe8, 0x6b,
+	0x83, 0xbe, 0x64, 0x59, 0xb2, 0x44, 0xa9, 0x08, 0x81, 0xfd, 0x8c, 0xad, 0x31, 0xf2, 0x86, 0xde,
+	0x28, 0xa4, 0x26, 0x26, 0x11, 0x1c, 0x6c, 0x50, 0xc8, 0x84, 0x67, 0x51, 0xcb, 0x94, 0x5d, 0x4a,
+	0x86, 0xd0, 0x5d, 0xa0, 0x9c, 0x8b, 0x24, 0xd7, 0xa3, 0x22, 0xdf, 0x74, 0xeb, 0x25, 0xd2, 0x87,
+	0x0e, 0x2b, 0xd4, 0x1d, 0x17, 0xd1, 0xbe, 0x69, 0xda, 0x8c, 0xbc, 0x04, 0xc8, 0x99, 0x60, 0x6b,
+	0x54, 0x28, 0x64, 0xd4, 0x1e, 0xfa, 0xa3, 0xee, 0xf4, 0x68, 0xbc, 0x73, 0xa8, 0x6b, 0xd7, 0xa7,
+	0x35, 0x68, 0xfc, 0x00, 0x61, 0xd5, 0x68, 0x54, 0x4b, 0x60, 0x5f, 0x6d, 0x73, 0xb4, 0x52, 0x4d,
+	0xfc, 0x1b, 0x3a, 0x0f, 0xa1, 0xbd, 0x61, 0x69, 0x81, 0x56, 0x66, 0x99, 0xc4, 0xdf, 0x5a, 0xd0,
+	0x2d, 0xd7, 0xf4, 0xa1, 0x40, 0xb1, 0x25, 0x67, 0x70, 0xc0, 0x0d, 0x5e, 0x46, 0x9e, 0x91, 0xfc,
+	0xff, 0xae, 0xe4, 0x1a, 0x76, 0x7c, 0x55, 0x02, 0x2f, 0x32, 0x25, 0xb6, 0xd4, 0xd1, 0xf4, 0x04,
+	0x89, 0x73, 0x81, 0x4a, 0x46, 0xad, 0x5f, 0x4d, 0xb8, 0x29, 0x81, 0x76, 0x82, 0xa5, 0x91, 0xbf,
+	0xa1, 0x73, 0xcf, 0x67, 0xb7, 0xc9, 0xc2, 0x1c, 0xc3, 0xa7, 0xed, 0x7b, 0x3e, 0x7b, 0xbf, 0x20,
+	0xff, 0x01, 0x48, 0x85, 0xf9, 0x2d, 0x17, 0x0b, 0x2c, 0x97, 0xed, 0xd3, 0x50, 0x57, 0xae, 0x74,
+	0x61, 0x70, 0x0a, 0xbd, 0xba, 0x20, 0xf2, 0x17, 0xf8, 0x0f, 0xb8, 0xb5, 0x8b, 0xd3, 0xe1, 0xe3,
+	0x06, 0x5a, 0xb5, 0x0d, 0x9c, 0xb6, 0x5e, 0x79, 0x9a, 0x5b, 0x97, 0xf2, 0x27, 0xdc, 0xf8, 0xbb,
+	0xe7, 0x36, 0x78, 0xb1, 0xc1, 0x4c, 0x69, 0x6e, 0xca, 0x57, 0x8e, 0x9b, 0xf2, 0x15, 0x99, 0x42,
+	0xb0, 0x61, 0x22, 0x61, 0xb3, 0xb4, 0xa4, 0x77, 0xa7, 0xfd, 0xdd, 0x95, 0x7c, 0xb2, 0x5d, 0x5a,
+	0xe1, 0x34, 0x87, 0x09, 0x95, 0x2c, 0xd9, 0x5c, 0x45, 0x7e, 0x13, 0xe7, 0xdc, 0x76, 0x69, 0x85,
+	0x23, 0x13, 0x68, 0x2b, 0x94, 0x4a, 0x9a, 0xdd, 0x74, 0xa7, 0xc7, 0xbb, 0x84, 0x8f, 0xba, 0x45,
+	0x31, 0xe7, 0x42, 0xd1, 0x12, 0x47, 0xa6, 0xd0, 0x11, 0x28, 0x8b, 0x54, 0x45, 0x6d, 0xc3, 0x18,
+	0x34, 0xdd, 0x14, 0x35, 0x08, 0x6a, 0x91, 0xf1, 0x0b, 0x08, 0x9c, 0xdc, 0xc6, 0xc7, 0xd9, 0xb8,
+	0xa8, 0xf8, 0x04, 0x02, 0x27, 0x58, 0xb3, 0x72, 0xa6, 0xee, 0x1c, 0x4b, 0xc7, 0x7a, 0x69, 0x8a,
+	0xad, 0x2c, 0x47, 0x87, 0xf1, 0x6b, 0xe8, 0xd6, 0x14, 0x93, 0x09, 0x74, 0x64, 0x91, 0x28, 0x74,
+	0xcf, 0xf2, 0xe8, 0xe9, 0xe1, 0x6e, 0x74, 0x9f, 0x5a, 0x58, 0x7c, 0x09, 0x61, 0x55, 0x6c, 0x14,
+	0xfa, 0x0c, 0xda, 0x73, 0x26, 0xd1, 0xbd, 0xd2, 0xfe, 0xd3, 0x81, 0x6f, 0x99, 0x44, 0x5a, 0x82,
+	0xe2, 0xaf, 0x1e, 0x04, 0xae, 0xd6, 0x38, 0xee, 0x5f, 0x08, 0xe7, 0x29, 0x93, 0xd2, 0x34, 0xca,
+	0x73, 0x3c, 0x16, 0x34, 0x43, 0x25, 0x6b, 0xb4, 0xff, 0xa5, 0x89, 0xc9, 0x00, 0x82, 0x25, 0x4b,
+	0xd2, 0x42, 0xa0, 0xbe, 0x31, 0x7f, 0x14, 0xd2, 0x2a, 0xd7, 0xa6, 0x82, 0x42, 0x70, 0x6b, 0x1c,
+	0x21, 0xb5, 0x99, 0x36, 0x2a, 0xf9, 0x90, 0xe4, 0x39, 0x2e, 0xa2, 0xce, 0xd0, 0x1b, 0x05, 0xd4,
+	0xa5, 0xfa, 0xfb, 0x72, 0x2b, 0x15, 0xae, 0x79, 0xa1, 0xa2, 0x83, 0xf2, 0xfb, 0x55, 0x21, 0x3e,
+	0x83, 0x5e, 0xfd, 0x36, 0xf5, 0x7c, 0xa9, 0x98, 0x2a, 0xa4, 0x3d, 0x83, 0xcd, 0xf4, 0xfc, 0x05,
+	0x2a, 0x96, 0xa4, 0xd2, 0x19, 0xa1, 0x4d, 0xa7, 0x9f, 0x3d, 0xe8, 0xd5, 0xfd, 0x94, 0xbc, 0x83,
+	0xa0, 0xf2, 0xd4, 0xfe, 0xb8, 0xb4, 0xe2, 0xb1, 0xb3, 0xe2, 0xf1, 0x85, 0xb6, 0xe2, 0x41, 0xdc,
+	0xf4, 0xa0, 0x76, 0xfd, 0x38, 0xde, 0x23, 0x6f, 0xc0, 0xa7, 0x45, 0x46, 0x8e, 0x7f, 0xea, 0x13,
+	0x83, 0xc6, 0x96, 0xf9, 0xdd, 0xe2, 0xbd, 0x13, 0x6f, 0xd6, 0x31, 0x9f, 0x7d, 0xfe, 0x63, 0x00,
+	0x52, 0xde, 0x3d, 0x66, 0x33, 0x06, 0x00, 0x00,
+}
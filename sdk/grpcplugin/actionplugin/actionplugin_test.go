@@ -0,0 +1,87 @@
+package actionplugin
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/ovh/cds/sdk/grpcplugin"
+)
+
+type dummyActionPlugin struct{}
+
+func (dummyActionPlugin) Manifest(context.Context, *empty.Empty) (*ActionPluginManifest, error) {
+	return &ActionPluginManifest{
+		Name:       "dummy",
+		Author:     "CDS",
+		Parameters: []*Parameter{{Name: "param1", Type: "string", Description: "this is a parameter", Value: "default"}},
+	}, nil
+}
+
+func (dummyActionPlugin) Run(q *ActionQuery, stream ActionPlugin_RunServer) error {
+	if err := Log(stream, "Hello %s", q.Options["param1"]); err != nil {
+		return err
+	}
+	if err := SetVariable(stream, "foo", "bar"); err != nil {
+		return err
+	}
+	return SendResult(stream, Success, "")
+}
+
+func TestActionPluginClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestActionPluginClient")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "plugin"))
+	assert.NoError(t, err)
+	s := grpc.NewServer()
+	RegisterActionPluginServer(s, dummyActionPlugin{})
+	go s.Serve(listener)
+	defer s.Stop()
+
+	p := &grpcplugin.Plugin{
+		Handshake: grpcplugin.Handshake{
+			CoreVersion: 1,
+			Version:     ProtocolVersion,
+			Network:     "unix",
+			Address:     listener.Addr().String(),
+			Protocol:    grpcplugin.ProtocolGRPC,
+		},
+	}
+	c, err := NewClient(p)
+	assert.NoError(t, err)
+	defer c.Close()
+
+	m, err := c.Manifest(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "dummy", m.Name)
+	params := m.PluginParameters()
+	assert.Equal(t, "default", params.GetValue("param1"))
+
+	stream, err := c.Run(context.Background(), &ActionQuery{Options: map[string]string{"param1": "world"}})
+	assert.NoError(t, err)
+
+	events := []*ActionEvent{}
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		events = append(events, e)
+	}
+
+	assert.Len(t, events, 3)
+	assert.Equal(t, "Hello world", events[0].Log)
+	assert.Equal(t, "bar", events[1].GetVariable().GetValue())
+	assert.Equal(t, Success, events[2].GetResult().GetStatus())
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk/grpcplugin"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
+)
+
+func TestDummyPlugin(t *testing.T) {
+	if _, err := os.Stat("../dummy"); os.IsNotExist(err) {
+		t.SkipNow()
+	}
+	p, err := grpcplugin.Start(context.Background(), "../dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Kill()
+	assert.Equal(t, actionplugin.ProtocolVersion, p.Version)
+
+	m, err := actionplugin.Manifest(context.Background(), p, "dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "dummy", m.Name)
+	assert.Equal(t, "This is a dummy plugin", m.Description)
+	params := m.PluginParameters()
+	assert.Equal(t, "value1", params.GetValue("param1"))
+
+	c, err := actionplugin.NewClient(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	stream, err := c.Run(context.Background(), &actionplugin.ActionQuery{Options: map[string]string{"param1": "value2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []*actionplugin.ActionEvent
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	assert.Len(t, events, 3)
+	assert.Equal(t, "value2", events[1].GetVariable().GetValue())
+	assert.Equal(t, actionplugin.Success, events[2].GetResult().GetStatus())
+}
+
+func TestNetRPCDummyPlugin(t *testing.T) {
+	if _, err := os.Stat("../../../../plugin/dummy/dummy"); os.IsNotExist(err) {
+		t.SkipNow()
+	}
+	p, err := grpcplugin.Start(context.Background(), "../../../../plugin/dummy/dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Kill()
+	assert.Equal(t, 1, p.Version)
+
+	m, err := actionplugin.Manifest(context.Background(), p, "dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "dummy", m.Name)
+	params := m.PluginParameters()
+	assert.Equal(t, "value1", params.GetValue("param1"))
+}
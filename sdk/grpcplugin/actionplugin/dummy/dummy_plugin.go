@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"golang.org/x/net/context"
+
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
+)
+
+type dummyActionPlugin struct{}
+
+func (d dummyActionPlugin) Manifest(context.Context, *empty.Empty) (*actionplugin.ActionPluginManifest, error) {
+	return &actionplugin.ActionPluginManifest{
+		Name:        "dummy",
+		Version:     "1.0",
+		Description: "This is a dummy plugin",
+		Author:      "François SAMIN <francois.samin@corp.ovh.com>",
+		Parameters: []*actionplugin.Parameter{
+			{Name: "param1", Type: "string", Description: "this is a parameter", Value: "value1"},
+		},
+	}, nil
+}
+
+func (d dummyActionPlugin) Run(q *actionplugin.ActionQuery, stream actionplugin.ActionPlugin_RunServer) error {
+	if err := actionplugin.Log(stream, "This is a log from dummy, param1=%s", q.Options["param1"]); err != nil {
+		return err
+	}
+	if err := actionplugin.SetVariable(stream, "dummy", q.Options["param1"]); err != nil {
+		return err
+	}
+	return actionplugin.SendResult(stream, actionplugin.Success, "")
+}
+
+func main() {
+	if err := actionplugin.Serve(dummyActionPlugin{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,224 @@
+package grpcplugin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	goplugin "github.com/hashicorp/go-plugin"
+
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/plugin"
+)
+
+// Protocols announced by the plugins in their handshake
+const (
+	ProtocolNetRPC = "netrpc"
+	ProtocolGRPC   = "grpc"
+)
+
+// StartTimeout is the time given to a plugin to print its handshake
+var StartTimeout = time.Minute
+
+// Handshake is the line printed on stdout by a plugin once it's listening:
+// CORE-VERSION|APP-VERSION|NETWORK|ADDRESS|PROTOCOL
+// The protocol is omitted by the net/rpc plugins
+type Handshake struct {
+	CoreVersion int
+	Version     int
+	Network     string
+	Address     string
+	Protocol    string
+}
+
+// ParseHandshake parses the handshake line of a plugin
+func ParseHandshake(line string) (Handshake, error) {
+	var h Handshake
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) < 4 {
+		return h, fmt.Errorf("Invalid plugin handshake: %s", line)
+	}
+
+	var err error
+	if h.CoreVersion, err = strconv.Atoi(parts[0]); err != nil {
+		return h, fmt.Errorf("Invalid plugin core version %s: %s", parts[0], err)
+	}
+	if h.CoreVersion != goplugin.CoreProtocolVersion {
+		return h, fmt.Errorf("Unsupported plugin core version %d", h.CoreVersion)
+	}
+	if h.Version, err = strconv.Atoi(parts[1]); err != nil {
+		return h, fmt.Errorf("Invalid plugin protocol version %s: %s", parts[1], err)
+	}
+	h.Network = parts[2]
+	h.Address = parts[3]
+	h.Protocol = ProtocolNetRPC
+	if len(parts) > 4 && parts[4] != "" {
+		h.Protocol = parts[4]
+	}
+	return h, nil
+}
+
+// String returns the handshake line
+func (h Handshake) String() string {
+	return fmt.Sprintf("%d|%d|%s|%s|%s", h.CoreVersion, h.Version, h.Network, h.Address, h.Protocol)
+}
+
+// Addr returns the address the plugin is listening on
+func (h Handshake) Addr() (net.Addr, error) {
+	switch h.Network {
+	case "unix":
+		return net.ResolveUnixAddr(h.Network, h.Address)
+	case "tcp":
+		return net.ResolveTCPAddr(h.Network, h.Address)
+	}
+	return nil, fmt.Errorf("Unsupported plugin network %s", h.Network)
+}
+
+// Plugin is a plugin process started by Start
+type Plugin struct {
+	Handshake
+	Binary string
+	cmd    *exec.Cmd
+	done   chan struct{}
+}
+
+// Start runs the plugin binary and waits for its handshake. Plugins speaking the
+// net/rpc protocol and the grpc protocol are both started this way, the caller
+// chooses how to talk to the plugin from the handshake version
+func Start(ctx context.Context, binary string) (*Plugin, error) {
+	cmd := exec.CommandContext(ctx, binary)
+	cmd.Env = append(plugin.Environ(),
+		fmt.Sprintf("%s=%s", plugin.Handshake.MagicCookieKey, plugin.Handshake.MagicCookieValue),
+		"PLUGIN_MIN_PORT=10000",
+		"PLUGIN_MAX_PORT=25000",
+	)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("Unable to start plugin %s: %s", binary, err)
+	}
+
+	p := &Plugin{Binary: binary, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Debug("plugin %s> %s", binary, scanner.Text())
+		}
+	}()
+
+	linesCh := make(chan string)
+	go func() {
+		r := bufio.NewReader(stdout)
+		line, err := r.ReadString('\n')
+		if err == nil {
+			linesCh <- line
+		}
+		close(linesCh)
+		// The plugin output is not used after the handshake
+		io.Copy(ioutil.Discard, r)
+	}()
+
+	go func() {
+		cmd.Wait()
+		close(p.done)
+	}()
+
+	select {
+	case line, ok := <-linesCh:
+		if !ok {
+			p.Kill()
+			return nil, fmt.Errorf("Plugin %s exited before its handshake", binary)
+		}
+		h, err := ParseHandshake(line)
+		if err != nil {
+			p.Kill()
+			return nil, err
+		}
+		p.Handshake = h
+	case <-time.After(StartTimeout):
+		p.Kill()
+		return nil, fmt.Errorf("Timeout while waiting handshake of plugin %s", binary)
+	}
+
+	return p, nil
+}
+
+// ReattachConfig returns the configuration used by the net/rpc clients to attach the started plugin
+func (p *Plugin) ReattachConfig() (*goplugin.ReattachConfig, error) {
+	addr, err := p.Addr()
+	if err != nil {
+		return nil, err
+	}
+	return &goplugin.ReattachConfig{Addr: addr, Pid: p.cmd.Process.Pid}, nil
+}
+
+// Kill stops the plugin process and waits for its end
+func (p *Plugin) Kill() {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	p.cmd.Process.Kill()
+	<-p.done
+}
+
+// Listen opens the listener of a plugin and prints the handshake on stdout. The
+// plugin has to serve its protocol on the returned listener
+func Listen(version int, protocol string) (net.Listener, error) {
+	if os.Getenv(plugin.Handshake.MagicCookieKey) != plugin.Handshake.MagicCookieValue {
+		return nil, fmt.Errorf("This binary is a plugin. It's not meant to be executed directly")
+	}
+
+	var listener net.Listener
+	if runtime.GOOS == "windows" {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		listener = l
+	} else {
+		f, err := ioutil.TempFile("", "plugin")
+		if err != nil {
+			return nil, err
+		}
+		path := f.Name()
+		f.Close()
+		os.Remove(path)
+
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		listener = l
+	}
+
+	h := Handshake{
+		CoreVersion: goplugin.CoreProtocolVersion,
+		Version:     version,
+		Network:     listener.Addr().Network(),
+		Address:     listener.Addr().String(),
+		Protocol:    protocol,
+	}
+	fmt.Println(h.String())
+	os.Stdout.Sync()
+
+	return listener, nil
+}
@@ -0,0 +1,24 @@
+package grpcplugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHandshake(t *testing.T) {
+	h, err := ParseHandshake("1|1|unix|/tmp/plugin123\n")
+	assert.NoError(t, err)
+	assert.Equal(t, Handshake{CoreVersion: 1, Version: 1, Network: "unix", Address: "/tmp/plugin123", Protocol: ProtocolNetRPC}, h)
+
+	h, err = ParseHandshake("1|2|tcp|127.0.0.1:1234|grpc\n")
+	assert.NoError(t, err)
+	assert.Equal(t, Handshake{CoreVersion: 1, Version: 2, Network: "tcp", Address: "127.0.0.1:1234", Protocol: ProtocolGRPC}, h)
+	assert.Equal(t, "1|2|tcp|127.0.0.1:1234|grpc", h.String())
+
+	_, err = ParseHandshake("This binary is a plugin.")
+	assert.Error(t, err)
+
+	_, err = ParseHandshake("2|1|unix|/tmp/plugin123")
+	assert.Error(t, err)
+}
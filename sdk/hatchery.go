@@ -6,13 +6,14 @@ import (
 
 // Hatchery registration model
 type Hatchery struct {
-	ID       int64     `json:"id"`
-	UID      string    `json:"uid"`
-	Name     string    `json:"name"`
-	Status   string    `json:"status"`
-	GroupID  int64     `json:"group_id"`
-	LastBeat time.Time `json:"-"`
-	Model    Model     `json:"model"`
-	Uptodate bool      `json:"up_to_date"`
-	Version  string    `json:"version"`
+	ID          int64     `json:"id"`
+	UID         string    `json:"uid"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	GroupID     int64     `json:"group_id"`
+	LastBeat    time.Time `json:"-"`
+	Model       Model     `json:"model"`
+	Uptodate    bool      `json:"up_to_date"`
+	Version     string    `json:"version"`
+	Maintenance bool      `json:"maintenance"` // In maintenance, the hatchery stops spawning workers
 }
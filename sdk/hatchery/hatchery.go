@@ -3,6 +3,7 @@ package hatchery
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"os/exec"
 	"sync/atomic"
 	"time"
@@ -36,6 +37,13 @@ type CommonConfiguration struct {
 		MaxWorker         int  `toml:"maxWorker" default:"10" comment:"Maximum allowed simultaneous workers"`
 		GraceTimeQueued   int  `toml:"graceTimeQueued" default:"4" comment:"if worker is queued less than this value (seconds), hatchery does not take care of it"`
 		RegisterFrequency int  `toml:"registerFrequency" default:"60" comment:"Check if some worker model have to be registered each n Seconds"`
+		WarmPool          struct {
+			Size         int    `toml:"size" default:"0" comment:"Number of idle workers kept registered for each worker model. 0: warm pool disabled"`
+			MaxSize      int    `toml:"maxSize" default:"0" comment:"The warm pool of a worker model grows up to this size with the number of workers building recently"`
+			DemandWindow int    `toml:"demandWindow" default:"30" comment:"Compute the number of workers building recently on the last n minutes"`
+			IdleTTL      int    `toml:"idleTTL" default:"600" comment:"Idle workers not needed anymore are killed after n seconds"`
+			Hours        string `toml:"hours" default:"" comment:"Hours of the day when the warm pool is enabled, ie: 8-20. Empty: all day"`
+		} `toml:"warmPool" comment:"Warm pool of idle workers"`
 		WorkerLogsOptions struct {
 			Graylog struct {
 				Host       string `toml:"host"`
@@ -106,14 +114,18 @@ func CheckRequirement(r sdk.Requirement) (bool, error) {
 	}
 }
 
-func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, hostname string) bool {
-	if jobID == 0 {
+// reserveRoutine reserves a routine to spawn a worker, it returns false if there is too many routines
+func reserveRoutine(nRoutines *int64) bool {
+	if n := atomic.AddInt64(nRoutines, 1); n > 10 {
+		atomic.AddInt64(nRoutines, -1)
+		log.Info("too many routines in same time %d", n-1)
 		return false
 	}
+	return true
+}
 
-	n := atomic.LoadInt64(nRoutines)
-	if n > 10 {
-		log.Info("too many routines in same time %d", n)
+func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, spawnIDs *cache.Cache, hostname string) bool {
+	if jobID == 0 {
 		return false
 	}
 
@@ -137,8 +149,6 @@ func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID i
 		return false
 	}
 
-	atomic.AddInt64(nRoutines, 1)
-	defer atomic.AddInt64(nRoutines, -1)
 	if errR := routine(h, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix()); errR != nil {
 		log.Warning("Error on routine: %s", errR)
 		return false
@@ -162,7 +172,7 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 
 	for _, model := range models {
 		if canRunJob(h, timestamp, execGroups, jobID, requirements, &model, hostname) {
-			if err := h.Client().QueueJobBook(isWorkflowJob, jobID); err != nil {
+			if err := h.Client().QueueJobBook(isWorkflowJob, jobID, model.Name); err != nil {
 				// perhaps already booked by another hatchery
 				log.Debug("routine> %d - cannot book job %d %s: %s", timestamp, jobID, model.Name, err)
 				break // go to next job
@@ -176,7 +186,9 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStarts.ID, Args: []interface{}{fmt.Sprintf("%s", h.Hatchery().Name), fmt.Sprintf("%d", h.Hatchery().ID), model.Name}},
 				},
 			}
-			workerName, errSpawn := h.SpawnWorker(&model, jobID, requirements, false, "spawn for job")
+			// the next version of the model is spawned for a part of the jobs while it's rolled out
+			spawnModel := model.ModelForSpawn(rand.Intn(100))
+			workerName, errSpawn := h.SpawnWorker(&spawnModel, jobID, requirements, false, "spawn for job")
 			if errSpawn != nil {
 				log.Warning("routine> %d - cannot spawn worker %s for job %d: %s", timestamp, model.Name, jobID, errSpawn)
 				infos = append(infos, sdk.SpawnInfo{
@@ -230,7 +242,7 @@ func provisioning(h Interface, provisionDisabled bool, models []sdk.Model) {
 							log.Error("provisioning> cannot client.WorkerModelSpawnError for worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
 						}
 					}
-				}(models[k])
+				}(models[k].ModelForSpawn(rand.Intn(100)))
 			}
 		}
 	}
@@ -284,6 +296,31 @@ func canRunJob(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64
 			return false
 		}
 
+		// Labels are declared by the worker model
+		if r.Type == sdk.LabelRequirement {
+			found := false
+			for _, c := range model.Capabilities {
+				if c.Type == sdk.LabelRequirement && c.Value == r.Value {
+					found = true
+					break
+				}
+			}
+			if !found {
+				log.Debug("canRunJob> %d - job %d - model(%s) does not have label %s", timestamp, jobID, model.Name, r.Value)
+				return false
+			}
+			continue
+		}
+
+		// If the model doesn't declare its os-arch, the hatchery checks it in CanSpawn
+		if r.Type == sdk.OSArchRequirement {
+			if model.OSArch() != "" && r.Value != model.OSArch() {
+				log.Debug("canRunJob> %d - job %d - os-arch requirement r.Value(%s) != model os-arch(%s)", timestamp, jobID, r.Value, model.OSArch())
+				return false
+			}
+			continue
+		}
+
 		// service and memory requirements are only supported by docker model
 		if model.Type != sdk.Docker && (r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement) {
 			log.Debug("canRunJob> %d - job %d - job with service requirement or memory requirement: only for model docker. current model:%s", timestamp, jobID, model.Type)
@@ -0,0 +1,204 @@
+package hatchery
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// warmPool keeps idle workers registered for each worker model, so that jobs don't wait for the spawn of a worker.
+// The size of the pool of a model grows with the number of workers of this model building recently.
+type warmPool struct {
+	// building workers, by model
+	demand map[int64][]demandSample
+	// idle workers of the hatchery, by worker name
+	idle map[string]time.Time
+}
+
+type demandSample struct {
+	time     time.Time
+	building int
+}
+
+func newWarmPool() *warmPool {
+	return &warmPool{
+		demand: map[int64][]demandSample{},
+		idle:   map[string]time.Time{},
+	}
+}
+
+// addDemand records the number of building workers of a model and returns the max number
+// of building workers since the given time, older samples are forgotten
+func (p *warmPool) addDemand(modelID int64, building int, now, since time.Time) int {
+	samples := append(p.demand[modelID], demandSample{time: now, building: building})
+	i := sort.Search(len(samples), func(i int) bool { return !samples[i].time.Before(since) })
+	samples = samples[i:]
+	p.demand[modelID] = samples
+
+	var max int
+	for _, s := range samples {
+		if s.building > max {
+			max = s.building
+		}
+	}
+	return max
+}
+
+// parseHours parses hours of the day, ie: 8-20. The end can be before the start, ie: 20-6
+func parseHours(s string) (int, int, error) {
+	t := strings.Split(s, "-")
+	if len(t) != 2 {
+		return 0, 0, fmt.Errorf("invalid hours %s, expected start-end", s)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(t[0]))
+	if err != nil || start < 0 || start > 24 {
+		return 0, 0, fmt.Errorf("invalid hours %s, expected start-end", s)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(t[1]))
+	if err != nil || end < 0 || end > 24 {
+		return 0, 0, fmt.Errorf("invalid hours %s, expected start-end", s)
+	}
+	return start, end, nil
+}
+
+// inHours returns true if the hour is between start (included) and end (excluded)
+func inHours(start, end, hour int) bool {
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// warmPoolTarget returns the number of idle workers to keep for a model:
+// the size of the pool, or the recent demand if it's higher, up to the max size
+func warmPoolTarget(size, maxSize, demand int) int {
+	target := size
+	if demand > target {
+		target = demand
+	}
+	if maxSize < size {
+		maxSize = size
+	}
+	if target > maxSize {
+		target = maxSize
+	}
+	return target
+}
+
+// refresh spawns the missing idle workers and kills the idle workers which are not needed anymore
+// It's called by the main loop of the hatchery only, the pool is not safe for concurrent use
+func (p *warmPool) refresh(h Interface, models []sdk.Model, maxWorkersReached bool) {
+	conf := h.Configuration().Provision.WarmPool
+	if conf.Size <= 0 && conf.MaxSize <= 0 {
+		return
+	}
+	if h.Hatchery() == nil || h.ID() == 0 {
+		return
+	}
+
+	now := time.Now()
+	enabled := true
+	if conf.Hours != "" {
+		start, end, err := parseHours(conf.Hours)
+		if err != nil {
+			log.Warning("warmPool> %s", err)
+		} else {
+			enabled = inHours(start, end, now.Hour())
+		}
+	}
+
+	workers, err := h.Client().WorkerList()
+	if err != nil {
+		log.Warning("warmPool> Cannot get workers: %s", err)
+		return
+	}
+
+	// Number of workers which can be spawned without reaching the max number of workers of the hatchery
+	free := h.Configuration().Provision.MaxWorker - h.WorkersStarted()
+	if maxWorkersReached {
+		free = 0
+	}
+
+	seen := map[string]bool{}
+	for k := range models {
+		m := &models[k]
+		if m.Type != h.ModelType() {
+			continue
+		}
+
+		var registered, building int
+		idle := []sdk.Worker{}
+		for _, w := range workers {
+			if w.HatcheryID != h.ID() || w.ModelID != m.ID {
+				continue
+			}
+			registered++
+			if w.Status == sdk.StatusBuilding {
+				building++
+			}
+			if w.Status != sdk.StatusWaiting {
+				continue
+			}
+			seen[w.Name] = true
+			if _, ok := p.idle[w.Name]; !ok {
+				p.idle[w.Name] = now
+			}
+			idle = append(idle, w)
+		}
+
+		demand := p.addDemand(m.ID, building, now, now.Add(-time.Duration(conf.DemandWindow)*time.Minute))
+		target := 0
+		if enabled {
+			target = warmPoolTarget(conf.Size, conf.MaxSize, demand)
+		}
+
+		// Workers started but not registered yet will be idle soon
+		available := len(idle)
+		if started := h.WorkersStartedByModel(m); started > registered {
+			available += started - registered
+		}
+
+		if available < target && free > 0 && (m.NbSpawnErr <= 5 || h.Hatchery().GroupID == m.GroupID) {
+			log.Debug("warmPool> spawn %d workers of model %s, target:%d available:%d", target-available, m.Name, target, available)
+			for i := available; i < target && free > 0; i++ {
+				free--
+				go func(m sdk.Model) {
+					if name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for warm pool"); errSpawn != nil {
+						log.Warning("warmPool> cannot spawn worker %s with model %s: %s", name, m.Name, errSpawn)
+						if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("warmPool> cannot spawn worker %s for warm pool: %s", m.Name, errSpawn)); err != nil {
+							log.Error("warmPool> cannot client.WorkerModelSpawnError for worker %s with model %s: %s", name, m.Name, errSpawn)
+						}
+					}
+				}(m.ModelForSpawn(rand.Intn(100)))
+			}
+		}
+
+		// Kill the oldest idle workers which are not needed, once they're idle for more than the TTL
+		sort.Slice(idle, func(i, j int) bool { return p.idle[idle[i].Name].Before(p.idle[idle[j].Name]) })
+		for i := 0; i < len(idle)-target; i++ {
+			w := idle[i]
+			if now.Sub(p.idle[w.Name]) < time.Duration(conf.IdleTTL)*time.Second {
+				break
+			}
+			log.Info("warmPool> disable idle worker %s of model %s", w.Name, m.Name)
+			if err := h.Client().WorkerDisable(w.ID); err != nil {
+				log.Warning("warmPool> cannot disable worker %s: %s", w.Name, err)
+				continue
+			}
+			delete(p.idle, w.Name)
+		}
+	}
+
+	// Forget the workers which are not idle anymore
+	for name := range p.idle {
+		if !seen[name] {
+			delete(p.idle, name)
+		}
+	}
+}
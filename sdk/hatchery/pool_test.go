@@ -0,0 +1,49 @@
+package hatchery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_warmPoolTarget(t *testing.T) {
+	assert.Equal(t, 2, warmPoolTarget(2, 5, 0))
+	assert.Equal(t, 4, warmPoolTarget(2, 5, 4))
+	assert.Equal(t, 5, warmPoolTarget(2, 5, 10))
+	// max size lower than size is ignored
+	assert.Equal(t, 2, warmPoolTarget(2, 0, 10))
+	assert.Equal(t, 3, warmPoolTarget(0, 3, 3))
+}
+
+func Test_inHours(t *testing.T) {
+	start, end, err := parseHours("8-20")
+	assert.NoError(t, err)
+	assert.True(t, inHours(start, end, 8))
+	assert.True(t, inHours(start, end, 19))
+	assert.False(t, inHours(start, end, 20))
+	assert.False(t, inHours(start, end, 3))
+
+	start, end, err = parseHours("20-6")
+	assert.NoError(t, err)
+	assert.True(t, inHours(start, end, 23))
+	assert.True(t, inHours(start, end, 2))
+	assert.False(t, inHours(start, end, 12))
+
+	for _, s := range []string{"8", "a-b", "8-25"} {
+		_, _, err := parseHours(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func Test_warmPoolDemand(t *testing.T) {
+	p := newWarmPool()
+	now := time.Now()
+	window := 30 * time.Minute
+
+	assert.Equal(t, 3, p.addDemand(1, 3, now.Add(-40*time.Minute), now.Add(-40*time.Minute-window)))
+	assert.Equal(t, 3, p.addDemand(1, 1, now.Add(-20*time.Minute), now.Add(-20*time.Minute-window)))
+	// the first sample is out of the window
+	assert.Equal(t, 1, p.addDemand(1, 0, now, now.Add(-window)))
+	assert.Equal(t, 0, p.addDemand(2, 0, now, now.Add(-window)))
+}
@@ -74,6 +74,7 @@ func Create(h Interface) {
 
 	var maxWorkersReached bool
 	var models []sdk.Model
+	pool := newWarmPool()
 
 	for {
 		select {
@@ -101,23 +102,40 @@ func Create(h Interface) {
 				log.Error("error on h.Client().WorkerModelsEnabled():%e", errwm)
 			}
 		case j := <-pbjobs:
+			// In maintenance, the jobs are spawned by other hatcheries
+			if h.Hatchery().Maintenance {
+				continue
+			}
 			if maxWorkersReached {
 				log.Debug("maxWorkerReached:%d", workersStarted)
 				continue
 			}
+			if !reserveRoutine(&nRoutines) {
+				continue
+			}
 			go func(job sdk.PipelineBuildJob) {
-				if isRun := receiveJob(h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
+				defer atomic.AddInt64(&nRoutines, -1)
+				if isRun := receiveJob(h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, spawnIDs, hostname); isRun {
 					atomic.AddInt64(&workersStarted, 1)
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				}
 			}(j)
 		case j := <-wjobs:
+			if h.Hatchery().Maintenance {
+				continue
+			}
 			if maxWorkersReached {
 				log.Debug("maxWorkerReached:%d", workersStarted)
 				continue
 			}
+			// The queue is sorted by priority: the routines are reserved in the order of the queue,
+			// so that the jobs with the highest priority are spawned first
+			if !reserveRoutine(&nRoutines) {
+				continue
+			}
 			go func(job sdk.WorkflowNodeJobRun) {
-				if isRun := receiveJob(h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
+				defer atomic.AddInt64(&nRoutines, -1)
+				if isRun := receiveJob(h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, spawnIDs, hostname); isRun {
 					atomic.AddInt64(&workersStarted, 1)
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				}
@@ -125,8 +143,15 @@ func Create(h Interface) {
 		case err := <-errs:
 			log.Error("%v", err)
 		case <-tickerProvision.C:
+			if h.Hatchery().Maintenance {
+				continue
+			}
 			provisioning(h, h.Configuration().Provision.Disabled, models)
+			pool.refresh(h, models, maxWorkersReached)
 		case <-tickerRegister.C:
+			if h.Hatchery().Maintenance {
+				continue
+			}
 			if err := workerRegister(h, models); err != nil {
 				log.Warning("Error on workerRegister: %s", err)
 			}
@@ -191,13 +216,23 @@ func hearbeat(m Interface, token string, maxFailures int) {
 			log.Info("hearbeat> %s Registered back: ID %d with model ID %d", m.Hatchery().Name, m.Hatchery().ID, m.Hatchery().Model.ID)
 		}
 
-		if err := m.Client().HatcheryRefresh(m.Hatchery().ID); err != nil {
+		maintenance, err := m.Client().HatcheryRefresh(m.Hatchery().ID)
+		if err != nil {
 			log.Info("heartbeat> %s cannot refresh beat: %s", m.Hatchery().Name, err)
 			m.Hatchery().ID = 0
 			checkFailures(maxFailures, failures)
 			continue
 		}
 		failures = 0
+
+		if maintenance != m.Hatchery().Maintenance {
+			if maintenance {
+				log.Info("heartbeat> %s is in maintenance, stop spawning workers", m.Hatchery().Name)
+			} else {
+				log.Info("heartbeat> %s is not in maintenance anymore", m.Hatchery().Name)
+			}
+			m.Hatchery().Maintenance = maintenance
+		}
 	}
 }
 
@@ -230,6 +265,17 @@ func workerRegister(h Interface, models []sdk.Model) error {
 			continue
 		}
 
+		// the next version of the model is checked before its promotion
+		if m.NeedVersionCheck() {
+			v := m.WithVersion(*m.NextVersion)
+			log.Info("workerRegister> spawn a worker for check version %d of worker model %s (%d)", v.Version, m.Name, m.ID)
+			if _, errSpawn := h.SpawnWorker(&v, 0, nil, true, "spawn for check version"); errSpawn != nil {
+				log.Warning("workerRegister> cannot spawn worker for check version %d of %s: %s", v.Version, m.Name, errSpawn)
+			} else {
+				nRegistered++
+			}
+		}
+
 		if h.NeedRegistration(&m) {
 			log.Info("workerRegister> spawn a worker for register worker model %s (%d)", m.Name, m.ID)
 			if _, errSpawn := h.SpawnWorker(&m, 0, nil, true, "spawn for register"); errSpawn != nil {
@@ -0,0 +1,18 @@
+package sdk
+
+// Impact types
+const (
+	ImpactTypeApplication  = "application"
+	ImpactTypePipeline     = "pipeline"
+	ImpactTypeJob          = "job"
+	ImpactTypeWorkflowNode = "workflow_node"
+)
+
+// Impact is an entity depending on another one, which may break if the latter is changed or deleted.
+// Name is the path of the entity in its project, ie: pipeline/build/Compile for a job
+type Impact struct {
+	Type       string `json:"type" cli:"type"`
+	ProjectKey string `json:"project_key" cli:"project"`
+	Name       string `json:"name" cli:"name"`
+	Reason     string `json:"reason" cli:"reason"`
+}
@@ -0,0 +1,17 @@
+package sdk
+
+// Actions planned by the import of a project tree
+const (
+	ImportAdd       = "add"
+	ImportUpdate    = "update"
+	ImportUnchanged = "unchanged"
+)
+
+// ImportChange is a change of an entity planned by the import of a project tree. The values of
+// the secrets are replaced in the diff by fingerprints which only show whether they change
+type ImportChange struct {
+	EntityType string      `json:"entity_type" cli:"type"`
+	EntityName string      `json:"entity_name" cli:"name"`
+	Action     string      `json:"action" cli:"action"`
+	Diff       []AuditDiff `json:"diff,omitempty" cli:"-"`
+}
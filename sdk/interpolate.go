@@ -2,32 +2,50 @@ package sdk
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
-	"html/template"
+	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
+
+	"github.com/blang/semver"
 )
 
-// InterpolateFilterFunc is the type of a filter func
-type InterpolateFilterFunc func() (string, func(string) string)
+// InterpolateFilterFunc is the type of a filter func, it returns the name of the filter and a function usable in a text/template pipeline
+type InterpolateFilterFunc func() (string, interface{})
 
 // InterpolateFilters provides some standers filters
 var InterpolateFilters = struct {
-	Title  InterpolateFilterFunc
-	Lower  InterpolateFilterFunc
-	Upper  InterpolateFilterFunc
-	Escape InterpolateFilterFunc
+	Title      InterpolateFilterFunc
+	Lower      InterpolateFilterFunc
+	Upper      InterpolateFilterFunc
+	Escape     InterpolateFilterFunc
+	Default    InterpolateFilterFunc
+	Trim       InterpolateFilterFunc
+	Replace    InterpolateFilterFunc
+	Substr     InterpolateFilterFunc
+	B64Enc     InterpolateFilterFunc
+	B64Dec     InterpolateFilterFunc
+	Sha256     InterpolateFilterFunc
+	URLEncode  InterpolateFilterFunc
+	SemverBump InterpolateFilterFunc
+	Date       InterpolateFilterFunc
 }{
-	Title: func() (string, func(string) string) {
+	Title: func() (string, interface{}) {
 		return "title", strings.Title
 	},
-	Lower: func() (string, func(string) string) {
+	Lower: func() (string, interface{}) {
 		return "lower", strings.ToLower
 	},
-	Upper: func() (string, func(string) string) {
+	Upper: func() (string, interface{}) {
 		return "upper", strings.ToUpper
 	},
-	Escape: func() (string, func(string) string) {
+	Escape: func() (string, interface{}) {
 		return "escape", func(s string) string {
 			s1 := strings.Replace(s, "_", "-", -1)
 			s1 = strings.Replace(s1, "/", "-", -1)
@@ -35,41 +53,179 @@ var InterpolateFilters = struct {
 			return s1
 		}
 	},
-}
-
-// Interpolate returns interpolated input with vars
-func Interpolate(input string, vars map[string]string, filters ...InterpolateFilterFunc) (string, error) {
-	data := map[string]string{}
-
-	for k, v := range vars {
-		kb := strings.Replace(k, ".", "__", -1)
-		data[kb] = v
-		re := regexp.MustCompile("{{." + k + "(.*)}}")
-		for {
-			sm := re.FindStringSubmatch(input)
-			if len(sm) > 0 {
-				input = strings.Replace(input, sm[0], "{{."+kb+sm[1]+"}}", -1)
-			} else {
-				break
+	// {{.cds.env.X | default "foo"}}
+	Default: func() (string, interface{}) {
+		return "default", func(d, s string) string {
+			if s == "" {
+				return d
+			}
+			return s
+		}
+	},
+	Trim: func() (string, interface{}) {
+		return "trim", strings.TrimSpace
+	},
+	// {{.git.branch | replace "/" "-"}}
+	Replace: func() (string, interface{}) {
+		return "replace", func(old, new, s string) string {
+			return strings.Replace(s, old, new, -1)
+		}
+	},
+	// {{.git.hash | substr 0 8}}, a negative or too big end means the end of the string
+	Substr: func() (string, interface{}) {
+		return "substr", func(start, end int, s string) string {
+			r := []rune(s)
+			if end < 0 || end > len(r) {
+				end = len(r)
+			}
+			if start < 0 {
+				start = 0
+			}
+			if start > end {
+				return ""
+			}
+			return string(r[start:end])
+		}
+	},
+	B64Enc: func() (string, interface{}) {
+		return "b64enc", func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		}
+	},
+	B64Dec: func() (string, interface{}) {
+		return "b64dec", func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}
+	},
+	Sha256: func() (string, interface{}) {
+		return "sha256", func(s string) string {
+			return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))
+		}
+	},
+	URLEncode: func() (string, interface{}) {
+		return "urlencode", url.QueryEscape
+	},
+	// {{.cds.semver | bump "minor"}}, the prerelease and the build metadata are removed
+	SemverBump: func() (string, interface{}) {
+		return "bump", func(part, s string) (string, error) {
+			v, err := semver.ParseTolerant(s)
+			if err != nil {
+				return "", err
+			}
+			switch part {
+			case "major":
+				v.Major++
+				v.Minor, v.Patch = 0, 0
+			case "minor":
+				v.Minor++
+				v.Patch = 0
+			case "patch":
+				v.Patch++
+			default:
+				return "", fmt.Errorf("unknown semver part %s, it should be major, minor or patch", part)
+			}
+			v.Pre, v.Build = nil, nil
+			if strings.HasPrefix(s, "v") {
+				return "v" + v.String(), nil
+			}
+			return v.String(), nil
+		}
+	},
+	// {{.cds.hook.date | date "2006-01-02"}}, the value is a RFC3339 date or an unix timestamp
+	Date: func() (string, interface{}) {
+		return "date", func(layout, s string) (string, error) {
+			if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return time.Unix(ts, 0).UTC().Format(layout), nil
 			}
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return "", fmt.Errorf("invalid date %s", s)
+			}
+			return t.Format(layout), nil
 		}
+	},
+}
+
+var (
+	interpolateDefaultFilters = []InterpolateFilterFunc{
+		InterpolateFilters.Title,
+		InterpolateFilters.Lower,
+		InterpolateFilters.Upper,
+		InterpolateFilters.Escape,
+		InterpolateFilters.Default,
+		InterpolateFilters.Trim,
+		InterpolateFilters.Replace,
+		InterpolateFilters.Substr,
+		InterpolateFilters.B64Enc,
+		InterpolateFilters.B64Dec,
+		InterpolateFilters.Sha256,
+		InterpolateFilters.URLEncode,
+		InterpolateFilters.SemverBump,
+		InterpolateFilters.Date,
 	}
 
+	// {{.var}} or {{.var | filter arg | filter}}
+	interpolateRegexp        = regexp.MustCompile(`{{\s*\.([a-zA-Z0-9_\-.]+)\s*(\|[^{}]*)?}}`)
+	interpolateDefaultRegexp = regexp.MustCompile(`\|\s*default\b`)
+)
+
+// Interpolate returns interpolated input with vars. Unknown variables are kept as is in the result
+func Interpolate(input string, vars map[string]string, filters ...InterpolateFilterFunc) (string, error) {
+	s, _, err := InterpolateWithUnknown(input, vars, filters...)
+	return s, err
+}
+
+// InterpolateWithUnknown returns interpolated input with vars and the sorted names of the unknown variables,
+// which are kept as is in the result. A variable with a default filter is never unknown.
+func InterpolateWithUnknown(input string, vars map[string]string, filters ...InterpolateFilterFunc) (string, []string, error) {
 	funcMap := template.FuncMap{}
-	for i := range filters {
-		s, fun := filters[i]()
+	for _, f := range append(interpolateDefaultFilters, filters...) {
+		s, fun := f()
 		funcMap[s] = fun
 	}
 
-	t, err := template.New("input").Funcs(funcMap).Parse(input)
-	if err != nil {
-		return "", fmt.Errorf("Invalid template format: %s", err.Error())
-	}
+	unknown := map[string]struct{}{}
+	var errInterpolate error
+	res := interpolateRegexp.ReplaceAllStringFunc(input, func(m string) string {
+		if errInterpolate != nil {
+			return m
+		}
+		sm := interpolateRegexp.FindStringSubmatch(m)
+		name, pipeline := sm[1], sm[2]
 
-	var buff bytes.Buffer
-	if err := t.Execute(&buff, data); err != nil {
-		return "", fmt.Errorf("Failed to execute template: %s", err.Error())
+		v, ok := vars[name]
+		if !ok && !interpolateDefaultRegexp.MatchString(pipeline) {
+			unknown[name] = struct{}{}
+			return m
+		}
+		if pipeline == "" {
+			return v
+		}
+
+		t, err := template.New("input").Funcs(funcMap).Parse("{{.value " + pipeline + "}}")
+		if err != nil {
+			errInterpolate = fmt.Errorf("Invalid template format %s: %s", m, err.Error())
+			return m
+		}
+		var buff bytes.Buffer
+		if err := t.Execute(&buff, map[string]string{"value": v}); err != nil {
+			errInterpolate = fmt.Errorf("Failed to execute template %s: %s", m, err.Error())
+			return m
+		}
+		return buff.String()
+	})
+	if errInterpolate != nil {
+		return "", nil, errInterpolate
 	}
 
-	return buff.String(), nil
+	var names []string
+	for k := range unknown {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return res, names, nil
 }
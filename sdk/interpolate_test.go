@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	vars := map[string]string{
+		"cds.application": "my_app",
+		"cds.semver":      "v1.2.3-rc.1",
+		"git.branch":      "feat/new thing",
+		"git.hash":        "0123456789abcdef",
+		"cds.hook.date":   "2017-11-02T10:11:12Z",
+		"html":            "a&b<c>",
+	}
+	tests := []struct {
+		input   string
+		want    string
+		unknown []string
+	}{
+		{input: "{{.cds.application}}", want: "my_app"},
+		{input: "{{ .cds.application | upper }}-{{.cds.application | escape}}", want: "MY_APP-my-app"},
+		{input: "{{.html}}", want: "a&b<c>"},
+		{input: `{{.cds.env.X | default "foo"}}`, want: "foo"},
+		{input: `{{.cds.application | default "foo"}}`, want: "my_app"},
+		{input: `{{.git.branch | replace "/" "-" | replace " " "_"}}`, want: "feat-new_thing"},
+		{input: "{{.git.hash | substr 0 8}}", want: "01234567"},
+		{input: "{{.git.hash | substr 10 100}}", want: "abcdef"},
+		{input: "{{.cds.application | b64enc}}", want: "bXlfYXBw"},
+		{input: "{{.cds.application | b64enc | b64dec}}", want: "my_app"},
+		{input: "{{.cds.application | sha256 | substr 0 8}}", want: "3fe58225"},
+		{input: "{{.git.branch | urlencode}}", want: "feat%2Fnew+thing"},
+		{input: `{{.cds.semver | bump "minor"}}`, want: "v1.3.0"},
+		{input: `{{.cds.hook.date | date "2006-01-02"}}`, want: "2017-11-02"},
+		{input: "{{.cds.unknown}} and {{.cds.application}}", want: "{{.cds.unknown}} and my_app", unknown: []string{"cds.unknown"}},
+		{input: "docker ps --format '{{.Names}}'", want: "docker ps --format '{{.Names}}'", unknown: []string{"Names"}},
+		{input: "{{json .}}", want: "{{json .}}"},
+	}
+
+	for _, tt := range tests {
+		got, unknown, err := InterpolateWithUnknown(tt.input, vars)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.input, err)
+		}
+		assert.Equal(t, tt.want, got, tt.input)
+		assert.Equal(t, tt.unknown, unknown, tt.input)
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	vars := map[string]string{"v": "not a version"}
+	for _, input := range []string{
+		`{{.v | bump "minor"}}`,
+		`{{.v | date "2006"}}`,
+		"{{.v | b64dec}}",
+		"{{.v | unknownfilter}}",
+	} {
+		_, err := Interpolate(input, vars)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestWorkflowCheckConditionsUnknownVariable(t *testing.T) {
+	params := []Parameter{{Name: "git.branch", Value: "master"}}
+	ok, err := WorkflowCheckConditions([]WorkflowTriggerCondition{
+		{Variable: "git.branch", Operator: WorkflowConditionsOperatorEquals, Value: `{{.cds.default.branch | default "master"}}`},
+	}, params)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = WorkflowCheckConditions([]WorkflowTriggerCondition{
+		{Variable: "git.branch", Operator: WorkflowConditionsOperatorEquals, Value: "{{.cds.default.branch}}"},
+	}, params)
+	assert.Error(t, err)
+}
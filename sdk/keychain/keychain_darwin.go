@@ -24,3 +24,9 @@ func GetSecret(url string) (username string, secret string, err error) {
 	username, secret, err = nativeStore.Get(url)
 	return
 }
+
+//DeleteSecret removes a credential through the keychain
+func DeleteSecret(url string) error {
+	var nativeStore = osxkeychain.Osxkeychain{}
+	return nativeStore.Delete(url)
+}
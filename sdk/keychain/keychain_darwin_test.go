@@ -20,3 +20,12 @@ func Test_EmptyStoreSecret(t *testing.T) {
 	_, _, err := GetSecret("http://test.url.local.empty")
 	assert.Error(t, err)
 }
+
+func Test_DeleteSecret(t *testing.T) {
+	err := StoreSecret("http://test.url.local.delete", "username", "password")
+	assert.NoError(t, err)
+
+	assert.NoError(t, DeleteSecret("http://test.url.local.delete"))
+	_, _, err = GetSecret("http://test.url.local.delete")
+	assert.Error(t, err)
+}
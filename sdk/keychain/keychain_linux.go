@@ -58,3 +58,16 @@ func GetSecret(url string) (username string, secret string, err error) {
 	username, secret, err = nativeStore.Get(url)
 	return
 }
+
+//DeleteSecret removes a credential through libsecret
+func DeleteSecret(url string) error {
+	ok, err := checkLibSecretAvailable()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLibSecretUnavailable
+	}
+	var nativeStore = secretservice.Secretservice{}
+	return nativeStore.Delete(url)
+}
@@ -36,3 +36,17 @@ func Test_EmptyStoreSecret(t *testing.T) {
 	_, _, err := GetSecret("http://test.url.local.empty")
 	assert.Error(t, err)
 }
+
+func Test_DeleteSecret(t *testing.T) {
+	//If we are runnig inside a CDS worker. Skip the test
+	if os.Getenv("CDS_KEY") != "" {
+		t.SkipNow()
+	}
+
+	err := StoreSecret("http://test.url.local.delete", "username", "password")
+	assert.NoError(t, err)
+
+	assert.NoError(t, DeleteSecret("http://test.url.local.delete"))
+	_, _, err = GetSecret("http://test.url.local.delete")
+	assert.Error(t, err)
+}
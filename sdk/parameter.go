@@ -112,7 +112,7 @@ func ParametersFromProjectVariables(proj *Project) map[string]string {
 	if proj == nil {
 		return nil
 	}
-	params := variablesToParameters("cds.proj", proj.Variable)
+	params := variablesToParameters("cds.proj", VariablesWithSets(proj.Variable, proj.VariableSets))
 	return ParametersToMap(params)
 }
 
@@ -121,7 +121,7 @@ func ParametersFromApplicationVariables(app *Application) map[string]string {
 	if app == nil {
 		return nil
 	}
-	params := variablesToParameters("cds.app", app.Variable)
+	params := variablesToParameters("cds.app", VariablesWithSets(app.Variable, app.VariableSets))
 	return ParametersToMap(params)
 }
 
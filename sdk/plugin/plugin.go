@@ -31,29 +31,33 @@ func Serve(a CDSAction) {
 //NewClient has to be called every time we nedd to call a plugin
 func NewClient(ctx context.Context, name, binary, id, url string, tlsSkipVerify bool) *Client {
 	cmd := exec.CommandContext(ctx, binary)
+	cmd.Env = Environ()
 
-	env := os.Environ()
-	cmd.Env = []string{}
-	// filter technical env variables
-	for _, e := range env {
-		if strings.HasPrefix(e, "CDS_MODEL=") ||
-			strings.HasPrefix(e, "CDS_TTL=") ||
-			strings.HasPrefix(e, "CDS_SINGLE_USE=") ||
-			strings.HasPrefix(e, "CDS_NAME=") ||
-			strings.HasPrefix(e, "CDS_TOKEN=") ||
-			strings.HasPrefix(e, "CDS_API=") ||
-			strings.HasPrefix(e, "CDS_HATCHERY=") {
-			continue
-		}
-		cmd.Env = append(cmd.Env, e)
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			name: CDSActionPlugin{},
+		},
+		Cmd: cmd,
+	})
+
+	options := Options{
+		ID:            id,
+		URL:           url,
+		TlsSkipVerify: tlsSkipVerify,
 	}
 
+	return &Client{client, name, binary, options}
+}
+
+//NewReattachClient returns a client on a plugin process already started by the caller
+func NewReattachClient(name, binary string, reattach *plugin.ReattachConfig, id, url string, tlsSkipVerify bool) *Client {
 	client := plugin.NewClient(&plugin.ClientConfig{
 		HandshakeConfig: Handshake,
 		Plugins: map[string]plugin.Plugin{
 			name: CDSActionPlugin{},
 		},
-		Cmd: cmd,
+		Reattach: reattach,
 	})
 
 	options := Options{
@@ -65,6 +69,24 @@ func NewClient(ctx context.Context, name, binary, id, url string, tlsSkipVerify
 	return &Client{client, name, binary, options}
 }
 
+//Environ returns the environment of the worker without its technical variables
+func Environ() []string {
+	env := []string{}
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, "CDS_MODEL=") ||
+			strings.HasPrefix(e, "CDS_TTL=") ||
+			strings.HasPrefix(e, "CDS_SINGLE_USE=") ||
+			strings.HasPrefix(e, "CDS_NAME=") ||
+			strings.HasPrefix(e, "CDS_TOKEN=") ||
+			strings.HasPrefix(e, "CDS_API=") ||
+			strings.HasPrefix(e, "CDS_HATCHERY=") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return env
+}
+
 //CDSActionPlugin is the implementation of plugin.Plugin so we can serve/consume this
 type CDSActionPlugin struct {
 	CDSAction
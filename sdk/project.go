@@ -19,6 +19,7 @@ type Project struct {
 	Applications  []Application         `json:"applications,omitempty" yaml:"applications,omitempty" db:"-"  cli:"-"`
 	ProjectGroups []GroupPermission     `json:"groups,omitempty" yaml:"permissions,omitempty" db:"-"  cli:"-"`
 	Variable      []Variable            `json:"variables,omitempty" yaml:"variables,omitempty" db:"-"  cli:"-"`
+	VariableSets  []VariableSet         `json:"variable_sets,omitempty" yaml:"-" db:"-"  cli:"-"`
 	Environments  []Environment         `json:"environments,omitempty"  yaml:"environments,omitempty" db:"-"  cli:"-"`
 	Permission    int                   `json:"permission"  yaml:"-" db:"-"  cli:"-"`
 	Created       time.Time             `json:"created"  yaml:"created" db:"created" `
@@ -26,6 +27,7 @@ type Project struct {
 	ReposManager  []RepositoriesManager `json:"repositories_manager"  yaml:"-" db:"-" cli:"-"`
 	Metadata      Metadata              `json:"metadata" yaml:"metadata" db:"-" cli:"-"`
 	Keys          []ProjectKey          `json:"keys" yaml:"keys" db:"-" cli:"-"`
+	Priority      int64                 `json:"priority" yaml:"priority,omitempty" db:"priority" cli:"priority"`
 }
 
 // ProjectVariableAudit represents an audit on a project variable
@@ -0,0 +1,46 @@
+package sdk
+
+// Search result types
+const (
+	SearchTypeProject     = "project"
+	SearchTypeApplication = "application"
+	SearchTypePipeline    = "pipeline"
+	SearchTypeEnvironment = "environment"
+	SearchTypeWorkflow    = "workflow"
+	SearchTypeVariable    = "variable"
+	SearchTypeAction      = "action"
+	SearchTypeRequirement = "requirement"
+	SearchTypeRepository  = "repository"
+	SearchTypeScript      = "script"
+)
+
+// SearchTypes lists all the types of result of the search
+var SearchTypes = []string{
+	SearchTypeProject,
+	SearchTypeApplication,
+	SearchTypePipeline,
+	SearchTypeEnvironment,
+	SearchTypeWorkflow,
+	SearchTypeVariable,
+	SearchTypeAction,
+	SearchTypeRequirement,
+	SearchTypeRepository,
+	SearchTypeScript,
+}
+
+// SearchFilter represents the criteria of a search across projects
+type SearchFilter struct {
+	Query      string   `json:"query"`
+	ProjectKey string   `json:"project_key,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+}
+
+// SearchResult is an entity matching a search. Entity is the path of the entity
+// in its project, ie: pipeline/build/Compile for a job, Match the matching content
+type SearchResult struct {
+	Type       string `json:"type" cli:"type"`
+	ProjectKey string `json:"project_key" cli:"project"`
+	Entity     string `json:"entity" cli:"entity"`
+	Match      string `json:"match" cli:"match"`
+}
@@ -140,6 +140,21 @@ type ApplyTemplatesOptions struct {
 	TemplateParams  []TemplateParam `json:"template_params"`
 }
 
+//ApplyWorkflowTemplateOptions represents arguments to create a workflow and all its components from a template
+type ApplyWorkflowTemplateOptions struct {
+	WorkflowName   string          `json:"name"`
+	TemplateName   string          `json:"template"`
+	TemplateParams []TemplateParam `json:"template_params"`
+}
+
+//TemplateWorkflow represents all the entities generated by a workflow template extension
+type TemplateWorkflow struct {
+	Pipelines    []Pipeline    `json:"pipelines"`
+	Applications []Application `json:"applications"`
+	Environments []Environment `json:"environments"`
+	Workflow     Workflow      `json:"workflow"`
+}
+
 //GetName returns the name of the template extension
 func (a *TemplateExtension) GetName() string {
 	return a.Name
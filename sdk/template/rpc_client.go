@@ -3,6 +3,7 @@ package template
 import (
 	"log"
 	"net/rpc"
+	"strings"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/plugin"
@@ -109,3 +110,17 @@ func (c *RPCClient) Apply(opts IApplyOptions) (sdk.Application, error) {
 	}
 	return resp, err
 }
+
+//ApplyWorkflow create a fresh new CDS workflow from the template extension
+func (c *RPCClient) ApplyWorkflow(opts IApplyOptions) (sdk.TemplateWorkflow, error) {
+	var resp sdk.TemplateWorkflow
+	err := c.client.Call("Plugin.ApplyWorkflow", &opts, &resp)
+	if err != nil {
+		// Template extensions built with an older sdk don't expose the method at all
+		if err.Error() == ErrWorkflowNotSupported.Error() || strings.Contains(err.Error(), "can't find method") {
+			return resp, ErrWorkflowNotSupported
+		}
+		log.Println("[ERROR] Plugin.ApplyWorkflow rpc failed")
+	}
+	return resp, err
+}
@@ -60,6 +60,18 @@ func (s *RPCServer) Apply(args interface{}, resp *sdk.Application) error {
 	return err
 }
 
+//ApplyWorkflow returns a workflow and all its components ready to persist in database
+func (s *RPCServer) ApplyWorkflow(args interface{}, resp *sdk.TemplateWorkflow) error {
+	impl, ok := s.Impl.(WorkflowInterface)
+	if !ok {
+		return ErrWorkflowNotSupported
+	}
+	var err error
+	opts := args.(IApplyOptions)
+	*resp, err = impl.ApplyWorkflow(opts)
+	return err
+}
+
 //Init the rpc plugin
 func (s *RPCServer) Init(args interface{}, resp *string) error {
 	opts := args.(plugin.IOptions)
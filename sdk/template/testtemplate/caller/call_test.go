@@ -55,4 +55,15 @@ func TestTemplateCall(t *testing.T) {
 
 	assert.Equal(t, "myApp", app.Name)
 
+	wtemplate, ok := _template.(template.WorkflowInterface)
+	assert.True(t, ok)
+	res, err := wtemplate.ApplyWorkflow(template.NewApplyWorkflowOptions("proj", "myWorkflow", *params))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	assert.Equal(t, "myWorkflow", res.Workflow.Name)
+	assert.Equal(t, 2, len(res.Pipelines))
+	assert.Equal(t, "deploy", res.Workflow.Root.Triggers[0].WorkflowDestNode.Pipeline.Name)
+
 }
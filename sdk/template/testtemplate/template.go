@@ -166,6 +166,41 @@ func (t *TestTemplate) Apply(opts template.IApplyOptions) (sdk.Application, erro
 	}, nil
 }
 
+func (t *TestTemplate) ApplyWorkflow(opts template.IApplyOptions) (sdk.TemplateWorkflow, error) {
+	return sdk.TemplateWorkflow{
+		Pipelines: []sdk.Pipeline{
+			{Name: "build", Type: sdk.BuildPipeline},
+			{Name: "deploy", Type: sdk.DeploymentPipeline},
+		},
+		Applications: []sdk.Application{
+			{Name: opts.WorkflowName()},
+		},
+		Environments: []sdk.Environment{
+			{Name: "Production"},
+		},
+		Workflow: sdk.Workflow{
+			Name: opts.WorkflowName(),
+			Root: &sdk.WorkflowNode{
+				Pipeline: sdk.Pipeline{Name: "build"},
+				Context: &sdk.WorkflowNodeContext{
+					Application: &sdk.Application{Name: opts.WorkflowName()},
+				},
+				Triggers: []sdk.WorkflowNodeTrigger{
+					{
+						WorkflowDestNode: sdk.WorkflowNode{
+							Pipeline: sdk.Pipeline{Name: "deploy"},
+							Context: &sdk.WorkflowNodeContext{
+								Application: &sdk.Application{Name: opts.WorkflowName()},
+								Environment: &sdk.Environment{Name: "Production"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 func main() {
 	p := TestTemplate{}
 	template.Serve(&p)
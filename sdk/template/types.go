@@ -2,6 +2,7 @@ package template
 
 import (
 	"encoding/gob"
+	"errors"
 
 	"github.com/ovh/cds/sdk/plugin"
 
@@ -12,6 +13,7 @@ func init() {
 	gob.Register(ApplyOptions{})
 	gob.Register(Parameters{})
 	gob.Register(sdk.Application{})
+	gob.Register(sdk.TemplateWorkflow{})
 	gob.Register(sdk.TemplateParam{})
 }
 
@@ -28,6 +30,16 @@ type Interface interface {
 	Apply(opts IApplyOptions) (sdk.Application, error)
 }
 
+//WorkflowInterface is the interface for template extensions which generate a whole workflow
+//with its pipelines, applications and environments
+type WorkflowInterface interface {
+	Interface
+	ApplyWorkflow(opts IApplyOptions) (sdk.TemplateWorkflow, error)
+}
+
+//ErrWorkflowNotSupported is returned by ApplyWorkflow when the template extension only generates applications
+var ErrWorkflowNotSupported = errors.New("template: workflow is not supported")
+
 //MapVar is an interface for map[string]string
 type MapVar interface {
 	All() map[string]string
@@ -43,6 +55,7 @@ type Parameters struct {
 type IApplyOptions interface {
 	ProjetKey() string
 	ApplicationName() string
+	WorkflowName() string
 	Parameters() MapVar
 }
 
@@ -50,6 +63,7 @@ type IApplyOptions interface {
 type ApplyOptions struct {
 	ProjKey string
 	AppName string
+	WfName  string
 	Params  MapVar
 }
 
@@ -72,11 +86,25 @@ func (o ApplyOptions) ApplicationName() string {
 	return o.AppName
 }
 
+//WorkflowName returns the workflow name
+func (o ApplyOptions) WorkflowName() string {
+	return o.WfName
+}
+
 //Parameters returns the list of parameters
 func (o ApplyOptions) Parameters() MapVar {
 	return o.Params
 }
 
+//NewApplyWorkflowOptions instanciate a ApplyOptions struct for a workflow template
+func NewApplyWorkflowOptions(proj, workflow string, params Parameters) ApplyOptions {
+	return ApplyOptions{
+		ProjKey: proj,
+		WfName:  workflow,
+		Params:  params,
+	}
+}
+
 //NewParameters instanciates a parameters struct
 func NewParameters(d map[string]string) *Parameters {
 	return &Parameters{Data: d}
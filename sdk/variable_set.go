@@ -0,0 +1,52 @@
+package sdk
+
+import "time"
+
+// VariableSet is a set of variables owned by a group, which can be attached to projects and applications
+// to share common variables like registry urls or tokens
+type VariableSet struct {
+	ID           int64      `json:"id" db:"id"`
+	Name         string     `json:"name" db:"name" cli:"name,key"`
+	Description  string     `json:"description" db:"description" cli:"description"`
+	GroupID      int64      `json:"group_id" db:"group_id"`
+	GroupName    string     `json:"group_name" db:"-" cli:"group"`
+	LastModified time.Time  `json:"last_modified" db:"last_modified"`
+	Variables    []Variable `json:"variables,omitempty" db:"-"`
+}
+
+// VariableSetAudit represents an audit on a variable of a variable set
+type VariableSetAudit struct {
+	ID             int64     `json:"id" db:"id"`
+	VariableSetID  int64     `json:"variable_set_id" db:"variable_set_id"`
+	VariableID     int64     `json:"variable_id" db:"variable_id"`
+	Type           string    `json:"type" db:"type"`
+	VariableBefore *Variable `json:"variable_before,omitempty" db:"-"`
+	VariableAfter  *Variable `json:"variable_after,omitempty" db:"-"`
+	Versionned     time.Time `json:"versionned" db:"versionned"`
+	Author         string    `json:"author" db:"author"`
+}
+
+// VariablesWithSets returns the variables completed with the variables of the sets. The variables
+// have the precedence over the sets, and each set has the precedence over the next ones
+func VariablesWithSets(variables []Variable, sets []VariableSet) []Variable {
+	if len(sets) == 0 {
+		return variables
+	}
+
+	res := make([]Variable, 0, len(variables))
+	names := map[string]struct{}{}
+	for _, v := range variables {
+		res = append(res, v)
+		names[v.Name] = struct{}{}
+	}
+	for _, s := range sets {
+		for _, v := range s.Variables {
+			if _, ok := names[v.Name]; ok {
+				continue
+			}
+			res = append(res, v)
+			names[v.Name] = struct{}{}
+		}
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParametersFromProjectVariablesWithSets(t *testing.T) {
+	proj := &Project{
+		Variable: []Variable{
+			{Name: "registry", Value: "registry.project", Type: StringVariable},
+		},
+		VariableSets: []VariableSet{
+			{
+				Name: "a-docker",
+				Variables: []Variable{
+					{Name: "registry", Value: "registry.a", Type: StringVariable},
+					{Name: "registry.user", Value: "user.a", Type: StringVariable},
+					{Name: "registry.password", Value: PasswordPlaceholder, Type: SecretVariable},
+				},
+			},
+			{
+				Name: "b-sonar",
+				Variables: []Variable{
+					{Name: "registry.user", Value: "user.b", Type: StringVariable},
+					{Name: "sonar.url", Value: "https://sonar", Type: StringVariable},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"cds.proj.registry":      "registry.project",
+		"cds.proj.registry.user": "user.a",
+		"cds.proj.sonar.url":     "https://sonar",
+	}, ParametersFromProjectVariables(proj))
+}
@@ -77,12 +77,17 @@ func getRepoURL(repo string, auth *AuthOpts) (string, error) {
 }
 
 func runGitCommands(repo string, commands []cmd, auth *AuthOpts, output *OutputOpts) error {
-	if strings.HasPrefix(repo, "https://") {
+	if strings.HasPrefix(repo, "https://") || isLocalRepo(repo) {
 		return runGitCommandRaw(commands, output)
 	}
 	return runGitCommandsOverSSH(commands, auth, output)
 }
 
+// isLocalRepo returns true if the repository is a directory of the local filesystem
+func isLocalRepo(repo string) bool {
+	return strings.HasPrefix(repo, "file://") || filepath.IsAbs(repo)
+}
+
 func runGitCommandsOverSSH(commands []cmd, auth *AuthOpts, output *OutputOpts) error {
 	if auth == nil {
 		return fmt.Errorf("Authentication is required for git over ssh")
@@ -42,13 +42,13 @@ func prepareGitTagCreateCommands(repo string, opts *TagOpts) cmds {
 		// Create command to import key
 		importpubCmd := cmd{
 			cmd:  "gpg",
-			args: []string{"--import", "pgp.pub.key"},
+			args: []string{"--batch", "--import", "pgp.pub.key"},
 		}
 		allCmd = append(allCmd, importpubCmd)
 
 		importcmd := cmd{
 			cmd:  "gpg",
-			args: []string{"--import", "pgp.key"},
+			args: []string{"--batch", "--import", "pgp.key"},
 		}
 
 		allCmd = append(allCmd, importcmd)
@@ -70,14 +70,15 @@ func prepareGitTagCreateCommands(repo string, opts *TagOpts) cmds {
 			gitcmd.dir = opts.Path
 			optPush.Directory = opts.Path
 		}
-		gitcmd.args = append(gitcmd.args, "-a", opts.Name)
+		if opts.SignKey != "" {
+			gitcmd.args = append(gitcmd.args, "-u", opts.SignID, opts.Name)
+		} else {
+			gitcmd.args = append(gitcmd.args, "-a", opts.Name)
+		}
 
 		if opts.Message != "" {
 			gitcmd.args = append(gitcmd.args, "-m", fmt.Sprintf("\"%s\"", opts.Message))
 		}
-		if opts.SignKey != "" {
-			gitcmd.args = append(gitcmd.args, "-u", opts.SignID)
-		}
 		optPush.Branch = opts.Name
 	}
 
@@ -18,6 +18,42 @@ type Worker struct {
 	HatcheryName string    `json:"hatchery_name" cli:"-"`
 	Status       Status    `json:"status" cli:"status"` // Waiting, Building, Disabled, Unknown
 	Uptodate     bool      `json:"up_to_date" cli:"-"`
+	Labels       []string  `json:"labels,omitempty" cli:"-"`
+	OSArch       string    `json:"os_arch,omitempty" cli:"os_arch"`
+	Maintenance  bool      `json:"maintenance" cli:"maintenance"` // In maintenance, the worker exits after its current job
+}
+
+// Capabilities returns the labels and the os-arch declared by the worker as requirements
+func (w Worker) Capabilities() []Requirement {
+	res := make([]Requirement, 0, len(w.Labels)+1)
+	for _, l := range w.Labels {
+		res = append(res, Requirement{Name: l, Type: LabelRequirement, Value: l})
+	}
+	if w.OSArch != "" {
+		res = append(res, Requirement{Name: OSArchRequirement, Type: OSArchRequirement, Value: w.OSArch})
+	}
+	return res
+}
+
+// MatchLabelRequirements returns true if all the label and os-arch requirements are provided by the capabilities,
+// other types of requirements are not checked
+func MatchLabelRequirements(capabilities []Requirement, requirements []Requirement) bool {
+	for _, r := range requirements {
+		if r.Type != LabelRequirement && r.Type != OSArchRequirement {
+			continue
+		}
+		found := false
+		for _, c := range capabilities {
+			if c.Type == r.Type && c.Value == r.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // Existing worker type
@@ -79,6 +115,18 @@ type Model struct {
 	NbSpawnErr       int64              `json:"nb_spawn_err" db:"nb_spawn_err" cli:"nb_spawn_err"`
 	LastSpawnErr     string             `json:"last_spawn_err" db:"last_spawn_err" cli:"-"`
 	DateLastSpawnErr *time.Time         `json:"date_last_spawn_err" db:"date_last_spawn_err" cli:"-"`
+	Version          int64              `json:"version" db:"version" cli:"version"`
+	NextVersion      *ModelVersion      `json:"next_version,omitempty" db:"-" cli:"-"`
+}
+
+// OSArch returns the os-arch declared in the capabilities of the model, empty if the model doesn't declare it
+func (m Model) OSArch() string {
+	for _, c := range m.Capabilities {
+		if c.Type == OSArchRequirement {
+			return c.Value
+		}
+	}
+	return ""
 }
 
 // OpenstackModelData type details the "Image" field of Openstack type model
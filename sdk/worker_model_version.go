@@ -0,0 +1,66 @@
+package sdk
+
+import "time"
+
+// Status of a worker model version
+const (
+	// ModelVersionPending is a version waiting for its registration check
+	ModelVersionPending = "Pending"
+	// ModelVersionChecked is a version which passed its registration check, it can be rolled out and promoted
+	ModelVersionChecked = "Checked"
+	// ModelVersionActive is the current version of the model
+	ModelVersionActive = "Active"
+	// ModelVersionRetired is a previous version of the model, the model can be rolled back to it
+	ModelVersionRetired = "Retired"
+)
+
+// ModelVersion is a version of the image or the template of a worker model
+type ModelVersion struct {
+	ID           int64              `json:"id" cli:"-"`
+	ModelID      int64              `json:"model_id" cli:"-"`
+	Version      int64              `json:"version" cli:"version"`
+	Image        string             `json:"image" cli:"image"`
+	Template     *map[string]string `json:"template,omitempty" cli:"-"`
+	Status       string             `json:"status" cli:"status"`
+	Rollout      int64              `json:"rollout" cli:"rollout"`
+	Capabilities []Requirement      `json:"capabilities" cli:"-"`
+	ToolVersions map[string]string  `json:"tool_versions" cli:"-"`
+	Created      time.Time          `json:"created" cli:"created"`
+	CreatedBy    string             `json:"created_by" cli:"created_by"`
+	Checked      *time.Time         `json:"checked,omitempty" cli:"-"`
+}
+
+// ModelVersionRolloutForm is the form to roll out a version to a percent of the spawns
+type ModelVersionRolloutForm struct {
+	Rollout int64 `json:"rollout"`
+}
+
+// WithVersion returns a copy of the model with the image and the template of the version
+func (m Model) WithVersion(v ModelVersion) Model {
+	m.Image = v.Image
+	m.Template = v.Template
+	m.Version = v.Version
+	m.NextVersion = nil
+	return m
+}
+
+// CanCheckVersions returns true if the versions of the model can be checked and rolled out before their promotion:
+// the image of a docker model is pulled at each spawn, the images of the other types of models are built
+// by the hatcheries at the registration of the model
+func (m Model) CanCheckVersions() bool {
+	return m.Type == Docker
+}
+
+// NeedVersionCheck returns true if the next version of the model waits for its registration check
+func (m Model) NeedVersionCheck() bool {
+	return m.CanCheckVersions() && m.NextVersion != nil && m.NextVersion.Status == ModelVersionPending
+}
+
+// ModelForSpawn returns the model to spawn a worker: the next version of the model if it is rolled out
+// and n is below its rollout percent, the current version otherwise. n is in [0, 100)
+func (m Model) ModelForSpawn(n int) Model {
+	if m.CanCheckVersions() && m.NextVersion != nil && m.NextVersion.Status == ModelVersionChecked && int64(n) < m.NextVersion.Rollout {
+		return m.WithVersion(*m.NextVersion)
+	}
+	return m
+}
@@ -0,0 +1,40 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelForSpawn(t *testing.T) {
+	m := Model{Name: "go", Type: Docker, Image: "golang:1.8", Version: 1}
+	assert.Equal(t, "golang:1.8", m.ModelForSpawn(0).Image)
+	assert.False(t, m.NeedVersionCheck())
+
+	m.NextVersion = &ModelVersion{Version: 2, Image: "golang:1.9", Status: ModelVersionPending, Rollout: 50}
+	assert.True(t, m.NeedVersionCheck())
+	// A version which has not passed its registration check is not rolled out
+	assert.Equal(t, "golang:1.8", m.ModelForSpawn(0).Image)
+
+	m.NextVersion.Status = ModelVersionChecked
+	assert.False(t, m.NeedVersionCheck())
+	next := m.ModelForSpawn(49)
+	assert.Equal(t, "golang:1.9", next.Image)
+	assert.Equal(t, int64(2), next.Version)
+	assert.Nil(t, next.NextVersion)
+	assert.Equal(t, "go", next.Name)
+
+	current := m.ModelForSpawn(50)
+	assert.Equal(t, "golang:1.8", current.Image)
+	assert.Equal(t, int64(1), current.Version)
+
+	m.NextVersion.Rollout = 0
+	assert.Equal(t, "golang:1.8", m.ModelForSpawn(0).Image)
+
+	// The images of openstack models are built at the registration
+	m.Type = Openstack
+	m.NextVersion.Rollout = 100
+	assert.Equal(t, "golang:1.8", m.ModelForSpawn(0).Image)
+	m.NextVersion.Status = ModelVersionPending
+	assert.False(t, m.NeedVersionCheck())
+}
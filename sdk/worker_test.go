@@ -0,0 +1,24 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLabelRequirements(t *testing.T) {
+	w := Worker{Labels: []string{"pool=high-cpu", "gpu"}, OSArch: "linux/arm64"}
+
+	assert.True(t, MatchLabelRequirements(w.Capabilities(), nil))
+	assert.True(t, MatchLabelRequirements(w.Capabilities(), []Requirement{
+		{Name: "gpu", Type: LabelRequirement, Value: "gpu"},
+		{Name: OSArchRequirement, Type: OSArchRequirement, Value: "linux/arm64"},
+		{Name: "go", Type: BinaryRequirement, Value: "go"},
+	}))
+	assert.False(t, MatchLabelRequirements(w.Capabilities(), []Requirement{
+		{Name: OSArchRequirement, Type: OSArchRequirement, Value: "linux/amd64"},
+	}))
+	assert.False(t, MatchLabelRequirements(w.Capabilities(), []Requirement{
+		{Name: "pool=low-cpu", Type: LabelRequirement, Value: "pool=low-cpu"},
+	}))
+}
@@ -88,6 +88,23 @@ func (w *Workflow) GetNode(id int64) *WorkflowNode {
 	return nil
 }
 
+//GetNodeByName returns the node given its name
+func (w *Workflow) GetNodeByName(name string) *WorkflowNode {
+	n := w.Root.GetNodeByName(name)
+	if n != nil {
+		return n
+	}
+	for _, j := range w.Joins {
+		for _, t := range j.Triggers {
+			n = t.WorkflowDestNode.GetNodeByName(name)
+			if n != nil {
+				return n
+			}
+		}
+	}
+	return nil
+}
+
 //GetJoin returns the join given its id
 func (w *Workflow) GetJoin(id int64) *WorkflowNodeJoin {
 	for _, j := range w.Joins {
@@ -274,6 +291,22 @@ func (n *WorkflowNode) GetNode(id int64) *WorkflowNode {
 	return nil
 }
 
+//GetNodeByName returns the node given its name
+func (n *WorkflowNode) GetNodeByName(name string) *WorkflowNode {
+	if n == nil {
+		return nil
+	}
+	if n.Name == name {
+		return n
+	}
+	for i := range n.Triggers {
+		if res := n.Triggers[i].WorkflowDestNode.GetNodeByName(name); res != nil {
+			return res
+		}
+	}
+	return nil
+}
+
 //Nodes returns a slice with all node IDs
 func (n *WorkflowNode) Nodes() []int64 {
 	res := []int64{n.ID}
@@ -390,6 +423,7 @@ type WorkflowNodeContext struct {
 	EnvironmentID             int64        `json:"environment_id" db:"environment_id"`
 	DefaultPayload            interface{}  `json:"default_payload,omitempty" db:"-"`
 	DefaultPipelineParameters []Parameter  `json:"default_pipeline_parameters,omitempty" db:"-"`
+	Priority                  int64        `json:"priority,omitempty" db:"priority"`
 }
 
 //WorkflowNodeHook represents a hook which cann trigger the workflow from a given node
@@ -43,11 +43,14 @@ func WorkflowCheckConditions(conditions []WorkflowTriggerCondition, params []Par
 
 	var conditionsOK = true
 	for _, cond := range conditions {
-		var err error
-		cond.Value, err = Interpolate(cond.Value, mapParams)
+		value, unknown, err := InterpolateWithUnknown(cond.Value, mapParams)
 		if err != nil {
 			return false, fmt.Errorf("Unable to interpolate %s (%v)", cond.Value, err)
 		}
+		if len(unknown) > 0 {
+			return false, fmt.Errorf("Unable to interpolate %s (unknown variables %s)", cond.Value, strings.Join(unknown, ", "))
+		}
+		cond.Value = value
 
 		switch cond.Operator {
 		case WorkflowConditionsOperatorEquals:
@@ -23,6 +23,39 @@ type WorkflowRun struct {
 	Tags             []WorkflowRunTag            `json:"tags" db:"-"`
 }
 
+// LastNodeRun returns the last run of a node, nil if the node has not been run
+func (r *WorkflowRun) LastNodeRun(nodeID int64) *WorkflowNodeRun {
+	var last *WorkflowNodeRun
+	runs := r.WorkflowNodeRuns[nodeID]
+	for i := range runs {
+		if last == nil || runs[i].SubNumber > last.SubNumber {
+			last = &runs[i]
+		}
+	}
+	return last
+}
+
+// Status returns the status of the run computed from the last run of each node: Building while a node
+// run is not over, Success if all the node runs succeeded or were skipped, Fail otherwise. A node run
+// ended with any other status, like a stopped or an unknown one, fails the run
+func (r *WorkflowRun) Status() string {
+	if len(r.WorkflowNodeRuns) == 0 {
+		return StatusWaiting.String()
+	}
+	status := StatusSuccess
+	for id := range r.WorkflowNodeRuns {
+		switch StatusFromString(r.LastNodeRun(id).Status) {
+		case StatusWaiting, StatusChecking, StatusBuilding:
+			return StatusBuilding.String()
+		case StatusSuccess, StatusSkipped, StatusDisabled:
+			continue
+		default:
+			status = StatusFail
+		}
+	}
+	return status.String()
+}
+
 // WorkflowNodeRunRelease represents the request struct use by release builtin action for workflow
 type WorkflowNodeRunRelease struct {
 	TagName        string   `json:"tag_name"`
@@ -131,6 +164,9 @@ type WorkflowNodeJobRun struct {
 	Model             string      `json:"model,omitempty" db:"model"`
 	BookedBy          Hatchery    `json:"bookedby" db:"-"`
 	SpawnInfos        []SpawnInfo `json:"spawninfos" db:"-"`
+	Priority          int64       `json:"priority" db:"priority"`
+	QueuePosition     int         `json:"queue_position,omitempty" db:"-"`
+	QueueETASeconds   int64       `json:"queue_eta_seconds,omitempty" db:"-"`
 }
 
 // Translate translates messages in WorkflowNodeJobRun
@@ -142,6 +178,16 @@ func (njr *WorkflowNodeJobRun) Translate(lang string) {
 
 }
 
+// SetQueuePosition sets the position of a waiting job in the queue and the estimated time before it starts,
+// computed from the number of jobs started during the last window
+func (njr *WorkflowNodeJobRun) SetQueuePosition(position int, started int64, window time.Duration) {
+	njr.QueuePosition = position
+	njr.QueueETASeconds = 0
+	if started > 0 {
+		njr.QueueETASeconds = int64(float64(position) * window.Seconds() / float64(started))
+	}
+}
+
 //WorkflowNodeRunHookEvent is an instanc of event received on a hook
 type WorkflowNodeRunHookEvent struct {
 	Payload              map[string]string `json:"payload" db:"-"`
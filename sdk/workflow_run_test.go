@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRunStatus(t *testing.T) {
+	r := WorkflowRun{}
+	assert.Equal(t, StatusWaiting.String(), r.Status())
+
+	r.WorkflowNodeRuns = map[int64][]WorkflowNodeRun{
+		1: {{SubNumber: 1, Status: StatusSuccess.String()}, {SubNumber: 0, Status: StatusFail.String()}},
+		2: {{SubNumber: 0, Status: StatusSkipped.String()}},
+	}
+	// The node 1 has been run again successfully
+	assert.Equal(t, StatusSuccess.String(), r.Status())
+	assert.Equal(t, int64(1), r.LastNodeRun(1).SubNumber)
+	assert.Nil(t, r.LastNodeRun(3))
+
+	r.WorkflowNodeRuns[3] = []WorkflowNodeRun{{Status: StatusTimeout.String()}}
+	assert.Equal(t, StatusFail.String(), r.Status())
+
+	r.WorkflowNodeRuns[4] = []WorkflowNodeRun{{Status: StatusBuilding.String()}}
+	assert.Equal(t, StatusBuilding.String(), r.Status())
+
+	// A stopped or unknown node run fails the run
+	r.WorkflowNodeRuns = map[int64][]WorkflowNodeRun{
+		1: {{Status: StatusSuccess.String()}},
+		2: {{Status: "Stopped"}},
+	}
+	assert.Equal(t, StatusFail.String(), r.Status())
+	r.WorkflowNodeRuns[2] = []WorkflowNodeRun{{Status: StatusUnknown.String()}}
+	assert.Equal(t, StatusFail.String(), r.Status())
+	r.WorkflowNodeRuns[2] = []WorkflowNodeRun{{Status: StatusDisabled.String()}}
+	assert.Equal(t, StatusSuccess.String(), r.Status())
+}
+
+func TestWorkflowGetNodeByName(t *testing.T) {
+	w := Workflow{
+		Root: &WorkflowNode{
+			ID:   1,
+			Name: "build",
+			Triggers: []WorkflowNodeTrigger{
+				{WorkflowDestNode: WorkflowNode{ID: 2, Name: "test"}},
+				{WorkflowDestNode: WorkflowNode{ID: 3, Name: "package"}},
+			},
+		},
+		Joins: []WorkflowNodeJoin{
+			{Triggers: []WorkflowNodeJoinTrigger{{WorkflowDestNode: WorkflowNode{ID: 4, Name: "deploy"}}}},
+		},
+	}
+	assert.Equal(t, int64(1), w.GetNodeByName("build").ID)
+	assert.Equal(t, int64(3), w.GetNodeByName("package").ID)
+	assert.Equal(t, int64(4), w.GetNodeByName("deploy").ID)
+	assert.Nil(t, w.GetNodeByName("unknown"))
+}
+
+func TestWorkflowNodeJobRunSetQueuePosition(t *testing.T) {
+	j := WorkflowNodeJobRun{QueueETASeconds: 42}
+	j.SetQueuePosition(1, 30, 15*time.Minute)
+	assert.Equal(t, 1, j.QueuePosition)
+	assert.Equal(t, int64(30), j.QueueETASeconds)
+
+	j.SetQueuePosition(7, 30, 15*time.Minute)
+	assert.Equal(t, int64(210), j.QueueETASeconds)
+
+	// Without started jobs the queue can't be estimated
+	j.SetQueuePosition(7, 0, 15*time.Minute)
+	assert.Equal(t, 7, j.QueuePosition)
+	assert.Equal(t, int64(0), j.QueueETASeconds)
+}
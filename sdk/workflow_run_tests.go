@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"sort"
+	"time"
+)
+
+// WorkflowTestCaseResult is the result of a test case in a workflow node run
+type WorkflowTestCaseResult struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	Number            int64     `json:"num" db:"num" cli:"num"`
+	GitHash           string    `json:"git_hash" db:"git_hash" cli:"git_hash"`
+	TestSuite         string    `json:"test_suite" db:"test_suite"`
+	TestCase          string    `json:"test_case" db:"test_case"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Duration          float64   `json:"duration" db:"duration" cli:"duration"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// WorkflowTestCaseStats aggregates the results of a test case across workflow runs.
+// A test case is flaky when it both succeeded and failed on the same commit
+type WorkflowTestCaseStats struct {
+	TestSuite       string                   `json:"test_suite" cli:"suite"`
+	TestCase        string                   `json:"test_case" cli:"case"`
+	Runs            int                      `json:"runs" cli:"runs"`
+	Failures        int                      `json:"failures" cli:"failures"`
+	Skipped         int                      `json:"skipped" cli:"skipped"`
+	FailureRate     float64                  `json:"failure_rate" cli:"failure_rate"`
+	AverageDuration float64                  `json:"average_duration" cli:"avg_duration"`
+	LastStatus      string                   `json:"last_status" cli:"last_status"`
+	Flaky           bool                     `json:"flaky" cli:"flaky"`
+	FlakyCommits    []string                 `json:"flaky_commits,omitempty"`
+	History         []WorkflowTestCaseResult `json:"history,omitempty"`
+}
+
+// ComputeTestCaseStats aggregates test case results by suite and test case. Results
+// have to be sorted from the oldest to the newest run
+func ComputeTestCaseStats(results []WorkflowTestCaseResult, withHistory bool) []WorkflowTestCaseStats {
+	type key struct{ suite, name string }
+	stats := map[key]*WorkflowTestCaseStats{}
+	statuses := map[key]map[string]map[string]bool{}
+	keys := []key{}
+
+	for _, r := range results {
+		k := key{r.TestSuite, r.TestCase}
+		s, ok := stats[k]
+		if !ok {
+			s = &WorkflowTestCaseStats{TestSuite: r.TestSuite, TestCase: r.TestCase}
+			stats[k] = s
+			statuses[k] = map[string]map[string]bool{}
+			keys = append(keys, k)
+		}
+
+		s.LastStatus = r.Status
+		if withHistory {
+			s.History = append(s.History, r)
+		}
+		if r.Status == StatusSkipped.String() {
+			s.Skipped++
+			continue
+		}
+
+		s.AverageDuration = (s.AverageDuration*float64(s.Runs) + r.Duration) / float64(s.Runs+1)
+		s.Runs++
+		if r.Status == StatusFail.String() {
+			s.Failures++
+		}
+
+		if r.GitHash == "" {
+			continue
+		}
+		if statuses[k][r.GitHash] == nil {
+			statuses[k][r.GitHash] = map[string]bool{}
+		}
+		statuses[k][r.GitHash][r.Status] = true
+	}
+
+	res := make([]WorkflowTestCaseStats, 0, len(keys))
+	for _, k := range keys {
+		s := stats[k]
+		if s.Runs > 0 {
+			s.FailureRate = float64(s.Failures) / float64(s.Runs)
+		}
+		for hash, st := range statuses[k] {
+			if st[StatusSuccess.String()] && st[StatusFail.String()] {
+				s.Flaky = true
+				s.FlakyCommits = append(s.FlakyCommits, hash)
+			}
+		}
+		sort.Strings(s.FlakyCommits)
+		res = append(res, *s)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].TestSuite == res[j].TestSuite {
+			return res[i].TestCase < res[j].TestCase
+		}
+		return res[i].TestSuite < res[j].TestSuite
+	})
+	return res
+}
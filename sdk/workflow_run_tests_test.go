@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTestCaseStats(t *testing.T) {
+	results := []WorkflowTestCaseResult{
+		{Number: 1, GitHash: "aaa", TestSuite: "suite", TestCase: "stable", Status: "Success", Duration: 1},
+		{Number: 1, GitHash: "aaa", TestSuite: "suite", TestCase: "flaky", Status: "Fail", Duration: 2},
+		{Number: 2, GitHash: "aaa", TestSuite: "suite", TestCase: "stable", Status: "Success", Duration: 3},
+		{Number: 2, GitHash: "aaa", TestSuite: "suite", TestCase: "flaky", Status: "Success", Duration: 4},
+		{Number: 3, GitHash: "bbb", TestSuite: "suite", TestCase: "flaky", Status: "Fail", Duration: 3},
+		{Number: 3, GitHash: "bbb", TestSuite: "suite", TestCase: "stable", Status: "Skipped"},
+	}
+
+	stats := ComputeTestCaseStats(results, false)
+	assert.Len(t, stats, 2)
+
+	flaky := stats[0]
+	assert.Equal(t, "flaky", flaky.TestCase)
+	assert.Equal(t, 3, flaky.Runs)
+	assert.Equal(t, 2, flaky.Failures)
+	assert.InDelta(t, 2.0/3.0, flaky.FailureRate, 0.001)
+	assert.InDelta(t, 3, flaky.AverageDuration, 0.001)
+	assert.Equal(t, "Fail", flaky.LastStatus)
+	assert.True(t, flaky.Flaky)
+	assert.Equal(t, []string{"aaa"}, flaky.FlakyCommits)
+	assert.Empty(t, flaky.History)
+
+	stable := stats[1]
+	assert.Equal(t, "stable", stable.TestCase)
+	assert.Equal(t, 2, stable.Runs)
+	assert.Equal(t, 1, stable.Skipped)
+	assert.Equal(t, 0.0, stable.FailureRate)
+	assert.InDelta(t, 2, stable.AverageDuration, 0.001)
+	assert.Equal(t, "Skipped", stable.LastStatus)
+	assert.False(t, stable.Flaky)
+
+	stats = ComputeTestCaseStats(results, true)
+	assert.Len(t, stats[0].History, 3)
+}